@@ -1,25 +1,60 @@
 package main
 
-import "github.com/ObiaNzk/LTK-test-manu/internal/platform"
+import (
+	"time"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal"
+	"github.com/ObiaNzk/LTK-test-manu/internal/auth"
+	"github.com/ObiaNzk/LTK-test-manu/internal/outbox"
+	"github.com/ObiaNzk/LTK-test-manu/internal/platform"
+)
 
 type config struct {
 	IsProduction bool
 	DBConfig     platform.DBConfig
+	AuthConfig   auth.Config
+	// IdempotencyTTL is how long responses to an Idempotency-Key are replayed.
+	IdempotencyTTL time.Duration
+	// Outbox is where the relay publishes the changes to events.
+	Outbox outboxConfig
+}
+
+// outboxConfig picks the publisher of the relay: the NATS server at NATS.URL
+// when set, else the file at File when set, else memory.
+type outboxConfig struct {
+	NATS outbox.NATSConfig
+	File string
+	// Interval is how often the relay polls the outbox.
+	Interval time.Duration
 }
 
 func newLocalConfig() config {
 	dbConfig := platform.DBConfig{
-		Host:     "localhost",
-		Port:     "5432",
-		User:     "postgres",
-		Password: "postgres",
+		Host: "localhost",
+		Port: "5432",
+		// Superusers bypass row level security, so connect as the app role.
+		User:     "events_app",
+		Password: "events_app",
 		DBName:   "events_db",
 		SSLMode:  "disable",
 	}
 
+	authConfig := auth.Config{
+		JWKSFile: "jwks.json",
+		Issuer:   "http://localhost:8080",
+		Audience: "events-api",
+		Leeway:   30 * time.Second,
+	}
+
 	config := config{
-		IsProduction: false,
-		DBConfig:     dbConfig,
+		IsProduction:   false,
+		DBConfig:       dbConfig,
+		AuthConfig:     authConfig,
+		IdempotencyTTL: internal.DefaultIdempotencyTTL,
+		Outbox: outboxConfig{
+			File:     "outbox.jsonl",
+			Interval: internal.DefaultRelayInterval,
+		},
 	}
 
 	return config
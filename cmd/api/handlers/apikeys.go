@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal"
+	"github.com/go-chi/chi/v5"
+)
+
+//go:generate mockgen -source=apikeys.go -destination=mocks/mock_api_keys_service.go -package=mocks
+
+type apiKeysService interface {
+	CreateAPIKey(ctx context.Context, key internal.APIKey) (internal.APIKey, string, error)
+	GetAPIKeys(ctx context.Context) ([]internal.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id string) error
+	AuthenticateAPIKey(ctx context.Context, token string) (internal.APIKey, error)
+}
+
+type apiKeyBody struct {
+	Name   string           `json:"name"`
+	Scopes []internal.Scope `json:"scopes"`
+	// ExpiresAt defaults to 90 days after creation.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type apiKeyResponse struct {
+	ID         string           `json:"id"`
+	Name       string           `json:"name"`
+	Prefix     string           `json:"prefix"`
+	Scopes     []internal.Scope `json:"scopes"`
+	CreatedBy  string           `json:"created_by,omitempty"`
+	CreatedAt  time.Time        `json:"created_at"`
+	ExpiresAt  time.Time        `json:"expires_at"`
+	LastUsedAt *time.Time       `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time       `json:"revoked_at,omitempty"`
+	// Key is the key itself, only returned when it is created.
+	Key string `json:"key,omitempty"`
+}
+
+func newAPIKeyResponse(key internal.APIKey) apiKeyResponse {
+	return apiKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		CreatedBy:  key.CreatedBy,
+		CreatedAt:  key.CreatedAt,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+	}
+}
+
+// CreateAPIKey issues an API key (POST /admin/api-keys). The response is the
+// only time the key is shown.
+func (h *Handler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var payload apiKeyBody
+
+	if err := decodeBody(r, &payload); err != nil {
+		writeDecodeError(w, r, err)
+		return
+	}
+
+	request := internal.APIKey{Name: payload.Name, Scopes: payload.Scopes}
+	if payload.ExpiresAt != nil {
+		request.ExpiresAt = *payload.ExpiresAt
+	}
+
+	key, token, err := h.apiKeysService.CreateAPIKey(r.Context(), request)
+	if err != nil {
+		writeAPIKeyError(w, r, err, "error creating api key")
+		return
+	}
+
+	response := newAPIKeyResponse(key)
+	response.Key = token
+
+	writeJSON(w, r, http.StatusCreated, response)
+}
+
+// GetAPIKeys lists the API keys of the tenant (GET /admin/api-keys).
+func (h *Handler) GetAPIKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.apiKeysService.GetAPIKeys(r.Context())
+	if err != nil {
+		writeAPIKeyError(w, r, err, "error getting api keys")
+		return
+	}
+
+	response := struct {
+		APIKeys []apiKeyResponse `json:"api_keys"`
+	}{
+		APIKeys: make([]apiKeyResponse, 0, len(keys)),
+	}
+
+	for _, key := range keys {
+		response.APIKeys = append(response.APIKeys, newAPIKeyResponse(key))
+	}
+
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+// RevokeAPIKey stops an API key from being accepted (DELETE /admin/api-keys/{kid}).
+func (h *Handler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	if err := h.apiKeysService.RevokeAPIKey(r.Context(), chi.URLParam(r, "kid")); err != nil {
+		writeAPIKeyError(w, r, err, "error revoking api key")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeAPIKeyError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	writeError(w, r, err, "api key not found", message)
+}
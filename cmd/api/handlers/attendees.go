@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal"
+	"github.com/go-chi/chi/v5"
+)
+
+//go:generate mockgen -source=attendees.go -destination=mocks/mock_attendees_service.go -package=mocks
+
+type attendeesService interface {
+	Invite(ctx context.Context, attendee internal.Attendee) (internal.Attendee, error)
+	GetAttendees(ctx context.Context, eventID string) ([]internal.Attendee, error)
+	RSVP(ctx context.Context, eventID, email string, status internal.AttendeeStatus) (internal.Attendee, error)
+	RemoveAttendee(ctx context.Context, eventID, email string) error
+	CountAttendees(ctx context.Context, eventID string) (map[internal.AttendeeStatus]int, error)
+}
+
+type inviteBody struct {
+	Email string                `json:"email"`
+	Name  string                `json:"name,omitempty"`
+	Role  internal.AttendeeRole `json:"role,omitempty"`
+}
+
+type rsvpBody struct {
+	Status internal.AttendeeStatus `json:"status"`
+}
+
+type attendeeResponse struct {
+	Email           string                  `json:"email"`
+	Name            string                  `json:"name,omitempty"`
+	Role            internal.AttendeeRole   `json:"role"`
+	Status          internal.AttendeeStatus `json:"status"`
+	InvitedAt       time.Time               `json:"invited_at"`
+	StatusChangedAt time.Time               `json:"status_changed_at"`
+}
+
+func newAttendeeResponse(attendee internal.Attendee) attendeeResponse {
+	return attendeeResponse{
+		Email:           attendee.Email,
+		Name:            attendee.Name,
+		Role:            attendee.Role,
+		Status:          attendee.Status,
+		InvitedAt:       attendee.InvitedAt,
+		StatusChangedAt: attendee.StatusChangedAt,
+	}
+}
+
+// InviteAttendee adds an attendee to an event (POST /events/{id}/attendees).
+func (h *Handler) InviteAttendee(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var payload inviteBody
+
+	if err := decodeBody(r, &payload); err != nil {
+		writeDecodeError(w, r, err)
+		return
+	}
+
+	attendee, err := h.attendeesService.Invite(ctx, internal.Attendee{
+		EventID: chi.URLParam(r, "id"),
+		Email:   payload.Email,
+		Name:    payload.Name,
+		Role:    payload.Role,
+	})
+	if err != nil {
+		writeAttendeeError(w, r, err, "error inviting attendee")
+		return
+	}
+
+	writeJSON(w, r, http.StatusCreated, newAttendeeResponse(attendee))
+}
+
+// GetAttendees lists the attendees of an event (GET /events/{id}/attendees).
+func (h *Handler) GetAttendees(w http.ResponseWriter, r *http.Request) {
+	attendees, err := h.attendeesService.GetAttendees(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeAttendeeError(w, r, err, "error getting attendees")
+		return
+	}
+
+	response := struct {
+		Attendees []attendeeResponse `json:"attendees"`
+	}{
+		Attendees: make([]attendeeResponse, 0, len(attendees)),
+	}
+
+	for _, attendee := range attendees {
+		response.Attendees = append(response.Attendees, newAttendeeResponse(attendee))
+	}
+
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+// RSVP records the answer of an attendee (PUT /events/{id}/attendees/{email}/rsvp).
+func (h *Handler) RSVP(w http.ResponseWriter, r *http.Request) {
+	email, err := emailParam(r)
+	if err != nil {
+		writeStatus(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var payload rsvpBody
+
+	if err := decodeBody(r, &payload); err != nil {
+		writeDecodeError(w, r, err)
+		return
+	}
+
+	attendee, err := h.attendeesService.RSVP(r.Context(), chi.URLParam(r, "id"), email, payload.Status)
+	if err != nil {
+		writeAttendeeError(w, r, err, "error answering invitation")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, newAttendeeResponse(attendee))
+}
+
+// RemoveAttendee uninvites an attendee (DELETE /events/{id}/attendees/{email}).
+func (h *Handler) RemoveAttendee(w http.ResponseWriter, r *http.Request) {
+	email, err := emailParam(r)
+	if err != nil {
+		writeStatus(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.attendeesService.RemoveAttendee(r.Context(), chi.URLParam(r, "id"), email); err != nil {
+		writeAttendeeError(w, r, err, "error removing attendee")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeAttendeeError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	writeError(w, r, err, "event or attendee not found", message)
+}
+
+// emailParam reads the attendee email from the path, where "@" may be escaped.
+func emailParam(r *http.Request) (string, error) {
+	email, err := url.PathUnescape(chi.URLParam(r, "email"))
+	if err != nil {
+		return "", errors.New("invalid email in path")
+	}
+
+	return email, nil
+}
+
+func writeJSON(w http.ResponseWriter, r *http.Request, status int, response any) {
+	jsonResult, err := json.Marshal(response)
+	if err != nil {
+		writeStatus(w, r, http.StatusInternalServerError, "error creating json response")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(jsonResult)
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal"
+	"github.com/ObiaNzk/LTK-test-manu/internal/auth"
+)
+
+// readRoutes are the routes that only read although their method is not GET,
+// such as searches taking their query in the body.
+var readRoutes = map[string]bool{
+	http.MethodPost + " /availability/search": true,
+}
+
+// requiredScope is the scope an API key needs for r: events:read for GET and
+// HEAD requests and readRoutes, events:write for everything else.
+func requiredScope(r *http.Request) internal.Scope {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead || readRoutes[r.Method+" "+r.URL.Path] {
+		return internal.ScopeEventsRead
+	}
+
+	return internal.ScopeEventsWrite
+}
+
+// Authenticate requires a bearer token, either a user JWT that verifier accepts
+// or an API key. The subject of a JWT, or "apikey:<id>" for a key, becomes the
+// actor of the request. The tenant claim of a JWT, which every JWT needs, or the
+// tenant of a key becomes the tenant of the request. API keys need the scope
+// requiredScope gives the request. The admin claim makes the actor an admin of
+// its tenant.
+func Authenticate(verifier *auth.Verifier, apiKeys apiKeysService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+			if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+				w.Header().Set("WWW-Authenticate", `Bearer`)
+				writeStatus(w, r, http.StatusUnauthorized, "missing bearer token")
+				return
+			}
+
+			var ctx context.Context
+
+			if internal.IsAPIKey(token) {
+				key, err := apiKeys.AuthenticateAPIKey(r.Context(), token)
+				if err != nil {
+					writeAuthError(w, r, err)
+					return
+				}
+
+				scope := requiredScope(r)
+				if !slices.Contains(key.Scopes, scope) {
+					w.Header().Set("WWW-Authenticate", `Bearer error="insufficient_scope", scope="`+string(scope)+`"`)
+					writeStatus(w, r, http.StatusForbidden, "api key lacks the "+string(scope)+" scope")
+					return
+				}
+
+				ctx = internal.WithActor(r.Context(), "apikey:"+key.ID)
+				ctx = internal.WithTenant(ctx, key.TenantID)
+			} else {
+				claims, err := verifier.Verify(token)
+				if err != nil || internal.ValidateTenantID(claims.Tenant) != nil {
+					writeAuthError(w, r, internal.ErrUnauthorized)
+					return
+				}
+
+				ctx = internal.WithActor(r.Context(), claims.Subject)
+
+				if claims.Admin {
+					ctx = internal.WithAdmin(ctx)
+				}
+
+				ctx = internal.WithTenant(ctx, claims.Tenant)
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequireAdmin only lets user tokens with the admin claim through.
+func RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !internal.IsAdmin(r.Context()) {
+			writeStatus(w, r, http.StatusForbidden, "admin token required")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeAuthError(w http.ResponseWriter, r *http.Request, err error) {
+	if !errors.Is(err, internal.ErrUnauthorized) {
+		writeStatus(w, r, http.StatusInternalServerError, "error checking credentials: "+err.Error())
+		return
+	}
+
+	w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+	writeStatus(w, r, http.StatusUnauthorized, "invalid token")
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal"
+	"github.com/ObiaNzk/LTK-test-manu/internal/interval"
+	"github.com/ObiaNzk/LTK-test-manu/internal/recurrence"
+)
+
+type availabilityBody struct {
+	Resources       []string          `json:"resources"`
+	CalendarIDs     []string          `json:"calendar_ids"`
+	From            time.Time         `json:"from"`
+	To              time.Time         `json:"to"`
+	DurationMinutes int               `json:"duration_minutes"`
+	WorkingHours    *workingHoursBody `json:"working_hours,omitempty"`
+	// TimeZone is the IANA zone of the working hours and of the response. Defaults to UTC.
+	TimeZone string `json:"time_zone,omitempty"`
+	Limit    int    `json:"limit,omitempty"`
+}
+
+// workingHoursBody holds local "15:04" clock times and RRULE weekday codes,
+// e.g. {"start": "09:00", "end": "17:00", "days": ["MO", "TU"]}.
+type workingHoursBody struct {
+	Start string   `json:"start"`
+	End   string   `json:"end"`
+	Days  []string `json:"days,omitempty"`
+}
+
+func (b availabilityBody) toQuery() (internal.AvailabilityQuery, error) {
+	query := internal.AvailabilityQuery{
+		Resources:   b.Resources,
+		CalendarIDs: b.CalendarIDs,
+		From:        b.From,
+		To:          b.To,
+		Duration:    time.Duration(b.DurationMinutes) * time.Minute,
+		TimeZone:    b.TimeZone,
+		Limit:       b.Limit,
+	}
+
+	if b.WorkingHours == nil {
+		return query, nil
+	}
+
+	start, err := parseClock(b.WorkingHours.Start)
+	if err != nil {
+		return internal.AvailabilityQuery{}, err
+	}
+
+	end, err := parseClock(b.WorkingHours.End)
+	if err != nil {
+		return internal.AvailabilityQuery{}, err
+	}
+
+	query.WorkingHours = &internal.WorkingHours{Start: start, End: end}
+
+	for _, code := range b.WorkingHours.Days {
+		day, ok := recurrence.ParseWeekday(strings.ToUpper(code))
+		if !ok {
+			return internal.AvailabilityQuery{}, fmt.Errorf("invalid weekday %q, expected MO, TU, WE, TH, FR, SA or SU", code)
+		}
+
+		query.WorkingHours.Days = append(query.WorkingHours.Days, day)
+	}
+
+	return query, nil
+}
+
+// parseClock reads "15:04" as the time since midnight. "24:00" is the end of the day.
+func parseClock(raw string) (time.Duration, error) {
+	if raw == "24:00" {
+		return 24 * time.Hour, nil
+	}
+
+	clock, err := time.Parse("15:04", raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", raw)
+	}
+
+	return time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute, nil
+}
+
+type intervalResponse struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+func newIntervalResponses(intervals []interval.Interval, loc *time.Location) []intervalResponse {
+	responses := make([]intervalResponse, 0, len(intervals))
+	for _, i := range intervals {
+		responses = append(responses, intervalResponse{Start: i.Start.In(loc), End: i.End.In(loc)})
+	}
+
+	return responses
+}
+
+// SearchAvailability finds when all the given resources and calendars are free (POST /availability/search).
+func (h *Handler) SearchAvailability(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var payload availabilityBody
+
+	if err := decodeBody(r, &payload); err != nil {
+		writeDecodeError(w, r, err)
+		return
+	}
+
+	query, err := payload.toQuery()
+	if err != nil {
+		writeStatus(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	availability, err := h.eventsService.SearchAvailability(ctx, query)
+	if err != nil {
+		writeError(w, r, err, "calendar not found", "error searching availability")
+		return
+	}
+
+	// The service already rejected unknown zones.
+	loc, _ := time.LoadLocation(query.TimeZone)
+
+	response := struct {
+		TimeZone string             `json:"time_zone"`
+		Slots    []intervalResponse `json:"slots"`
+		Free     []intervalResponse `json:"free"`
+		Busy     []intervalResponse `json:"busy"`
+	}{
+		TimeZone: loc.String(),
+		Slots:    newIntervalResponses(availability.Slots, loc),
+		Free:     newIntervalResponses(availability.Free, loc),
+		Busy:     newIntervalResponses(availability.Busy, loc),
+	}
+
+	writeJSON(w, r, http.StatusOK, response)
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal"
+	"github.com/go-chi/chi/v5"
+)
+
+//go:generate mockgen -source=calendars.go -destination=mocks/mock_calendars_service.go -package=mocks
+
+type calendarsService interface {
+	CreateCalendar(ctx context.Context, calendar internal.Calendar) (internal.Calendar, error)
+	GetCalendars(ctx context.Context) ([]internal.Calendar, error)
+	GetCalendar(ctx context.Context, id string) (internal.Calendar, error)
+	UpdateCalendar(ctx context.Context, id string, calendar internal.Calendar) (internal.Calendar, error)
+	DeleteCalendar(ctx context.Context, id string) error
+}
+
+type calendarBody struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type calendarResponse struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	CreatedBy   string    `json:"created_by,omitempty"`
+}
+
+func newCalendarResponse(calendar internal.Calendar) calendarResponse {
+	return calendarResponse{
+		ID:          calendar.ID,
+		Name:        calendar.Name,
+		Description: calendar.Description,
+		CreatedAt:   calendar.CreatedAt,
+		CreatedBy:   calendar.CreatedBy,
+	}
+}
+
+// CreateCalendar adds a calendar (POST /calendars).
+func (h *Handler) CreateCalendar(w http.ResponseWriter, r *http.Request) {
+	var payload calendarBody
+
+	if err := decodeBody(r, &payload); err != nil {
+		writeDecodeError(w, r, err)
+		return
+	}
+
+	calendar, err := h.calendarsService.CreateCalendar(r.Context(), internal.Calendar{Name: payload.Name, Description: payload.Description})
+	if err != nil {
+		writeCalendarError(w, r, err, "error creating calendar")
+		return
+	}
+
+	writeJSON(w, r, http.StatusCreated, newCalendarResponse(calendar))
+}
+
+// GetCalendars lists the calendars the caller may view (GET /calendars).
+func (h *Handler) GetCalendars(w http.ResponseWriter, r *http.Request) {
+	calendars, err := h.calendarsService.GetCalendars(r.Context())
+	if err != nil {
+		writeCalendarError(w, r, err, "error getting calendars")
+		return
+	}
+
+	response := struct {
+		Calendars []calendarResponse `json:"calendars"`
+	}{
+		Calendars: make([]calendarResponse, 0, len(calendars)),
+	}
+
+	for _, calendar := range calendars {
+		response.Calendars = append(response.Calendars, newCalendarResponse(calendar))
+	}
+
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+// GetCalendar returns one calendar (GET /calendars/{cid}).
+func (h *Handler) GetCalendar(w http.ResponseWriter, r *http.Request) {
+	calendar, err := h.calendarsService.GetCalendar(r.Context(), chi.URLParam(r, "cid"))
+	if err != nil {
+		writeCalendarError(w, r, err, "error getting calendar")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, newCalendarResponse(calendar))
+}
+
+// UpdateCalendar replaces the name and description of a calendar (PUT /calendars/{cid}).
+func (h *Handler) UpdateCalendar(w http.ResponseWriter, r *http.Request) {
+	var payload calendarBody
+
+	if err := decodeBody(r, &payload); err != nil {
+		writeDecodeError(w, r, err)
+		return
+	}
+
+	calendar, err := h.calendarsService.UpdateCalendar(r.Context(), chi.URLParam(r, "cid"),
+		internal.Calendar{Name: payload.Name, Description: payload.Description})
+	if err != nil {
+		writeCalendarError(w, r, err, "error updating calendar")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, newCalendarResponse(calendar))
+}
+
+// DeleteCalendar removes an empty calendar (DELETE /calendars/{cid}).
+func (h *Handler) DeleteCalendar(w http.ResponseWriter, r *http.Request) {
+	if err := h.calendarsService.DeleteCalendar(r.Context(), chi.URLParam(r, "cid")); err != nil {
+		writeCalendarError(w, r, err, "error deleting calendar")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeCalendarError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	writeError(w, r, err, "calendar not found", message)
+}
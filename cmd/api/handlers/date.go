@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// date is a calendar date of an all-day event, written as "2006-01-02". It
+// holds midnight UTC, the way the service stores floating dates.
+type date struct {
+	time.Time
+}
+
+func newDate(t time.Time) *date {
+	return &date{Time: t.UTC()}
+}
+
+func (d date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Format(time.DateOnly))
+}
+
+func (d *date) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	parsed, err := time.Parse(time.DateOnly, raw)
+	if err != nil {
+		return fmt.Errorf("invalid date %q, expected YYYY-MM-DD", raw)
+	}
+
+	d.Time = parsed
+
+	return nil
+}
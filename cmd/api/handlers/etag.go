@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// eventETag is the strong entity tag of an event at version.
+func eventETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// notModified tells whether If-None-Match names etag, comparing weakly as
+// RFC 9110 asks for it.
+func notModified(r *http.Request, etag string) bool {
+	header := r.Header.Get("If-None-Match")
+	if header == "" {
+		return false
+	}
+
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ifMatchVersion reads the version an update expects from If-Match, which is
+// required. "*" matches any version and reads as 0. A missing header gets 428
+// and a weak or unknown tag, which can never match, 412; false is returned then.
+func ifMatchVersion(w http.ResponseWriter, r *http.Request) (int, bool) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+
+	switch {
+	case header == "":
+		writeStatus(w, r, http.StatusPreconditionRequired, "updates need an If-Match header with the ETag of the event")
+		return 0, false
+	case header == "*":
+		return 0, true
+	case strings.Contains(header, ","):
+		writeStatus(w, r, http.StatusBadRequest, "If-Match should name one ETag")
+		return 0, false
+	}
+
+	version, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(header, `"`), `"`))
+	if err != nil || version <= 0 || !strings.HasPrefix(header, `"`) || !strings.HasSuffix(header, `"`) {
+		writeStatus(w, r, http.StatusPreconditionFailed, "If-Match does not name a version of the event")
+		return 0, false
+	}
+
+	return version, true
+}
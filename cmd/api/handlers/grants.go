@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal"
+	"github.com/go-chi/chi/v5"
+)
+
+//go:generate mockgen -source=grants.go -destination=mocks/mock_grants_service.go -package=mocks
+
+type grantsService interface {
+	GetGrants(ctx context.Context, objectType internal.ObjectType, objectID string) ([]internal.Grant, error)
+	SetGrant(ctx context.Context, grant internal.Grant) (internal.Grant, error)
+	DeleteGrant(ctx context.Context, objectType internal.ObjectType, objectID, principal string) error
+}
+
+type grantBody struct {
+	Role internal.AccessRole `json:"role"`
+}
+
+type grantResponse struct {
+	Principal string              `json:"principal"`
+	Role      internal.AccessRole `json:"role"`
+	GrantedBy string              `json:"granted_by,omitempty"`
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+func newGrantResponse(grant internal.Grant) grantResponse {
+	return grantResponse{
+		Principal: grant.Principal,
+		Role:      grant.Role,
+		GrantedBy: grant.GrantedBy,
+		CreatedAt: grant.CreatedAt,
+	}
+}
+
+// GetCalendarGrants lists who holds a role on a calendar (GET /calendars/{cid}/grants).
+func (h *Handler) GetCalendarGrants(w http.ResponseWriter, r *http.Request) {
+	h.getGrants(w, r, internal.ObjectCalendar, chi.URLParam(r, "cid"))
+}
+
+// SetCalendarGrant gives a principal a role on a calendar (PUT /calendars/{cid}/grants/{principal}).
+func (h *Handler) SetCalendarGrant(w http.ResponseWriter, r *http.Request) {
+	h.setGrant(w, r, internal.ObjectCalendar, chi.URLParam(r, "cid"))
+}
+
+// DeleteCalendarGrant takes a role on a calendar away (DELETE /calendars/{cid}/grants/{principal}).
+func (h *Handler) DeleteCalendarGrant(w http.ResponseWriter, r *http.Request) {
+	h.deleteGrant(w, r, internal.ObjectCalendar, chi.URLParam(r, "cid"))
+}
+
+// GetEventGrants lists who holds a role on an event (GET /events/{id}/grants).
+func (h *Handler) GetEventGrants(w http.ResponseWriter, r *http.Request) {
+	h.getGrants(w, r, internal.ObjectEvent, chi.URLParam(r, "id"))
+}
+
+// SetEventGrant gives a principal a role on an event (PUT /events/{id}/grants/{principal}).
+func (h *Handler) SetEventGrant(w http.ResponseWriter, r *http.Request) {
+	h.setGrant(w, r, internal.ObjectEvent, chi.URLParam(r, "id"))
+}
+
+// DeleteEventGrant takes a role on an event away (DELETE /events/{id}/grants/{principal}).
+func (h *Handler) DeleteEventGrant(w http.ResponseWriter, r *http.Request) {
+	h.deleteGrant(w, r, internal.ObjectEvent, chi.URLParam(r, "id"))
+}
+
+func (h *Handler) getGrants(w http.ResponseWriter, r *http.Request, objectType internal.ObjectType, objectID string) {
+	grants, err := h.grantsService.GetGrants(r.Context(), objectType, objectID)
+	if err != nil {
+		writeGrantError(w, r, err, "error getting grants")
+		return
+	}
+
+	response := struct {
+		Grants []grantResponse `json:"grants"`
+	}{
+		Grants: make([]grantResponse, 0, len(grants)),
+	}
+
+	for _, grant := range grants {
+		response.Grants = append(response.Grants, newGrantResponse(grant))
+	}
+
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+func (h *Handler) setGrant(w http.ResponseWriter, r *http.Request, objectType internal.ObjectType, objectID string) {
+	principal, err := principalParam(r)
+	if err != nil {
+		writeStatus(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var payload grantBody
+
+	if err := decodeBody(r, &payload); err != nil {
+		writeDecodeError(w, r, err)
+		return
+	}
+
+	grant, err := h.grantsService.SetGrant(r.Context(), internal.Grant{
+		ObjectType: objectType,
+		ObjectID:   objectID,
+		Principal:  principal,
+		Role:       payload.Role,
+	})
+	if err != nil {
+		writeGrantError(w, r, err, "error setting grant")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, newGrantResponse(grant))
+}
+
+func (h *Handler) deleteGrant(w http.ResponseWriter, r *http.Request, objectType internal.ObjectType, objectID string) {
+	principal, err := principalParam(r)
+	if err != nil {
+		writeStatus(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.grantsService.DeleteGrant(r.Context(), objectType, objectID, principal); err != nil {
+		writeGrantError(w, r, err, "error deleting grant")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeGrantError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	writeError(w, r, err, "calendar, event or grant not found", message)
+}
+
+// principalParam reads the principal from the path, where "|" and ":" may be escaped.
+func principalParam(r *http.Request) (string, error) {
+	principal, err := url.PathUnescape(chi.URLParam(r, "principal"))
+	if err != nil {
+		return "", errors.New("invalid principal in path")
+	}
+
+	return principal, nil
+}
@@ -5,7 +5,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/ObiaNzk/LTK-test-manu/internal"
@@ -16,187 +19,679 @@ import (
 
 type eventsService interface {
 	CreateEvent(ctx context.Context, event internal.CreateEventRequest) (internal.CreateEventResponse, error)
-	GetEventByID(ctx context.Context, id string) (internal.CreateEventResponse, error)
-	GetEvents(ctx context.Context) ([]internal.CreateEventResponse, error)
+	GetEventByID(ctx context.Context, id string, includeDeleted bool) (internal.CreateEventResponse, error)
+	GetEvents(ctx context.Context, filter internal.EventFilter, page internal.PageRequest) (internal.EventsPage, error)
+	UpdateEvent(ctx context.Context, id string, version int, event internal.CreateEventRequest) (internal.CreateEventResponse, error)
+	DeleteEvent(ctx context.Context, id string) error
+	RestoreEvent(ctx context.Context, id string) (internal.CreateEventResponse, error)
+	GetOccurrences(ctx context.Context, id string, from, to time.Time) ([]internal.CreateEventResponse, error)
+	GetOccurrenceExceptions(ctx context.Context, id string) ([]internal.OccurrenceException, error)
+	UpdateOccurrence(ctx context.Context, exception internal.OccurrenceException) (internal.CreateEventResponse, error)
+	CancelOccurrence(ctx context.Context, seriesID string, recurrenceID time.Time) error
+	SplitSeries(ctx context.Context, id string, recurrenceID time.Time, changes internal.CreateEventRequest) (internal.CreateEventResponse, error)
+	ImportEvents(ctx context.Context, events []internal.ImportedEvent) ([]internal.ImportResult, error)
+	GetEventHistory(ctx context.Context, id string) ([]internal.EventRevision, error)
+	RevertEvent(ctx context.Context, id string, revision, version int) (internal.CreateEventResponse, error)
+	SearchAvailability(ctx context.Context, query internal.AvailabilityQuery) (internal.Availability, error)
+}
+
+type eventBody struct {
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	StartTime   time.Time `json:"start_time"`
+	EndTime     time.Time `json:"end_time"`
+	// RRule makes the event a series whose first occurrence is StartTime-EndTime.
+	RRule   string      `json:"rrule,omitempty"`
+	ExDates []time.Time `json:"exdates,omitempty"`
+	RDates  []time.Time `json:"rdates,omitempty"`
+	// TimeZone is the IANA zone of the event, e.g. "Europe/Madrid". Defaults to UTC.
+	TimeZone string `json:"time_zone,omitempty"`
+	// StartDate and EndDate make an all-day event, instead of StartTime and EndTime.
+	// EndDate is exclusive.
+	StartDate *date `json:"start_date,omitempty"`
+	EndDate   *date `json:"end_date,omitempty"`
+	// Resource is what the event books, e.g. "room-4b".
+	Resource string `json:"resource,omitempty"`
+	// Capacity caps the accepted attendees, the rest are waitlisted. Omitted means no limit.
+	Capacity int `json:"capacity,omitempty"`
+	// CalendarID moves the event to another calendar. Omitted means the default
+	// calendar on creation and the current one on updates.
+	CalendarID string `json:"calendar_id,omitempty"`
+}
+
+func newEventBody(event internal.CreateEventResponse) eventBody {
+	body := eventBody{
+		Title:       event.Title,
+		Description: event.Description,
+		StartTime:   event.StartTime,
+		EndTime:     event.EndTime,
+		RRule:       event.RRule,
+		ExDates:     event.ExDates,
+		RDates:      event.RDates,
+		TimeZone:    event.TimeZone,
+		Resource:    event.Resource,
+		Capacity:    event.Capacity,
+		CalendarID:  event.CalendarID,
+	}
+
+	if event.AllDay {
+		body.StartTime, body.EndTime = time.Time{}, time.Time{}
+		body.StartDate, body.EndDate = newDate(event.StartTime), newDate(event.EndTime)
+	}
+
+	return body
+}
+
+func (b eventBody) toRequest() (internal.CreateEventRequest, error) {
+	request := internal.CreateEventRequest{
+		Title:       b.Title,
+		Description: b.Description,
+		StartTime:   b.StartTime,
+		EndTime:     b.EndTime,
+		RRule:       b.RRule,
+		ExDates:     b.ExDates,
+		RDates:      b.RDates,
+		TimeZone:    b.TimeZone,
+		Resource:    b.Resource,
+		Capacity:    b.Capacity,
+		CalendarID:  b.CalendarID,
+	}
+
+	if b.StartDate == nil && b.EndDate == nil {
+		return request, nil
+	}
+
+	if !b.StartTime.IsZero() || !b.EndTime.IsZero() {
+		return internal.CreateEventRequest{}, errors.New("use either start_time and end_time or start_date and end_date")
+	}
+
+	request.AllDay = true
+
+	if b.StartDate != nil {
+		request.StartTime = b.StartDate.Time
+	}
+
+	if b.EndDate != nil {
+		request.EndTime = b.EndDate.Time
+	}
+
+	return request, nil
+}
+
+// occurrenceBody edits one occurrence. Omitted fields keep the series value.
+type occurrenceBody struct {
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	StartTime   time.Time `json:"start_time"`
+	EndTime     time.Time `json:"end_time"`
+}
+
+// eventResponse renders all-day events with start_date and end_date instead of
+// start_time and end_time.
+type eventResponse struct {
+	ID           string      `json:"id"`
+	Title        string      `json:"title"`
+	Description  string      `json:"description"`
+	StartTime    *time.Time  `json:"start_time,omitempty"`
+	EndTime      *time.Time  `json:"end_time,omitempty"`
+	StartDate    *date       `json:"start_date,omitempty"`
+	EndDate      *date       `json:"end_date,omitempty"`
+	CreatedAt    time.Time   `json:"created_at"`
+	DeletedAt    *time.Time  `json:"deleted_at,omitempty"`
+	RRule        string      `json:"rrule,omitempty"`
+	ExDates      []time.Time `json:"exdates,omitempty"`
+	RDates       []time.Time `json:"rdates,omitempty"`
+	RecurrenceID *time.Time  `json:"recurrence_id,omitempty"`
+	TimeZone     string      `json:"time_zone,omitempty"`
+	Resource     string      `json:"resource,omitempty"`
+	Capacity     int         `json:"capacity,omitempty"`
+	CalendarID   string      `json:"calendar_id,omitempty"`
+	CreatedBy    string      `json:"created_by,omitempty"`
+	Version      int         `json:"version,omitempty"`
+	// Attendees counts attendees by status. Only GET /events/{id} sets it.
+	Attendees map[internal.AttendeeStatus]int `json:"attendees,omitempty"`
+}
+
+// newEventResponse renders the event in loc, or in its own zone when loc is nil.
+func newEventResponse(event internal.CreateEventResponse, loc *time.Location) eventResponse {
+	if loc != nil {
+		event = event.In(loc)
+	}
+
+	response := eventResponse{
+		ID:           event.ID,
+		Title:        event.Title,
+		Description:  event.Description,
+		CreatedAt:    event.CreatedAt,
+		DeletedAt:    event.DeletedAt,
+		RRule:        event.RRule,
+		ExDates:      event.ExDates,
+		RDates:       event.RDates,
+		RecurrenceID: event.RecurrenceID,
+		TimeZone:     event.TimeZone,
+		Resource:     event.Resource,
+		Capacity:     event.Capacity,
+		CalendarID:   event.CalendarID,
+		CreatedBy:    event.CreatedBy,
+		Version:      event.Version,
+	}
+
+	if event.AllDay {
+		response.StartDate, response.EndDate = newDate(event.StartTime), newDate(event.EndTime)
+	} else {
+		response.StartTime, response.EndTime = &event.StartTime, &event.EndTime
+	}
+
+	return response
 }
 
 type Handler struct {
-	eventsService eventsService
+	eventsService    eventsService
+	attendeesService attendeesService
+	calendarsService calendarsService
+	apiKeysService   apiKeysService
+	grantsService    grantsService
 }
 
-func NewHandler(service eventsService) *Handler {
+func NewHandler(service eventsService, attendees attendeesService, calendars calendarsService, apiKeys apiKeysService,
+	grants grantsService) *Handler {
 	return &Handler{
-		eventsService: service,
+		eventsService:    service,
+		attendeesService: attendees,
+		calendarsService: calendars,
+		apiKeysService:   apiKeys,
+		grantsService:    grants,
 	}
 }
 
+// CreateEvent adds an event (POST /events), in the calendar of the path when
+// nested (POST /calendars/{cid}/events).
 func (h *Handler) CreateEvent(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	type body struct {
-		Title       string    `json:"title" validate:"required"`
-		Description string    `json:"description"`
-		StartTime   time.Time `json:"start_time" validate:"required"`
-		EndTime     time.Time `json:"end_time" validate:"required"`
-	}
+	defer r.Body.Close()
 
-	var payload body
+	var payload eventBody
 
 	if err := decodeBody(r, &payload); err != nil {
-		http.Error(w, fmt.Sprintf("Invalid JSON format: %s", err.Error()), http.StatusBadRequest)
+		writeDecodeError(w, r, err)
+		return
+	}
 
+	request, err := payload.toRequest()
+	if err != nil {
+		writeStatus(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	if payload.Title == "" {
-		http.Error(w, "empty title", http.StatusBadRequest)
+	if calendarID := chi.URLParam(r, "cid"); calendarID != "" {
+		if request.CalendarID != "" && request.CalendarID != calendarID {
+			writeFieldError(w, r, "calendar_id", "calendar_id does not match the calendar of the path")
+			return
+		}
+
+		request.CalendarID = calendarID
 	}
 
-	if payload.StartTime.IsZero() || payload.EndTime.IsZero() {
-		http.Error(w, "start time and end time should be set", http.StatusBadRequest)
+	result, err := h.eventsService.CreateEvent(ctx, request)
+	if err != nil {
+		writeError(w, r, err, "calendar not found", "Error creating event")
+		return
 	}
 
-	if len(payload.Title) <= 100 {
-		http.Error(w, "title should have more than 100 words", http.StatusBadRequest)
+	writeJSON(w, r, http.StatusCreated, newEventResponse(result, renderLocation(r)))
+}
+
+func (h *Handler) GetEventByID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	if id == "" {
+		writeStatus(w, r, http.StatusBadRequest, "empty event")
+		return
 	}
 
-	if payload.StartTime.After(payload.EndTime) {
-		http.Error(w, "start time should be before end time", http.StatusBadRequest)
+	includeDeleted, err := includeDeletedParam(r)
+	if err != nil {
+		writeParamError(w, r, err)
+		return
 	}
 
-	defer r.Body.Close()
+	event, err := h.eventsService.GetEventByID(ctx, id, includeDeleted)
+	if err != nil {
+		writeError(w, r, err, "event not found", "error getting event")
+		return
+	}
+
+	etag := eventETag(event.Version)
+	w.Header().Set("ETag", etag)
 
-	event := internal.CreateEventRequest{
-		Title:       payload.Title,
-		Description: payload.Description,
-		StartTime:   payload.StartTime,
-		EndTime:     payload.EndTime,
+	if notModified(r, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
 	}
 
-	result, err := h.eventsService.CreateEvent(ctx, event)
+	counts, err := h.attendeesService.CountAttendees(ctx, id)
+	if err != nil {
+		writeError(w, r, err, "event not found", "error counting attendees")
+		return
+	}
 
+	response := newEventResponse(event, renderLocation(r))
+	response.Attendees = counts
+
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+// GetEvents lists events across calendars (GET /events), or of one calendar
+// when nested (GET /calendars/{cid}/events).
+func (h *Handler) GetEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter, err := parseEventFilter(r)
 	if err != nil {
-		if errors.Is(err, internal.ErrPepito) {
-			http.Error(w, "pepito", http.StatusConflict)
+		writeParamError(w, r, err)
+		return
+	}
+
+	page := internal.PageRequest{Cursor: r.URL.Query().Get("cursor")}
+
+	if value := r.URL.Query().Get("limit"); value != "" {
+		page.Limit, err = strconv.Atoi(value)
+		if err != nil || page.Limit <= 0 {
+			writeFieldError(w, r, "limit", "limit should be a positive integer")
+			return
 		}
+	}
 
-		message := fmt.Sprintf("Error creating event: %s", err.Error())
-		http.Error(w, message, http.StatusInternalServerError)
+	result, err := h.eventsService.GetEvents(ctx, filter, page)
+	if err != nil {
+		writeError(w, r, err, "calendar not found", "error getting events")
+		return
 	}
 
 	response := struct {
-		ID          string    `json:"id" validate:"required"`
-		Title       string    `json:"title" validate:"required"`
-		Description string    `json:"description"`
-		StartTime   time.Time `json:"start_time" validate:"required"`
-		EndTime     time.Time `json:"end_time" validate:"required"`
-		CreatedAt   time.Time `json:"created_at" validate:"required"`
+		Events     []eventResponse `json:"events"`
+		NextCursor string          `json:"next_cursor,omitempty"`
 	}{
-		ID:          result.ID,
-		Title:       result.Title,
-		Description: result.Description,
-		StartTime:   result.StartTime,
-		EndTime:     result.EndTime,
-		CreatedAt:   result.CreatedAt,
+		Events:     make([]eventResponse, 0, len(result.Events)),
+		NextCursor: result.NextCursor,
+	}
+
+	loc := renderLocation(r)
+
+	for _, event := range result.Events {
+		response.Events = append(response.Events, newEventResponse(event, loc))
 	}
 
-	jsonResult, err := json.Marshal(response)
+	if result.NextCursor != "" {
+		w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, nextPageURL(r, result.NextCursor)))
+	}
+
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+// UpdateEvent replaces every editable field of an event (PUT /events/{id}).
+// If-Match must carry the ETag of the version being replaced.
+func (h *Handler) UpdateEvent(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if id == "" {
+		writeStatus(w, r, http.StatusBadRequest, "empty event")
+		return
+	}
+
+	version, ok := ifMatchVersion(w, r)
+	if !ok {
+		return
+	}
+
+	defer r.Body.Close()
+
+	var payload eventBody
+
+	if err := decodeBody(r, &payload); err != nil {
+		writeDecodeError(w, r, err)
+		return
+	}
+
+	h.updateEvent(w, r, id, version, payload)
+}
+
+// PatchEvent applies a JSON Merge Patch (RFC 7396) to an event (PATCH /events/{id}).
+// The patch is applied on top of the stored representation and the result goes
+// through the same path as a full replacement, If-Match included.
+func (h *Handler) PatchEvent(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	if id == "" {
+		writeStatus(w, r, http.StatusBadRequest, "empty event")
+		return
+	}
+
+	version, ok := ifMatchVersion(w, r)
+	if !ok {
+		return
+	}
+
+	defer r.Body.Close()
+
+	patch, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeStatus(w, r, http.StatusBadRequest, fmt.Sprintf("reading body: %s", err.Error()))
+		return
+	}
+
+	current, err := h.eventsService.GetEventByID(ctx, id, false)
 	if err != nil {
-		http.Error(w, "creating json response", http.StatusInternalServerError)
+		writeError(w, r, err, "event not found", "error getting event")
+		return
+	}
 
+	// A patch of another version would merge into the wrong document.
+	if version != 0 && version != current.Version {
+		writeStatus(w, r, http.StatusPreconditionFailed, fmt.Sprintf("event is at version %d, not %d", current.Version, version))
+		return
+	}
+
+	original, err := json.Marshal(newEventBody(current))
+	if err != nil {
+		writeStatus(w, r, http.StatusInternalServerError, "error creating json document")
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	_, _ = w.Write(jsonResult)
+	patched, err := mergePatch(original, patch)
+	if err != nil {
+		writeDecodeError(w, r, err)
+		return
+	}
+
+	var payload eventBody
+
+	if err := json.Unmarshal(patched, &payload); err != nil {
+		writeDecodeError(w, r, err)
+		return
+	}
+
+	h.updateEvent(w, r, id, version, payload)
 }
 
-func (h *Handler) GetEventByID(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) updateEvent(w http.ResponseWriter, r *http.Request, id string, version int, payload eventBody) {
+	request, err := payload.toRequest()
+	if err != nil {
+		writeStatus(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := h.eventsService.UpdateEvent(r.Context(), id, version, request)
+	if err != nil {
+		writeError(w, r, err, "event not found", "error updating event")
+		return
+	}
+
+	w.Header().Set("ETag", eventETag(result.Version))
+
+	writeJSON(w, r, http.StatusOK, newEventResponse(result, renderLocation(r)))
+}
+
+// DeleteEvent soft deletes an event (DELETE /events/{id}).
+func (h *Handler) DeleteEvent(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	id := chi.URLParam(r, "id")
 
 	if id == "" {
-		http.Error(w, "empty event", http.StatusBadRequest)
+		writeStatus(w, r, http.StatusBadRequest, "empty event")
 		return
 	}
 
-	event, err := h.eventsService.GetEventByID(ctx, id)
+	if err := h.eventsService.DeleteEvent(ctx, id); err != nil {
+		writeError(w, r, err, "event not found", "error deleting event")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RestoreEvent undoes a soft delete (POST /events/{id}/restore).
+func (h *Handler) RestoreEvent(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	if id == "" {
+		writeStatus(w, r, http.StatusBadRequest, "empty event")
+		return
+	}
+
+	event, err := h.eventsService.RestoreEvent(ctx, id)
 	if err != nil {
-		if errors.Is(err, internal.ErrNotFound) {
-			http.Error(w, "event not found", http.StatusNotFound)
-			return
-		}
+		writeError(w, r, err, "deleted event not found", "error restoring event")
+		return
+	}
 
-		http.Error(w, fmt.Sprintf("error getting event: %s", err.Error()), http.StatusInternalServerError)
+	writeJSON(w, r, http.StatusOK, newEventResponse(event, renderLocation(r)))
+}
+
+// GetOccurrences lists the occurrences of an event inside a window (GET /events/{id}/occurrences).
+func (h *Handler) GetOccurrences(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	if id == "" {
+		writeStatus(w, r, http.StatusBadRequest, "empty event")
+		return
+	}
+
+	filter, err := parseEventFilter(r)
+	if err != nil {
+		writeParamError(w, r, err)
+		return
+	}
+
+	occurrences, err := h.eventsService.GetOccurrences(ctx, id, filter.From, filter.To)
+	if err != nil {
+		writeOccurrenceError(w, r, err, "error getting occurrences")
 		return
 	}
 
 	response := struct {
-		ID          string    `json:"id"`
-		Title       string    `json:"title"`
-		Description string    `json:"description"`
-		StartTime   time.Time `json:"start_time"`
-		EndTime     time.Time `json:"end_time"`
-		CreatedAt   time.Time `json:"created_at"`
+		Occurrences []eventResponse `json:"occurrences"`
 	}{
-		ID:          event.ID,
-		Title:       event.Title,
-		Description: event.Description,
-		StartTime:   event.StartTime,
-		EndTime:     event.EndTime,
-		CreatedAt:   event.CreatedAt,
+		Occurrences: make([]eventResponse, 0, len(occurrences)),
+	}
+
+	loc := renderLocation(r)
+
+	for _, occurrence := range occurrences {
+		response.Occurrences = append(response.Occurrences, newEventResponse(occurrence, loc))
+	}
+
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+// UpdateOccurrence moves or edits a single occurrence (PUT /events/{id}/occurrences/{recurrence_id}).
+func (h *Handler) UpdateOccurrence(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	recurrenceID, err := recurrenceIDParam(r)
+	if err != nil {
+		writeParamError(w, r, err)
+		return
+	}
+
+	defer r.Body.Close()
+
+	var payload occurrenceBody
+
+	if err := decodeBody(r, &payload); err != nil {
+		writeDecodeError(w, r, err)
+		return
 	}
 
-	jsonResult, err := json.Marshal(response)
+	occurrence, err := h.eventsService.UpdateOccurrence(ctx, internal.OccurrenceException{
+		SeriesID:     id,
+		RecurrenceID: recurrenceID,
+		Title:        payload.Title,
+		Description:  payload.Description,
+		StartTime:    payload.StartTime,
+		EndTime:      payload.EndTime,
+	})
 	if err != nil {
-		http.Error(w, "error creating json response", http.StatusInternalServerError)
+		writeOccurrenceError(w, r, err, "error updating occurrence")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write(jsonResult)
+	writeJSON(w, r, http.StatusOK, newEventResponse(occurrence, renderLocation(r)))
 }
 
-func (h *Handler) GetEvents(w http.ResponseWriter, r *http.Request) {
+// CancelOccurrence removes a single occurrence (DELETE /events/{id}/occurrences/{recurrence_id}).
+func (h *Handler) CancelOccurrence(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	id := chi.URLParam(r, "id")
 
-	events, err := h.eventsService.GetEvents(ctx)
+	recurrenceID, err := recurrenceIDParam(r)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("error getting events: %s", err.Error()), http.StatusInternalServerError)
+		writeParamError(w, r, err)
 		return
 	}
 
-	type eventResponse struct {
-		ID          string    `json:"id"`
-		Title       string    `json:"title"`
-		Description string    `json:"description"`
-		StartTime   time.Time `json:"start_time"`
-		EndTime     time.Time `json:"end_time"`
-		CreatedAt   time.Time `json:"created_at"`
+	if err := h.eventsService.CancelOccurrence(ctx, id, recurrenceID); err != nil {
+		writeOccurrenceError(w, r, err, "error cancelling occurrence")
+		return
 	}
 
-	response := make([]eventResponse, 0, len(events))
-	for _, event := range events {
-		response = append(response, eventResponse{
-			ID:          event.ID,
-			Title:       event.Title,
-			Description: event.Description,
-			StartTime:   event.StartTime,
-			EndTime:     event.EndTime,
-			CreatedAt:   event.CreatedAt,
-		})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SplitSeries changes "this and following" occurrences by starting a new series
+// at the occurrence (POST /events/{id}/occurrences/{recurrence_id}/split).
+func (h *Handler) SplitSeries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	recurrenceID, err := recurrenceIDParam(r)
+	if err != nil {
+		writeParamError(w, r, err)
+		return
 	}
 
-	jsonResult, err := json.Marshal(response)
+	defer r.Body.Close()
+
+	var payload eventBody
+
+	if err := decodeBody(r, &payload); err != nil {
+		writeDecodeError(w, r, err)
+		return
+	}
+
+	changes, err := payload.toRequest()
+	if err != nil {
+		writeStatus(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	series, err := h.eventsService.SplitSeries(ctx, id, recurrenceID, changes)
 	if err != nil {
-		http.Error(w, "error creating json response", http.StatusInternalServerError)
+		writeOccurrenceError(w, r, err, "error splitting series")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write(jsonResult)
+	writeJSON(w, r, http.StatusCreated, newEventResponse(series, renderLocation(r)))
+}
+
+func writeOccurrenceError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	writeError(w, r, err, "event or occurrence not found", message)
+}
+
+// recurrenceIDParam reads the original start of an occurrence from the path, as RFC 3339.
+func recurrenceIDParam(r *http.Request) (time.Time, error) {
+	recurrenceID, err := time.Parse(time.RFC3339, chi.URLParam(r, "recurrence_id"))
+	if err != nil {
+		return time.Time{}, &paramError{"recurrence_id", "recurrence_id should be an RFC 3339 time"}
+	}
+
+	return recurrenceID, nil
+}
+
+// nextPageURL keeps every query parameter of the current request and only swaps the cursor.
+func nextPageURL(r *http.Request, cursor string) string {
+	query := r.URL.Query()
+	query.Set("cursor", cursor)
+
+	next := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+
+	return next.String()
+}
+
+// parseEventFilter reads the listing filters from the query string. Times are RFC 3339.
+func parseEventFilter(r *http.Request) (internal.EventFilter, error) {
+	query := r.URL.Query()
+
+	includeDeleted, err := includeDeletedParam(r)
+	if err != nil {
+		return internal.EventFilter{}, err
+	}
+
+	filter := internal.EventFilter{
+		Title:          query.Get("title"),
+		Resources:      query["resource"],
+		CalendarID:     chi.URLParam(r, "cid"),
+		IncludeDeleted: includeDeleted,
+	}
+
+	times := []struct {
+		name  string
+		value *time.Time
+	}{
+		{"from", &filter.From},
+		{"to", &filter.To},
+		{"created_from", &filter.CreatedFrom},
+		{"created_to", &filter.CreatedTo},
+	}
+
+	for _, param := range times {
+		raw := query.Get(param.name)
+		if raw == "" {
+			continue
+		}
+
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return internal.EventFilter{}, &paramError{param.name, param.name + " should be an RFC 3339 time"}
+		}
+
+		*param.value = parsed
+	}
+
+	// All-day events are matched against the dates of the window in the zone of
+	// the caller.
+	if loc := renderLocation(r); loc != nil {
+		filter.From = filter.From.In(loc)
+		filter.To = filter.To.In(loc)
+	}
+
+	return filter, nil
+}
+
+// includeDeletedParam reads the ?include_deleted admin switch, which defaults to
+// false. Only admins may turn it on.
+func includeDeletedParam(r *http.Request) (bool, error) {
+	value := r.URL.Query().Get("include_deleted")
+	if value == "" {
+		return false, nil
+	}
+
+	includeDeleted, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, &paramError{"include_deleted", "include_deleted should be a boolean"}
+	}
+
+	if includeDeleted && !internal.IsAdmin(r.Context()) {
+		return false, fmt.Errorf("include_deleted requires an admin token: %w", internal.ErrForbidden)
+	}
+
+	return includeDeleted, nil
 }
 
 func decodeBody(r *http.Request, v interface{}) error {
@@ -2,7 +2,10 @@ package handlers
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -11,7 +14,13 @@ import (
 
 	"github.com/ObiaNzk/LTK-test-manu/cmd/api/handlers/mocks"
 	"github.com/ObiaNzk/LTK-test-manu/internal"
+	"github.com/ObiaNzk/LTK-test-manu/internal/auth"
+	"github.com/ObiaNzk/LTK-test-manu/internal/ical"
+	"github.com/ObiaNzk/LTK-test-manu/internal/interval"
+	"github.com/ObiaNzk/LTK-test-manu/internal/validation"
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/mock/gomock"
@@ -19,15 +28,25 @@ import (
 
 type HandlerTestSuite struct {
 	suite.Suite
-	ctrl        *gomock.Controller
-	mockService *mocks.MockeventsService
-	handler     *Handler
+	ctrl          *gomock.Controller
+	mockService   *mocks.MockeventsService
+	mockAttendees *mocks.MockattendeesService
+	mockCalendars *mocks.MockcalendarsService
+	mockAPIKeys   *mocks.MockapiKeysService
+	mockGrants    *mocks.MockgrantsService
+	mockKeys      *mocks.MockidempotencyService
+	handler       *Handler
 }
 
 func (s *HandlerTestSuite) SetupTest() {
 	s.ctrl = gomock.NewController(s.T())
 	s.mockService = mocks.NewMockeventsService(s.ctrl)
-	s.handler = NewHandler(s.mockService)
+	s.mockAttendees = mocks.NewMockattendeesService(s.ctrl)
+	s.mockCalendars = mocks.NewMockcalendarsService(s.ctrl)
+	s.mockAPIKeys = mocks.NewMockapiKeysService(s.ctrl)
+	s.mockGrants = mocks.NewMockgrantsService(s.ctrl)
+	s.mockKeys = mocks.NewMockidempotencyService(s.ctrl)
+	s.handler = NewHandler(s.mockService, s.mockAttendees, s.mockCalendars, s.mockAPIKeys, s.mockGrants)
 }
 
 func (s *HandlerTestSuite) TearDownTest() {
@@ -35,9 +54,13 @@ func (s *HandlerTestSuite) TearDownTest() {
 }
 
 func (s *HandlerTestSuite) TestNewHandler() {
-	handler := NewHandler(s.mockService)
+	handler := NewHandler(s.mockService, s.mockAttendees, s.mockCalendars, s.mockAPIKeys, s.mockGrants)
 	require.NotNil(s.T(), handler)
 	require.NotNil(s.T(), handler.eventsService)
+	require.NotNil(s.T(), handler.attendeesService)
+	require.NotNil(s.T(), handler.calendarsService)
+	require.NotNil(s.T(), handler.apiKeysService)
+	require.NotNil(s.T(), handler.grantsService)
 }
 
 func (s *HandlerTestSuite) TestGetEventByID_Success() {
@@ -52,9 +75,12 @@ func (s *HandlerTestSuite) TestGetEventByID_Success() {
 	}
 
 	s.mockService.EXPECT().
-		GetEventByID(gomock.Any(), eventID).
+		GetEventByID(gomock.Any(), eventID, false).
 		Return(expectedEvent, nil).
 		Times(1)
+	s.mockAttendees.EXPECT().
+		CountAttendees(gomock.Any(), eventID).
+		Return(map[internal.AttendeeStatus]int{internal.StatusInvited: 1, internal.StatusAccepted: 2, internal.StatusDeclined: 0, internal.StatusTentative: 0}, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/events/"+eventID, nil)
 	rctx := chi.NewRouteContext()
@@ -69,13 +95,14 @@ func (s *HandlerTestSuite) TestGetEventByID_Success() {
 	require.Equal(s.T(), "application/json", resp.Header.Get("Content-Type"))
 	require.Contains(s.T(), w.Body.String(), eventID)
 	require.Contains(s.T(), w.Body.String(), "Test Event")
+	require.Contains(s.T(), w.Body.String(), `"attendees":{"accepted":2,"declined":0,"invited":1,"tentative":0}`)
 }
 
 func (s *HandlerTestSuite) TestGetEventByID_NotFound() {
 	eventID := "nonexistent-id"
 
 	s.mockService.EXPECT().
-		GetEventByID(gomock.Any(), eventID).
+		GetEventByID(gomock.Any(), eventID, false).
 		Return(internal.CreateEventResponse{}, internal.ErrNotFound).
 		Times(1)
 
@@ -145,113 +172,224 @@ func (s *HandlerTestSuite) TestCreateEvent_Success() {
 	require.Contains(s.T(), w.Body.String(), longTitle)
 }
 
-func (s *HandlerTestSuite) TestCreateEvent_InvalidJSON() {
-	reqBody := `{"title": invalid json}`
-	req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(reqBody))
+func (s *HandlerTestSuite) TestCreateEvent_Recurring() {
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	longTitle := strings.Repeat("a", 101)
+
+	expectedRequest := internal.CreateEventRequest{
+		Title:       longTitle,
+		Description: "Standup",
+		StartTime:   start,
+		EndTime:     start.Add(15 * time.Minute),
+		RRule:       "FREQ=WEEKLY;BYDAY=MO,WE,FR",
+		ExDates:     []time.Time{start.AddDate(0, 0, 2)},
+	}
+
+	s.mockService.EXPECT().
+		CreateEvent(gomock.Any(), expectedRequest).
+		Return(internal.CreateEventResponse{
+			ID:          "123e4567-e89b-12d3-a456-426614174000",
+			Title:       longTitle,
+			Description: "Standup",
+			StartTime:   start,
+			EndTime:     start.Add(15 * time.Minute),
+			RRule:       expectedRequest.RRule,
+			ExDates:     expectedRequest.ExDates,
+		}, nil).
+		Times(1)
+
+	body := `{"title": "` + longTitle + `", "description": "Standup", "start_time": "2025-12-01T09:00:00Z", "end_time": "2025-12-01T09:15:00Z",
+		"rrule": "FREQ=WEEKLY;BYDAY=MO,WE,FR", "exdates": ["2025-12-03T09:00:00Z"]}`
+	req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
 	s.handler.CreateEvent(w, req)
 
 	resp := w.Result()
-	require.Equal(s.T(), http.StatusBadRequest, resp.StatusCode)
-	require.Contains(s.T(), w.Body.String(), "Invalid JSON format")
+	require.Equal(s.T(), http.StatusCreated, resp.StatusCode)
+	require.Contains(s.T(), w.Body.String(), `"rrule":"FREQ=WEEKLY;BYDAY=MO,WE,FR"`)
+	require.Contains(s.T(), w.Body.String(), `"exdates":["2025-12-03T09:00:00Z"]`)
 }
 
-func (s *HandlerTestSuite) TestCreateEvent_EmptyTitle() {
-	now := time.Now()
-	requestBody := map[string]interface{}{
-		"title":       "",
-		"description": "Test Description",
-		"start_time":  now.Format(time.RFC3339),
-		"end_time":    now.Add(time.Hour).Format(time.RFC3339),
+func (s *HandlerTestSuite) TestCreateEvent_Capacity() {
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	longTitle := strings.Repeat("a", 101)
+
+	expectedRequest := internal.CreateEventRequest{
+		Title:       longTitle,
+		Description: "Workshop",
+		StartTime:   start,
+		EndTime:     start.Add(2 * time.Hour),
+		Capacity:    20,
 	}
 
 	s.mockService.EXPECT().
-		CreateEvent(gomock.Any(), gomock.Any()).
-		Return(internal.CreateEventResponse{}, nil).
+		CreateEvent(gomock.Any(), expectedRequest).
+		Return(internal.CreateEventResponse{
+			ID:          "123e4567-e89b-12d3-a456-426614174000",
+			Title:       longTitle,
+			Description: "Workshop",
+			StartTime:   start,
+			EndTime:     start.Add(2 * time.Hour),
+			Capacity:    20,
+		}, nil).
 		Times(1)
 
-	jsonBody, _ := json.Marshal(requestBody)
-	req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(string(jsonBody)))
+	body := `{"title": "` + longTitle + `", "description": "Workshop", "start_time": "2025-12-01T09:00:00Z", "end_time": "2025-12-01T11:00:00Z",
+		"capacity": 20}`
+	req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
 	s.handler.CreateEvent(w, req)
 
 	resp := w.Result()
-	require.Equal(s.T(), http.StatusBadRequest, resp.StatusCode)
-	require.Contains(s.T(), w.Body.String(), "empty title")
+	require.Equal(s.T(), http.StatusCreated, resp.StatusCode)
+	require.Contains(s.T(), w.Body.String(), `"capacity":20`)
 }
 
-func (s *HandlerTestSuite) TestCreateEvent_TitleTooShort() {
-	now := time.Now()
-	requestBody := map[string]interface{}{
-		"title":       "Short",
-		"description": "Test Description",
-		"start_time":  now.Format(time.RFC3339),
-		"end_time":    now.Add(time.Hour).Format(time.RFC3339),
+func (s *HandlerTestSuite) TestCreateEvent_TimeZone() {
+	start := time.Date(2025, 12, 1, 8, 0, 0, 0, time.UTC)
+	longTitle := strings.Repeat("a", 101)
+
+	expectedRequest := internal.CreateEventRequest{
+		Title:       longTitle,
+		Description: "Standup",
+		StartTime:   start,
+		EndTime:     start.Add(15 * time.Minute),
+		TimeZone:    "Europe/Madrid",
 	}
 
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	require.NoError(s.T(), err)
+
 	s.mockService.EXPECT().
-		CreateEvent(gomock.Any(), gomock.Any()).
-		Return(internal.CreateEventResponse{}, nil).
+		CreateEvent(gomock.Any(), expectedRequest).
+		Return(internal.CreateEventResponse{
+			ID:        "123e4567-e89b-12d3-a456-426614174000",
+			Title:     longTitle,
+			StartTime: start.In(madrid),
+			EndTime:   start.Add(15 * time.Minute).In(madrid),
+			TimeZone:  "Europe/Madrid",
+		}, nil).
 		Times(1)
 
-	jsonBody, _ := json.Marshal(requestBody)
-	req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(string(jsonBody)))
+	body := `{"title": "` + longTitle + `", "description": "Standup", "start_time": "2025-12-01T08:00:00Z", "end_time": "2025-12-01T08:15:00Z",
+		"time_zone": "Europe/Madrid"}`
+	req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
 	s.handler.CreateEvent(w, req)
 
 	resp := w.Result()
-	require.Equal(s.T(), http.StatusBadRequest, resp.StatusCode)
-	require.Contains(s.T(), w.Body.String(), "title should have more than 100 words")
+	require.Equal(s.T(), http.StatusCreated, resp.StatusCode)
+	require.Contains(s.T(), w.Body.String(), `"start_time":"2025-12-01T09:00:00+01:00"`)
+	require.Contains(s.T(), w.Body.String(), `"time_zone":"Europe/Madrid"`)
 }
 
-func (s *HandlerTestSuite) TestCreateEvent_MissingStartTime() {
-	now := time.Now()
+func (s *HandlerTestSuite) TestGetEventByID_RenderTimeZone() {
+	eventID := "123e4567-e89b-12d3-a456-426614174000"
+	start := time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC)
+
+	s.mockService.EXPECT().
+		GetEventByID(gomock.Any(), eventID, false).
+		Return(internal.CreateEventResponse{ID: eventID, StartTime: start, EndTime: start.Add(time.Hour), TimeZone: "UTC"}, nil).
+		Times(2)
+	s.mockAttendees.EXPECT().CountAttendees(gomock.Any(), eventID).Return(nil, nil).Times(2)
+
+	for _, set := range []func(*http.Request){
+		func(r *http.Request) { r.URL.RawQuery = "tz=America/New_York" },
+		func(r *http.Request) { r.Header.Set("Accept-Timezone", "America/New_York") },
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/events/"+eventID, nil)
+		set(req)
+
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", eventID)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+
+		RenderTimeZone(http.HandlerFunc(s.handler.GetEventByID)).ServeHTTP(w, req)
+
+		require.Equal(s.T(), http.StatusOK, w.Code)
+		require.Contains(s.T(), w.Body.String(), `"start_time":"2025-07-01T05:00:00-04:00"`)
+		require.Contains(s.T(), w.Body.String(), `"time_zone":"UTC"`)
+	}
+}
+
+func (s *HandlerTestSuite) TestRenderTimeZone_Unknown() {
+	req := httptest.NewRequest(http.MethodGet, "/events?tz=Mars/Olympus", nil)
+	w := httptest.NewRecorder()
+
+	RenderTimeZone(http.HandlerFunc(s.handler.GetEvents)).ServeHTTP(w, req)
+
+	require.Equal(s.T(), http.StatusBadRequest, w.Code)
+	require.Contains(s.T(), s.problem(w).Detail, `unknown time zone "Mars/Olympus"`)
+}
+
+func (s *HandlerTestSuite) TestCreateEvent_AllDay() {
+	start := time.Date(2025, 12, 24, 0, 0, 0, 0, time.UTC)
 	longTitle := strings.Repeat("a", 101)
-	requestBody := map[string]interface{}{
-		"title":       longTitle,
-		"description": "Test Description",
-		"end_time":    now.Add(time.Hour).Format(time.RFC3339),
+
+	expectedRequest := internal.CreateEventRequest{
+		Title:       longTitle,
+		Description: "Holidays",
+		StartTime:   start,
+		EndTime:     start.AddDate(0, 0, 2),
+		AllDay:      true,
 	}
 
 	s.mockService.EXPECT().
-		CreateEvent(gomock.Any(), gomock.Any()).
-		Return(internal.CreateEventResponse{}, nil).
+		CreateEvent(gomock.Any(), expectedRequest).
+		Return(internal.CreateEventResponse{
+			ID:        "123e4567-e89b-12d3-a456-426614174000",
+			Title:     longTitle,
+			StartTime: start,
+			EndTime:   start.AddDate(0, 0, 2),
+			AllDay:    true,
+			TimeZone:  "UTC",
+		}, nil).
 		Times(1)
 
-	jsonBody, _ := json.Marshal(requestBody)
-	req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(string(jsonBody)))
+	body := `{"title": "` + longTitle + `", "description": "Holidays", "start_date": "2025-12-24", "end_date": "2025-12-26"}`
+	req := httptest.NewRequest(http.MethodPost, "/events?tz=Pacific/Auckland", strings.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
+	RenderTimeZone(http.HandlerFunc(s.handler.CreateEvent)).ServeHTTP(w, req)
+
+	require.Equal(s.T(), http.StatusCreated, w.Code)
+	require.Contains(s.T(), w.Body.String(), `"start_date":"2025-12-24","end_date":"2025-12-26"`)
+	require.NotContains(s.T(), w.Body.String(), "start_time")
+}
+
+func (s *HandlerTestSuite) TestCreateEvent_TimesAndDates() {
+	body := `{"title": "` + strings.Repeat("a", 101) + `", "description": "Holidays", "start_time": "2025-12-24T00:00:00Z", "start_date": "2025-12-24"}`
+	req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
 	s.handler.CreateEvent(w, req)
 
-	resp := w.Result()
-	require.Equal(s.T(), http.StatusBadRequest, resp.StatusCode)
-	require.Contains(s.T(), w.Body.String(), "start time and end time should be set")
+	require.Equal(s.T(), http.StatusBadRequest, w.Code)
+	require.Contains(s.T(), w.Body.String(), "use either start_time and end_time or start_date and end_date")
 }
 
-func (s *HandlerTestSuite) TestCreateEvent_MissingEndTime() {
-	now := time.Now()
-	longTitle := strings.Repeat("a", 101)
-	requestBody := map[string]interface{}{
-		"title":       longTitle,
-		"description": "Test Description",
-		"start_time":  now.Format(time.RFC3339),
-	}
+func (s *HandlerTestSuite) TestCreateEvent_InvalidDate() {
+	body := `{"title": "` + strings.Repeat("a", 101) + `", "description": "Holidays", "start_date": "24/12/2025"}`
+	req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(body))
+	w := httptest.NewRecorder()
 
-	s.mockService.EXPECT().
-		CreateEvent(gomock.Any(), gomock.Any()).
-		Return(internal.CreateEventResponse{}, nil).
-		Times(1)
+	s.handler.CreateEvent(w, req)
 
-	jsonBody, _ := json.Marshal(requestBody)
-	req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(string(jsonBody)))
+	require.Equal(s.T(), http.StatusBadRequest, w.Code)
+	require.Contains(s.T(), s.problem(w).Detail, `invalid date "24/12/2025", expected YYYY-MM-DD`)
+}
+
+func (s *HandlerTestSuite) TestCreateEvent_InvalidJSON() {
+	reqBody := `{"title": invalid json}`
+	req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(reqBody))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
@@ -259,34 +397,32 @@ func (s *HandlerTestSuite) TestCreateEvent_MissingEndTime() {
 
 	resp := w.Result()
 	require.Equal(s.T(), http.StatusBadRequest, resp.StatusCode)
-	require.Contains(s.T(), w.Body.String(), "start time and end time should be set")
+	require.Contains(s.T(), w.Body.String(), "Invalid JSON format")
 }
 
-func (s *HandlerTestSuite) TestCreateEvent_StartTimeAfterEndTime() {
-	now := time.Now()
-	longTitle := strings.Repeat("a", 101)
-	requestBody := map[string]interface{}{
-		"title":       longTitle,
-		"description": "Test Description",
-		"start_time":  now.Add(time.Hour).Format(time.RFC3339),
-		"end_time":    now.Format(time.RFC3339),
+func (s *HandlerTestSuite) TestCreateEvent_ValidationErrors() {
+	violations := validation.Errors{
+		{Field: "title", Message: "title should have at least 101 characters"},
+		{Field: "end_time", Message: "end_time should not be before start_time"},
 	}
 
 	s.mockService.EXPECT().
 		CreateEvent(gomock.Any(), gomock.Any()).
-		Return(internal.CreateEventResponse{}, nil).
-		Times(1)
+		Return(internal.CreateEventResponse{}, fmt.Errorf("%w: %w", violations, internal.ErrInput))
 
-	jsonBody, _ := json.Marshal(requestBody)
-	req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(string(jsonBody)))
-	req.Header.Set("Content-Type", "application/json")
+	body := `{"title": "Short", "description": "Standup", "start_time": "2030-01-01T11:00:00Z", "end_time": "2030-01-01T10:00:00Z"}`
+	req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(body))
 	w := httptest.NewRecorder()
 
 	s.handler.CreateEvent(w, req)
 
-	resp := w.Result()
-	require.Equal(s.T(), http.StatusBadRequest, resp.StatusCode)
-	require.Contains(s.T(), w.Body.String(), "start time should be before end time")
+	p := s.problem(w)
+	require.Equal(s.T(), http.StatusBadRequest, p.Status)
+	require.Equal(s.T(), "title should have at least 101 characters; end_time should not be before start_time", p.Detail)
+	require.Equal(s.T(), []fieldError{
+		{Field: "title", Message: "title should have at least 101 characters"},
+		{Field: "end_time", Message: "end_time should not be before start_time"},
+	}, p.Errors)
 }
 
 func (s *HandlerTestSuite) TestCreateEvent_ServiceErrorConflict() {
@@ -301,7 +437,10 @@ func (s *HandlerTestSuite) TestCreateEvent_ServiceErrorConflict() {
 
 	s.mockService.EXPECT().
 		CreateEvent(gomock.Any(), gomock.Any()).
-		Return(internal.CreateEventResponse{}, internal.ErrPepito).
+		Return(internal.CreateEventResponse{}, fmt.Errorf("creating event: %w", &internal.ConflictError{
+			Resource: "room-4b",
+			EventIDs: []string{"booking-1", "booking-2"},
+		})).
 		Times(1)
 
 	jsonBody, _ := json.Marshal(requestBody)
@@ -313,7 +452,10 @@ func (s *HandlerTestSuite) TestCreateEvent_ServiceErrorConflict() {
 
 	resp := w.Result()
 	require.Equal(s.T(), http.StatusConflict, resp.StatusCode)
-	require.Contains(s.T(), w.Body.String(), "pepito")
+	require.Equal(s.T(), "application/problem+json", resp.Header.Get("Content-Type"))
+	require.JSONEq(s.T(), `{"type": "/problems/conflict", "title": "Conflict", "status": 409,
+		"detail": "resource \"room-4b\" is already booked at that time", "resource": "room-4b",
+		"conflicting_event_ids": ["booking-1", "booking-2"]}`, w.Body.String())
 }
 
 func (s *HandlerTestSuite) TestCreateEvent_ServiceErrorGeneric() {
@@ -328,7 +470,7 @@ func (s *HandlerTestSuite) TestCreateEvent_ServiceErrorGeneric() {
 
 	s.mockService.EXPECT().
 		CreateEvent(gomock.Any(), gomock.Any()).
-		Return(internal.CreateEventResponse{}, internal.ErrInput).
+		Return(internal.CreateEventResponse{}, errors.New("connection refused")).
 		Times(1)
 
 	jsonBody, _ := json.Marshal(requestBody)
@@ -343,6 +485,69 @@ func (s *HandlerTestSuite) TestCreateEvent_ServiceErrorGeneric() {
 	require.Contains(s.T(), w.Body.String(), "Error creating event")
 }
 
+func (s *HandlerTestSuite) TestCreateEvent_InCalendar() {
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	longTitle := strings.Repeat("a", 101)
+
+	s.mockService.EXPECT().
+		CreateEvent(gomock.Any(), internal.CreateEventRequest{
+			Title:       longTitle,
+			Description: "Planning",
+			StartTime:   start,
+			EndTime:     start.Add(time.Hour),
+			CalendarID:  "work",
+		}).
+		Return(internal.CreateEventResponse{
+			ID:          "123e4567-e89b-12d3-a456-426614174000",
+			Title:       longTitle,
+			Description: "Planning",
+			StartTime:   start,
+			EndTime:     start.Add(time.Hour),
+			CalendarID:  "work",
+		}, nil)
+
+	body := `{"title": "` + longTitle + `", "description": "Planning", "start_time": "2025-12-01T09:00:00Z", "end_time": "2025-12-01T10:00:00Z"}`
+	req := routedRequest(http.MethodPost, "/calendars/work/events", body, map[string]string{"cid": "work"})
+	w := httptest.NewRecorder()
+
+	s.handler.CreateEvent(w, req)
+
+	require.Equal(s.T(), http.StatusCreated, w.Code)
+	require.Contains(s.T(), w.Body.String(), `"calendar_id":"work"`)
+}
+
+func (s *HandlerTestSuite) TestCreateEvent_CalendarMismatch() {
+	longTitle := strings.Repeat("a", 101)
+
+	body := `{"title": "` + longTitle + `", "description": "Planning", "start_time": "2025-12-01T09:00:00Z", "end_time": "2025-12-01T10:00:00Z",
+		"calendar_id": "home"}`
+	req := routedRequest(http.MethodPost, "/calendars/work/events", body, map[string]string{"cid": "work"})
+	w := httptest.NewRecorder()
+
+	s.handler.CreateEvent(w, req)
+
+	require.Equal(s.T(), http.StatusBadRequest, w.Code)
+	require.Contains(s.T(), w.Body.String(), "calendar_id does not match the calendar of the path")
+}
+
+func (s *HandlerTestSuite) TestCreateEvent_UnknownCalendar() {
+	longTitle := strings.Repeat("a", 101)
+
+	s.mockService.EXPECT().
+		CreateEvent(gomock.Any(), gomock.Any()).
+		Return(internal.CreateEventResponse{}, fmt.Errorf(`calendar "home" does not exist: %w`, internal.ErrInput))
+
+	body := `{"title": "` + longTitle + `", "description": "Planning", "start_time": "2025-12-01T09:00:00Z", "end_time": "2025-12-01T10:00:00Z",
+		"calendar_id": "home"}`
+	req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	s.handler.CreateEvent(w, req)
+
+	require.Equal(s.T(), http.StatusBadRequest, w.Code)
+	require.Contains(s.T(), s.problem(w).Detail, `calendar "home" does not exist`)
+}
+
 func (s *HandlerTestSuite) TestGetEvents_Success() {
 	now := time.Now()
 	expectedEvents := []internal.CreateEventResponse{
@@ -365,8 +570,8 @@ func (s *HandlerTestSuite) TestGetEvents_Success() {
 	}
 
 	s.mockService.EXPECT().
-		GetEvents(gomock.Any()).
-		Return(expectedEvents, nil).
+		GetEvents(gomock.Any(), internal.EventFilter{}, internal.PageRequest{}).
+		Return(internal.EventsPage{Events: expectedEvents}, nil).
 		Times(1)
 
 	req := httptest.NewRequest(http.MethodGet, "/events", nil)
@@ -385,8 +590,8 @@ func (s *HandlerTestSuite) TestGetEvents_Success() {
 
 func (s *HandlerTestSuite) TestGetEvents_EmptyList() {
 	s.mockService.EXPECT().
-		GetEvents(gomock.Any()).
-		Return([]internal.CreateEventResponse{}, nil).
+		GetEvents(gomock.Any(), internal.EventFilter{}, internal.PageRequest{}).
+		Return(internal.EventsPage{Events: []internal.CreateEventResponse{}}, nil).
 		Times(1)
 
 	req := httptest.NewRequest(http.MethodGet, "/events", nil)
@@ -397,13 +602,14 @@ func (s *HandlerTestSuite) TestGetEvents_EmptyList() {
 	resp := w.Result()
 	require.Equal(s.T(), http.StatusOK, resp.StatusCode)
 	require.Equal(s.T(), "application/json", resp.Header.Get("Content-Type"))
-	require.Equal(s.T(), "[]", w.Body.String())
+	require.Equal(s.T(), `{"events":[]}`, w.Body.String())
+	require.Empty(s.T(), resp.Header.Get("Link"))
 }
 
 func (s *HandlerTestSuite) TestGetEvents_ServiceError() {
 	s.mockService.EXPECT().
-		GetEvents(gomock.Any()).
-		Return(nil, internal.ErrInput).
+		GetEvents(gomock.Any(), internal.EventFilter{}, internal.PageRequest{}).
+		Return(internal.EventsPage{}, errors.New("database error")).
 		Times(1)
 
 	req := httptest.NewRequest(http.MethodGet, "/events", nil)
@@ -416,6 +622,2133 @@ func (s *HandlerTestSuite) TestGetEvents_ServiceError() {
 	require.Contains(s.T(), w.Body.String(), "error getting events")
 }
 
-func TestHandlerTestSuite(t *testing.T) {
-	suite.Run(t, new(HandlerTestSuite))
+func (s *HandlerTestSuite) TestUpdateEvent_Success() {
+	eventID := "123e4567-e89b-12d3-a456-426614174000"
+	now := time.Now().UTC().Truncate(time.Second)
+	longTitle := strings.Repeat("a", 101)
+
+	requestBody := map[string]interface{}{
+		"title":       longTitle,
+		"description": "Updated Description",
+		"start_time":  now.Format(time.RFC3339),
+		"end_time":    now.Add(time.Hour).Format(time.RFC3339),
+	}
+
+	expectedRequest := internal.CreateEventRequest{
+		Title:       longTitle,
+		Description: "Updated Description",
+		StartTime:   now,
+		EndTime:     now.Add(time.Hour),
+	}
+
+	s.mockService.EXPECT().
+		UpdateEvent(gomock.Any(), eventID, 3, expectedRequest).
+		Return(internal.CreateEventResponse{
+			ID:          eventID,
+			Title:       longTitle,
+			Description: "Updated Description",
+			StartTime:   now,
+			EndTime:     now.Add(time.Hour),
+			CreatedAt:   now,
+		}, nil).
+		Times(1)
+
+	jsonBody, _ := json.Marshal(requestBody)
+	req := httptest.NewRequest(http.MethodPut, "/events/"+eventID, strings.NewReader(string(jsonBody)))
+	req.Header.Set("If-Match", `"3"`)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", eventID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	s.handler.UpdateEvent(w, req)
+
+	resp := w.Result()
+	require.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	require.Equal(s.T(), "application/json", resp.Header.Get("Content-Type"))
+	require.Contains(s.T(), w.Body.String(), "Updated Description")
+}
+
+func (s *HandlerTestSuite) TestUpdateEvent_InvalidJSON() {
+	eventID := "123e4567-e89b-12d3-a456-426614174000"
+
+	req := httptest.NewRequest(http.MethodPut, "/events/"+eventID, strings.NewReader(`{"title": invalid json}`))
+	req.Header.Set("If-Match", `"3"`)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", eventID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	s.handler.UpdateEvent(w, req)
+
+	resp := w.Result()
+	require.Equal(s.T(), http.StatusBadRequest, resp.StatusCode)
+	require.Contains(s.T(), w.Body.String(), "Invalid JSON format")
+}
+
+func (s *HandlerTestSuite) TestUpdateEvent_ValidationError() {
+	eventID := "123e4567-e89b-12d3-a456-426614174000"
+
+	s.mockService.EXPECT().
+		UpdateEvent(gomock.Any(), eventID, 3, gomock.Any()).
+		Return(internal.CreateEventResponse{}, fmt.Errorf("title cannot be empty: %w", internal.ErrInput)).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodPut, "/events/"+eventID, strings.NewReader(`{"title": ""}`))
+	req.Header.Set("If-Match", `"3"`)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", eventID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	s.handler.UpdateEvent(w, req)
+
+	resp := w.Result()
+	require.Equal(s.T(), http.StatusBadRequest, resp.StatusCode)
+	require.Contains(s.T(), w.Body.String(), "title cannot be empty")
+}
+
+func (s *HandlerTestSuite) TestUpdateEvent_NotFound() {
+	eventID := "nonexistent-id"
+
+	s.mockService.EXPECT().
+		UpdateEvent(gomock.Any(), eventID, 3, gomock.Any()).
+		Return(internal.CreateEventResponse{}, internal.ErrNotFound).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodPut, "/events/"+eventID, strings.NewReader(`{}`))
+	req.Header.Set("If-Match", `"3"`)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", eventID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	s.handler.UpdateEvent(w, req)
+
+	resp := w.Result()
+	require.Equal(s.T(), http.StatusNotFound, resp.StatusCode)
+	require.Contains(s.T(), w.Body.String(), "event not found")
+}
+
+func (s *HandlerTestSuite) TestUpdateEvent_ResourceConflict() {
+	eventID := "123e4567-e89b-12d3-a456-426614174000"
+
+	s.mockService.EXPECT().
+		UpdateEvent(gomock.Any(), eventID, 3, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ int, event internal.CreateEventRequest) (internal.CreateEventResponse, error) {
+			require.Equal(s.T(), "room-4b", event.Resource)
+
+			return internal.CreateEventResponse{}, &internal.ConflictError{Resource: "room-4b", EventIDs: []string{"booking-1"}}
+		}).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodPut, "/events/"+eventID, strings.NewReader(`{"resource": "room-4b"}`))
+	req.Header.Set("If-Match", `"3"`)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", eventID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	s.handler.UpdateEvent(w, req)
+
+	require.Equal(s.T(), http.StatusConflict, w.Code)
+	require.Contains(s.T(), w.Body.String(), `"conflicting_event_ids":["booking-1"]`)
+}
+
+func (s *HandlerTestSuite) TestPatchEvent_Success() {
+	eventID := "123e4567-e89b-12d3-a456-426614174000"
+	now := time.Now().UTC().Truncate(time.Second)
+	longTitle := strings.Repeat("a", 101)
+
+	current := internal.CreateEventResponse{
+		ID:          eventID,
+		Title:       longTitle,
+		Description: "Old Description",
+		StartTime:   now,
+		EndTime:     now.Add(time.Hour),
+		CreatedAt:   now,
+		Version:     3,
+	}
+
+	expectedRequest := internal.CreateEventRequest{
+		Title:       longTitle,
+		Description: "New Description",
+		StartTime:   now,
+		EndTime:     now.Add(time.Hour),
+	}
+
+	s.mockService.EXPECT().
+		GetEventByID(gomock.Any(), eventID, false).
+		Return(current, nil).
+		Times(1)
+
+	s.mockService.EXPECT().
+		UpdateEvent(gomock.Any(), eventID, 3, expectedRequest).
+		Return(internal.CreateEventResponse{
+			ID:          eventID,
+			Title:       longTitle,
+			Description: "New Description",
+			StartTime:   now,
+			EndTime:     now.Add(time.Hour),
+			CreatedAt:   now,
+		}, nil).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodPatch, "/events/"+eventID, strings.NewReader(`{"description": "New Description"}`))
+	req.Header.Set("If-Match", `"3"`)
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", eventID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	s.handler.PatchEvent(w, req)
+
+	resp := w.Result()
+	require.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	require.Contains(s.T(), w.Body.String(), "New Description")
+}
+
+func (s *HandlerTestSuite) TestPatchEvent_NullRemovesField() {
+	eventID := "123e4567-e89b-12d3-a456-426614174000"
+	now := time.Now().UTC().Truncate(time.Second)
+
+	current := internal.CreateEventResponse{
+		ID:          eventID,
+		Title:       strings.Repeat("a", 101),
+		Description: "Old Description",
+		StartTime:   now,
+		EndTime:     now.Add(time.Hour),
+		CreatedAt:   now,
+		Version:     3,
+	}
+
+	s.mockService.EXPECT().
+		GetEventByID(gomock.Any(), eventID, false).
+		Return(current, nil).
+		Times(1)
+
+	s.mockService.EXPECT().
+		UpdateEvent(gomock.Any(), eventID, 3, internal.CreateEventRequest{
+			Title:     current.Title,
+			StartTime: now,
+			EndTime:   now.Add(time.Hour),
+		}).
+		Return(internal.CreateEventResponse{}, fmt.Errorf("description cannot be empty: %w", internal.ErrInput)).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodPatch, "/events/"+eventID, strings.NewReader(`{"description": null}`))
+	req.Header.Set("If-Match", `"3"`)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", eventID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	s.handler.PatchEvent(w, req)
+
+	resp := w.Result()
+	require.Equal(s.T(), http.StatusBadRequest, resp.StatusCode)
+	require.Contains(s.T(), w.Body.String(), "description cannot be empty")
+}
+
+func (s *HandlerTestSuite) TestPatchEvent_NotFound() {
+	eventID := "nonexistent-id"
+
+	s.mockService.EXPECT().
+		GetEventByID(gomock.Any(), eventID, false).
+		Return(internal.CreateEventResponse{}, internal.ErrNotFound).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodPatch, "/events/"+eventID, strings.NewReader(`{"description": "New"}`))
+	req.Header.Set("If-Match", `"3"`)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", eventID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	s.handler.PatchEvent(w, req)
+
+	resp := w.Result()
+	require.Equal(s.T(), http.StatusNotFound, resp.StatusCode)
+	require.Contains(s.T(), w.Body.String(), "event not found")
+}
+
+func (s *HandlerTestSuite) TestPatchEvent_InvalidJSON() {
+	eventID := "123e4567-e89b-12d3-a456-426614174000"
+
+	s.mockService.EXPECT().
+		GetEventByID(gomock.Any(), eventID, false).
+		Return(internal.CreateEventResponse{ID: eventID, Version: 3}, nil).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodPatch, "/events/"+eventID, strings.NewReader(`{"description": `))
+	req.Header.Set("If-Match", `"3"`)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", eventID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	s.handler.PatchEvent(w, req)
+
+	resp := w.Result()
+	require.Equal(s.T(), http.StatusBadRequest, resp.StatusCode)
+	require.Contains(s.T(), w.Body.String(), "Invalid JSON format")
+}
+
+func (s *HandlerTestSuite) TestGetEventByID_ETag() {
+	eventID := "123e4567-e89b-12d3-a456-426614174000"
+
+	s.mockService.EXPECT().
+		GetEventByID(gomock.Any(), eventID, false).
+		Return(internal.CreateEventResponse{ID: eventID, Version: 4}, nil).
+		Times(2)
+	s.mockAttendees.EXPECT().
+		CountAttendees(gomock.Any(), eventID).
+		Return(map[internal.AttendeeStatus]int{}, nil).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodGet, "/events/"+eventID, nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", eventID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	s.handler.GetEventByID(w, req)
+
+	require.Equal(s.T(), http.StatusOK, w.Code)
+	require.Equal(s.T(), `"4"`, w.Header().Get("ETag"))
+
+	req.Header.Set("If-None-Match", `W/"3", "4"`)
+	w = httptest.NewRecorder()
+
+	s.handler.GetEventByID(w, req)
+
+	require.Equal(s.T(), http.StatusNotModified, w.Code)
+	require.Equal(s.T(), `"4"`, w.Header().Get("ETag"))
+	require.Empty(s.T(), w.Body.String())
+}
+
+func (s *HandlerTestSuite) TestUpdateEvent_ETag() {
+	eventID := "123e4567-e89b-12d3-a456-426614174000"
+
+	s.mockService.EXPECT().
+		UpdateEvent(gomock.Any(), eventID, 0, gomock.Any()).
+		Return(internal.CreateEventResponse{ID: eventID, Version: 5}, nil).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodPut, "/events/"+eventID, strings.NewReader(`{}`))
+	req.Header.Set("If-Match", "*")
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", eventID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	s.handler.UpdateEvent(w, req)
+
+	require.Equal(s.T(), http.StatusOK, w.Code)
+	require.Equal(s.T(), `"5"`, w.Header().Get("ETag"))
+	require.Contains(s.T(), w.Body.String(), `"version":5`)
+}
+
+func (s *HandlerTestSuite) TestUpdateEvent_IfMatch() {
+	eventID := "123e4567-e89b-12d3-a456-426614174000"
+
+	for header, want := range map[string]struct {
+		status int
+		typ    string
+	}{
+		"":         {http.StatusPreconditionRequired, "/problems/precondition-required"},
+		`W/"3"`:    {http.StatusPreconditionFailed, "/problems/precondition-failed"},
+		`"abc"`:    {http.StatusPreconditionFailed, "/problems/precondition-failed"},
+		"3":        {http.StatusPreconditionFailed, "/problems/precondition-failed"},
+		`"3", "4"`: {http.StatusBadRequest, "/problems/invalid-input"},
+	} {
+		for _, method := range []string{http.MethodPut, http.MethodPatch} {
+			req := httptest.NewRequest(method, "/events/"+eventID, strings.NewReader(`{}`))
+			if header != "" {
+				req.Header.Set("If-Match", header)
+			}
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", eventID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			if method == http.MethodPut {
+				s.handler.UpdateEvent(w, req)
+			} else {
+				s.handler.PatchEvent(w, req)
+			}
+
+			p := s.problem(w)
+			require.Equal(s.T(), want.status, p.Status, method+" "+header)
+			require.Equal(s.T(), want.typ, p.Type, method+" "+header)
+		}
+	}
+}
+
+func (s *HandlerTestSuite) TestUpdateEvent_VersionMismatch() {
+	eventID := "123e4567-e89b-12d3-a456-426614174000"
+
+	s.mockService.EXPECT().
+		UpdateEvent(gomock.Any(), eventID, 3, gomock.Any()).
+		Return(internal.CreateEventResponse{}, fmt.Errorf("event is at version 4, not 3: %w", internal.ErrVersionMismatch)).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodPut, "/events/"+eventID, strings.NewReader(`{}`))
+	req.Header.Set("If-Match", `"3"`)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", eventID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	s.handler.UpdateEvent(w, req)
+
+	require.Equal(s.T(), http.StatusPreconditionFailed, w.Code)
+	require.Contains(s.T(), w.Body.String(), "event is at version 4, not 3")
+}
+
+func (s *HandlerTestSuite) TestPatchEvent_VersionMismatch() {
+	eventID := "123e4567-e89b-12d3-a456-426614174000"
+
+	s.mockService.EXPECT().
+		GetEventByID(gomock.Any(), eventID, false).
+		Return(internal.CreateEventResponse{ID: eventID, Version: 4}, nil).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodPatch, "/events/"+eventID, strings.NewReader(`{"description": "New"}`))
+	req.Header.Set("If-Match", `"3"`)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", eventID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	s.handler.PatchEvent(w, req)
+
+	require.Equal(s.T(), http.StatusPreconditionFailed, w.Code)
+	require.Contains(s.T(), w.Body.String(), "event is at version 4, not 3")
+}
+
+func (s *HandlerTestSuite) TestGetEvents_IncludeDeleted() {
+	deletedAt := time.Now()
+
+	s.mockService.EXPECT().
+		GetEvents(gomock.Any(), internal.EventFilter{IncludeDeleted: true}, internal.PageRequest{}).
+		Return(internal.EventsPage{Events: []internal.CreateEventResponse{{ID: "deleted-id", DeletedAt: &deletedAt}}}, nil).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodGet, "/events?include_deleted=true", nil)
+	req = req.WithContext(internal.WithAdmin(req.Context()))
+	w := httptest.NewRecorder()
+
+	s.handler.GetEvents(w, req)
+
+	resp := w.Result()
+	require.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	require.Contains(s.T(), w.Body.String(), "deleted-id")
+	require.Contains(s.T(), w.Body.String(), "deleted_at")
+}
+
+func (s *HandlerTestSuite) TestGetEvents_IncludeDeletedNotAdmin() {
+	req := httptest.NewRequest(http.MethodGet, "/events?include_deleted=true", nil)
+	req = req.WithContext(internal.WithActor(req.Context(), "auth0|ada"))
+	w := httptest.NewRecorder()
+
+	s.handler.GetEvents(w, req)
+
+	resp := w.Result()
+	require.Equal(s.T(), http.StatusForbidden, resp.StatusCode)
+	require.Contains(s.T(), w.Body.String(), "include_deleted requires an admin token")
+}
+
+func (s *HandlerTestSuite) TestGetEventByID_IncludeDeletedNotAdmin() {
+	req := httptest.NewRequest(http.MethodGet, "/events/event-id?include_deleted=true", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "event-id")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	s.handler.GetEventByID(w, req)
+
+	require.Equal(s.T(), http.StatusForbidden, w.Code)
+}
+
+func (s *HandlerTestSuite) TestGetEvents_InvalidIncludeDeleted() {
+	req := httptest.NewRequest(http.MethodGet, "/events?include_deleted=maybe", nil)
+	w := httptest.NewRecorder()
+
+	s.handler.GetEvents(w, req)
+
+	resp := w.Result()
+	require.Equal(s.T(), http.StatusBadRequest, resp.StatusCode)
+	require.Contains(s.T(), w.Body.String(), "include_deleted should be a boolean")
+}
+
+func (s *HandlerTestSuite) TestGetEvents_NextPage() {
+	s.mockService.EXPECT().
+		GetEvents(gomock.Any(), internal.EventFilter{}, internal.PageRequest{Limit: 1, Cursor: "current"}).
+		Return(internal.EventsPage{
+			Events:     []internal.CreateEventResponse{{ID: "first-id"}},
+			NextCursor: "next",
+		}, nil).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodGet, "/events?limit=1&cursor=current", nil)
+	w := httptest.NewRecorder()
+
+	s.handler.GetEvents(w, req)
+
+	resp := w.Result()
+	require.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	require.Contains(s.T(), w.Body.String(), `"next_cursor":"next"`)
+	require.Equal(s.T(), `</events?cursor=next&limit=1>; rel="next"`, resp.Header.Get("Link"))
+}
+
+func (s *HandlerTestSuite) TestGetEvents_Filter() {
+	from := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+	createdFrom := from.AddDate(0, -1, 0)
+
+	expectedFilter := internal.EventFilter{
+		From:        from,
+		To:          to,
+		Title:       "standup",
+		CreatedFrom: createdFrom,
+	}
+
+	s.mockService.EXPECT().
+		GetEvents(gomock.Any(), expectedFilter, internal.PageRequest{}).
+		Return(internal.EventsPage{Events: []internal.CreateEventResponse{}}, nil).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodGet, "/events?from=2025-12-01T00:00:00Z&to=2025-12-08T00:00:00Z&title=standup&created_from=2025-11-01T00:00:00Z", nil)
+	w := httptest.NewRecorder()
+
+	s.handler.GetEvents(w, req)
+
+	resp := w.Result()
+	require.Equal(s.T(), http.StatusOK, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestGetEvents_FilterInCallerZone() {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(s.T(), err)
+
+	s.mockService.EXPECT().
+		GetEvents(gomock.Any(), gomock.Any(), internal.PageRequest{}).
+		DoAndReturn(func(_ context.Context, filter internal.EventFilter, _ internal.PageRequest) (internal.EventsPage, error) {
+			require.Equal(s.T(), tokyo, filter.From.Location())
+			require.True(s.T(), filter.From.Equal(time.Date(2025, 12, 25, 0, 0, 0, 0, tokyo)))
+			require.Equal(s.T(), tokyo, filter.To.Location())
+
+			return internal.EventsPage{Events: []internal.CreateEventResponse{}}, nil
+		}).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodGet, "/events?from=2025-12-24T15:00:00Z&to=2025-12-25T15:00:00Z&tz=Asia/Tokyo", nil)
+	w := httptest.NewRecorder()
+
+	RenderTimeZone(http.HandlerFunc(s.handler.GetEvents)).ServeHTTP(w, req)
+
+	require.Equal(s.T(), http.StatusOK, w.Code)
+}
+
+func (s *HandlerTestSuite) TestGetEvents_Occurrences() {
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	filter := internal.EventFilter{From: start, To: start.AddDate(0, 0, 7)}
+	occurrence := start.AddDate(0, 0, 1)
+
+	s.mockService.EXPECT().
+		GetEvents(gomock.Any(), filter, internal.PageRequest{}).
+		Return(internal.EventsPage{Events: []internal.CreateEventResponse{{
+			ID:           "123e4567-e89b-12d3-a456-426614174000",
+			StartTime:    occurrence,
+			EndTime:      occurrence.Add(time.Hour),
+			RRule:        "FREQ=DAILY",
+			RecurrenceID: &occurrence,
+		}}}, nil).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodGet, "/events?from=2025-12-01T09:00:00Z&to=2025-12-08T09:00:00Z", nil)
+	w := httptest.NewRecorder()
+
+	s.handler.GetEvents(w, req)
+
+	resp := w.Result()
+	require.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	require.Contains(s.T(), w.Body.String(), `"recurrence_id":"2025-12-02T09:00:00Z"`)
+}
+
+func (s *HandlerTestSuite) TestGetEvents_InvalidFilterTime() {
+	req := httptest.NewRequest(http.MethodGet, "/events?from=yesterday", nil)
+	w := httptest.NewRecorder()
+
+	s.handler.GetEvents(w, req)
+
+	resp := w.Result()
+	require.Equal(s.T(), http.StatusBadRequest, resp.StatusCode)
+	require.Contains(s.T(), w.Body.String(), "from should be an RFC 3339 time")
+}
+
+func (s *HandlerTestSuite) TestGetEvents_InvalidLimit() {
+	req := httptest.NewRequest(http.MethodGet, "/events?limit=abc", nil)
+	w := httptest.NewRecorder()
+
+	s.handler.GetEvents(w, req)
+
+	resp := w.Result()
+	require.Equal(s.T(), http.StatusBadRequest, resp.StatusCode)
+	require.Contains(s.T(), w.Body.String(), "limit should be a positive integer")
+}
+
+func (s *HandlerTestSuite) TestGetEvents_InvalidCursor() {
+	s.mockService.EXPECT().
+		GetEvents(gomock.Any(), internal.EventFilter{}, internal.PageRequest{Cursor: "garbage"}).
+		Return(internal.EventsPage{}, fmt.Errorf("invalid cursor: %w", internal.ErrInput)).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodGet, "/events?cursor=garbage", nil)
+	w := httptest.NewRecorder()
+
+	s.handler.GetEvents(w, req)
+
+	resp := w.Result()
+	require.Equal(s.T(), http.StatusBadRequest, resp.StatusCode)
+	require.Contains(s.T(), w.Body.String(), "invalid cursor")
+}
+
+func (s *HandlerTestSuite) TestDeleteEvent_Success() {
+	eventID := "123e4567-e89b-12d3-a456-426614174000"
+
+	s.mockService.EXPECT().
+		DeleteEvent(gomock.Any(), eventID).
+		Return(nil).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodDelete, "/events/"+eventID, nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", eventID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	s.handler.DeleteEvent(w, req)
+
+	resp := w.Result()
+	require.Equal(s.T(), http.StatusNoContent, resp.StatusCode)
+	require.Empty(s.T(), w.Body.String())
+}
+
+func (s *HandlerTestSuite) TestDeleteEvent_NotFound() {
+	eventID := "nonexistent-id"
+
+	s.mockService.EXPECT().
+		DeleteEvent(gomock.Any(), eventID).
+		Return(internal.ErrNotFound).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodDelete, "/events/"+eventID, nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", eventID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	s.handler.DeleteEvent(w, req)
+
+	resp := w.Result()
+	require.Equal(s.T(), http.StatusNotFound, resp.StatusCode)
+	require.Contains(s.T(), w.Body.String(), "event not found")
+}
+
+func (s *HandlerTestSuite) TestRestoreEvent_Success() {
+	eventID := "123e4567-e89b-12d3-a456-426614174000"
+
+	s.mockService.EXPECT().
+		RestoreEvent(gomock.Any(), eventID).
+		Return(internal.CreateEventResponse{ID: eventID, Title: "Restored Event"}, nil).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodPost, "/events/"+eventID+"/restore", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", eventID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	s.handler.RestoreEvent(w, req)
+
+	resp := w.Result()
+	require.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	require.Contains(s.T(), w.Body.String(), "Restored Event")
+	require.NotContains(s.T(), w.Body.String(), "deleted_at")
+}
+
+func (s *HandlerTestSuite) TestRestoreEvent_NotFound() {
+	eventID := "nonexistent-id"
+
+	s.mockService.EXPECT().
+		RestoreEvent(gomock.Any(), eventID).
+		Return(internal.CreateEventResponse{}, internal.ErrNotFound).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodPost, "/events/"+eventID+"/restore", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", eventID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	s.handler.RestoreEvent(w, req)
+
+	resp := w.Result()
+	require.Equal(s.T(), http.StatusNotFound, resp.StatusCode)
+	require.Contains(s.T(), w.Body.String(), "deleted event not found")
+}
+
+func TestHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(HandlerTestSuite))
+}
+
+func (s *HandlerTestSuite) TestGetEventHistory_Success() {
+	eventID := "123e4567-e89b-12d3-a456-426614174000"
+	createdAt := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+
+	s.mockService.EXPECT().
+		GetEventHistory(gomock.Any(), eventID).
+		Return([]internal.EventRevision{
+			{
+				EventID:   eventID,
+				Revision:  1,
+				Action:    internal.RevisionCreated,
+				Actor:     "user-1",
+				CreatedAt: createdAt,
+				Changes:   map[string]internal.FieldChange{"title": {To: json.RawMessage(`"Standup"`)}},
+			},
+			{
+				EventID:   eventID,
+				Revision:  2,
+				Action:    internal.RevisionUpdated,
+				CreatedAt: createdAt.Add(time.Hour),
+				Changes:   map[string]internal.FieldChange{"title": {From: json.RawMessage(`"Standup"`), To: json.RawMessage(`"Retro"`)}},
+			},
+		}, nil).
+		Times(1)
+
+	w := httptest.NewRecorder()
+
+	s.handler.GetEventHistory(w, routedRequest(http.MethodGet, "/events/"+eventID+"/history", "", map[string]string{"id": eventID}))
+
+	require.Equal(s.T(), http.StatusOK, w.Code)
+	require.JSONEq(s.T(), `{"revisions":[
+		{"revision":1,"action":"created","actor":"user-1","created_at":"2025-12-01T09:00:00Z","changes":{"title":{"to":"Standup"}}},
+		{"revision":2,"action":"updated","created_at":"2025-12-01T10:00:00Z","changes":{"title":{"from":"Standup","to":"Retro"}}}
+	]}`, w.Body.String())
+}
+
+func (s *HandlerTestSuite) TestGetEventHistory_NotFound() {
+	s.mockService.EXPECT().
+		GetEventHistory(gomock.Any(), "missing").
+		Return(nil, internal.ErrNotFound).
+		Times(1)
+
+	w := httptest.NewRecorder()
+
+	s.handler.GetEventHistory(w, routedRequest(http.MethodGet, "/events/missing/history", "", map[string]string{"id": "missing"}))
+
+	require.Equal(s.T(), http.StatusNotFound, w.Code)
+	require.Equal(s.T(), "event not found", s.problem(w).Detail)
+}
+
+func (s *HandlerTestSuite) TestRevertEvent_Success() {
+	eventID := "123e4567-e89b-12d3-a456-426614174000"
+
+	s.mockService.EXPECT().
+		RevertEvent(gomock.Any(), eventID, 2, 3).
+		Return(internal.CreateEventResponse{ID: eventID, Title: "Standup", Version: 4}, nil).
+		Times(1)
+
+	req := routedRequest(http.MethodPost, "/events/"+eventID+"/history/2/revert", "", map[string]string{"id": eventID, "revision": "2"})
+	req.Header.Set("If-Match", `"3"`)
+	w := httptest.NewRecorder()
+
+	s.handler.RevertEvent(w, req)
+
+	require.Equal(s.T(), http.StatusOK, w.Code)
+	require.Equal(s.T(), `"4"`, w.Header().Get("ETag"))
+	require.Contains(s.T(), w.Body.String(), "Standup")
+}
+
+func (s *HandlerTestSuite) TestRevertEvent_InvalidRevision() {
+	for _, revision := range []string{"abc", "0", "-1"} {
+		req := routedRequest(http.MethodPost, "/events/evt/history/"+revision+"/revert", "", map[string]string{"id": "evt", "revision": revision})
+		req.Header.Set("If-Match", `"3"`)
+		w := httptest.NewRecorder()
+
+		s.handler.RevertEvent(w, req)
+
+		require.Equal(s.T(), http.StatusBadRequest, w.Code, revision)
+		require.Equal(s.T(), "revision should be a positive integer", s.problem(w).Detail)
+	}
+}
+
+func (s *HandlerTestSuite) TestRevertEvent_MissingIfMatch() {
+	w := httptest.NewRecorder()
+
+	s.handler.RevertEvent(w, routedRequest(http.MethodPost, "/events/evt/history/2/revert", "", map[string]string{"id": "evt", "revision": "2"}))
+
+	require.Equal(s.T(), http.StatusPreconditionRequired, w.Code)
+}
+
+func (s *HandlerTestSuite) TestRevertEvent_NotFound() {
+	s.mockService.EXPECT().
+		RevertEvent(gomock.Any(), "evt", 9, 3).
+		Return(internal.CreateEventResponse{}, internal.ErrNotFound).
+		Times(1)
+
+	req := routedRequest(http.MethodPost, "/events/evt/history/9/revert", "", map[string]string{"id": "evt", "revision": "9"})
+	req.Header.Set("If-Match", `"3"`)
+	w := httptest.NewRecorder()
+
+	s.handler.RevertEvent(w, req)
+
+	require.Equal(s.T(), http.StatusNotFound, w.Code)
+	require.Equal(s.T(), "event or revision not found", s.problem(w).Detail)
+}
+
+func (s *HandlerTestSuite) TestGetOccurrences_Success() {
+	eventID := "123e4567-e89b-12d3-a456-426614174000"
+	from := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+	occurrence := from.Add(9 * time.Hour)
+
+	s.mockService.EXPECT().
+		GetOccurrences(gomock.Any(), eventID, from, to).
+		Return([]internal.CreateEventResponse{{ID: eventID, StartTime: occurrence, EndTime: occurrence.Add(time.Hour), RecurrenceID: &occurrence}}, nil).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodGet, "/events/"+eventID+"/occurrences?from=2025-12-01T00:00:00Z&to=2025-12-08T00:00:00Z", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", eventID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	s.handler.GetOccurrences(w, req)
+
+	resp := w.Result()
+	require.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	require.Contains(s.T(), w.Body.String(), `"occurrences":[`)
+	require.Contains(s.T(), w.Body.String(), `"recurrence_id":"2025-12-01T09:00:00Z"`)
+}
+
+func (s *HandlerTestSuite) TestUpdateOccurrence_Success() {
+	eventID := "123e4567-e89b-12d3-a456-426614174000"
+	recurrenceID := time.Date(2025, 12, 8, 9, 0, 0, 0, time.UTC)
+
+	s.mockService.EXPECT().
+		UpdateOccurrence(gomock.Any(), internal.OccurrenceException{
+			SeriesID:     eventID,
+			RecurrenceID: recurrenceID,
+			StartTime:    recurrenceID.Add(time.Hour),
+			EndTime:      recurrenceID.Add(2 * time.Hour),
+		}).
+		Return(internal.CreateEventResponse{ID: eventID, StartTime: recurrenceID.Add(time.Hour), RecurrenceID: &recurrenceID}, nil).
+		Times(1)
+
+	body := `{"start_time": "2025-12-08T10:00:00Z", "end_time": "2025-12-08T11:00:00Z"}`
+	req := httptest.NewRequest(http.MethodPut, "/events/"+eventID+"/occurrences/2025-12-08T09:00:00Z", strings.NewReader(body))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", eventID)
+	rctx.URLParams.Add("recurrence_id", "2025-12-08T09:00:00Z")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	s.handler.UpdateOccurrence(w, req)
+
+	resp := w.Result()
+	require.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	require.Contains(s.T(), w.Body.String(), `"start_time":"2025-12-08T10:00:00Z"`)
+}
+
+func (s *HandlerTestSuite) TestUpdateOccurrence_InvalidRecurrenceID() {
+	req := httptest.NewRequest(http.MethodPut, "/events/123/occurrences/tomorrow", strings.NewReader(`{}`))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "123")
+	rctx.URLParams.Add("recurrence_id", "tomorrow")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	s.handler.UpdateOccurrence(w, req)
+
+	require.Equal(s.T(), http.StatusBadRequest, w.Result().StatusCode)
+}
+
+func (s *HandlerTestSuite) TestCancelOccurrence_Success() {
+	eventID := "123e4567-e89b-12d3-a456-426614174000"
+	recurrenceID := time.Date(2025, 12, 8, 9, 0, 0, 0, time.UTC)
+
+	s.mockService.EXPECT().
+		CancelOccurrence(gomock.Any(), eventID, recurrenceID).
+		Return(nil).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodDelete, "/events/"+eventID+"/occurrences/2025-12-08T09:00:00Z", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", eventID)
+	rctx.URLParams.Add("recurrence_id", "2025-12-08T09:00:00Z")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	s.handler.CancelOccurrence(w, req)
+
+	require.Equal(s.T(), http.StatusNoContent, w.Result().StatusCode)
+}
+
+func (s *HandlerTestSuite) TestCancelOccurrence_NotFound() {
+	eventID := "123e4567-e89b-12d3-a456-426614174000"
+
+	s.mockService.EXPECT().
+		CancelOccurrence(gomock.Any(), eventID, gomock.Any()).
+		Return(fmt.Errorf("occurrence not found: %w", internal.ErrNotFound)).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodDelete, "/events/"+eventID+"/occurrences/2025-12-09T09:00:00Z", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", eventID)
+	rctx.URLParams.Add("recurrence_id", "2025-12-09T09:00:00Z")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	s.handler.CancelOccurrence(w, req)
+
+	require.Equal(s.T(), http.StatusNotFound, w.Result().StatusCode)
+}
+
+func (s *HandlerTestSuite) TestSplitSeries_Success() {
+	eventID := "123e4567-e89b-12d3-a456-426614174000"
+	recurrenceID := time.Date(2025, 12, 15, 9, 0, 0, 0, time.UTC)
+
+	s.mockService.EXPECT().
+		SplitSeries(gomock.Any(), eventID, recurrenceID, internal.CreateEventRequest{Description: "new agenda"}).
+		Return(internal.CreateEventResponse{ID: "223e4567-e89b-12d3-a456-426614174001", Description: "new agenda"}, nil).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodPost, "/events/"+eventID+"/occurrences/2025-12-15T09:00:00Z/split", strings.NewReader(`{"description": "new agenda"}`))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", eventID)
+	rctx.URLParams.Add("recurrence_id", "2025-12-15T09:00:00Z")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	s.handler.SplitSeries(w, req)
+
+	resp := w.Result()
+	require.Equal(s.T(), http.StatusCreated, resp.StatusCode)
+	require.Contains(s.T(), w.Body.String(), "223e4567-e89b-12d3-a456-426614174001")
+}
+
+func (s *HandlerTestSuite) TestExportEvents_WalksPages() {
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	filter := internal.EventFilter{From: start, To: start.AddDate(0, 0, 7)}
+	occurrence := start.AddDate(0, 0, 1)
+
+	first := internal.CreateEventResponse{ID: "first", Title: "One, two", StartTime: start, EndTime: start.Add(time.Hour), CreatedAt: start}
+	second := internal.CreateEventResponse{
+		ID:           "series",
+		Title:        "Standup",
+		StartTime:    occurrence,
+		EndTime:      occurrence.Add(time.Hour),
+		CreatedAt:    start,
+		RRule:        "FREQ=DAILY",
+		RecurrenceID: &occurrence,
+	}
+
+	gomock.InOrder(
+		s.mockService.EXPECT().
+			GetEvents(gomock.Any(), filter, internal.PageRequest{Limit: internal.MaxPageSize}).
+			Return(internal.EventsPage{Events: []internal.CreateEventResponse{first}, NextCursor: "next"}, nil),
+		s.mockService.EXPECT().
+			GetEvents(gomock.Any(), filter, internal.PageRequest{Limit: internal.MaxPageSize, Cursor: "next"}).
+			Return(internal.EventsPage{Events: []internal.CreateEventResponse{second}}, nil),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/events.ics?from=2025-12-01T09:00:00Z&to=2025-12-08T09:00:00Z", nil)
+	w := httptest.NewRecorder()
+
+	s.handler.ExportEvents(w, req)
+
+	resp := w.Result()
+	require.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	require.Equal(s.T(), "text/calendar; charset=utf-8", resp.Header.Get("Content-Type"))
+	require.Contains(s.T(), w.Body.String(), "UID:first\r\n")
+	require.Contains(s.T(), w.Body.String(), "SUMMARY:One\\, two\r\n")
+	require.Contains(s.T(), w.Body.String(), "UID:series\r\nDTSTAMP:20251201T090000Z\r\nRECURRENCE-ID:20251202T090000Z\r\n")
+	require.NotContains(s.T(), w.Body.String(), "RRULE")
+}
+
+func (s *HandlerTestSuite) TestExportEvents_DefaultFrom() {
+	// Without to, series are exported whole, so the window stays open.
+	s.mockService.EXPECT().
+		GetEvents(gomock.Any(), gomock.Cond(func(filter internal.EventFilter) bool {
+			return filter.From.Sub(time.Now().AddDate(0, 0, -30)).Abs() < time.Minute && filter.To.IsZero()
+		}), internal.PageRequest{Limit: internal.MaxPageSize}).
+		Return(internal.EventsPage{}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/events.ics", nil)
+	w := httptest.NewRecorder()
+
+	s.handler.ExportEvents(w, req)
+
+	require.Equal(s.T(), http.StatusOK, w.Code)
+}
+
+func (s *HandlerTestSuite) TestExportEvents_PastTo() {
+	to := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	s.mockService.EXPECT().
+		GetEvents(gomock.Any(), internal.EventFilter{From: to.AddDate(0, 0, -30), To: to}, gomock.Any()).
+		Return(internal.EventsPage{}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/events.ics?to=2025-12-01T00:00:00Z", nil)
+	w := httptest.NewRecorder()
+
+	s.handler.ExportEvents(w, req)
+
+	require.Equal(s.T(), http.StatusOK, w.Code)
+}
+
+func (s *HandlerTestSuite) TestExportEvents_TooManyEvents() {
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+
+	events := make([]internal.CreateEventResponse, internal.MaxPageSize)
+	for i := range events {
+		events[i] = internal.CreateEventResponse{ID: fmt.Sprintf("event-%d", i), StartTime: start, EndTime: start.Add(time.Hour)}
+	}
+
+	// The walk stops at the first page over the limit.
+	s.mockService.EXPECT().
+		GetEvents(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(internal.EventsPage{Events: events, NextCursor: "next"}, nil).
+		Times(5000/internal.MaxPageSize + 1)
+
+	req := httptest.NewRequest(http.MethodGet, "/events.ics?from=2025-12-01T00:00:00Z&to=2025-12-08T00:00:00Z", nil)
+	w := httptest.NewRecorder()
+
+	s.handler.ExportEvents(w, req)
+
+	require.Equal(s.T(), http.StatusBadRequest, w.Code)
+	require.Contains(s.T(), w.Body.String(), "the feed has more than 5000 events")
+}
+
+func (s *HandlerTestSuite) TestExportEvents_InvalidFilter() {
+	req := httptest.NewRequest(http.MethodGet, "/events.ics?from=yesterday", nil)
+	w := httptest.NewRecorder()
+
+	s.handler.ExportEvents(w, req)
+
+	require.Equal(s.T(), http.StatusBadRequest, w.Result().StatusCode)
+}
+
+func (s *HandlerTestSuite) TestExportEvent_Success() {
+	eventID := "123e4567-e89b-12d3-a456-426614174000"
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+
+	s.mockService.EXPECT().
+		GetEventByID(gomock.Any(), eventID, false).
+		Return(internal.CreateEventResponse{ID: eventID, Title: "Standup", StartTime: start, EndTime: start.Add(time.Hour), CreatedAt: start, RRule: "FREQ=DAILY"}, nil).
+		Times(1)
+
+	s.mockService.EXPECT().
+		GetOccurrenceExceptions(gomock.Any(), eventID).
+		Return(nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/events/"+eventID+".ics", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", eventID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	s.handler.ExportEvent(w, req)
+
+	resp := w.Result()
+	require.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	require.True(s.T(), strings.HasPrefix(w.Body.String(), "BEGIN:VCALENDAR\r\n"))
+	require.Contains(s.T(), w.Body.String(), "UID:"+eventID+"\r\n")
+	require.Contains(s.T(), w.Body.String(), "RRULE:FREQ=DAILY\r\n")
+}
+
+func (s *HandlerTestSuite) TestExportEvent_Exceptions() {
+	eventID := "123e4567-e89b-12d3-a456-426614174000"
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	cancelled, moved := start.AddDate(0, 0, 1), start.AddDate(0, 0, 2)
+
+	s.mockService.EXPECT().
+		GetEventByID(gomock.Any(), eventID, false).
+		Return(internal.CreateEventResponse{ID: eventID, Title: "Standup", StartTime: start, EndTime: start.Add(time.Hour), CreatedAt: start, RRule: "FREQ=DAILY"}, nil)
+
+	s.mockService.EXPECT().
+		GetOccurrenceExceptions(gomock.Any(), eventID).
+		Return([]internal.OccurrenceException{
+			{SeriesID: eventID, RecurrenceID: cancelled, Cancelled: true},
+			{SeriesID: eventID, RecurrenceID: moved, Title: "Late standup", StartTime: moved.Add(2 * time.Hour), EndTime: moved.Add(3 * time.Hour)},
+		}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/events/"+eventID+".ics", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", eventID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	s.handler.ExportEvent(w, req)
+
+	require.Equal(s.T(), http.StatusOK, w.Code)
+
+	// A calendar client reading the feed sees the occurrence cancelled and the
+	// other one moved.
+	decoded, err := ical.Decode(w.Body)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), decoded, 2)
+
+	series, override := decoded[0].Event, decoded[1].Event
+	require.Equal(s.T(), "FREQ=DAILY", series.RRule)
+	require.Equal(s.T(), []time.Time{cancelled}, series.ExDates)
+	require.Equal(s.T(), eventID, override.UID)
+	require.Equal(s.T(), &moved, override.RecurrenceID)
+	require.Equal(s.T(), "Late standup", override.Summary)
+	require.Equal(s.T(), moved.Add(2*time.Hour), override.Start)
+	require.Empty(s.T(), override.RRule)
+}
+
+func (s *HandlerTestSuite) TestExportEvents_SeriesExceptions() {
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	from := start.AddDate(0, 0, -30)
+
+	s.mockService.EXPECT().
+		GetEvents(gomock.Any(), internal.EventFilter{From: from}, gomock.Any()).
+		Return(internal.EventsPage{
+			Events:     []internal.CreateEventResponse{{ID: "series", Title: "Standup", StartTime: start, EndTime: start.Add(time.Hour), RRule: "FREQ=DAILY"}},
+			Exceptions: []internal.OccurrenceException{{SeriesID: "series", RecurrenceID: start.AddDate(0, 0, 1), Cancelled: true}},
+		}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/events.ics?from="+from.Format(time.RFC3339), nil)
+	w := httptest.NewRecorder()
+
+	s.handler.ExportEvents(w, req)
+
+	require.Equal(s.T(), http.StatusOK, w.Code)
+	require.Contains(s.T(), w.Body.String(), "RRULE:FREQ=DAILY\r\nEXDATE:20251202T090000Z\r\n")
+}
+
+func (s *HandlerTestSuite) TestImportEvents_Override() {
+	body := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//test//EN",
+		"BEGIN:VEVENT",
+		"UID:abc@example.com",
+		"RECURRENCE-ID:20251202T090000Z",
+		"DTSTART:20251202T110000Z",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+
+	s.mockService.EXPECT().
+		ImportEvents(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, events []internal.ImportedEvent) ([]internal.ImportResult, error) {
+			require.Len(s.T(), events, 1)
+			require.EqualError(s.T(), events[0].Err, "overridden occurrences are not supported")
+
+			return []internal.ImportResult{{UID: "abc@example.com", Status: internal.ImportRejected, Reason: events[0].Err.Error()}}, nil
+		})
+
+	req := httptest.NewRequest(http.MethodPost, "/events/import", strings.NewReader(body))
+	req.Header.Set("Content-Type", "text/calendar")
+	w := httptest.NewRecorder()
+
+	s.handler.ImportEvents(w, req)
+
+	require.Equal(s.T(), http.StatusOK, w.Code)
+	require.Contains(s.T(), w.Body.String(), `"rejected":1`)
+}
+
+func (s *HandlerTestSuite) TestExportEvent_NotFound() {
+	s.mockService.EXPECT().
+		GetEventByID(gomock.Any(), "missing", false).
+		Return(internal.CreateEventResponse{}, internal.ErrNotFound).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodGet, "/events/missing.ics", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "missing")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	s.handler.ExportEvent(w, req)
+
+	require.Equal(s.T(), http.StatusNotFound, w.Result().StatusCode)
+}
+
+func (s *HandlerTestSuite) TestImportEvents_Success() {
+	body := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//test//EN",
+		"BEGIN:VEVENT",
+		"UID:abc@example.com",
+		"DTSTART;TZID=Europe/Madrid:20251201T090000",
+		"DURATION:PT1H",
+		"SUMMARY:Planning",
+		"DESCRIPTION:Q1",
+		"RRULE:FREQ=WEEKLY;COUNT=4",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:broken@example.com",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+
+	s.mockService.EXPECT().
+		ImportEvents(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, events []internal.ImportedEvent) ([]internal.ImportResult, error) {
+			require.Len(s.T(), events, 2)
+			require.Equal(s.T(), "abc@example.com", events[0].UID)
+			require.Equal(s.T(), "Planning", events[0].Event.Title)
+			require.Equal(s.T(), "FREQ=WEEKLY;COUNT=4", events[0].Event.RRule)
+			require.True(s.T(), events[0].Event.StartTime.Equal(time.Date(2025, 12, 1, 8, 0, 0, 0, time.UTC)))
+			require.Equal(s.T(), time.Hour, events[0].Event.EndTime.Sub(events[0].Event.StartTime))
+			require.Error(s.T(), events[1].Err)
+
+			return []internal.ImportResult{
+				{UID: "abc@example.com", ID: "123e4567-e89b-12d3-a456-426614174000", Status: internal.ImportCreated},
+				{UID: "broken@example.com", Status: internal.ImportRejected, Reason: "missing DTSTART"},
+			}, nil
+		}).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodPost, "/events/import", strings.NewReader(body))
+	req.Header.Set("Content-Type", "text/calendar; charset=utf-8")
+	w := httptest.NewRecorder()
+
+	s.handler.ImportEvents(w, req)
+
+	resp := w.Result()
+	require.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	require.JSONEq(s.T(), `{
+		"created": 1,
+		"updated": 0,
+		"rejected": 1,
+		"events": [
+			{"uid": "abc@example.com", "id": "123e4567-e89b-12d3-a456-426614174000", "status": "created"},
+			{"uid": "broken@example.com", "status": "rejected", "error": "missing DTSTART"}
+		]
+	}`, w.Body.String())
+}
+
+func (s *HandlerTestSuite) TestImportEvents_WrongContentType() {
+	req := httptest.NewRequest(http.MethodPost, "/events/import", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	s.handler.ImportEvents(w, req)
+
+	require.Equal(s.T(), http.StatusUnsupportedMediaType, w.Result().StatusCode)
+}
+
+func (s *HandlerTestSuite) TestImportEvents_InvalidCalendar() {
+	req := httptest.NewRequest(http.MethodPost, "/events/import", strings.NewReader("BEGIN:VCALENDAR\r\n"))
+	req.Header.Set("Content-Type", "text/calendar")
+	w := httptest.NewRecorder()
+
+	s.handler.ImportEvents(w, req)
+
+	require.Equal(s.T(), http.StatusBadRequest, w.Result().StatusCode)
+}
+
+func (s *HandlerTestSuite) TestGetEvents_FilterResources() {
+	s.mockService.EXPECT().
+		GetEvents(gomock.Any(), internal.EventFilter{Resources: []string{"room-a", "room-b"}}, internal.PageRequest{}).
+		Return(internal.EventsPage{}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/events?resource=room-a&resource=room-b", nil)
+	w := httptest.NewRecorder()
+
+	s.handler.GetEvents(w, req)
+
+	require.Equal(s.T(), http.StatusOK, w.Code)
+}
+
+func (s *HandlerTestSuite) TestSearchAvailability_Success() {
+	from := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	slot := interval.Interval{Start: from.Add(9 * time.Hour), End: from.Add(10 * time.Hour)}
+
+	s.mockService.EXPECT().
+		SearchAvailability(gomock.Any(), internal.AvailabilityQuery{
+			Resources:   []string{"room-a"},
+			CalendarIDs: []string{"ada"},
+			From:        from,
+			To:          from.AddDate(0, 0, 7),
+			Duration:    45 * time.Minute,
+			WorkingHours: &internal.WorkingHours{
+				Start: 9 * time.Hour,
+				End:   17*time.Hour + 30*time.Minute,
+				Days:  []time.Weekday{time.Monday, time.Tuesday},
+			},
+			TimeZone: "Europe/Madrid",
+			Limit:    5,
+		}).
+		Return(internal.Availability{Free: interval.Set{slot}, Slots: []interval.Interval{slot}}, nil)
+
+	body := `{"resources": ["room-a"], "calendar_ids": ["ada"], "from": "2025-12-01T00:00:00Z", "to": "2025-12-08T00:00:00Z", "duration_minutes": 45,
+		"working_hours": {"start": "09:00", "end": "17:30", "days": ["mo", "TU"]}, "time_zone": "Europe/Madrid", "limit": 5}`
+	req := httptest.NewRequest(http.MethodPost, "/availability/search", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	s.handler.SearchAvailability(w, req)
+
+	require.Equal(s.T(), http.StatusOK, w.Code)
+	require.JSONEq(s.T(), `{
+		"time_zone": "Europe/Madrid",
+		"slots": [{"start": "2025-12-01T10:00:00+01:00", "end": "2025-12-01T11:00:00+01:00"}],
+		"free": [{"start": "2025-12-01T10:00:00+01:00", "end": "2025-12-01T11:00:00+01:00"}],
+		"busy": []
+	}`, w.Body.String())
+}
+
+func (s *HandlerTestSuite) TestSearchAvailability_UnknownCalendar() {
+	s.mockService.EXPECT().
+		SearchAvailability(gomock.Any(), gomock.Any()).
+		Return(internal.Availability{}, fmt.Errorf("getting calendar %q: %w", "bob", internal.ErrNotFound))
+
+	req := httptest.NewRequest(http.MethodPost, "/availability/search", strings.NewReader(`{"calendar_ids": ["bob"]}`))
+	w := httptest.NewRecorder()
+
+	s.handler.SearchAvailability(w, req)
+
+	require.Equal(s.T(), http.StatusNotFound, w.Code)
+	require.Equal(s.T(), "calendar not found", s.problem(w).Detail)
+}
+
+func (s *HandlerTestSuite) TestSearchAvailability_InvalidWorkingHours() {
+	bodies := []string{
+		`{"resources": ["room-a"], "working_hours": {"start": "9am", "end": "17:00"}}`,
+		`{"resources": ["room-a"], "working_hours": {"start": "09:00", "end": "17:00", "days": ["monday"]}}`,
+		`{"resources": "room-a"}`,
+	}
+
+	for _, body := range bodies {
+		req := httptest.NewRequest(http.MethodPost, "/availability/search", strings.NewReader(body))
+		w := httptest.NewRecorder()
+
+		s.handler.SearchAvailability(w, req)
+
+		require.Equal(s.T(), http.StatusBadRequest, w.Code, body)
+	}
+}
+
+func (s *HandlerTestSuite) TestSearchAvailability_ValidationError() {
+	s.mockService.EXPECT().
+		SearchAvailability(gomock.Any(), gomock.Any()).
+		Return(internal.Availability{}, fmt.Errorf("resources and calendar ids cannot both be empty: %w", internal.ErrInput))
+
+	req := httptest.NewRequest(http.MethodPost, "/availability/search", strings.NewReader(`{}`))
+	w := httptest.NewRecorder()
+
+	s.handler.SearchAvailability(w, req)
+
+	require.Equal(s.T(), http.StatusBadRequest, w.Code)
+	require.Contains(s.T(), w.Body.String(), "resources and calendar ids cannot both be empty")
+}
+
+// problem decodes the problem details w rendered.
+func (s *HandlerTestSuite) problem(w *httptest.ResponseRecorder) problem {
+	require.Equal(s.T(), "application/problem+json", w.Header().Get("Content-Type"))
+
+	var p problem
+	require.NoError(s.T(), json.Unmarshal(w.Body.Bytes(), &p))
+	require.Equal(s.T(), w.Code, p.Status)
+
+	return p
+}
+
+func routedRequest(method, target, body string, params map[string]string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+
+	rctx := chi.NewRouteContext()
+	for key, value := range params {
+		rctx.URLParams.Add(key, value)
+	}
+
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func (s *HandlerTestSuite) TestInviteAttendee_Success() {
+	now := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+
+	s.mockAttendees.EXPECT().
+		Invite(gomock.Any(), internal.Attendee{EventID: "event", Email: "ada@example.com", Name: "Ada", Role: internal.RoleOptional}).
+		Return(internal.Attendee{
+			EventID:         "event",
+			Email:           "ada@example.com",
+			Name:            "Ada",
+			Role:            internal.RoleOptional,
+			Status:          internal.StatusInvited,
+			InvitedAt:       now,
+			StatusChangedAt: now,
+		}, nil)
+
+	req := routedRequest(http.MethodPost, "/events/event/attendees", `{"email": "ada@example.com", "name": "Ada", "role": "optional"}`,
+		map[string]string{"id": "event"})
+	w := httptest.NewRecorder()
+
+	s.handler.InviteAttendee(w, req)
+
+	require.Equal(s.T(), http.StatusCreated, w.Code)
+	require.JSONEq(s.T(), `{
+		"email": "ada@example.com",
+		"name": "Ada",
+		"role": "optional",
+		"status": "invited",
+		"invited_at": "2025-12-01T09:00:00Z",
+		"status_changed_at": "2025-12-01T09:00:00Z"
+	}`, w.Body.String())
+}
+
+func (s *HandlerTestSuite) TestInviteAttendee_Errors() {
+	cases := []struct {
+		err    error
+		status int
+	}{
+		{fmt.Errorf("invalid email: %w", internal.ErrInput), http.StatusBadRequest},
+		{fmt.Errorf("event not found: %w", internal.ErrNotFound), http.StatusNotFound},
+		{fmt.Errorf("already invited: %w", internal.ErrConflict), http.StatusConflict},
+		{errors.New("connection refused"), http.StatusInternalServerError},
+	}
+
+	for _, c := range cases {
+		s.mockAttendees.EXPECT().Invite(gomock.Any(), gomock.Any()).Return(internal.Attendee{}, c.err)
+
+		req := routedRequest(http.MethodPost, "/events/event/attendees", `{"email": "ada@example.com"}`, map[string]string{"id": "event"})
+		w := httptest.NewRecorder()
+
+		s.handler.InviteAttendee(w, req)
+
+		require.Equal(s.T(), c.status, w.Code, c.err.Error())
+	}
+}
+
+func (s *HandlerTestSuite) TestGetAttendees_Success() {
+	s.mockAttendees.EXPECT().GetAttendees(gomock.Any(), "event").Return([]internal.Attendee{}, nil)
+
+	req := routedRequest(http.MethodGet, "/events/event/attendees", "", map[string]string{"id": "event"})
+	w := httptest.NewRecorder()
+
+	s.handler.GetAttendees(w, req)
+
+	require.Equal(s.T(), http.StatusOK, w.Code)
+	require.JSONEq(s.T(), `{"attendees": []}`, w.Body.String())
+}
+
+func (s *HandlerTestSuite) TestRSVP_Success() {
+	s.mockAttendees.EXPECT().
+		RSVP(gomock.Any(), "event", "ada@example.com", internal.StatusAccepted).
+		Return(internal.Attendee{Email: "ada@example.com", Status: internal.StatusAccepted}, nil)
+
+	req := routedRequest(http.MethodPut, "/events/event/attendees/ada%40example.com/rsvp", `{"status": "accepted"}`,
+		map[string]string{"id": "event", "email": "ada%40example.com"})
+	w := httptest.NewRecorder()
+
+	s.handler.RSVP(w, req)
+
+	require.Equal(s.T(), http.StatusOK, w.Code)
+	require.Contains(s.T(), w.Body.String(), `"status":"accepted"`)
+}
+
+func (s *HandlerTestSuite) TestRemoveAttendee_NotFound() {
+	s.mockAttendees.EXPECT().
+		RemoveAttendee(gomock.Any(), "event", "ada@example.com").
+		Return(fmt.Errorf("attendee not found: %w", internal.ErrNotFound))
+
+	req := routedRequest(http.MethodDelete, "/events/event/attendees/ada@example.com", "",
+		map[string]string{"id": "event", "email": "ada@example.com"})
+	w := httptest.NewRecorder()
+
+	s.handler.RemoveAttendee(w, req)
+
+	require.Equal(s.T(), http.StatusNotFound, w.Code)
+}
+
+func (s *HandlerTestSuite) TestCreateCalendar_Success() {
+	createdAt := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+
+	s.mockCalendars.EXPECT().
+		CreateCalendar(gomock.Any(), internal.Calendar{Name: "Work", Description: "Team events"}).
+		Return(internal.Calendar{ID: "work", Name: "Work", Description: "Team events", CreatedAt: createdAt}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/calendars", strings.NewReader(`{"name": "Work", "description": "Team events"}`))
+	w := httptest.NewRecorder()
+
+	s.handler.CreateCalendar(w, req)
+
+	require.Equal(s.T(), http.StatusCreated, w.Code)
+	require.JSONEq(s.T(), `{"id": "work", "name": "Work", "description": "Team events", "created_at": "2025-12-01T09:00:00Z"}`, w.Body.String())
+}
+
+func (s *HandlerTestSuite) TestGetCalendars_Success() {
+	s.mockCalendars.EXPECT().
+		GetCalendars(gomock.Any()).
+		Return([]internal.Calendar{{ID: internal.DefaultCalendarID, Name: "Default"}, {ID: "work", Name: "Work"}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/calendars", nil)
+	w := httptest.NewRecorder()
+
+	s.handler.GetCalendars(w, req)
+
+	require.Equal(s.T(), http.StatusOK, w.Code)
+	require.Contains(s.T(), w.Body.String(), `"id":"default"`)
+	require.Contains(s.T(), w.Body.String(), `"id":"work"`)
+}
+
+func (s *HandlerTestSuite) TestUpdateCalendar_InvalidName() {
+	s.mockCalendars.EXPECT().
+		UpdateCalendar(gomock.Any(), "work", internal.Calendar{}).
+		Return(internal.Calendar{}, fmt.Errorf("empty name: %w", internal.ErrInput))
+
+	req := routedRequest(http.MethodPut, "/calendars/work", `{}`, map[string]string{"cid": "work"})
+	w := httptest.NewRecorder()
+
+	s.handler.UpdateCalendar(w, req)
+
+	require.Equal(s.T(), http.StatusBadRequest, w.Code)
+}
+
+func (s *HandlerTestSuite) TestDeleteCalendar_StillHasEvents() {
+	s.mockCalendars.EXPECT().
+		DeleteCalendar(gomock.Any(), "work").
+		Return(fmt.Errorf("calendar still has events: %w", internal.ErrConflict))
+
+	req := routedRequest(http.MethodDelete, "/calendars/work", "", map[string]string{"cid": "work"})
+	w := httptest.NewRecorder()
+
+	s.handler.DeleteCalendar(w, req)
+
+	require.Equal(s.T(), http.StatusConflict, w.Code)
+	require.Contains(s.T(), w.Body.String(), "calendar still has events")
+}
+
+func (s *HandlerTestSuite) TestGetCalendar_NotFound() {
+	s.mockCalendars.EXPECT().
+		GetCalendar(gomock.Any(), "missing").
+		Return(internal.Calendar{}, fmt.Errorf("calendar not found: %w", internal.ErrNotFound))
+
+	req := routedRequest(http.MethodGet, "/calendars/missing", "", map[string]string{"cid": "missing"})
+	w := httptest.NewRecorder()
+
+	s.handler.GetCalendar(w, req)
+
+	require.Equal(s.T(), http.StatusNotFound, w.Code)
+}
+
+func (s *HandlerTestSuite) TestGetEvents_InCalendar() {
+	s.mockService.EXPECT().
+		GetEvents(gomock.Any(), internal.EventFilter{CalendarID: "work"}, internal.PageRequest{}).
+		Return(internal.EventsPage{Events: []internal.CreateEventResponse{{ID: "event", CalendarID: "work"}}}, nil)
+
+	req := routedRequest(http.MethodGet, "/calendars/work/events", "", map[string]string{"cid": "work"})
+	w := httptest.NewRecorder()
+
+	s.handler.GetEvents(w, req)
+
+	require.Equal(s.T(), http.StatusOK, w.Code)
+	require.Contains(s.T(), w.Body.String(), `"calendar_id":"work"`)
+}
+
+func (s *HandlerTestSuite) TestGetEvents_UnknownCalendar() {
+	s.mockService.EXPECT().
+		GetEvents(gomock.Any(), internal.EventFilter{CalendarID: "missing"}, internal.PageRequest{}).
+		Return(internal.EventsPage{}, fmt.Errorf("getting calendar: calendar not found: %w", internal.ErrNotFound))
+
+	req := routedRequest(http.MethodGet, "/calendars/missing/events", "", map[string]string{"cid": "missing"})
+	w := httptest.NewRecorder()
+
+	s.handler.GetEvents(w, req)
+
+	require.Equal(s.T(), http.StatusNotFound, w.Code)
+	require.Contains(s.T(), w.Body.String(), "calendar not found")
+}
+
+func (s *HandlerTestSuite) TestTenant_KeepsContextTenant() {
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	req = req.WithContext(internal.WithTenant(req.Context(), "acme"))
+	w := httptest.NewRecorder()
+
+	var tenantID string
+
+	Tenant(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		tenantID, _ = internal.TenantFromContext(r.Context())
+	})).ServeHTTP(w, req)
+
+	require.Equal(s.T(), "acme", tenantID)
+}
+
+func (s *HandlerTestSuite) TestTenant_Missing() {
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	req = req.WithContext(internal.WithAdmin(internal.WithActor(req.Context(), "auth0|ada")))
+	w := httptest.NewRecorder()
+
+	Tenant(http.HandlerFunc(s.handler.GetEvents)).ServeHTTP(w, req)
+
+	require.Equal(s.T(), http.StatusUnauthorized, w.Code)
+	require.Equal(s.T(), "credentials have no tenant", s.problem(w).Detail)
+}
+
+func testVerifier(t require.TestingT) (*auth.Verifier, func(claims jwt.MapClaims) string) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+
+	keys, err := auth.ParseKeySet([]byte(fmt.Sprintf(`{"keys": [{"kty": "oct", "k": %q}]}`, base64.RawURLEncoding.EncodeToString(secret))))
+	require.NoError(t, err)
+
+	sign := func(claims jwt.MapClaims) string {
+		claims["iss"], claims["aud"], claims["exp"] = "issuer", "events-api", time.Now().Add(time.Hour).Unix()
+
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+		require.NoError(t, err)
+
+		return token
+	}
+
+	return auth.NewVerifier(keys, auth.Config{Issuer: "issuer", Audience: "events-api"}), sign
+}
+
+func (s *HandlerTestSuite) TestAuthenticate_SetsActorAndTenant() {
+	verifier, sign := testVerifier(s.T())
+
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	req.Header.Set("Authorization", "Bearer "+sign(jwt.MapClaims{"sub": "auth0|ada", "tenant": "acme"}))
+	w := httptest.NewRecorder()
+
+	var actor, tenantID string
+
+	Authenticate(verifier, s.mockAPIKeys)(Tenant(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		actor = internal.ActorFromContext(r.Context())
+		tenantID, _ = internal.TenantFromContext(r.Context())
+	}))).ServeHTTP(w, req)
+
+	require.Equal(s.T(), http.StatusOK, w.Code)
+	require.Equal(s.T(), "auth0|ada", actor)
+	require.Equal(s.T(), "acme", tenantID)
+}
+
+func (s *HandlerTestSuite) TestAuthenticate_Rejects() {
+	verifier, sign := testVerifier(s.T())
+
+	for name, header := range map[string]string{
+		"missing":        "",
+		"basic":          "Basic YWRhOnNlY3JldA==",
+		"garbage":        "Bearer not-a-token",
+		"invalid tenant": "Bearer " + sign(jwt.MapClaims{"sub": "auth0|ada", "tenant": "acme corp"}),
+		"no tenant":      "Bearer " + sign(jwt.MapClaims{"sub": "auth0|ada"}),
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/events", nil)
+		req.Header.Set("Authorization", header)
+		w := httptest.NewRecorder()
+
+		Authenticate(verifier, s.mockAPIKeys)(http.HandlerFunc(s.handler.GetEvents)).ServeHTTP(w, req)
+
+		require.Equal(s.T(), http.StatusUnauthorized, w.Code, name)
+		require.Contains(s.T(), w.Header().Get("WWW-Authenticate"), "Bearer", name)
+	}
+}
+
+func (s *HandlerTestSuite) TestAuthenticate_AdminTokenWithoutTenant() {
+	verifier, sign := testVerifier(s.T())
+
+	// An admin of no tenant is not an admin of any.
+	for _, path := range []string{"/events", "/admin/api-keys"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer "+sign(jwt.MapClaims{"sub": "auth0|root", "admin": true}))
+		w := httptest.NewRecorder()
+
+		Authenticate(verifier, s.mockAPIKeys)(Tenant(http.HandlerFunc(s.handler.GetEvents))).ServeHTTP(w, req)
+
+		require.Equal(s.T(), http.StatusUnauthorized, w.Code, path)
+	}
+}
+
+func (s *HandlerTestSuite) TestGetEventByID_CreatedBy() {
+	eventID := "123e4567-e89b-12d3-a456-426614174000"
+
+	s.mockService.EXPECT().
+		GetEventByID(gomock.Any(), eventID, false).
+		Return(internal.CreateEventResponse{ID: eventID, TimeZone: "UTC", CreatedBy: "auth0|ada"}, nil)
+	s.mockAttendees.EXPECT().CountAttendees(gomock.Any(), eventID).Return(nil, nil)
+
+	req := routedRequest(http.MethodGet, "/events/"+eventID, "", map[string]string{"id": eventID})
+	w := httptest.NewRecorder()
+
+	s.handler.GetEventByID(w, req)
+
+	require.Equal(s.T(), http.StatusOK, w.Code)
+	require.Contains(s.T(), w.Body.String(), `"created_by":"auth0|ada"`)
+}
+
+func (s *HandlerTestSuite) TestAuthenticate_APIKey() {
+	verifier, _ := testVerifier(s.T())
+	key := internal.APIKey{ID: "key", TenantID: "acme", Scopes: []internal.Scope{internal.ScopeEventsRead}}
+
+	s.mockAPIKeys.EXPECT().AuthenticateAPIKey(gomock.Any(), "evk.acme.secret").Return(key, nil).Times(2)
+
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	req.Header.Set("Authorization", "Bearer evk.acme.secret")
+	w := httptest.NewRecorder()
+
+	var actor, tenantID string
+
+	Authenticate(verifier, s.mockAPIKeys)(Tenant(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		actor = internal.ActorFromContext(r.Context())
+		tenantID, _ = internal.TenantFromContext(r.Context())
+	}))).ServeHTTP(w, req)
+
+	require.Equal(s.T(), http.StatusOK, w.Code)
+	require.Equal(s.T(), "apikey:key", actor)
+	require.Equal(s.T(), "acme", tenantID)
+
+	req = httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(`{}`))
+	req.Header.Set("Authorization", "Bearer evk.acme.secret")
+	w = httptest.NewRecorder()
+
+	Authenticate(verifier, s.mockAPIKeys)(http.HandlerFunc(s.handler.CreateEvent)).ServeHTTP(w, req)
+
+	require.Equal(s.T(), http.StatusForbidden, w.Code)
+	require.Contains(s.T(), w.Header().Get("WWW-Authenticate"), `error="insufficient_scope"`)
+}
+
+func (s *HandlerTestSuite) TestAuthenticate_APIKeyReadRoutes() {
+	verifier, _ := testVerifier(s.T())
+
+	for scope, code := range map[internal.Scope]int{
+		internal.ScopeEventsRead:  http.StatusOK,
+		internal.ScopeEventsWrite: http.StatusForbidden,
+	} {
+		key := internal.APIKey{ID: "key", TenantID: "acme", Scopes: []internal.Scope{scope}}
+		s.mockAPIKeys.EXPECT().AuthenticateAPIKey(gomock.Any(), "evk.acme.secret").Return(key, nil)
+
+		// Searching availability only reads, although it is a POST.
+		req := httptest.NewRequest(http.MethodPost, "/availability/search", strings.NewReader(`{"resources": ["room-a"]}`))
+		req.Header.Set("Authorization", "Bearer evk.acme.secret")
+		w := httptest.NewRecorder()
+
+		Authenticate(verifier, s.mockAPIKeys)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})).ServeHTTP(w, req)
+
+		require.Equal(s.T(), code, w.Code, scope)
+	}
+}
+
+func (s *HandlerTestSuite) TestAuthenticate_APIKeyRejected() {
+	verifier, _ := testVerifier(s.T())
+
+	s.mockAPIKeys.EXPECT().
+		AuthenticateAPIKey(gomock.Any(), "evk.acme.revoked").
+		Return(internal.APIKey{}, fmt.Errorf("api key was revoked: %w", internal.ErrUnauthorized))
+
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	req.Header.Set("Authorization", "Bearer evk.acme.revoked")
+	w := httptest.NewRecorder()
+
+	Authenticate(verifier, s.mockAPIKeys)(http.HandlerFunc(s.handler.GetEvents)).ServeHTTP(w, req)
+
+	require.Equal(s.T(), http.StatusUnauthorized, w.Code)
+	require.Contains(s.T(), w.Header().Get("WWW-Authenticate"), `error="invalid_token"`)
+}
+
+func (s *HandlerTestSuite) TestRequireAdmin() {
+	verifier, sign := testVerifier(s.T())
+
+	for name, test := range map[string]struct {
+		claims jwt.MapClaims
+		code   int
+	}{
+		"admin":     {jwt.MapClaims{"sub": "auth0|ada", "tenant": "acme", "admin": true}, http.StatusOK},
+		"not admin": {jwt.MapClaims{"sub": "auth0|bob", "tenant": "acme"}, http.StatusForbidden},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/admin/api-keys", nil)
+		req.Header.Set("Authorization", "Bearer "+sign(test.claims))
+		w := httptest.NewRecorder()
+
+		Authenticate(verifier, s.mockAPIKeys)(RequireAdmin(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))).ServeHTTP(w, req)
+
+		require.Equal(s.T(), test.code, w.Code, name)
+	}
+}
+
+func (s *HandlerTestSuite) TestCreateAPIKey_Success() {
+	s.mockAPIKeys.EXPECT().
+		CreateAPIKey(gomock.Any(), internal.APIKey{Name: "sync", Scopes: []internal.Scope{internal.ScopeEventsRead}}).
+		Return(internal.APIKey{ID: "key", Name: "sync", Prefix: "evk.acme.abcdefg", Scopes: []internal.Scope{internal.ScopeEventsRead}},
+			"evk.acme.abcdefgsecret", nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/api-keys", strings.NewReader(`{"name": "sync", "scopes": ["events:read"]}`))
+	w := httptest.NewRecorder()
+
+	s.handler.CreateAPIKey(w, req)
+
+	require.Equal(s.T(), http.StatusCreated, w.Code)
+	require.Contains(s.T(), w.Body.String(), `"key":"evk.acme.abcdefgsecret"`)
+	require.Contains(s.T(), w.Body.String(), `"prefix":"evk.acme.abcdefg"`)
+}
+
+func (s *HandlerTestSuite) TestCreateAPIKey_InvalidInput() {
+	s.mockAPIKeys.EXPECT().
+		CreateAPIKey(gomock.Any(), gomock.Any()).
+		Return(internal.APIKey{}, "", fmt.Errorf("a key needs at least one scope: %w", internal.ErrInput))
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/api-keys", strings.NewReader(`{"name": "sync"}`))
+	w := httptest.NewRecorder()
+
+	s.handler.CreateAPIKey(w, req)
+
+	require.Equal(s.T(), http.StatusBadRequest, w.Code)
+	require.Contains(s.T(), w.Body.String(), "at least one scope")
+}
+
+func (s *HandlerTestSuite) TestGetAPIKeys_HidesKeys() {
+	s.mockAPIKeys.EXPECT().
+		GetAPIKeys(gomock.Any()).
+		Return([]internal.APIKey{{ID: "key", Name: "sync", Prefix: "evk.acme.abcdefg"}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/api-keys", nil)
+	w := httptest.NewRecorder()
+
+	s.handler.GetAPIKeys(w, req)
+
+	require.Equal(s.T(), http.StatusOK, w.Code)
+	require.Contains(s.T(), w.Body.String(), `"api_keys":[{"id":"key"`)
+	require.NotContains(s.T(), w.Body.String(), `"key":`)
+}
+
+func (s *HandlerTestSuite) TestRevokeAPIKey() {
+	s.mockAPIKeys.EXPECT().RevokeAPIKey(gomock.Any(), "key").Return(nil)
+	s.mockAPIKeys.EXPECT().RevokeAPIKey(gomock.Any(), "missing").Return(fmt.Errorf("api key not found: %w", internal.ErrNotFound))
+
+	for id, code := range map[string]int{"key": http.StatusNoContent, "missing": http.StatusNotFound} {
+		req := routedRequest(http.MethodDelete, "/admin/api-keys/"+id, "", map[string]string{"kid": id})
+		w := httptest.NewRecorder()
+
+		s.handler.RevokeAPIKey(w, req)
+
+		require.Equal(s.T(), code, w.Code, id)
+	}
+}
+
+func (s *HandlerTestSuite) TestGetCalendarGrants_Success() {
+	now := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+
+	s.mockGrants.EXPECT().
+		GetGrants(gomock.Any(), internal.ObjectCalendar, "team").
+		Return([]internal.Grant{{ObjectType: internal.ObjectCalendar, ObjectID: "team", Principal: "auth0|bob",
+			Role: internal.AccessViewer, GrantedBy: "auth0|ada", CreatedAt: now}}, nil)
+
+	req := routedRequest(http.MethodGet, "/calendars/team/grants", "", map[string]string{"cid": "team"})
+	w := httptest.NewRecorder()
+
+	s.handler.GetCalendarGrants(w, req)
+
+	require.Equal(s.T(), http.StatusOK, w.Code)
+	require.JSONEq(s.T(), `{"grants": [{"principal": "auth0|bob", "role": "viewer", "granted_by": "auth0|ada",
+		"created_at": "2025-12-01T09:00:00Z"}]}`, w.Body.String())
+}
+
+func (s *HandlerTestSuite) TestSetEventGrant_Success() {
+	now := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	grant := internal.Grant{ObjectType: internal.ObjectEvent, ObjectID: "event", Principal: "auth0|bob", Role: internal.AccessEditor}
+
+	s.mockGrants.EXPECT().
+		SetGrant(gomock.Any(), grant).
+		DoAndReturn(func(_ context.Context, grant internal.Grant) (internal.Grant, error) {
+			grant.GrantedBy, grant.CreatedAt = "auth0|ada", now
+			return grant, nil
+		})
+
+	req := routedRequest(http.MethodPut, "/events/event/grants/auth0%7Cbob", `{"role": "editor"}`,
+		map[string]string{"id": "event", "principal": "auth0%7Cbob"})
+	w := httptest.NewRecorder()
+
+	s.handler.SetEventGrant(w, req)
+
+	require.Equal(s.T(), http.StatusOK, w.Code)
+	require.JSONEq(s.T(), `{"principal": "auth0|bob", "role": "editor", "granted_by": "auth0|ada",
+		"created_at": "2025-12-01T09:00:00Z"}`, w.Body.String())
+}
+
+func (s *HandlerTestSuite) TestSetCalendarGrant_Errors() {
+	for _, test := range []struct {
+		err  error
+		code int
+	}{
+		{fmt.Errorf("role should be owner, editor or viewer: %w", internal.ErrInput), http.StatusBadRequest},
+		{fmt.Errorf(`owner role on calendar "team" required: %w`, internal.ErrForbidden), http.StatusForbidden},
+		{fmt.Errorf("calendar not found: %w", internal.ErrNotFound), http.StatusNotFound},
+		{errors.New("connection refused"), http.StatusInternalServerError},
+	} {
+		s.mockGrants.EXPECT().SetGrant(gomock.Any(), gomock.Any()).Return(internal.Grant{}, test.err)
+
+		req := routedRequest(http.MethodPut, "/calendars/team/grants/bob", `{"role": "viewer"}`,
+			map[string]string{"cid": "team", "principal": "bob"})
+		w := httptest.NewRecorder()
+
+		s.handler.SetCalendarGrant(w, req)
+
+		require.Equal(s.T(), test.code, w.Code, test.err.Error())
+	}
+
+	req := routedRequest(http.MethodPut, "/calendars/team/grants/bob", `{"role": `, map[string]string{"cid": "team", "principal": "bob"})
+	w := httptest.NewRecorder()
+
+	s.handler.SetCalendarGrant(w, req)
+
+	require.Equal(s.T(), http.StatusBadRequest, w.Code)
+}
+
+func (s *HandlerTestSuite) TestDeleteGrant() {
+	s.mockGrants.EXPECT().DeleteGrant(gomock.Any(), internal.ObjectCalendar, "team", "auth0|bob").Return(nil)
+	s.mockGrants.EXPECT().
+		DeleteGrant(gomock.Any(), internal.ObjectEvent, "event", "auth0|bob").
+		Return(fmt.Errorf("grant not found: %w", internal.ErrNotFound))
+
+	req := routedRequest(http.MethodDelete, "/calendars/team/grants/auth0%7Cbob", "",
+		map[string]string{"cid": "team", "principal": "auth0%7Cbob"})
+	w := httptest.NewRecorder()
+
+	s.handler.DeleteCalendarGrant(w, req)
+
+	require.Equal(s.T(), http.StatusNoContent, w.Code)
+
+	req = routedRequest(http.MethodDelete, "/events/event/grants/auth0%7Cbob", "",
+		map[string]string{"id": "event", "principal": "auth0%7Cbob"})
+	w = httptest.NewRecorder()
+
+	s.handler.DeleteEventGrant(w, req)
+
+	require.Equal(s.T(), http.StatusNotFound, w.Code)
+
+	req = routedRequest(http.MethodDelete, "/events/event/grants/bob", "", map[string]string{"id": "event", "principal": "%zz"})
+	w = httptest.NewRecorder()
+
+	s.handler.DeleteEventGrant(w, req)
+
+	require.Equal(s.T(), http.StatusBadRequest, w.Code)
+}
+
+func (s *HandlerTestSuite) TestForbidden() {
+	forbidden := fmt.Errorf(`editor role on event "event" required: %w`, internal.ErrForbidden)
+
+	s.mockService.EXPECT().DeleteEvent(gomock.Any(), "event").Return(forbidden)
+	s.mockService.EXPECT().GetEventByID(gomock.Any(), "event", false).Return(internal.CreateEventResponse{}, forbidden)
+	s.mockAttendees.EXPECT().GetAttendees(gomock.Any(), "event").Return(nil, forbidden)
+	s.mockCalendars.EXPECT().DeleteCalendar(gomock.Any(), "team").Return(forbidden)
+
+	for name, serve := range map[string]func(w http.ResponseWriter){
+		"delete event": func(w http.ResponseWriter) {
+			s.handler.DeleteEvent(w, routedRequest(http.MethodDelete, "/events/event", "", map[string]string{"id": "event"}))
+		},
+		"get event": func(w http.ResponseWriter) {
+			s.handler.GetEventByID(w, routedRequest(http.MethodGet, "/events/event", "", map[string]string{"id": "event"}))
+		},
+		"get attendees": func(w http.ResponseWriter) {
+			s.handler.GetAttendees(w, routedRequest(http.MethodGet, "/events/event/attendees", "", map[string]string{"id": "event"}))
+		},
+		"delete calendar": func(w http.ResponseWriter) {
+			s.handler.DeleteCalendar(w, routedRequest(http.MethodDelete, "/calendars/team", "", map[string]string{"cid": "team"}))
+		},
+	} {
+		w := httptest.NewRecorder()
+
+		serve(w)
+
+		require.Equal(s.T(), http.StatusForbidden, w.Code, name)
+		require.Contains(s.T(), w.Body.String(), "editor role", name)
+	}
+}
+
+func (s *HandlerTestSuite) TestWriteError() {
+	for _, test := range []struct {
+		err    error
+		status int
+		typ    string
+		detail string
+	}{
+		{fmt.Errorf("empty id: %w", internal.ErrInput), http.StatusBadRequest, "/problems/invalid-input", "empty id: missing input values"},
+		{fmt.Errorf("no actor: %w", internal.ErrForbidden), http.StatusForbidden, "/problems/forbidden", "no actor: forbidden"},
+		{fmt.Errorf("getting event: %w", internal.ErrNotFound), http.StatusNotFound, "/problems/not-found", "event not found"},
+		{fmt.Errorf("already invited: %w", internal.ErrConflict), http.StatusConflict, "/problems/conflict", "already invited: conflict"},
+		{fmt.Errorf("key %q: %w", "k", internal.ErrKeyReused), http.StatusUnprocessableEntity, "/problems/idempotency-key-reused",
+			`key "k": idempotency key reused`},
+		{fmt.Errorf("event is at version 4, not 3: %w", internal.ErrVersionMismatch), http.StatusPreconditionFailed, "/problems/precondition-failed",
+			"event is at version 4, not 3: version mismatch"},
+		{errors.New("connection refused"), http.StatusInternalServerError, "/problems/internal", "error getting event: connection refused"},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/events/event", nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.RequestIDKey, "host/abc-000001"))
+		w := httptest.NewRecorder()
+
+		writeError(w, req, test.err, "event not found", "error getting event")
+
+		p := s.problem(w)
+		require.Equal(s.T(), test.status, p.Status, test.err.Error())
+		require.Equal(s.T(), test.typ, p.Type, test.err.Error())
+		require.Equal(s.T(), test.detail, p.Detail, test.err.Error())
+		require.NotEmpty(s.T(), p.Title)
+		require.Equal(s.T(), "host/abc-000001", p.RequestID)
+	}
+}
+
+func (s *HandlerTestSuite) TestWriteStatus_UntypedStatus() {
+	// Only a reused idempotency key is typed, not every 422.
+	req := httptest.NewRequest(http.MethodPost, "/events", nil)
+	w := httptest.NewRecorder()
+
+	writeStatus(w, req, http.StatusUnprocessableEntity, "cannot process")
+
+	p := s.problem(w)
+	require.Equal(s.T(), http.StatusUnprocessableEntity, p.Status)
+	require.Equal(s.T(), "about:blank", p.Type)
+	require.Equal(s.T(), "Unprocessable Entity", p.Title)
+}
+
+func (s *HandlerTestSuite) TestCreateEvent_FieldErrors() {
+	for body, field := range map[string]string{
+		`{"title": "Standup", "capacity": "ten"}`: "capacity",
+		`{"title": 42}`: "title",
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(body))
+		w := httptest.NewRecorder()
+
+		s.handler.CreateEvent(w, req)
+
+		p := s.problem(w)
+		require.Equal(s.T(), http.StatusBadRequest, p.Status, body)
+		require.Len(s.T(), p.Errors, 1, body)
+		require.Equal(s.T(), field, p.Errors[0].Field, body)
+	}
+}
+
+// idempotentRequest runs a POST /events with key through Idempotent and
+// CreateEvent.
+func (s *HandlerTestSuite) idempotentRequest(key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+
+	w := httptest.NewRecorder()
+
+	Idempotent(s.mockKeys)(http.HandlerFunc(s.handler.CreateEvent)).ServeHTTP(w, req)
+
+	return w
+}
+
+func (s *HandlerTestSuite) TestIdempotent_WithoutKey() {
+	s.mockService.EXPECT().
+		CreateEvent(gomock.Any(), gomock.Any()).
+		Return(internal.CreateEventResponse{ID: "e1"}, nil)
+
+	w := s.idempotentRequest("", `{"title": "Standup"}`)
+
+	require.Equal(s.T(), http.StatusCreated, w.Code)
+}
+
+func (s *HandlerTestSuite) TestIdempotent_StoresCreated() {
+	var first []byte
+
+	s.mockKeys.EXPECT().
+		Begin(gomock.Any(), "retry-1", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, fingerprint []byte) (internal.IdempotencyRecord, bool, error) {
+			first = fingerprint
+			return internal.IdempotencyRecord{}, false, nil
+		})
+	s.mockService.EXPECT().
+		CreateEvent(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, event internal.CreateEventRequest) (internal.CreateEventResponse, error) {
+			require.Equal(s.T(), "Standup", event.Title)
+			return internal.CreateEventResponse{ID: "e1", Title: event.Title}, nil
+		})
+
+	var stored []byte
+
+	s.mockKeys.EXPECT().
+		Complete(gomock.Any(), "retry-1", http.StatusCreated, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ int, body []byte) error {
+			stored = body
+			return nil
+		})
+
+	w := s.idempotentRequest("retry-1", `{"title": "Standup"}`)
+
+	require.Equal(s.T(), http.StatusCreated, w.Code)
+	require.Equal(s.T(), w.Body.Bytes(), stored)
+
+	s.mockKeys.EXPECT().
+		Begin(gomock.Any(), "retry-1", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, fingerprint []byte) (internal.IdempotencyRecord, bool, error) {
+			require.NotEqual(s.T(), first, fingerprint)
+			return internal.IdempotencyRecord{}, false, internal.ErrKeyReused
+		})
+
+	w = s.idempotentRequest("retry-1", `{"title": "Retro"}`)
+
+	require.Equal(s.T(), http.StatusUnprocessableEntity, s.problem(w).Status)
+}
+
+func (s *HandlerTestSuite) TestIdempotent_TooLarge() {
+	w := s.idempotentRequest("retry-1", `{"title": "`+strings.Repeat("a", maxIdempotentSize)+`"}`)
+
+	require.Equal(s.T(), http.StatusRequestEntityTooLarge, w.Code)
+}
+
+func (s *HandlerTestSuite) TestIdempotent_Replays() {
+	s.mockKeys.EXPECT().
+		Begin(gomock.Any(), "retry-1", gomock.Any()).
+		Return(internal.IdempotencyRecord{Status: http.StatusCreated, Body: []byte(`{"id":"e1"}`)}, true, nil)
+
+	w := s.idempotentRequest("retry-1", `{"title": "Standup"}`)
+
+	require.Equal(s.T(), http.StatusCreated, w.Code)
+	require.Equal(s.T(), `{"id":"e1"}`, w.Body.String())
+	require.Equal(s.T(), "application/json", w.Header().Get("Content-Type"))
+	require.Equal(s.T(), "true", w.Header().Get("Idempotent-Replayed"))
+}
+
+func (s *HandlerTestSuite) TestIdempotent_ReleasesOnFailure() {
+	s.mockKeys.EXPECT().
+		Begin(gomock.Any(), "retry-1", gomock.Any()).
+		Return(internal.IdempotencyRecord{}, false, nil)
+	s.mockService.EXPECT().
+		CreateEvent(gomock.Any(), gomock.Any()).
+		Return(internal.CreateEventResponse{}, errors.New("connection refused"))
+	s.mockKeys.EXPECT().
+		Release(gomock.Any(), "retry-1").
+		Return(nil)
+
+	w := s.idempotentRequest("retry-1", `{"title": "Standup"}`)
+
+	require.Equal(s.T(), http.StatusInternalServerError, w.Code)
+}
+
+func (s *HandlerTestSuite) TestIdempotent_InProgress() {
+	s.mockKeys.EXPECT().
+		Begin(gomock.Any(), "retry-1", gomock.Any()).
+		Return(internal.IdempotencyRecord{}, false, fmt.Errorf("a request with idempotency key %q is in progress: %w", "retry-1", internal.ErrConflict))
+
+	w := s.idempotentRequest("retry-1", `{"title": "Standup"}`)
+
+	require.Equal(s.T(), http.StatusConflict, s.problem(w).Status)
+}
+
+func (s *HandlerTestSuite) TestGetEvents_ParamError() {
+	req := httptest.NewRequest(http.MethodGet, "/events?from=yesterday", nil)
+	w := httptest.NewRecorder()
+
+	s.handler.GetEvents(w, req)
+
+	p := s.problem(w)
+	require.Equal(s.T(), http.StatusBadRequest, p.Status)
+	require.Equal(s.T(), []fieldError{{Field: "from", Message: "from should be an RFC 3339 time"}}, p.Errors)
 }
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal"
+	"github.com/go-chi/chi/v5"
+)
+
+type revisionResponse struct {
+	Revision  int                             `json:"revision"`
+	Action    internal.RevisionAction         `json:"action"`
+	Actor     string                          `json:"actor,omitempty"`
+	CreatedAt time.Time                       `json:"created_at"`
+	Changes   map[string]internal.FieldChange `json:"changes"`
+}
+
+func newRevisionResponse(revision internal.EventRevision) revisionResponse {
+	return revisionResponse{
+		Revision:  revision.Revision,
+		Action:    revision.Action,
+		Actor:     revision.Actor,
+		CreatedAt: revision.CreatedAt,
+		Changes:   revision.Changes,
+	}
+}
+
+// GetEventHistory lists who changed an event, when and what (GET /events/{id}/history).
+func (h *Handler) GetEventHistory(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if id == "" {
+		writeStatus(w, r, http.StatusBadRequest, "empty event")
+		return
+	}
+
+	revisions, err := h.eventsService.GetEventHistory(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err, "event not found", "error getting history")
+		return
+	}
+
+	response := struct {
+		Revisions []revisionResponse `json:"revisions"`
+	}{
+		Revisions: make([]revisionResponse, 0, len(revisions)),
+	}
+
+	for _, revision := range revisions {
+		response.Revisions = append(response.Revisions, newRevisionResponse(revision))
+	}
+
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+// RevertEvent puts an event back the way it was at a revision
+// (POST /events/{id}/history/{revision}/revert). Like any update it needs If-Match.
+func (h *Handler) RevertEvent(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if id == "" {
+		writeStatus(w, r, http.StatusBadRequest, "empty event")
+		return
+	}
+
+	revision, err := strconv.Atoi(chi.URLParam(r, "revision"))
+	if err != nil || revision <= 0 {
+		writeStatus(w, r, http.StatusBadRequest, "revision should be a positive integer")
+		return
+	}
+
+	version, ok := ifMatchVersion(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.eventsService.RevertEvent(r.Context(), id, revision, version)
+	if err != nil {
+		writeError(w, r, err, "event or revision not found", "error reverting event")
+		return
+	}
+
+	w.Header().Set("ETag", eventETag(result.Version))
+
+	writeJSON(w, r, http.StatusOK, newEventResponse(result, renderLocation(r)))
+}
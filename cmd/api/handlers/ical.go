@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal"
+	"github.com/ObiaNzk/LTK-test-manu/internal/ical"
+	"github.com/go-chi/chi/v5"
+)
+
+const calendarContentType = "text/calendar; charset=utf-8"
+
+// maxImportSize bounds the body of an import.
+const maxImportSize = 5 << 20
+
+// A feed requested without from starts exportPast before now, or before to
+// when that is earlier. It holds at most maxExportEvents events, occurrences of
+// series included.
+const (
+	exportPast      = 30 * 24 * time.Hour
+	maxExportEvents = 5000
+)
+
+type importItem struct {
+	UID    string `json:"uid"`
+	ID     string `json:"id,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ExportEvents renders the event listing as an iCalendar feed (GET /events.ics),
+// or the events of one calendar when nested (GET /calendars/{cid}/events.ics).
+// It takes the same filters as GET /events and walks every page, so calendar
+// clients can subscribe to it. Without from, events that ended long ago are left
+// out, and a feed of more than maxExportEvents events is rejected.
+func (h *Handler) ExportEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter, err := parseEventFilter(r)
+	if err != nil {
+		writeParamError(w, r, err)
+		return
+	}
+
+	filter = exportWindow(filter, time.Now())
+
+	var events []ical.Event
+
+	page := internal.PageRequest{Limit: internal.MaxPageSize}
+
+	for {
+		result, err := h.eventsService.GetEvents(ctx, filter, page)
+		if err != nil {
+			writeError(w, r, err, "calendar not found", "error getting events")
+			return
+		}
+
+		events = append(events, newICalEvents(result.Events, result.Exceptions)...)
+
+		if len(events) > maxExportEvents {
+			writeStatus(w, r, http.StatusBadRequest, fmt.Sprintf("the feed has more than %d events, narrow it with from and to", maxExportEvents))
+			return
+		}
+
+		if result.NextCursor == "" {
+			break
+		}
+
+		page.Cursor = result.NextCursor
+	}
+
+	writeCalendar(w, r, events)
+}
+
+// exportWindow defaults the start of the window of filter, in the zone the
+// filter is in, which all-day events are matched in. The end stays open, so a
+// feed without to keeps its series whole, with their RRULE.
+func exportWindow(filter internal.EventFilter, now time.Time) internal.EventFilter {
+	if !filter.From.IsZero() {
+		return filter
+	}
+
+	end := now
+	if !filter.To.IsZero() && filter.To.Before(now) {
+		end = filter.To
+	}
+
+	filter.From = end.Add(-exportPast).In(filter.From.Location())
+
+	return filter
+}
+
+// ExportEvent renders a single event as iCalendar (GET /events/{id}.ics).
+func (h *Handler) ExportEvent(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	if id == "" {
+		writeStatus(w, r, http.StatusBadRequest, "empty event")
+		return
+	}
+
+	event, err := h.eventsService.GetEventByID(ctx, id, false)
+	if err != nil {
+		writeError(w, r, err, "event not found", "error getting event")
+		return
+	}
+
+	var exceptions []internal.OccurrenceException
+
+	if event.RRule != "" || len(event.RDates) > 0 {
+		exceptions, err = h.eventsService.GetOccurrenceExceptions(ctx, id)
+		if err != nil {
+			writeError(w, r, err, "event not found", "error getting event")
+			return
+		}
+	}
+
+	writeCalendar(w, r, newICalEvents([]internal.CreateEventResponse{event}, exceptions))
+}
+
+// ImportEvents upserts the VEVENTs of an iCalendar file by UID (POST /events/import)
+// and reports what happened to each of them.
+func (h *Handler) ImportEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "text/calendar" {
+		writeStatus(w, r, http.StatusUnsupportedMediaType, "content type should be text/calendar")
+		return
+	}
+
+	defer r.Body.Close()
+
+	decoded, err := ical.Decode(http.MaxBytesReader(w, r.Body, maxImportSize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeStatus(w, r, http.StatusRequestEntityTooLarge, "calendar is too large")
+			return
+		}
+
+		writeStatus(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	events := make([]internal.ImportedEvent, 0, len(decoded))
+
+	for _, item := range decoded {
+		if item.Err == nil && item.Event.RecurrenceID != nil {
+			item.Err = errors.New("overridden occurrences are not supported")
+		}
+
+		events = append(events, internal.ImportedEvent{
+			UID: item.Event.UID,
+			Event: internal.CreateEventRequest{
+				Title:       item.Event.Summary,
+				Description: item.Event.Description,
+				StartTime:   item.Event.Start,
+				EndTime:     item.Event.End,
+				RRule:       item.Event.RRule,
+				ExDates:     item.Event.ExDates,
+				RDates:      item.Event.RDates,
+				AllDay:      item.Event.AllDay,
+			},
+			Err: item.Err,
+		})
+	}
+
+	results, err := h.eventsService.ImportEvents(ctx, events)
+	if err != nil {
+		writeError(w, r, err, "event not found", "error importing events")
+		return
+	}
+
+	response := struct {
+		Created  int          `json:"created"`
+		Updated  int          `json:"updated"`
+		Rejected int          `json:"rejected"`
+		Events   []importItem `json:"events"`
+	}{
+		Events: make([]importItem, 0, len(results)),
+	}
+
+	for _, result := range results {
+		switch result.Status {
+		case internal.ImportCreated:
+			response.Created++
+		case internal.ImportUpdated:
+			response.Updated++
+		case internal.ImportRejected:
+			response.Rejected++
+		}
+
+		response.Events = append(response.Events, importItem{
+			UID:    result.UID,
+			ID:     result.ID,
+			Status: string(result.Status),
+			Error:  result.Reason,
+		})
+	}
+
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+// newICalEvents maps events to VEVENTs. A series kept whole lists its cancelled
+// occurrences as EXDATEs and is followed by a VEVENT with a RECURRENCE-ID for
+// each occurrence moved or edited.
+func newICalEvents(events []internal.CreateEventResponse, exceptions []internal.OccurrenceException) []ical.Event {
+	bySeries := make(map[string][]internal.OccurrenceException)
+	for _, exception := range exceptions {
+		bySeries[exception.SeriesID] = append(bySeries[exception.SeriesID], exception)
+	}
+
+	result := make([]ical.Event, 0, len(events)+len(exceptions))
+
+	for _, event := range events {
+		var overrides []ical.Event
+
+		exDates := slices.Clone(event.ExDates)
+
+		for _, exception := range bySeries[event.ID] {
+			if exception.Cancelled {
+				exDates = append(exDates, exception.RecurrenceID)
+				continue
+			}
+
+			overrides = append(overrides, newICalEvent(internal.ExceptionOccurrence(event, exception)))
+		}
+
+		event.ExDates = exDates
+
+		result = append(result, newICalEvent(event))
+		result = append(result, overrides...)
+	}
+
+	return result
+}
+
+// newICalEvent maps an event to a VEVENT. An expanded occurrence becomes an
+// instance of its series, without the recurrence rule.
+func newICalEvent(event internal.CreateEventResponse) ical.Event {
+	if event.RecurrenceID != nil {
+		event.RRule, event.ExDates, event.RDates = "", nil, nil
+	}
+
+	return ical.Event{
+		UID:          event.ID,
+		Stamp:        event.CreatedAt,
+		Summary:      event.Title,
+		Description:  event.Description,
+		Start:        event.StartTime,
+		End:          event.EndTime,
+		AllDay:       event.AllDay,
+		RRule:        event.RRule,
+		ExDates:      event.ExDates,
+		RDates:       event.RDates,
+		RecurrenceID: event.RecurrenceID,
+	}
+}
+
+func writeCalendar(w http.ResponseWriter, r *http.Request, events []ical.Event) {
+	var body bytes.Buffer
+
+	if err := ical.Encode(&body, events); err != nil {
+		writeStatus(w, r, http.StatusInternalServerError, "error creating calendar")
+		return
+	}
+
+	w.Header().Set("Content-Type", calendarContentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(body.Bytes())
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal"
+)
+
+//go:generate mockgen -source=idempotency.go -destination=mocks/mock_idempotency_service.go -package=mocks
+
+// maxIdempotentSize bounds the body of a request with an Idempotency-Key,
+// which is read into memory to fingerprint it.
+const maxIdempotentSize = 1 << 20
+
+type idempotencyService interface {
+	Begin(ctx context.Context, key string, fingerprint []byte) (internal.IdempotencyRecord, bool, error)
+	Complete(ctx context.Context, key string, status int, body []byte) error
+	Release(ctx context.Context, key string) error
+}
+
+// Idempotent lets clients retry a request safely by sending an Idempotency-Key
+// header. The first 201 response to a key is stored and replayed, with an
+// Idempotent-Replayed header, to every retry of the same request. A key sent
+// with another method, path or body gets 422, and a body over
+// maxIdempotentSize gets 413. Other responses are not stored, so a retry after
+// them runs again. Requests without the header pass through.
+func Idempotent(keys idempotencyService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get("Idempotency-Key")
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentSize))
+			if err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					writeStatus(w, r, http.StatusRequestEntityTooLarge, "body is too large")
+					return
+				}
+
+				writeStatus(w, r, http.StatusBadRequest, fmt.Sprintf("reading body: %s", err.Error()))
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			record, replay, err := keys.Begin(r.Context(), key, fingerprint(r, body))
+			if err != nil {
+				writeError(w, r, err, "", "Error checking idempotency key")
+				return
+			}
+
+			if replay {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("Idempotent-Replayed", "true")
+				w.WriteHeader(record.Status)
+				w.Write(record.Body)
+
+				return
+			}
+
+			recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+
+			// Runs on panics too, so that a failed request never holds its key.
+			defer func() {
+				ctx := context.WithoutCancel(r.Context())
+
+				if recorder.status != http.StatusCreated {
+					if err := keys.Release(ctx, key); err != nil {
+						log.Printf("idempotency key %q: %v", key, err)
+					}
+
+					return
+				}
+
+				// Left reserved on failure: a retry gets 409 until the key
+				// expires, rather than creating a duplicate.
+				if err := keys.Complete(ctx, key, recorder.status, recorder.body.Bytes()); err != nil {
+					log.Printf("idempotency key %q: %v", key, err)
+				}
+			}()
+
+			next.ServeHTTP(recorder, r)
+		})
+	}
+}
+
+// fingerprint identifies a request by its method, target and body.
+func fingerprint(r *http.Request, body []byte) []byte {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s %s\n", r.Method, r.URL.RequestURI())
+	hash.Write(body)
+
+	return hash.Sum(nil)
+}
+
+// responseRecorder writes through to the client and keeps a copy of the status
+// and body.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+
+	return r.ResponseWriter.Write(b)
+}
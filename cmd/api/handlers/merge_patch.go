@@ -0,0 +1,41 @@
+package handlers
+
+import "encoding/json"
+
+// mergePatch applies an RFC 7396 JSON Merge Patch to the target document.
+func mergePatch(target, patch []byte) ([]byte, error) {
+	var targetValue interface{}
+	if err := json.Unmarshal(target, &targetValue); err != nil {
+		return nil, err
+	}
+
+	var patchValue interface{}
+	if err := json.Unmarshal(patch, &patchValue); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(applyMergePatch(targetValue, patchValue))
+}
+
+func applyMergePatch(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+
+		targetObject[key] = applyMergePatch(targetObject[key], value)
+	}
+
+	return targetObject
+}
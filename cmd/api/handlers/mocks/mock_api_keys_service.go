@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: apikeys.go
+//
+// Generated by this command:
+//
+//	mockgen -source=apikeys.go -destination=mocks/mock_api_keys_service.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	internal "github.com/ObiaNzk/LTK-test-manu/internal"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockapiKeysService is a mock of apiKeysService interface.
+type MockapiKeysService struct {
+	ctrl     *gomock.Controller
+	recorder *MockapiKeysServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockapiKeysServiceMockRecorder is the mock recorder for MockapiKeysService.
+type MockapiKeysServiceMockRecorder struct {
+	mock *MockapiKeysService
+}
+
+// NewMockapiKeysService creates a new mock instance.
+func NewMockapiKeysService(ctrl *gomock.Controller) *MockapiKeysService {
+	mock := &MockapiKeysService{ctrl: ctrl}
+	mock.recorder = &MockapiKeysServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockapiKeysService) EXPECT() *MockapiKeysServiceMockRecorder {
+	return m.recorder
+}
+
+// AuthenticateAPIKey mocks base method.
+func (m *MockapiKeysService) AuthenticateAPIKey(ctx context.Context, token string) (internal.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthenticateAPIKey", ctx, token)
+	ret0, _ := ret[0].(internal.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthenticateAPIKey indicates an expected call of AuthenticateAPIKey.
+func (mr *MockapiKeysServiceMockRecorder) AuthenticateAPIKey(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthenticateAPIKey", reflect.TypeOf((*MockapiKeysService)(nil).AuthenticateAPIKey), ctx, token)
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockapiKeysService) CreateAPIKey(ctx context.Context, key internal.APIKey) (internal.APIKey, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, key)
+	ret0, _ := ret[0].(internal.APIKey)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockapiKeysServiceMockRecorder) CreateAPIKey(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockapiKeysService)(nil).CreateAPIKey), ctx, key)
+}
+
+// GetAPIKeys mocks base method.
+func (m *MockapiKeysService) GetAPIKeys(ctx context.Context) ([]internal.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeys", ctx)
+	ret0, _ := ret[0].([]internal.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeys indicates an expected call of GetAPIKeys.
+func (mr *MockapiKeysServiceMockRecorder) GetAPIKeys(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeys", reflect.TypeOf((*MockapiKeysService)(nil).GetAPIKeys), ctx)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockapiKeysService) RevokeAPIKey(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockapiKeysServiceMockRecorder) RevokeAPIKey(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockapiKeysService)(nil).RevokeAPIKey), ctx, id)
+}
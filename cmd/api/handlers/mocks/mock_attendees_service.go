@@ -0,0 +1,116 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: attendees.go
+//
+// Generated by this command:
+//
+//	mockgen -source=attendees.go -destination=mocks/mock_attendees_service.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	internal "github.com/ObiaNzk/LTK-test-manu/internal"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockattendeesService is a mock of attendeesService interface.
+type MockattendeesService struct {
+	ctrl     *gomock.Controller
+	recorder *MockattendeesServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockattendeesServiceMockRecorder is the mock recorder for MockattendeesService.
+type MockattendeesServiceMockRecorder struct {
+	mock *MockattendeesService
+}
+
+// NewMockattendeesService creates a new mock instance.
+func NewMockattendeesService(ctrl *gomock.Controller) *MockattendeesService {
+	mock := &MockattendeesService{ctrl: ctrl}
+	mock.recorder = &MockattendeesServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockattendeesService) EXPECT() *MockattendeesServiceMockRecorder {
+	return m.recorder
+}
+
+// CountAttendees mocks base method.
+func (m *MockattendeesService) CountAttendees(ctx context.Context, eventID string) (map[internal.AttendeeStatus]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAttendees", ctx, eventID)
+	ret0, _ := ret[0].(map[internal.AttendeeStatus]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAttendees indicates an expected call of CountAttendees.
+func (mr *MockattendeesServiceMockRecorder) CountAttendees(ctx, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAttendees", reflect.TypeOf((*MockattendeesService)(nil).CountAttendees), ctx, eventID)
+}
+
+// GetAttendees mocks base method.
+func (m *MockattendeesService) GetAttendees(ctx context.Context, eventID string) ([]internal.Attendee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAttendees", ctx, eventID)
+	ret0, _ := ret[0].([]internal.Attendee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAttendees indicates an expected call of GetAttendees.
+func (mr *MockattendeesServiceMockRecorder) GetAttendees(ctx, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttendees", reflect.TypeOf((*MockattendeesService)(nil).GetAttendees), ctx, eventID)
+}
+
+// Invite mocks base method.
+func (m *MockattendeesService) Invite(ctx context.Context, attendee internal.Attendee) (internal.Attendee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Invite", ctx, attendee)
+	ret0, _ := ret[0].(internal.Attendee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Invite indicates an expected call of Invite.
+func (mr *MockattendeesServiceMockRecorder) Invite(ctx, attendee any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Invite", reflect.TypeOf((*MockattendeesService)(nil).Invite), ctx, attendee)
+}
+
+// RSVP mocks base method.
+func (m *MockattendeesService) RSVP(ctx context.Context, eventID, email string, status internal.AttendeeStatus) (internal.Attendee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RSVP", ctx, eventID, email, status)
+	ret0, _ := ret[0].(internal.Attendee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RSVP indicates an expected call of RSVP.
+func (mr *MockattendeesServiceMockRecorder) RSVP(ctx, eventID, email, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RSVP", reflect.TypeOf((*MockattendeesService)(nil).RSVP), ctx, eventID, email, status)
+}
+
+// RemoveAttendee mocks base method.
+func (m *MockattendeesService) RemoveAttendee(ctx context.Context, eventID, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveAttendee", ctx, eventID, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveAttendee indicates an expected call of RemoveAttendee.
+func (mr *MockattendeesServiceMockRecorder) RemoveAttendee(ctx, eventID, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAttendee", reflect.TypeOf((*MockattendeesService)(nil).RemoveAttendee), ctx, eventID, email)
+}
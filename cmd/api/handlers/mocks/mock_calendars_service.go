@@ -0,0 +1,116 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: calendars.go
+//
+// Generated by this command:
+//
+//	mockgen -source=calendars.go -destination=mocks/mock_calendars_service.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	internal "github.com/ObiaNzk/LTK-test-manu/internal"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockcalendarsService is a mock of calendarsService interface.
+type MockcalendarsService struct {
+	ctrl     *gomock.Controller
+	recorder *MockcalendarsServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockcalendarsServiceMockRecorder is the mock recorder for MockcalendarsService.
+type MockcalendarsServiceMockRecorder struct {
+	mock *MockcalendarsService
+}
+
+// NewMockcalendarsService creates a new mock instance.
+func NewMockcalendarsService(ctrl *gomock.Controller) *MockcalendarsService {
+	mock := &MockcalendarsService{ctrl: ctrl}
+	mock.recorder = &MockcalendarsServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockcalendarsService) EXPECT() *MockcalendarsServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateCalendar mocks base method.
+func (m *MockcalendarsService) CreateCalendar(ctx context.Context, calendar internal.Calendar) (internal.Calendar, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCalendar", ctx, calendar)
+	ret0, _ := ret[0].(internal.Calendar)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCalendar indicates an expected call of CreateCalendar.
+func (mr *MockcalendarsServiceMockRecorder) CreateCalendar(ctx, calendar any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCalendar", reflect.TypeOf((*MockcalendarsService)(nil).CreateCalendar), ctx, calendar)
+}
+
+// DeleteCalendar mocks base method.
+func (m *MockcalendarsService) DeleteCalendar(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCalendar", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCalendar indicates an expected call of DeleteCalendar.
+func (mr *MockcalendarsServiceMockRecorder) DeleteCalendar(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCalendar", reflect.TypeOf((*MockcalendarsService)(nil).DeleteCalendar), ctx, id)
+}
+
+// GetCalendar mocks base method.
+func (m *MockcalendarsService) GetCalendar(ctx context.Context, id string) (internal.Calendar, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCalendar", ctx, id)
+	ret0, _ := ret[0].(internal.Calendar)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCalendar indicates an expected call of GetCalendar.
+func (mr *MockcalendarsServiceMockRecorder) GetCalendar(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalendar", reflect.TypeOf((*MockcalendarsService)(nil).GetCalendar), ctx, id)
+}
+
+// GetCalendars mocks base method.
+func (m *MockcalendarsService) GetCalendars(ctx context.Context) ([]internal.Calendar, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCalendars", ctx)
+	ret0, _ := ret[0].([]internal.Calendar)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCalendars indicates an expected call of GetCalendars.
+func (mr *MockcalendarsServiceMockRecorder) GetCalendars(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalendars", reflect.TypeOf((*MockcalendarsService)(nil).GetCalendars), ctx)
+}
+
+// UpdateCalendar mocks base method.
+func (m *MockcalendarsService) UpdateCalendar(ctx context.Context, id string, calendar internal.Calendar) (internal.Calendar, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCalendar", ctx, id, calendar)
+	ret0, _ := ret[0].(internal.Calendar)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCalendar indicates an expected call of UpdateCalendar.
+func (mr *MockcalendarsServiceMockRecorder) UpdateCalendar(ctx, id, calendar any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCalendar", reflect.TypeOf((*MockcalendarsService)(nil).UpdateCalendar), ctx, id, calendar)
+}
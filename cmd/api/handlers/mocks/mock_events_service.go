@@ -12,6 +12,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	internal "github.com/ObiaNzk/LTK-test-manu/internal"
 	gomock "go.uber.org/mock/gomock"
@@ -41,6 +42,20 @@ func (m *MockeventsService) EXPECT() *MockeventsServiceMockRecorder {
 	return m.recorder
 }
 
+// CancelOccurrence mocks base method.
+func (m *MockeventsService) CancelOccurrence(ctx context.Context, seriesID string, recurrenceID time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelOccurrence", ctx, seriesID, recurrenceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelOccurrence indicates an expected call of CancelOccurrence.
+func (mr *MockeventsServiceMockRecorder) CancelOccurrence(ctx, seriesID, recurrenceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelOccurrence", reflect.TypeOf((*MockeventsService)(nil).CancelOccurrence), ctx, seriesID, recurrenceID)
+}
+
 // CreateEvent mocks base method.
 func (m *MockeventsService) CreateEvent(ctx context.Context, event internal.CreateEventRequest) (internal.CreateEventResponse, error) {
 	m.ctrl.T.Helper()
@@ -56,32 +71,196 @@ func (mr *MockeventsServiceMockRecorder) CreateEvent(ctx, event any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEvent", reflect.TypeOf((*MockeventsService)(nil).CreateEvent), ctx, event)
 }
 
+// DeleteEvent mocks base method.
+func (m *MockeventsService) DeleteEvent(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEvent", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEvent indicates an expected call of DeleteEvent.
+func (mr *MockeventsServiceMockRecorder) DeleteEvent(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEvent", reflect.TypeOf((*MockeventsService)(nil).DeleteEvent), ctx, id)
+}
+
 // GetEventByID mocks base method.
-func (m *MockeventsService) GetEventByID(ctx context.Context, id string) (internal.CreateEventResponse, error) {
+func (m *MockeventsService) GetEventByID(ctx context.Context, id string, includeDeleted bool) (internal.CreateEventResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEventByID", ctx, id)
+	ret := m.ctrl.Call(m, "GetEventByID", ctx, id, includeDeleted)
 	ret0, _ := ret[0].(internal.CreateEventResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetEventByID indicates an expected call of GetEventByID.
-func (mr *MockeventsServiceMockRecorder) GetEventByID(ctx, id any) *gomock.Call {
+func (mr *MockeventsServiceMockRecorder) GetEventByID(ctx, id, includeDeleted any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventByID", reflect.TypeOf((*MockeventsService)(nil).GetEventByID), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventByID", reflect.TypeOf((*MockeventsService)(nil).GetEventByID), ctx, id, includeDeleted)
+}
+
+// GetEventHistory mocks base method.
+func (m *MockeventsService) GetEventHistory(ctx context.Context, id string) ([]internal.EventRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventHistory", ctx, id)
+	ret0, _ := ret[0].([]internal.EventRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventHistory indicates an expected call of GetEventHistory.
+func (mr *MockeventsServiceMockRecorder) GetEventHistory(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventHistory", reflect.TypeOf((*MockeventsService)(nil).GetEventHistory), ctx, id)
 }
 
 // GetEvents mocks base method.
-func (m *MockeventsService) GetEvents(ctx context.Context) ([]internal.CreateEventResponse, error) {
+func (m *MockeventsService) GetEvents(ctx context.Context, filter internal.EventFilter, page internal.PageRequest) (internal.EventsPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEvents", ctx)
-	ret0, _ := ret[0].([]internal.CreateEventResponse)
+	ret := m.ctrl.Call(m, "GetEvents", ctx, filter, page)
+	ret0, _ := ret[0].(internal.EventsPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetEvents indicates an expected call of GetEvents.
-func (mr *MockeventsServiceMockRecorder) GetEvents(ctx any) *gomock.Call {
+func (mr *MockeventsServiceMockRecorder) GetEvents(ctx, filter, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvents", reflect.TypeOf((*MockeventsService)(nil).GetEvents), ctx, filter, page)
+}
+
+// GetOccurrenceExceptions mocks base method.
+func (m *MockeventsService) GetOccurrenceExceptions(ctx context.Context, id string) ([]internal.OccurrenceException, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOccurrenceExceptions", ctx, id)
+	ret0, _ := ret[0].([]internal.OccurrenceException)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOccurrenceExceptions indicates an expected call of GetOccurrenceExceptions.
+func (mr *MockeventsServiceMockRecorder) GetOccurrenceExceptions(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOccurrenceExceptions", reflect.TypeOf((*MockeventsService)(nil).GetOccurrenceExceptions), ctx, id)
+}
+
+// GetOccurrences mocks base method.
+func (m *MockeventsService) GetOccurrences(ctx context.Context, id string, from, to time.Time) ([]internal.CreateEventResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOccurrences", ctx, id, from, to)
+	ret0, _ := ret[0].([]internal.CreateEventResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOccurrences indicates an expected call of GetOccurrences.
+func (mr *MockeventsServiceMockRecorder) GetOccurrences(ctx, id, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOccurrences", reflect.TypeOf((*MockeventsService)(nil).GetOccurrences), ctx, id, from, to)
+}
+
+// ImportEvents mocks base method.
+func (m *MockeventsService) ImportEvents(ctx context.Context, events []internal.ImportedEvent) ([]internal.ImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportEvents", ctx, events)
+	ret0, _ := ret[0].([]internal.ImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportEvents indicates an expected call of ImportEvents.
+func (mr *MockeventsServiceMockRecorder) ImportEvents(ctx, events any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportEvents", reflect.TypeOf((*MockeventsService)(nil).ImportEvents), ctx, events)
+}
+
+// RestoreEvent mocks base method.
+func (m *MockeventsService) RestoreEvent(ctx context.Context, id string) (internal.CreateEventResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreEvent", ctx, id)
+	ret0, _ := ret[0].(internal.CreateEventResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreEvent indicates an expected call of RestoreEvent.
+func (mr *MockeventsServiceMockRecorder) RestoreEvent(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreEvent", reflect.TypeOf((*MockeventsService)(nil).RestoreEvent), ctx, id)
+}
+
+// RevertEvent mocks base method.
+func (m *MockeventsService) RevertEvent(ctx context.Context, id string, revision, version int) (internal.CreateEventResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevertEvent", ctx, id, revision, version)
+	ret0, _ := ret[0].(internal.CreateEventResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevertEvent indicates an expected call of RevertEvent.
+func (mr *MockeventsServiceMockRecorder) RevertEvent(ctx, id, revision, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevertEvent", reflect.TypeOf((*MockeventsService)(nil).RevertEvent), ctx, id, revision, version)
+}
+
+// SearchAvailability mocks base method.
+func (m *MockeventsService) SearchAvailability(ctx context.Context, query internal.AvailabilityQuery) (internal.Availability, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchAvailability", ctx, query)
+	ret0, _ := ret[0].(internal.Availability)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchAvailability indicates an expected call of SearchAvailability.
+func (mr *MockeventsServiceMockRecorder) SearchAvailability(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchAvailability", reflect.TypeOf((*MockeventsService)(nil).SearchAvailability), ctx, query)
+}
+
+// SplitSeries mocks base method.
+func (m *MockeventsService) SplitSeries(ctx context.Context, id string, recurrenceID time.Time, changes internal.CreateEventRequest) (internal.CreateEventResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SplitSeries", ctx, id, recurrenceID, changes)
+	ret0, _ := ret[0].(internal.CreateEventResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SplitSeries indicates an expected call of SplitSeries.
+func (mr *MockeventsServiceMockRecorder) SplitSeries(ctx, id, recurrenceID, changes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SplitSeries", reflect.TypeOf((*MockeventsService)(nil).SplitSeries), ctx, id, recurrenceID, changes)
+}
+
+// UpdateEvent mocks base method.
+func (m *MockeventsService) UpdateEvent(ctx context.Context, id string, version int, event internal.CreateEventRequest) (internal.CreateEventResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEvent", ctx, id, version, event)
+	ret0, _ := ret[0].(internal.CreateEventResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateEvent indicates an expected call of UpdateEvent.
+func (mr *MockeventsServiceMockRecorder) UpdateEvent(ctx, id, version, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEvent", reflect.TypeOf((*MockeventsService)(nil).UpdateEvent), ctx, id, version, event)
+}
+
+// UpdateOccurrence mocks base method.
+func (m *MockeventsService) UpdateOccurrence(ctx context.Context, exception internal.OccurrenceException) (internal.CreateEventResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOccurrence", ctx, exception)
+	ret0, _ := ret[0].(internal.CreateEventResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOccurrence indicates an expected call of UpdateOccurrence.
+func (mr *MockeventsServiceMockRecorder) UpdateOccurrence(ctx, exception any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvents", reflect.TypeOf((*MockeventsService)(nil).GetEvents), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOccurrence", reflect.TypeOf((*MockeventsService)(nil).UpdateOccurrence), ctx, exception)
 }
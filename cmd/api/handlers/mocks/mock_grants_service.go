@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: grants.go
+//
+// Generated by this command:
+//
+//	mockgen -source=grants.go -destination=mocks/mock_grants_service.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	internal "github.com/ObiaNzk/LTK-test-manu/internal"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockgrantsService is a mock of grantsService interface.
+type MockgrantsService struct {
+	ctrl     *gomock.Controller
+	recorder *MockgrantsServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockgrantsServiceMockRecorder is the mock recorder for MockgrantsService.
+type MockgrantsServiceMockRecorder struct {
+	mock *MockgrantsService
+}
+
+// NewMockgrantsService creates a new mock instance.
+func NewMockgrantsService(ctrl *gomock.Controller) *MockgrantsService {
+	mock := &MockgrantsService{ctrl: ctrl}
+	mock.recorder = &MockgrantsServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockgrantsService) EXPECT() *MockgrantsServiceMockRecorder {
+	return m.recorder
+}
+
+// DeleteGrant mocks base method.
+func (m *MockgrantsService) DeleteGrant(ctx context.Context, objectType internal.ObjectType, objectID, principal string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGrant", ctx, objectType, objectID, principal)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteGrant indicates an expected call of DeleteGrant.
+func (mr *MockgrantsServiceMockRecorder) DeleteGrant(ctx, objectType, objectID, principal any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGrant", reflect.TypeOf((*MockgrantsService)(nil).DeleteGrant), ctx, objectType, objectID, principal)
+}
+
+// GetGrants mocks base method.
+func (m *MockgrantsService) GetGrants(ctx context.Context, objectType internal.ObjectType, objectID string) ([]internal.Grant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGrants", ctx, objectType, objectID)
+	ret0, _ := ret[0].([]internal.Grant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGrants indicates an expected call of GetGrants.
+func (mr *MockgrantsServiceMockRecorder) GetGrants(ctx, objectType, objectID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGrants", reflect.TypeOf((*MockgrantsService)(nil).GetGrants), ctx, objectType, objectID)
+}
+
+// SetGrant mocks base method.
+func (m *MockgrantsService) SetGrant(ctx context.Context, grant internal.Grant) (internal.Grant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetGrant", ctx, grant)
+	ret0, _ := ret[0].(internal.Grant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetGrant indicates an expected call of SetGrant.
+func (mr *MockgrantsServiceMockRecorder) SetGrant(ctx, grant any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetGrant", reflect.TypeOf((*MockgrantsService)(nil).SetGrant), ctx, grant)
+}
@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: idempotency.go
+//
+// Generated by this command:
+//
+//	mockgen -source=idempotency.go -destination=mocks/mock_idempotency_service.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	internal "github.com/ObiaNzk/LTK-test-manu/internal"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockidempotencyService is a mock of idempotencyService interface.
+type MockidempotencyService struct {
+	ctrl     *gomock.Controller
+	recorder *MockidempotencyServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockidempotencyServiceMockRecorder is the mock recorder for MockidempotencyService.
+type MockidempotencyServiceMockRecorder struct {
+	mock *MockidempotencyService
+}
+
+// NewMockidempotencyService creates a new mock instance.
+func NewMockidempotencyService(ctrl *gomock.Controller) *MockidempotencyService {
+	mock := &MockidempotencyService{ctrl: ctrl}
+	mock.recorder = &MockidempotencyServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockidempotencyService) EXPECT() *MockidempotencyServiceMockRecorder {
+	return m.recorder
+}
+
+// Begin mocks base method.
+func (m *MockidempotencyService) Begin(ctx context.Context, key string, fingerprint []byte) (internal.IdempotencyRecord, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Begin", ctx, key, fingerprint)
+	ret0, _ := ret[0].(internal.IdempotencyRecord)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Begin indicates an expected call of Begin.
+func (mr *MockidempotencyServiceMockRecorder) Begin(ctx, key, fingerprint any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MockidempotencyService)(nil).Begin), ctx, key, fingerprint)
+}
+
+// Complete mocks base method.
+func (m *MockidempotencyService) Complete(ctx context.Context, key string, status int, body []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", ctx, key, status, body)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockidempotencyServiceMockRecorder) Complete(ctx, key, status, body any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockidempotencyService)(nil).Complete), ctx, key, status, body)
+}
+
+// Release mocks base method.
+func (m *MockidempotencyService) Release(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockidempotencyServiceMockRecorder) Release(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockidempotencyService)(nil).Release), ctx, key)
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal"
+	"github.com/ObiaNzk/LTK-test-manu/internal/validation"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// problemTypes names the kind of problem behind each status, as the last
+// segment of its type URI, and gives its title.
+var problemTypes = map[int]struct{ name, title string }{
+	http.StatusBadRequest:            {"invalid-input", "Invalid input"},
+	http.StatusUnauthorized:          {"unauthorized", "Unauthorized"},
+	http.StatusForbidden:             {"forbidden", "Forbidden"},
+	http.StatusNotFound:              {"not-found", "Not found"},
+	http.StatusMethodNotAllowed:      {"method-not-allowed", "Method not allowed"},
+	http.StatusConflict:              {"conflict", "Conflict"},
+	http.StatusPreconditionFailed:    {"precondition-failed", "Precondition failed"},
+	http.StatusRequestEntityTooLarge: {"too-large", "Request too large"},
+	http.StatusUnsupportedMediaType:  {"unsupported-media-type", "Unsupported media type"},
+	http.StatusPreconditionRequired:  {"precondition-required", "Precondition required"},
+	http.StatusInternalServerError:   {"internal", "Internal server error"},
+}
+
+// problem is an RFC 7807 problem details document. Errors lists the invalid
+// fields of a request, and Resource and ConflictingEventIDs describe a booking
+// conflict.
+type problem struct {
+	Type                string       `json:"type"`
+	Title               string       `json:"title"`
+	Status              int          `json:"status"`
+	Detail              string       `json:"detail,omitempty"`
+	Errors              []fieldError `json:"errors,omitempty"`
+	Resource            string       `json:"resource,omitempty"`
+	ConflictingEventIDs []string     `json:"conflicting_event_ids,omitempty"`
+	RequestID           string       `json:"request_id,omitempty"`
+}
+
+type fieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func newProblem(status int, detail string) problem {
+	kind, ok := problemTypes[status]
+	if !ok {
+		kind.name, kind.title = "about:blank", http.StatusText(status)
+	} else {
+		kind.name = "/problems/" + kind.name
+	}
+
+	return problem{Type: kind.name, Title: kind.title, Status: status, Detail: detail}
+}
+
+// writeProblem renders a problem with the id the RequestID middleware gave r.
+func writeProblem(w http.ResponseWriter, r *http.Request, p problem) {
+	p.RequestID = middleware.GetReqID(r.Context())
+
+	jsonResult, err := json.Marshal(p)
+	if err != nil {
+		http.Error(w, "error creating json response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(p.Status)
+	w.Write(jsonResult)
+}
+
+// writeStatus renders a problem of status explained by detail.
+func writeStatus(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	writeProblem(w, r, newProblem(status, detail))
+}
+
+// writeFieldError rejects a request because of one of its fields.
+func writeFieldError(w http.ResponseWriter, r *http.Request, field, message string) {
+	p := newProblem(http.StatusBadRequest, message)
+	p.Errors = []fieldError{{Field: field, Message: message}}
+
+	writeProblem(w, r, p)
+}
+
+// paramError is an invalid query or path parameter.
+type paramError struct {
+	param   string
+	message string
+}
+
+func (e *paramError) Error() string {
+	return e.message
+}
+
+// writeParamError rejects a request because of a parameter, naming it when err
+// is a paramError. ErrForbidden is a parameter the caller may not set.
+func writeParamError(w http.ResponseWriter, r *http.Request, err error) {
+	var param *paramError
+	if errors.As(err, &param) {
+		writeFieldError(w, r, param.param, param.message)
+		return
+	}
+
+	if errors.Is(err, internal.ErrForbidden) {
+		writeStatus(w, r, http.StatusForbidden, err.Error())
+		return
+	}
+
+	writeStatus(w, r, http.StatusBadRequest, err.Error())
+}
+
+// writeDecodeError rejects a body that is not valid JSON, or whose fields have
+// the wrong type.
+func writeDecodeError(w http.ResponseWriter, r *http.Request, err error) {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		writeFieldError(w, r, typeErr.Field, fmt.Sprintf("should be a %s", typeErr.Type))
+		return
+	}
+
+	writeStatus(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid JSON format: %s", err.Error()))
+}
+
+// writeError maps an error of the services to its status: ErrInput to 400,
+// listing the invalid fields when there are violations, ErrForbidden to 403,
+// ErrNotFound to 404 explained by notFound, ErrConflict to 409,
+// ErrVersionMismatch to 412, ErrKeyReused to 422 typed as a reused
+// idempotency key and anything else to 500 prefixed with message.
+func writeError(w http.ResponseWriter, r *http.Request, err error, notFound, message string) {
+	var (
+		conflict   *internal.ConflictError
+		violations validation.Errors
+	)
+
+	switch {
+	case errors.As(err, &conflict):
+		p := newProblem(http.StatusConflict, fmt.Sprintf("resource %q is already booked at that time", conflict.Resource))
+		p.Resource = conflict.Resource
+		p.ConflictingEventIDs = conflict.EventIDs
+
+		writeProblem(w, r, p)
+	case errors.As(err, &violations):
+		p := newProblem(http.StatusBadRequest, violations.Error())
+		for _, violation := range violations {
+			p.Errors = append(p.Errors, fieldError{Field: violation.Field, Message: violation.Message})
+		}
+
+		writeProblem(w, r, p)
+	case errors.Is(err, internal.ErrInput):
+		writeStatus(w, r, http.StatusBadRequest, err.Error())
+	case errors.Is(err, internal.ErrUnauthorized):
+		writeStatus(w, r, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, internal.ErrForbidden):
+		writeStatus(w, r, http.StatusForbidden, err.Error())
+	case errors.Is(err, internal.ErrNotFound):
+		writeStatus(w, r, http.StatusNotFound, notFound)
+	case errors.Is(err, internal.ErrConflict):
+		writeStatus(w, r, http.StatusConflict, err.Error())
+	case errors.Is(err, internal.ErrVersionMismatch):
+		writeStatus(w, r, http.StatusPreconditionFailed, err.Error())
+	case errors.Is(err, internal.ErrKeyReused):
+		p := newProblem(http.StatusUnprocessableEntity, err.Error())
+		p.Type, p.Title = "/problems/idempotency-key-reused", "Idempotency key reused"
+
+		writeProblem(w, r, p)
+	default:
+		writeStatus(w, r, http.StatusInternalServerError, fmt.Sprintf("%s: %s", message, err.Error()))
+	}
+}
+
+// NotFound answers requests for routes that do not exist.
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	writeStatus(w, r, http.StatusNotFound, fmt.Sprintf("no route for %s", r.URL.Path))
+}
+
+// MethodNotAllowed answers requests with a method their route does not take.
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	writeStatus(w, r, http.StatusMethodNotAllowed, fmt.Sprintf("%s is not allowed on %s", r.Method, r.URL.Path))
+}
@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal"
+)
+
+// Tenant makes sure the request acts for a tenant, the one Authenticate took
+// from its credentials. Requests without one are rejected, since storage
+// refuses to run queries without a tenant.
+func Tenant(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := internal.TenantFromContext(r.Context()); !ok {
+			writeStatus(w, r, http.StatusUnauthorized, "credentials have no tenant")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type renderLocationKey struct{}
+
+// RenderTimeZone reads the zone the caller wants times rendered in, from the
+// ?tz= query parameter or the Accept-Timezone header, e.g. "America/New_York".
+// Without either, events are rendered in their own zone.
+func RenderTimeZone(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Query().Get("tz")
+		if name == "" {
+			name = r.Header.Get("Accept-Timezone")
+		}
+
+		if name == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			writeStatus(w, r, http.StatusBadRequest, fmt.Sprintf("unknown time zone %q", name))
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), renderLocationKey{}, loc)))
+	})
+}
+
+// renderLocation is the zone set by RenderTimeZone, or nil.
+func renderLocation(r *http.Request) *time.Location {
+	loc, _ := r.Context().Value(renderLocationKey{}).(*time.Location)
+
+	return loc
+}
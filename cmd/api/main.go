@@ -1,12 +1,17 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"time"
+	// TZIDs of imported calendars must resolve even without system zoneinfo.
+	_ "time/tzdata"
 
 	"github.com/ObiaNzk/LTK-test-manu/cmd/api/handlers"
 	"github.com/ObiaNzk/LTK-test-manu/internal"
+	"github.com/ObiaNzk/LTK-test-manu/internal/auth"
+	"github.com/ObiaNzk/LTK-test-manu/internal/outbox"
 	"github.com/ObiaNzk/LTK-test-manu/internal/platform"
 )
 
@@ -20,11 +25,29 @@ func main() {
 
 	defer db.Close()
 
+	keys, err := auth.LoadKeySet(cfg.AuthConfig.JWKSFile)
+	if err != nil {
+		log.Fatalf("Failed to load signing keys: %v", err)
+	}
+
 	storage := internal.NewStorage(db)
+
+	publisher, err := newPublisher(cfg.Outbox)
+	if err != nil {
+		log.Fatalf("Failed to open outbox publisher: %v", err)
+	}
+
+	go internal.NewRelay(storage, publisher, cfg.Outbox.Interval).Run(context.Background())
+
 	service := internal.NewService(storage)
-	handler := handlers.NewHandler(service)
+	attendees := internal.NewAttendeeService(storage)
+	calendars := internal.NewCalendarService(storage)
+	apiKeys := internal.NewAPIKeyService(storage)
+	grants := internal.NewGrantService(storage)
+	idempotency := internal.NewIdempotencyService(storage, cfg.IdempotencyTTL)
+	handler := handlers.NewHandler(service, attendees, calendars, apiKeys, grants)
 
-	router := NewRouter(handler)
+	router := NewRouter(handler, auth.NewVerifier(keys, cfg.AuthConfig), apiKeys, idempotency)
 
 	server := &http.Server{
 		Addr:         ":8080",
@@ -39,3 +62,19 @@ func main() {
 		log.Fatalf("Server error: %v", err)
 	}
 }
+
+func newPublisher(cfg outboxConfig) (internal.Publisher, error) {
+	switch {
+	case cfg.NATS.URL != "":
+		return outbox.NewNATS(cfg.NATS)
+	case cfg.File != "":
+		file, err := outbox.OpenFile(cfg.File)
+		if err != nil {
+			return nil, err
+		}
+
+		return file, nil
+	default:
+		return outbox.NewMemory(), nil
+	}
+}
@@ -2,22 +2,72 @@ package main
 
 import (
 	"github.com/ObiaNzk/LTK-test-manu/cmd/api/handlers"
+	"github.com/ObiaNzk/LTK-test-manu/internal"
+	"github.com/ObiaNzk/LTK-test-manu/internal/auth"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 )
 
-func NewRouter(handler *handlers.Handler) *chi.Mux {
+func NewRouter(handler *handlers.Handler, verifier *auth.Verifier, apiKeys *internal.APIKeyService,
+	idempotency *internal.IdempotencyService) *chi.Mux {
 	r := chi.NewRouter()
 
 	// Middleware
+	r.Use(middleware.RequestID)
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
+	r.Use(handlers.RenderTimeZone)
+	r.Use(handlers.Authenticate(verifier, apiKeys))
+	r.Use(handlers.Tenant)
+
+	r.NotFound(handlers.NotFound)
+	r.MethodNotAllowed(handlers.MethodNotAllowed)
 
 	// Routes
-	r.Post("/events", handler.CreateEvent)
+	r.With(handlers.Idempotent(idempotency)).Post("/events", handler.CreateEvent)
 	r.Get("/events", handler.GetEvents)
+	r.Get("/events.ics", handler.ExportEvents)
+	r.Post("/events/import", handler.ImportEvents)
 	r.Get("/events/{id}", handler.GetEventByID)
+	r.Get("/events/{id}.ics", handler.ExportEvent)
+	r.Put("/events/{id}", handler.UpdateEvent)
+	r.Patch("/events/{id}", handler.PatchEvent)
+	r.Delete("/events/{id}", handler.DeleteEvent)
+	r.Post("/events/{id}/restore", handler.RestoreEvent)
+	r.Get("/events/{id}/history", handler.GetEventHistory)
+	r.Post("/events/{id}/history/{revision}/revert", handler.RevertEvent)
+	r.Get("/events/{id}/occurrences", handler.GetOccurrences)
+	r.Put("/events/{id}/occurrences/{recurrence_id}", handler.UpdateOccurrence)
+	r.Delete("/events/{id}/occurrences/{recurrence_id}", handler.CancelOccurrence)
+	r.Post("/events/{id}/occurrences/{recurrence_id}/split", handler.SplitSeries)
+	r.Get("/events/{id}/grants", handler.GetEventGrants)
+	r.Put("/events/{id}/grants/{principal}", handler.SetEventGrant)
+	r.Delete("/events/{id}/grants/{principal}", handler.DeleteEventGrant)
+	r.Get("/events/{id}/attendees", handler.GetAttendees)
+	r.Post("/events/{id}/attendees", handler.InviteAttendee)
+	r.Put("/events/{id}/attendees/{email}/rsvp", handler.RSVP)
+	r.Delete("/events/{id}/attendees/{email}", handler.RemoveAttendee)
+	r.Post("/availability/search", handler.SearchAvailability)
+	r.Post("/calendars", handler.CreateCalendar)
+	r.Get("/calendars", handler.GetCalendars)
+	r.Get("/calendars/{cid}", handler.GetCalendar)
+	r.Put("/calendars/{cid}", handler.UpdateCalendar)
+	r.Delete("/calendars/{cid}", handler.DeleteCalendar)
+	r.With(handlers.Idempotent(idempotency)).Post("/calendars/{cid}/events", handler.CreateEvent)
+	r.Get("/calendars/{cid}/events", handler.GetEvents)
+	r.Get("/calendars/{cid}/events.ics", handler.ExportEvents)
+	r.Get("/calendars/{cid}/grants", handler.GetCalendarGrants)
+	r.Put("/calendars/{cid}/grants/{principal}", handler.SetCalendarGrant)
+	r.Delete("/calendars/{cid}/grants/{principal}", handler.DeleteCalendarGrant)
+
+	r.Route("/admin", func(r chi.Router) {
+		r.Use(handlers.RequireAdmin)
+
+		r.Post("/api-keys", handler.CreateAPIKey)
+		r.Get("/api-keys", handler.GetAPIKeys)
+		r.Delete("/api-keys/{kid}", handler.RevokeAPIKey)
+	})
 
 	return r
 }
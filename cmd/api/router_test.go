@@ -0,0 +1,353 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ObiaNzk/LTK-test-manu/cmd/api/handlers"
+	"github.com/ObiaNzk/LTK-test-manu/internal"
+	"github.com/ObiaNzk/LTK-test-manu/internal/auth"
+	"github.com/go-chi/chi/v5"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// errReached is what policyStorage answers once a request got past
+// authorization.
+var errReached = errors.New("storage reached")
+
+// policyStorage grants its role on the "team" calendar and the "evt" event in
+// it. Any other call fails with errReached, except reading the event, which a
+// PATCH does before the update is authorized.
+type policyStorage struct {
+	roles []internal.AccessRole
+}
+
+func (p policyStorage) GetCalendarAccess(context.Context, string, string) ([]internal.AccessRole, error) {
+	return p.roles, nil
+}
+
+func (p policyStorage) GetEventAccess(context.Context, string, string) (internal.EventAccess, error) {
+	return internal.EventAccess{CalendarID: "team", Roles: p.roles}, nil
+}
+
+func (p policyStorage) GetEventAccessByUID(context.Context, string, string) (internal.EventAccess, error) {
+	return internal.EventAccess{CalendarID: "team", Roles: p.roles}, nil
+}
+
+func (p policyStorage) GetEventByID(_ context.Context, id string, _ bool) (internal.CreateEventResponse, error) {
+	start := time.Date(2030, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	return internal.CreateEventResponse{ID: id, Title: "Standup", StartTime: start, EndTime: start.Add(time.Hour),
+		TimeZone: "UTC", CalendarID: "team"}, nil
+}
+
+func (policyStorage) CreateEvent(context.Context, internal.CreateEventRequest) (internal.CreateEventResponse, error) {
+	return internal.CreateEventResponse{}, errReached
+}
+
+func (policyStorage) GetEvents(context.Context, internal.EventFilter, internal.Page) ([]internal.CreateEventResponse, error) {
+	return nil, errReached
+}
+
+func (policyStorage) UpdateEvent(context.Context, string, int, internal.CreateEventRequest) (internal.CreateEventResponse, error) {
+	return internal.CreateEventResponse{}, errReached
+}
+
+func (policyStorage) DeleteEvent(context.Context, string) error {
+	return errReached
+}
+
+func (policyStorage) RestoreEvent(context.Context, string) (internal.CreateEventResponse, error) {
+	return internal.CreateEventResponse{}, errReached
+}
+
+func (policyStorage) GetOccurrenceExceptions(context.Context, []string) ([]internal.OccurrenceException, error) {
+	return nil, errReached
+}
+
+func (policyStorage) SaveOccurrenceException(context.Context, internal.OccurrenceException) error {
+	return errReached
+}
+
+func (policyStorage) SplitSeries(context.Context, string, time.Time, internal.CreateEventRequest, internal.CreateEventRequest) (internal.CreateEventResponse, error) {
+	return internal.CreateEventResponse{}, errReached
+}
+
+func (policyStorage) ImportEvents(context.Context, []internal.ImportedEvent) ([]internal.ImportResult, error) {
+	return nil, errReached
+}
+
+func (policyStorage) GetEventRevisions(context.Context, string) ([]internal.EventRevision, error) {
+	return nil, errReached
+}
+
+func (policyStorage) GetEventRevision(context.Context, string, int) (internal.EventRevision, error) {
+	return internal.EventRevision{}, errReached
+}
+
+func (policyStorage) GetCalendar(context.Context, string) (internal.Calendar, error) {
+	return internal.Calendar{}, errReached
+}
+
+func (policyStorage) CreateCalendar(context.Context, internal.Calendar) (internal.Calendar, error) {
+	return internal.Calendar{}, errReached
+}
+
+func (policyStorage) GetCalendars(context.Context, string) ([]internal.Calendar, error) {
+	return nil, errReached
+}
+
+func (policyStorage) UpdateCalendar(context.Context, internal.Calendar) (internal.Calendar, error) {
+	return internal.Calendar{}, errReached
+}
+
+func (policyStorage) DeleteCalendar(context.Context, string) error {
+	return errReached
+}
+
+func (policyStorage) AddAttendee(context.Context, internal.Attendee) error {
+	return errReached
+}
+
+func (policyStorage) GetAttendees(context.Context, string) ([]internal.Attendee, error) {
+	return nil, errReached
+}
+
+func (policyStorage) SetAttendeeStatus(context.Context, string, string, internal.AttendeeStatus, time.Time) (internal.Attendee, error) {
+	return internal.Attendee{}, errReached
+}
+
+func (policyStorage) RemoveAttendee(context.Context, string, string) error {
+	return errReached
+}
+
+func (policyStorage) CountAttendees(context.Context, string) (map[internal.AttendeeStatus]int, error) {
+	return nil, errReached
+}
+
+func (policyStorage) GetGrants(context.Context, internal.ObjectType, string) ([]internal.Grant, error) {
+	return nil, errReached
+}
+
+func (policyStorage) SetGrant(context.Context, internal.Grant) error {
+	return errReached
+}
+
+func (policyStorage) DeleteGrant(context.Context, internal.ObjectType, string, string) error {
+	return errReached
+}
+
+func (policyStorage) CreateAPIKey(context.Context, internal.APIKey, []byte) error {
+	return errReached
+}
+
+func (policyStorage) GetAPIKeys(context.Context) ([]internal.APIKey, error) {
+	return nil, errReached
+}
+
+func (policyStorage) GetAPIKeyByHash(context.Context, []byte) (internal.APIKey, error) {
+	return internal.APIKey{}, errReached
+}
+
+func (policyStorage) RevokeAPIKey(context.Context, string, time.Time) error {
+	return errReached
+}
+
+func (policyStorage) TouchAPIKey(context.Context, string, time.Time) error {
+	return errReached
+}
+
+// policyRoute is a route with a request that passes its own validation, and
+// the least a caller needs to get past authorization: "" for any actor of the
+// tenant, a role on the team calendar or evt event, or "admin".
+type policyRoute struct {
+	method  string
+	pattern string
+	path    string
+	body    string
+	need    internal.AccessRole
+}
+
+const adminOnly internal.AccessRole = "admin"
+
+// selfPlaceholder stands in a path for the actor of the caller, its email.
+const selfPlaceholder = "{self}"
+
+var (
+	eventJSON = fmt.Sprintf(`{"title": %q, "description": "Daily", "start_time": "2030-01-01T10:00:00Z", "end_time": "2030-01-01T11:00:00Z"}`,
+		strings.Repeat("a", 101))
+
+	policyRoutes = []policyRoute{
+		{"POST", "/events", "/events", eventJSON, ""},
+		{"GET", "/events", "/events", "", ""},
+		{"GET", "/events.ics", "/events.ics", "", ""},
+		{"POST", "/events/import", "/events/import", "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n", ""},
+		{"GET", "/events/{id}", "/events/evt", "", internal.AccessViewer},
+		{"GET", "/events/{id}.ics", "/events/evt.ics", "", internal.AccessViewer},
+		{"PUT", "/events/{id}", "/events/evt", eventJSON, internal.AccessEditor},
+		{"PATCH", "/events/{id}", "/events/evt", `{"title": "Retro"}`, internal.AccessEditor},
+		{"DELETE", "/events/{id}", "/events/evt", "", internal.AccessEditor},
+		{"POST", "/events/{id}/restore", "/events/evt/restore", "", internal.AccessEditor},
+		{"GET", "/events/{id}/history", "/events/evt/history", "", internal.AccessViewer},
+		{"POST", "/events/{id}/history/{revision}/revert", "/events/evt/history/1/revert", "", internal.AccessEditor},
+		{"GET", "/events/{id}/occurrences", "/events/evt/occurrences?from=2030-01-01T00:00:00Z&to=2030-02-01T00:00:00Z", "",
+			internal.AccessViewer},
+		{"PUT", "/events/{id}/occurrences/{recurrence_id}", "/events/evt/occurrences/2030-01-08T10:00:00Z", `{"title": "Moved"}`,
+			internal.AccessEditor},
+		{"DELETE", "/events/{id}/occurrences/{recurrence_id}", "/events/evt/occurrences/2030-01-08T10:00:00Z", "",
+			internal.AccessEditor},
+		{"POST", "/events/{id}/occurrences/{recurrence_id}/split", "/events/evt/occurrences/2030-01-08T10:00:00Z/split", `{}`,
+			internal.AccessEditor},
+		{"GET", "/events/{id}/grants", "/events/evt/grants", "", internal.AccessOwner},
+		{"PUT", "/events/{id}/grants/{principal}", "/events/evt/grants/auth0%7Cbob", `{"role": "viewer"}`, internal.AccessOwner},
+		{"DELETE", "/events/{id}/grants/{principal}", "/events/evt/grants/auth0%7Cbob", "", internal.AccessOwner},
+		{"GET", "/events/{id}/attendees", "/events/evt/attendees", "", internal.AccessViewer},
+		{"POST", "/events/{id}/attendees", "/events/evt/attendees", `{"email": "ada@example.com"}`, internal.AccessEditor},
+		{"PUT", "/events/{id}/attendees/{email}/rsvp", "/events/evt/attendees/ada@example.com/rsvp", `{"status": "accepted"}`,
+			internal.AccessEditor},
+		{"PUT", "/events/{id}/attendees/{email}/rsvp", "/events/evt/attendees/" + selfPlaceholder + "/rsvp", `{"status": "accepted"}`,
+			internal.AccessViewer},
+		{"DELETE", "/events/{id}/attendees/{email}", "/events/evt/attendees/ada@example.com", "", internal.AccessEditor},
+		{"POST", "/availability/search", "/availability/search",
+			`{"resources": ["room-1"], "from": "2030-01-01T00:00:00Z", "to": "2030-01-02T00:00:00Z", "duration_minutes": 30}`, ""},
+		{"POST", "/calendars", "/calendars", `{"name": "Team"}`, ""},
+		{"GET", "/calendars", "/calendars", "", ""},
+		{"GET", "/calendars/{cid}", "/calendars/team", "", internal.AccessViewer},
+		{"PUT", "/calendars/{cid}", "/calendars/team", `{"name": "Team"}`, internal.AccessOwner},
+		{"DELETE", "/calendars/{cid}", "/calendars/team", "", internal.AccessOwner},
+		{"POST", "/calendars/{cid}/events", "/calendars/team/events", eventJSON, internal.AccessEditor},
+		{"GET", "/calendars/{cid}/events", "/calendars/team/events", "", internal.AccessViewer},
+		{"GET", "/calendars/{cid}/events.ics", "/calendars/team/events.ics", "", internal.AccessViewer},
+		{"GET", "/calendars/{cid}/grants", "/calendars/team/grants", "", internal.AccessOwner},
+		{"PUT", "/calendars/{cid}/grants/{principal}", "/calendars/team/grants/auth0%7Cbob", `{"role": "editor"}`,
+			internal.AccessOwner},
+		{"DELETE", "/calendars/{cid}/grants/{principal}", "/calendars/team/grants/auth0%7Cbob", "", internal.AccessOwner},
+		{"POST", "/admin/api-keys", "/admin/api-keys", `{"name": "sync", "scopes": ["events:read"]}`, adminOnly},
+		{"GET", "/admin/api-keys", "/admin/api-keys", "", adminOnly},
+		{"DELETE", "/admin/api-keys/{kid}", "/admin/api-keys/key", "", adminOnly},
+	}
+)
+
+// policyCallers are the callers of the matrix, from no role at all to admin.
+var policyCallers = []struct {
+	name  string
+	role  internal.AccessRole
+	admin bool
+}{
+	{"stranger", "", false},
+	{"viewer", internal.AccessViewer, false},
+	{"editor", internal.AccessEditor, false},
+	{"owner", internal.AccessOwner, false},
+	{"admin", "", true},
+}
+
+var roleRanks = map[internal.AccessRole]int{"": 0, internal.AccessViewer: 1, internal.AccessEditor: 2, internal.AccessOwner: 3, adminOnly: 4}
+
+func TestPolicyMatrix(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+
+	keys, err := auth.ParseKeySet([]byte(fmt.Sprintf(`{"keys": [{"kty": "oct", "k": %q}]}`, base64.RawURLEncoding.EncodeToString(secret))))
+	require.NoError(t, err)
+
+	verifier := auth.NewVerifier(keys, auth.Config{Issuer: "issuer", Audience: "events-api"})
+
+	for _, caller := range policyCallers {
+		var roles []internal.AccessRole
+		if caller.role != "" {
+			roles = []internal.AccessRole{caller.role}
+		}
+
+		storage := policyStorage{roles: roles}
+		apiKeys := internal.NewAPIKeyService(storage)
+		handler := handlers.NewHandler(internal.NewService(storage), internal.NewAttendeeService(storage),
+			internal.NewCalendarService(storage), apiKeys, internal.NewGrantService(storage))
+		router := NewRouter(handler, verifier, apiKeys, nil)
+
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"sub": caller.name + "@example.com", "tenant": "acme", "admin": caller.admin,
+			"iss": "issuer", "aud": "events-api", "exp": time.Now().Add(time.Hour).Unix(),
+		}).SignedString(secret)
+		require.NoError(t, err)
+
+		for _, route := range policyRoutes {
+			path := strings.ReplaceAll(route.path, selfPlaceholder, caller.name+"@example.com")
+			name := fmt.Sprintf("%s %s as %s", route.method, path, caller.name)
+
+			req := httptest.NewRequest(route.method, path, strings.NewReader(route.body))
+			req.Header.Set("Authorization", "Bearer "+token)
+			req.Header.Set("If-Match", "*")
+
+			if route.pattern == "/events/import" {
+				req.Header.Set("Content-Type", "text/calendar")
+			}
+
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			allowed := caller.admin || (route.need != adminOnly && roleRanks[caller.role] >= roleRanks[route.need])
+
+			if allowed {
+				require.NotEqual(t, http.StatusForbidden, w.Code, "%s: %s", name, w.Body.String())
+			} else {
+				require.Equal(t, http.StatusForbidden, w.Code, "%s: %s", name, w.Body.String())
+			}
+		}
+	}
+}
+
+func TestPolicyMatrix_CoversEveryRoute(t *testing.T) {
+	router := NewRouter(handlers.NewHandler(nil, nil, nil, nil, nil), nil, nil, nil)
+
+	covered := make(map[string]bool, len(policyRoutes))
+	for _, route := range policyRoutes {
+		covered[route.method+" "+route.pattern] = true
+	}
+
+	err := chi.Walk(router, func(method, pattern string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if !covered[method+" "+pattern] {
+			return fmt.Errorf("%s %s is missing from the policy matrix", method, pattern)
+		}
+
+		delete(covered, method+" "+pattern)
+
+		return nil
+	})
+	require.NoError(t, err)
+	require.Empty(t, covered, "routes of the matrix that do not exist")
+}
+
+func TestRouter_UnknownRoute(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+
+	keys, err := auth.ParseKeySet([]byte(fmt.Sprintf(`{"keys": [{"kty": "oct", "k": %q}]}`, base64.RawURLEncoding.EncodeToString(secret))))
+	require.NoError(t, err)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "auth0|ada", "tenant": "acme", "iss": "issuer", "aud": "events-api", "exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString(secret)
+	require.NoError(t, err)
+
+	router := NewRouter(handlers.NewHandler(nil, nil, nil, nil, nil), auth.NewVerifier(keys, auth.Config{Issuer: "issuer",
+		Audience: "events-api"}), nil, nil)
+
+	for path, status := range map[string]int{"/venues": http.StatusNotFound, "/events.ics": http.StatusMethodNotAllowed} {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, status, w.Code, path)
+		require.Equal(t, "application/problem+json", w.Header().Get("Content-Type"), path)
+		require.Regexp(t, `"request_id":"[^"]+"`, w.Body.String(), path)
+	}
+}
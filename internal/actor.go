@@ -0,0 +1,33 @@
+package internal
+
+import "context"
+
+type actorKey struct{}
+
+// WithActor records who the request acts for, the subject of its token.
+// Storage writes it as the creator of new events.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor set by WithActor, or "".
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+
+	return actor
+}
+
+type adminKey struct{}
+
+// WithAdmin marks the actor as an admin of its tenant, who holds every role on
+// every calendar and event.
+func WithAdmin(ctx context.Context) context.Context {
+	return context.WithValue(ctx, adminKey{}, true)
+}
+
+// IsAdmin reports whether WithAdmin marked ctx.
+func IsAdmin(ctx context.Context) bool {
+	admin, _ := ctx.Value(adminKey{}).(bool)
+
+	return admin
+}
@@ -0,0 +1,53 @@
+package internal
+
+import "time"
+
+// floatingDate is the calendar date of t in its own location, at midnight UTC,
+// the way all-day events store their dates.
+func floatingDate(t time.Time) time.Time {
+	year, month, day := t.Date()
+
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func isFloatingDate(t time.Time) bool {
+	return t.Equal(floatingDate(t)) && t.Location() == time.UTC
+}
+
+// floatingWindow turns [from, to) into the dates it covers in the location of
+// from and to. The dates of an all-day event overlap the result exactly when
+// the event, laid out in that location, overlaps [from, to). Zero bounds stay zero.
+func floatingWindow(from, to time.Time) (time.Time, time.Time) {
+	if !from.IsZero() {
+		from = floatingDate(from)
+	}
+
+	if !to.IsZero() {
+		end := floatingDate(to)
+
+		// A window ending inside a day still covers that day.
+		if hour, minute, second := to.Clock(); hour != 0 || minute != 0 || second != 0 || to.Nanosecond() != 0 {
+			end = end.AddDate(0, 0, 1)
+		}
+
+		to = end
+	}
+
+	return from, to
+}
+
+// window is the [from, to) window the times of the event are compared with.
+func (e CreateEventResponse) window(from, to time.Time) (time.Time, time.Time) {
+	if e.AllDay {
+		return floatingWindow(from, to)
+	}
+
+	return from, to
+}
+
+// overlaps reports whether the event overlaps [from, to).
+func (e CreateEventResponse) overlaps(from, to time.Time) bool {
+	from, to = e.window(from, to)
+
+	return e.StartTime.Before(to) && e.EndTime.After(from)
+}
@@ -0,0 +1,181 @@
+package internal
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// apiKeyPrefix starts every API key, which reads "evk.<tenant>.<secret>".
+const apiKeyPrefix = "evk."
+
+// DefaultAPIKeyTTL is how long a key lasts when created without an expiry.
+const DefaultAPIKeyTTL = 90 * 24 * time.Hour
+
+// apiKeyTouchInterval limits how often using a key writes its last_used_at.
+const apiKeyTouchInterval = time.Minute
+
+const maxAPIKeyName = 200
+
+type apiKeyStorage interface {
+	CreateAPIKey(ctx context.Context, key APIKey, hash []byte) error
+	GetAPIKeys(ctx context.Context) ([]APIKey, error)
+	GetAPIKeyByHash(ctx context.Context, hash []byte) (APIKey, error)
+	RevokeAPIKey(ctx context.Context, id string, at time.Time) error
+	TouchAPIKey(ctx context.Context, id string, at time.Time) error
+}
+
+type APIKeyService struct {
+	storage apiKeyStorage
+}
+
+func NewAPIKeyService(storage apiKeyStorage) *APIKeyService {
+	return &APIKeyService{
+		storage: storage,
+	}
+}
+
+// IsAPIKey tells API keys apart from user tokens.
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, apiKeyPrefix)
+}
+
+// CreateAPIKey issues a key for the tenant of ctx. The key itself is returned
+// only here. Without an expiry it lasts DefaultAPIKeyTTL.
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, key APIKey) (APIKey, string, error) {
+	tenantID, ok := TenantFromContext(ctx)
+	if !ok {
+		return APIKey{}, "", errNoTenant
+	}
+
+	if key.Name == "" {
+		return APIKey{}, "", fmt.Errorf("empty name: %w", ErrInput)
+	}
+
+	if utf8.RuneCountInString(key.Name) > maxAPIKeyName {
+		return APIKey{}, "", fmt.Errorf("name should have at most %d characters: %w", maxAPIKeyName, ErrInput)
+	}
+
+	if len(key.Scopes) == 0 {
+		return APIKey{}, "", fmt.Errorf("a key needs at least one scope: %w", ErrInput)
+	}
+
+	for _, scope := range key.Scopes {
+		if scope != ScopeEventsRead && scope != ScopeEventsWrite {
+			return APIKey{}, "", fmt.Errorf("scope should be events:read or events:write, got %q: %w", scope, ErrInput)
+		}
+	}
+
+	now := time.Now().UTC()
+
+	if key.ExpiresAt.IsZero() {
+		key.ExpiresAt = now.Add(DefaultAPIKeyTTL)
+	}
+
+	if !key.ExpiresAt.After(now) {
+		return APIKey{}, "", fmt.Errorf("expires_at should be in the future: %w", ErrInput)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return APIKey{}, "", fmt.Errorf("generating key: %w", err)
+	}
+
+	token := apiKeyPrefix + tenantID + "." + base64.RawURLEncoding.EncodeToString(secret)
+
+	slices.Sort(key.Scopes)
+
+	key.ID = uuid.NewString()
+	key.TenantID = tenantID
+	key.Prefix = token[:len(apiKeyPrefix)+len(tenantID)+7]
+	key.CreatedBy = ActorFromContext(ctx)
+	key.CreatedAt = now
+	key.LastUsedAt = nil
+	key.RevokedAt = nil
+
+	if err := s.storage.CreateAPIKey(ctx, key, hashAPIKey(token)); err != nil {
+		return APIKey{}, "", fmt.Errorf("creating api key: %w", err)
+	}
+
+	return key, token, nil
+}
+
+// GetAPIKeys lists the keys of the tenant of ctx, revoked and expired ones
+// included.
+func (s *APIKeyService) GetAPIKeys(ctx context.Context) ([]APIKey, error) {
+	keys, err := s.storage.GetAPIKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting api keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey stops a key from being accepted. The key stays listed.
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("empty id: %w", ErrInput)
+	}
+
+	if err := s.storage.RevokeAPIKey(ctx, id, time.Now().UTC()); err != nil {
+		return fmt.Errorf("revoking api key: %w", err)
+	}
+
+	return nil
+}
+
+// AuthenticateAPIKey returns the key matching token, scoped to the tenant the
+// token names, and records that it was used. Unknown, expired and revoked keys
+// are ErrUnauthorized.
+func (s *APIKeyService) AuthenticateAPIKey(ctx context.Context, token string) (APIKey, error) {
+	tenantID, _, ok := strings.Cut(strings.TrimPrefix(token, apiKeyPrefix), ".")
+	if !IsAPIKey(token) || !ok || ValidateTenantID(tenantID) != nil {
+		return APIKey{}, fmt.Errorf("malformed api key: %w", ErrUnauthorized)
+	}
+
+	ctx = WithTenant(ctx, tenantID)
+
+	key, err := s.storage.GetAPIKeyByHash(ctx, hashAPIKey(token))
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return APIKey{}, fmt.Errorf("unknown api key: %w", ErrUnauthorized)
+		}
+
+		return APIKey{}, fmt.Errorf("getting api key: %w", err)
+	}
+
+	now := time.Now().UTC()
+
+	if key.RevokedAt != nil {
+		return APIKey{}, fmt.Errorf("api key was revoked: %w", ErrUnauthorized)
+	}
+
+	if !now.Before(key.ExpiresAt) {
+		return APIKey{}, fmt.Errorf("api key expired: %w", ErrUnauthorized)
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval {
+		if err := s.storage.TouchAPIKey(ctx, key.ID, now); err != nil {
+			return APIKey{}, fmt.Errorf("recording api key use: %w", err)
+		}
+
+		key.LastUsedAt = &now
+	}
+
+	return key, nil
+}
+
+func hashAPIKey(token string) []byte {
+	hash := sha256.Sum256([]byte(token))
+
+	return hash[:]
+}
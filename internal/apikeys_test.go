@@ -0,0 +1,148 @@
+package internal_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal"
+	"github.com/ObiaNzk/LTK-test-manu/internal/mocks"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+//go:generate mockgen -source=apikeys.go -destination=mocks/mock_api_key_storage.go -package=mocks
+
+type APIKeyServiceTestSuite struct {
+	suite.Suite
+	ctx         context.Context
+	ctrl        *gomock.Controller
+	mockStorage *mocks.MockapiKeyStorage
+	service     *internal.APIKeyService
+}
+
+func (s *APIKeyServiceTestSuite) SetupTest() {
+	s.ctx = internal.WithActor(internal.WithTenant(context.Background(), "acme"), "auth0|ada")
+	s.ctrl = gomock.NewController(s.T())
+	s.mockStorage = mocks.NewMockapiKeyStorage(s.ctrl)
+	s.service = internal.NewAPIKeyService(s.mockStorage)
+}
+
+func (s *APIKeyServiceTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func (s *APIKeyServiceTestSuite) TestCreateAPIKey_Success() {
+	var stored internal.APIKey
+	var storedHash []byte
+
+	s.mockStorage.EXPECT().
+		CreateAPIKey(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, key internal.APIKey, hash []byte) error {
+			stored, storedHash = key, hash
+			return nil
+		})
+
+	key, token, err := s.service.CreateAPIKey(s.ctx, internal.APIKey{
+		Name:   "nightly sync",
+		Scopes: []internal.Scope{internal.ScopeEventsWrite, internal.ScopeEventsRead},
+	})
+
+	require.NoError(s.T(), err)
+	require.True(s.T(), internal.IsAPIKey(token))
+	require.True(s.T(), strings.HasPrefix(token, "evk.acme."))
+	require.True(s.T(), strings.HasPrefix(token, key.Prefix))
+	require.Less(s.T(), len(key.Prefix), len(token))
+
+	hash := sha256.Sum256([]byte(token))
+	require.Equal(s.T(), hash[:], storedHash)
+	require.NotContains(s.T(), string(storedHash), token)
+
+	require.Equal(s.T(), stored, key)
+	require.Equal(s.T(), "acme", key.TenantID)
+	require.Equal(s.T(), "auth0|ada", key.CreatedBy)
+	require.Equal(s.T(), []internal.Scope{internal.ScopeEventsRead, internal.ScopeEventsWrite}, key.Scopes)
+	require.WithinDuration(s.T(), time.Now().Add(internal.DefaultAPIKeyTTL), key.ExpiresAt, time.Minute)
+}
+
+func (s *APIKeyServiceTestSuite) TestCreateAPIKey_Invalid() {
+	read := []internal.Scope{internal.ScopeEventsRead}
+
+	for name, key := range map[string]internal.APIKey{
+		"no name":       {Scopes: read},
+		"long name":     {Name: strings.Repeat("a", 201), Scopes: read},
+		"no scopes":     {Name: "sync"},
+		"unknown scope": {Name: "sync", Scopes: []internal.Scope{"events:delete"}},
+		"expired":       {Name: "sync", Scopes: read, ExpiresAt: time.Now().Add(-time.Hour)},
+	} {
+		_, _, err := s.service.CreateAPIKey(s.ctx, key)
+
+		require.ErrorIs(s.T(), err, internal.ErrInput, name)
+	}
+}
+
+func (s *APIKeyServiceTestSuite) TestAuthenticateAPIKey_Success() {
+	token := "evk.acme.c2VjcmV0"
+	hash := sha256.Sum256([]byte(token))
+	key := internal.APIKey{ID: "key", TenantID: "acme", Scopes: []internal.Scope{internal.ScopeEventsRead}, ExpiresAt: time.Now().Add(time.Hour)}
+
+	s.mockStorage.EXPECT().
+		GetAPIKeyByHash(gomock.Any(), hash[:]).
+		DoAndReturn(func(ctx context.Context, _ []byte) (internal.APIKey, error) {
+			tenantID, _ := internal.TenantFromContext(ctx)
+			require.Equal(s.T(), "acme", tenantID)
+
+			return key, nil
+		})
+	s.mockStorage.EXPECT().TouchAPIKey(gomock.Any(), "key", gomock.Any()).Return(nil)
+
+	authenticated, err := s.service.AuthenticateAPIKey(context.Background(), token)
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), "key", authenticated.ID)
+	require.NotNil(s.T(), authenticated.LastUsedAt)
+}
+
+func (s *APIKeyServiceTestSuite) TestAuthenticateAPIKey_RecentlyUsed() {
+	lastUsed := time.Now().Add(-10 * time.Second)
+	key := internal.APIKey{ID: "key", TenantID: "acme", ExpiresAt: time.Now().Add(time.Hour), LastUsedAt: &lastUsed}
+
+	s.mockStorage.EXPECT().GetAPIKeyByHash(gomock.Any(), gomock.Any()).Return(key, nil)
+
+	_, err := s.service.AuthenticateAPIKey(context.Background(), "evk.acme.c2VjcmV0")
+
+	require.NoError(s.T(), err)
+}
+
+func (s *APIKeyServiceTestSuite) TestAuthenticateAPIKey_Rejected() {
+	revokedAt := time.Now().Add(-time.Minute)
+
+	for name, key := range map[string]internal.APIKey{
+		"revoked": {ID: "key", ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt},
+		"expired": {ID: "key", ExpiresAt: time.Now().Add(-time.Second)},
+	} {
+		s.mockStorage.EXPECT().GetAPIKeyByHash(gomock.Any(), gomock.Any()).Return(key, nil)
+
+		_, err := s.service.AuthenticateAPIKey(context.Background(), "evk.acme.c2VjcmV0")
+
+		require.ErrorIs(s.T(), err, internal.ErrUnauthorized, name)
+	}
+
+	s.mockStorage.EXPECT().GetAPIKeyByHash(gomock.Any(), gomock.Any()).Return(internal.APIKey{}, internal.ErrNotFound)
+
+	_, err := s.service.AuthenticateAPIKey(context.Background(), "evk.acme.c2VjcmV0")
+	require.ErrorIs(s.T(), err, internal.ErrUnauthorized)
+
+	for _, token := range []string{"evk.", "evk.acme", "evk.acme corp.c2VjcmV0", "eyJhbGciOiJIUzI1NiJ9"} {
+		_, err := s.service.AuthenticateAPIKey(context.Background(), token)
+
+		require.ErrorIs(s.T(), err, internal.ErrUnauthorized, token)
+	}
+}
+
+func TestAPIKeyServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(APIKeyServiceTestSuite))
+}
@@ -0,0 +1,182 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal/validation"
+)
+
+type attendeeStorage interface {
+	accessStorage
+	AddAttendee(ctx context.Context, attendee Attendee) error
+	GetAttendees(ctx context.Context, eventID string) ([]Attendee, error)
+	SetAttendeeStatus(ctx context.Context, eventID, email string, status AttendeeStatus, at time.Time) (Attendee, error)
+	RemoveAttendee(ctx context.Context, eventID, email string) error
+	CountAttendees(ctx context.Context, eventID string) (map[AttendeeStatus]int, error)
+}
+
+// AttendeeService manages attendees. Editors of an event invite and remove
+// them, viewers list them and answer their own invitations.
+type AttendeeService struct {
+	storage   attendeeStorage
+	authorize authorizer
+}
+
+func NewAttendeeService(storage attendeeStorage) *AttendeeService {
+	return &AttendeeService{
+		storage:   storage,
+		authorize: authorizer{storage: storage},
+	}
+}
+
+// Invite adds an attendee to an event. The email is lower cased and the role
+// defaults to required.
+func (s *AttendeeService) Invite(ctx context.Context, attendee Attendee) (Attendee, error) {
+	if attendee.EventID == "" {
+		return Attendee{}, fmt.Errorf("empty id: %w", ErrInput)
+	}
+
+	if _, err := s.authorize.event(ctx, attendee.EventID, AccessEditor); err != nil {
+		return Attendee{}, err
+	}
+
+	email, err := normalizeEmail(attendee.Email)
+	if err != nil {
+		return Attendee{}, err
+	}
+
+	if attendee.Role == "" {
+		attendee.Role = RoleRequired
+	}
+
+	if err := inputError(validation.Struct(attendee)); err != nil {
+		return Attendee{}, err
+	}
+
+	now := time.Now().UTC()
+
+	attendee.Email = email
+	attendee.Status = StatusInvited
+	attendee.InvitedAt = now
+	attendee.StatusChangedAt = now
+
+	if err := s.storage.AddAttendee(ctx, attendee); err != nil {
+		return Attendee{}, fmt.Errorf("inviting attendee: %w", err)
+	}
+
+	return attendee, nil
+}
+
+func (s *AttendeeService) GetAttendees(ctx context.Context, eventID string) ([]Attendee, error) {
+	if eventID == "" {
+		return nil, fmt.Errorf("empty id: %w", ErrInput)
+	}
+
+	if _, err := s.authorize.event(ctx, eventID, AccessViewer); err != nil {
+		return nil, err
+	}
+
+	attendees, err := s.storage.GetAttendees(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("getting attendees: %w", err)
+	}
+
+	return attendees, nil
+}
+
+// RSVP records the answer of an attendee. Attendees who can view the event
+// answer for themselves, when their actor is their email, and editors answer for
+// anyone. Answering the same twice keeps the time of the first answer. Accepting
+// a full event returns the attendee as waitlisted.
+func (s *AttendeeService) RSVP(ctx context.Context, eventID, email string, status AttendeeStatus) (Attendee, error) {
+	if eventID == "" {
+		return Attendee{}, fmt.Errorf("empty id: %w", ErrInput)
+	}
+
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return Attendee{}, err
+	}
+
+	need := AccessEditor
+	if strings.EqualFold(ActorFromContext(ctx), email) {
+		need = AccessViewer
+	}
+
+	if _, err := s.authorize.event(ctx, eventID, need); err != nil {
+		return Attendee{}, err
+	}
+
+	switch status {
+	case StatusAccepted, StatusDeclined, StatusTentative:
+	default:
+		return Attendee{}, fmt.Errorf("status should be accepted, declined or tentative: %w", ErrInput)
+	}
+
+	attendee, err := s.storage.SetAttendeeStatus(ctx, eventID, email, status, time.Now().UTC())
+	if err != nil {
+		return Attendee{}, fmt.Errorf("answering invitation: %w", err)
+	}
+
+	return attendee, nil
+}
+
+func (s *AttendeeService) RemoveAttendee(ctx context.Context, eventID, email string) error {
+	if eventID == "" {
+		return fmt.Errorf("empty id: %w", ErrInput)
+	}
+
+	if _, err := s.authorize.event(ctx, eventID, AccessEditor); err != nil {
+		return err
+	}
+
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return err
+	}
+
+	if err := s.storage.RemoveAttendee(ctx, eventID, email); err != nil {
+		return fmt.Errorf("removing attendee: %w", err)
+	}
+
+	return nil
+}
+
+// CountAttendees counts the attendees of an event by status. Every status is
+// present, with zero when nobody is in it.
+func (s *AttendeeService) CountAttendees(ctx context.Context, eventID string) (map[AttendeeStatus]int, error) {
+	if eventID == "" {
+		return nil, fmt.Errorf("empty id: %w", ErrInput)
+	}
+
+	if _, err := s.authorize.event(ctx, eventID, AccessViewer); err != nil {
+		return nil, err
+	}
+
+	stored, err := s.storage.CountAttendees(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("counting attendees: %w", err)
+	}
+
+	counts := map[AttendeeStatus]int{StatusInvited: 0, StatusAccepted: 0, StatusDeclined: 0, StatusTentative: 0, StatusWaitlisted: 0}
+	for status, count := range stored {
+		counts[status] = count
+	}
+
+	return counts, nil
+}
+
+// normalizeEmail checks email is a bare address and lower cases it, so the
+// same person cannot be invited twice with different casing.
+func normalizeEmail(email string) (string, error) {
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return "", fmt.Errorf("invalid email %q: %w", email, ErrInput)
+	}
+
+	return strings.ToLower(email), nil
+}
@@ -0,0 +1,184 @@
+package internal_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal"
+	"github.com/ObiaNzk/LTK-test-manu/internal/mocks"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+//go:generate mockgen -source=attendees.go -destination=mocks/mock_attendee_storage.go -package=mocks
+
+type AttendeeServiceTestSuite struct {
+	suite.Suite
+	ctx         context.Context
+	ctrl        *gomock.Controller
+	mockStorage *mocks.MockattendeeStorage
+	service     *internal.AttendeeService
+}
+
+func (s *AttendeeServiceTestSuite) SetupTest() {
+	// Admins hold every role, so these tests need no access lookups.
+	s.ctx = internal.WithAdmin(context.Background())
+	s.ctrl = gomock.NewController(s.T())
+	s.mockStorage = mocks.NewMockattendeeStorage(s.ctrl)
+	s.service = internal.NewAttendeeService(s.mockStorage)
+}
+
+func (s *AttendeeServiceTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func (s *AttendeeServiceTestSuite) TestInvite_Success() {
+	ctx := s.ctx
+
+	s.mockStorage.EXPECT().AddAttendee(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, attendee internal.Attendee) error {
+			require.Equal(s.T(), "ada@example.com", attendee.Email)
+			require.Equal(s.T(), internal.RoleRequired, attendee.Role)
+			require.Equal(s.T(), internal.StatusInvited, attendee.Status)
+			require.False(s.T(), attendee.InvitedAt.IsZero())
+			require.Equal(s.T(), attendee.InvitedAt, attendee.StatusChangedAt)
+
+			return nil
+		})
+
+	attendee, err := s.service.Invite(ctx, internal.Attendee{EventID: "event", Email: "Ada@Example.com", Name: "Ada"})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), "ada@example.com", attendee.Email)
+	require.Equal(s.T(), "Ada", attendee.Name)
+}
+
+func (s *AttendeeServiceTestSuite) TestInvite_InvalidInput() {
+	invalid := map[string]internal.Attendee{
+		"empty event":  {Email: "ada@example.com"},
+		"empty email":  {EventID: "event"},
+		"bad email":    {EventID: "event", Email: "ada"},
+		"display name": {EventID: "event", Email: "Ada <ada@example.com>"},
+		"bad role":     {EventID: "event", Email: "ada@example.com", Role: "host"},
+	}
+
+	for name, attendee := range invalid {
+		_, err := s.service.Invite(s.ctx, attendee)
+
+		require.ErrorIs(s.T(), err, internal.ErrInput, name)
+	}
+}
+
+func (s *AttendeeServiceTestSuite) TestInvite_AlreadyInvited() {
+	s.mockStorage.EXPECT().AddAttendee(gomock.Any(), gomock.Any()).Return(internal.ErrConflict)
+
+	_, err := s.service.Invite(s.ctx, internal.Attendee{EventID: "event", Email: "ada@example.com", Role: internal.RoleChair})
+
+	require.ErrorIs(s.T(), err, internal.ErrConflict)
+}
+
+func (s *AttendeeServiceTestSuite) TestRSVP_Success() {
+	answered := internal.Attendee{EventID: "event", Email: "ada@example.com", Status: internal.StatusTentative}
+
+	s.mockStorage.EXPECT().
+		SetAttendeeStatus(gomock.Any(), "event", "ada@example.com", internal.StatusTentative, gomock.Any()).
+		Return(answered, nil)
+
+	attendee, err := s.service.RSVP(s.ctx, "event", "ADA@example.com", internal.StatusTentative)
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), answered, attendee)
+}
+
+func (s *AttendeeServiceTestSuite) TestRSVP_OwnInvitation() {
+	ctx := internal.WithActor(internal.WithTenant(context.Background(), "acme"), "ada@example.com")
+
+	s.mockStorage.EXPECT().
+		GetEventAccess(gomock.Any(), "ada@example.com", "event").
+		Return(internal.EventAccess{CalendarID: "team", Roles: []internal.AccessRole{internal.AccessViewer}}, nil)
+	s.mockStorage.EXPECT().
+		SetAttendeeStatus(gomock.Any(), "event", "ada@example.com", internal.StatusAccepted, gomock.Any()).
+		Return(internal.Attendee{EventID: "event", Email: "ada@example.com", Status: internal.StatusAccepted}, nil)
+
+	_, err := s.service.RSVP(ctx, "event", "Ada@Example.com", internal.StatusAccepted)
+
+	require.NoError(s.T(), err)
+}
+
+func (s *AttendeeServiceTestSuite) TestRSVP_OtherAttendee() {
+	ctx := internal.WithActor(internal.WithTenant(context.Background(), "acme"), "ada@example.com")
+
+	// A viewer cannot answer for someone else, an editor can.
+	s.mockStorage.EXPECT().
+		GetEventAccess(gomock.Any(), "ada@example.com", "event").
+		Return(internal.EventAccess{CalendarID: "team", Roles: []internal.AccessRole{internal.AccessViewer}}, nil)
+
+	_, err := s.service.RSVP(ctx, "event", "bob@example.com", internal.StatusAccepted)
+
+	require.ErrorIs(s.T(), err, internal.ErrForbidden)
+
+	s.mockStorage.EXPECT().
+		GetEventAccess(gomock.Any(), "ada@example.com", "event").
+		Return(internal.EventAccess{CalendarID: "team", Roles: []internal.AccessRole{internal.AccessEditor}}, nil)
+	s.mockStorage.EXPECT().
+		SetAttendeeStatus(gomock.Any(), "event", "bob@example.com", internal.StatusDeclined, gomock.Any()).
+		Return(internal.Attendee{EventID: "event", Email: "bob@example.com", Status: internal.StatusDeclined}, nil)
+
+	_, err = s.service.RSVP(ctx, "event", "bob@example.com", internal.StatusDeclined)
+
+	require.NoError(s.T(), err)
+}
+
+func (s *AttendeeServiceTestSuite) TestRSVP_InvalidStatus() {
+	for _, status := range []internal.AttendeeStatus{"", internal.StatusInvited, "maybe"} {
+		_, err := s.service.RSVP(s.ctx, "event", "ada@example.com", status)
+
+		require.ErrorIs(s.T(), err, internal.ErrInput, string(status))
+	}
+}
+
+func (s *AttendeeServiceTestSuite) TestRSVP_NotFound() {
+	s.mockStorage.EXPECT().
+		SetAttendeeStatus(gomock.Any(), "event", "ada@example.com", internal.StatusAccepted, gomock.Any()).
+		Return(internal.Attendee{}, internal.ErrNotFound)
+
+	_, err := s.service.RSVP(s.ctx, "event", "ada@example.com", internal.StatusAccepted)
+
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+}
+
+func (s *AttendeeServiceTestSuite) TestGetAttendees_StorageError() {
+	s.mockStorage.EXPECT().GetAttendees(gomock.Any(), "event").Return(nil, errors.New("connection refused"))
+
+	_, err := s.service.GetAttendees(s.ctx, "event")
+
+	require.EqualError(s.T(), err, "getting attendees: connection refused")
+}
+
+func (s *AttendeeServiceTestSuite) TestRemoveAttendee_Success() {
+	s.mockStorage.EXPECT().RemoveAttendee(gomock.Any(), "event", "ada@example.com").Return(nil)
+
+	require.NoError(s.T(), s.service.RemoveAttendee(s.ctx, "event", "Ada@example.com"))
+}
+
+func (s *AttendeeServiceTestSuite) TestCountAttendees_FillsMissingStatuses() {
+	s.mockStorage.EXPECT().CountAttendees(gomock.Any(), "event").
+		Return(map[internal.AttendeeStatus]int{internal.StatusAccepted: 3}, nil)
+
+	counts, err := s.service.CountAttendees(s.ctx, "event")
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), map[internal.AttendeeStatus]int{
+		internal.StatusInvited:    0,
+		internal.StatusAccepted:   3,
+		internal.StatusDeclined:   0,
+		internal.StatusTentative:  0,
+		internal.StatusWaitlisted: 0,
+	}, counts)
+}
+
+func TestAttendeeServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(AttendeeServiceTestSuite))
+}
@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// KeySet holds the keys tokens can be signed with, by key id. HS256 keys are
+// []byte and RS256 keys *rsa.PublicKey.
+type KeySet map[string]any
+
+// jwk is one key of a JSON Web Key Set (RFC 7517). Only the fields of
+// symmetric ("oct") and RSA keys are read.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	K   string `json:"k"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// LoadKeySet reads a JWKS file.
+func LoadKeySet(path string) (KeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading key set: %w", err)
+	}
+
+	return ParseKeySet(data)
+}
+
+// ParseKeySet reads the oct and RSA keys of a JWKS document. Keys meant for
+// something other than signatures, or for another algorithm, are rejected so a
+// misconfigured file fails at startup rather than on the first request.
+func ParseKeySet(data []byte) (KeySet, error) {
+	var document struct {
+		Keys []jwk `json:"keys"`
+	}
+
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("parsing key set: %w", err)
+	}
+
+	if len(document.Keys) == 0 {
+		return nil, errors.New("key set has no keys")
+	}
+
+	keys := make(KeySet, len(document.Keys))
+
+	for i, key := range document.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			return nil, fmt.Errorf("key %d: unsupported use %q", i, key.Use)
+		}
+
+		if _, ok := keys[key.Kid]; ok {
+			return nil, fmt.Errorf("key %d: duplicate kid %q", i, key.Kid)
+		}
+
+		parsed, err := parseKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("key %d: %w", i, err)
+		}
+
+		keys[key.Kid] = parsed
+	}
+
+	return keys, nil
+}
+
+func parseKey(key jwk) (any, error) {
+	switch key.Kty {
+	case "oct":
+		if key.Alg != "" && key.Alg != "HS256" {
+			return nil, fmt.Errorf("unsupported alg %q for an oct key", key.Alg)
+		}
+
+		secret, err := base64.RawURLEncoding.DecodeString(key.K)
+		if err != nil {
+			return nil, fmt.Errorf("decoding k: %w", err)
+		}
+
+		if len(secret) < 32 {
+			return nil, errors.New("HS256 keys need at least 256 bits")
+		}
+
+		return secret, nil
+
+	case "RSA":
+		if key.Alg != "" && key.Alg != "RS256" {
+			return nil, fmt.Errorf("unsupported alg %q for an RSA key", key.Alg)
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(key.N)
+		if err != nil {
+			return nil, fmt.Errorf("decoding n: %w", err)
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(key.E)
+		if err != nil {
+			return nil, fmt.Errorf("decoding e: %w", err)
+		}
+
+		exponent := new(big.Int).SetBytes(e)
+		if len(n) == 0 || !exponent.IsInt64() || exponent.Int64() < 3 {
+			return nil, errors.New("invalid RSA key")
+		}
+
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported kty %q", key.Kty)
+	}
+}
@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Claims are the claims of a verified token. Tenant names the tenant the token
+// acts for, which every token needs: Authenticate rejects tokens without one.
+// Admin lets the token manage API keys.
+type Claims struct {
+	jwt.RegisteredClaims
+	Tenant string `json:"tenant,omitempty"`
+	Admin  bool   `json:"admin,omitempty"`
+}
+
+// Config names the JWKS file and what tokens must be issued by and for.
+type Config struct {
+	JWKSFile string
+	Issuer   string
+	Audience string
+	// Leeway absorbs clock skew when checking exp and nbf.
+	Leeway time.Duration
+}
+
+// Verifier checks bearer tokens signed with HS256 or RS256.
+type Verifier struct {
+	keys   KeySet
+	parser *jwt.Parser
+}
+
+func NewVerifier(keys KeySet, config Config) *Verifier {
+	return &Verifier{
+		keys: keys,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodRS256.Alg()}),
+			jwt.WithIssuer(config.Issuer),
+			jwt.WithAudience(config.Audience),
+			jwt.WithExpirationRequired(),
+			jwt.WithLeeway(config.Leeway),
+		),
+	}
+}
+
+// Verify checks the signature, exp, nbf, iss and aud of a token and that it
+// names a subject.
+func (v *Verifier) Verify(token string) (Claims, error) {
+	var claims Claims
+
+	if _, err := v.parser.ParseWithClaims(token, &claims, v.key); err != nil {
+		return Claims{}, fmt.Errorf("verifying token: %w", err)
+	}
+
+	if claims.Subject == "" {
+		return Claims{}, errors.New("verifying token: no subject")
+	}
+
+	return claims, nil
+}
+
+// key picks the key named by the kid header, or the only key of the set when
+// the token names none. The key type must match the algorithm, so an RSA public
+// key can never be used as an HMAC secret.
+func (v *Verifier) key(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, ok := v.keys[kid]
+	if !ok && kid == "" && len(v.keys) == 1 {
+		for _, only := range v.keys {
+			key, ok = only, true
+		}
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+
+	switch key.(type) {
+	case []byte:
+		if token.Method != jwt.SigningMethodHS256 {
+			return nil, fmt.Errorf("key %q does not sign %s", kid, token.Method.Alg())
+		}
+	case *rsa.PublicKey:
+		if token.Method != jwt.SigningMethodRS256 {
+			return nil, fmt.Errorf("key %q does not sign %s", kid, token.Method.Alg())
+		}
+	}
+
+	return key, nil
+}
@@ -0,0 +1,134 @@
+package auth_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal/auth"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+var secret = []byte("0123456789abcdef0123456789abcdef")
+
+type VerifierTestSuite struct {
+	suite.Suite
+	rsaKey   *rsa.PrivateKey
+	verifier *auth.Verifier
+}
+
+func (s *VerifierTestSuite) SetupSuite() {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(s.T(), err)
+
+	s.rsaKey = key
+
+	keys, err := auth.ParseKeySet([]byte(fmt.Sprintf(`{"keys": [
+		{"kty": "oct", "kid": "hmac", "alg": "HS256", "k": %q},
+		{"kty": "RSA", "kid": "rsa", "alg": "RS256", "use": "sig", "n": %q, "e": %q}
+	]}`, encode(secret), encode(key.N.Bytes()), encode(big.NewInt(int64(key.E)).Bytes()))))
+	require.NoError(s.T(), err)
+
+	s.verifier = auth.NewVerifier(keys, auth.Config{Issuer: "https://issuer.example.com", Audience: "events-api"})
+}
+
+func encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":    "auth0|ada",
+		"iss":    "https://issuer.example.com",
+		"aud":    "events-api",
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"nbf":    time.Now().Add(-time.Minute).Unix(),
+		"tenant": "acme",
+	}
+}
+
+func (s *VerifierTestSuite) sign(method jwt.SigningMethod, kid string, key any, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+
+	signed, err := token.SignedString(key)
+	require.NoError(s.T(), err)
+
+	return signed
+}
+
+func (s *VerifierTestSuite) TestVerify_HS256() {
+	claims, err := s.verifier.Verify(s.sign(jwt.SigningMethodHS256, "hmac", secret, validClaims()))
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), "auth0|ada", claims.Subject)
+	require.Equal(s.T(), "acme", claims.Tenant)
+}
+
+func (s *VerifierTestSuite) TestVerify_RS256() {
+	claims, err := s.verifier.Verify(s.sign(jwt.SigningMethodRS256, "rsa", s.rsaKey, validClaims()))
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), "auth0|ada", claims.Subject)
+}
+
+func (s *VerifierTestSuite) TestVerify_RejectsClaims() {
+	for name, change := range map[string]func(jwt.MapClaims){
+		"expired":        func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() },
+		"no exp":         func(c jwt.MapClaims) { delete(c, "exp") },
+		"not yet valid":  func(c jwt.MapClaims) { c["nbf"] = time.Now().Add(time.Hour).Unix() },
+		"other audience": func(c jwt.MapClaims) { c["aud"] = "billing-api" },
+		"other issuer":   func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" },
+		"no subject":     func(c jwt.MapClaims) { delete(c, "sub") },
+	} {
+		claims := validClaims()
+		change(claims)
+
+		_, err := s.verifier.Verify(s.sign(jwt.SigningMethodHS256, "hmac", secret, claims))
+
+		require.Error(s.T(), err, name)
+	}
+}
+
+func (s *VerifierTestSuite) TestVerify_RejectsKeys() {
+	other := []byte("fedcba9876543210fedcba9876543210")
+
+	for name, token := range map[string]string{
+		"wrong secret": s.sign(jwt.SigningMethodHS256, "hmac", other, validClaims()),
+		"unknown kid":  s.sign(jwt.SigningMethodHS256, "missing", secret, validClaims()),
+		// An RSA public key is public, so it must never verify an HMAC.
+		"rsa key as hmac secret": s.sign(jwt.SigningMethodHS256, "rsa", s.rsaKey.N.Bytes(), validClaims()),
+		"hmac key for rs256":     s.sign(jwt.SigningMethodRS256, "hmac", s.rsaKey, validClaims()),
+		"unsigned":               s.sign(jwt.SigningMethodNone, "hmac", jwt.UnsafeAllowNoneSignatureType, validClaims()),
+	} {
+		_, err := s.verifier.Verify(token)
+
+		require.Error(s.T(), err, name)
+	}
+}
+
+func (s *VerifierTestSuite) TestParseKeySet_Invalid() {
+	for name, document := range map[string]string{
+		"no keys":       `{"keys": []}`,
+		"short secret":  fmt.Sprintf(`{"keys": [{"kty": "oct", "k": %q}]}`, encode([]byte("short"))),
+		"unknown kty":   `{"keys": [{"kty": "EC", "crv": "P-256"}]}`,
+		"encryption":    fmt.Sprintf(`{"keys": [{"kty": "oct", "use": "enc", "k": %q}]}`, encode(secret)),
+		"other alg":     fmt.Sprintf(`{"keys": [{"kty": "oct", "alg": "HS512", "k": %q}]}`, encode(secret)),
+		"duplicate kid": fmt.Sprintf(`{"keys": [{"kty": "oct", "kid": "a", "k": %q}, {"kty": "oct", "kid": "a", "k": %q}]}`, encode(secret), encode(secret)),
+		"not json":      `keys`,
+	} {
+		_, err := auth.ParseKeySet([]byte(document))
+
+		require.Error(s.T(), err, name)
+	}
+}
+
+func TestVerifierTestSuite(t *testing.T) {
+	suite.Run(t, new(VerifierTestSuite))
+}
@@ -0,0 +1,147 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+type accessStorage interface {
+	GetCalendarAccess(ctx context.Context, principal, calendarID string) ([]AccessRole, error)
+	GetEventAccess(ctx context.Context, principal, eventID string) (EventAccess, error)
+	// GetEventAccessByUID finds the event the way an import does, by id or iCalendar UID.
+	GetEventAccessByUID(ctx context.Context, principal, uid string) (EventAccess, error)
+}
+
+// roleRanks orders roles so that a higher one includes the lower ones.
+var roleRanks = map[AccessRole]int{AccessViewer: 1, AccessEditor: 2, AccessOwner: 3}
+
+// authorizer checks the role of the actor of ctx. Services call it before
+// anything else touches storage.
+//
+// Besides its grants, an actor owns the calendars and events it created and
+// edits the default calendar, which the whole tenant shares. Admins hold every
+// role. Requests without an actor are denied.
+type authorizer struct {
+	storage accessStorage
+}
+
+// member lets through any actor of the tenant.
+func (a authorizer) member(ctx context.Context) error {
+	if IsAdmin(ctx) {
+		return nil
+	}
+
+	if ActorFromContext(ctx) == "" {
+		return fmt.Errorf("no actor: %w", ErrForbidden)
+	}
+
+	return nil
+}
+
+// visibleTo is the principal listings are restricted to, "" for admins.
+func (a authorizer) visibleTo(ctx context.Context) (string, error) {
+	if err := a.member(ctx); err != nil {
+		return "", err
+	}
+
+	if IsAdmin(ctx) {
+		return "", nil
+	}
+
+	return ActorFromContext(ctx), nil
+}
+
+func (a authorizer) calendar(ctx context.Context, id string, need AccessRole) error {
+	if IsAdmin(ctx) {
+		return nil
+	}
+
+	principal := ActorFromContext(ctx)
+	if principal == "" {
+		return fmt.Errorf("no actor: %w", ErrForbidden)
+	}
+
+	roles, err := a.storage.GetCalendarAccess(ctx, principal, id)
+	if err != nil {
+		return fmt.Errorf("getting calendar access: %w", err)
+	}
+
+	if id == DefaultCalendarID {
+		roles = append(roles, AccessEditor)
+	}
+
+	if !allows(roles, need) {
+		return fmt.Errorf("%s role on calendar %q required: %w", need, id, ErrForbidden)
+	}
+
+	return nil
+}
+
+// event checks the role of the actor on an event, which includes its role on
+// the calendar of the event. Admins get a zero EventAccess back.
+func (a authorizer) event(ctx context.Context, id string, need AccessRole) (EventAccess, error) {
+	return a.checkEvent(ctx, id, need, a.storage.GetEventAccess)
+}
+
+// importedEvent checks the actor may overwrite the event an import with uid
+// would update. ErrNotFound means the import creates it.
+func (a authorizer) importedEvent(ctx context.Context, uid string) error {
+	_, err := a.checkEvent(ctx, uid, AccessEditor, a.storage.GetEventAccessByUID)
+
+	return err
+}
+
+func (a authorizer) checkEvent(ctx context.Context, id string, need AccessRole,
+	get func(ctx context.Context, principal, id string) (EventAccess, error)) (EventAccess, error) {
+	if IsAdmin(ctx) {
+		return EventAccess{}, nil
+	}
+
+	principal := ActorFromContext(ctx)
+	if principal == "" {
+		return EventAccess{}, fmt.Errorf("no actor: %w", ErrForbidden)
+	}
+
+	access, err := get(ctx, principal, id)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return EventAccess{}, err
+		}
+
+		return EventAccess{}, fmt.Errorf("getting event access: %w", err)
+	}
+
+	roles := access.Roles
+	if access.CalendarID == DefaultCalendarID {
+		roles = append(slices.Clone(roles), AccessEditor)
+	}
+
+	if !allows(roles, need) {
+		return EventAccess{}, fmt.Errorf("%s role on event %q required: %w", need, id, ErrForbidden)
+	}
+
+	return access, nil
+}
+
+// object checks the role of the actor on the calendar or event a grant is on.
+func (a authorizer) object(ctx context.Context, objectType ObjectType, id string, need AccessRole) error {
+	if objectType == ObjectCalendar {
+		return a.calendar(ctx, id, need)
+	}
+
+	_, err := a.event(ctx, id, need)
+
+	return err
+}
+
+func allows(roles []AccessRole, need AccessRole) bool {
+	for _, role := range roles {
+		if roleRanks[role] >= roleRanks[need] {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,178 @@
+package internal_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal"
+	"github.com/ObiaNzk/LTK-test-manu/internal/mocks"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+// AuthorizationTestSuite runs the event service as a regular member, so every
+// call first looks up the role of auth0|ada.
+type AuthorizationTestSuite struct {
+	suite.Suite
+	ctx         context.Context
+	ctrl        *gomock.Controller
+	mockStorage *mocks.Mockstorage
+	service     *internal.Service
+}
+
+func (s *AuthorizationTestSuite) SetupTest() {
+	s.ctx = internal.WithActor(internal.WithTenant(context.Background(), "acme"), "auth0|ada")
+	s.ctrl = gomock.NewController(s.T())
+	s.mockStorage = mocks.NewMockstorage(s.ctrl)
+	s.service = internal.NewService(s.mockStorage)
+}
+
+func (s *AuthorizationTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func (s *AuthorizationTestSuite) access(calendarID string, roles ...internal.AccessRole) {
+	s.mockStorage.EXPECT().
+		GetEventAccess(gomock.Any(), "auth0|ada", "evt").
+		Return(internal.EventAccess{CalendarID: calendarID, Roles: roles}, nil)
+}
+
+func (s *AuthorizationTestSuite) TestGetEventByID_Viewer() {
+	s.access("team", internal.AccessViewer)
+	s.mockStorage.EXPECT().GetEventByID(gomock.Any(), "evt", false).Return(internal.CreateEventResponse{ID: "evt"}, nil)
+
+	event, err := s.service.GetEventByID(s.ctx, "evt", false)
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), "evt", event.ID)
+}
+
+func (s *AuthorizationTestSuite) TestGetEventByID_NoRole() {
+	// The storage is never read past the access lookup.
+	s.access("team")
+
+	_, err := s.service.GetEventByID(s.ctx, "evt", false)
+
+	require.ErrorIs(s.T(), err, internal.ErrForbidden)
+}
+
+func (s *AuthorizationTestSuite) TestGetEventByID_NotFound() {
+	s.mockStorage.EXPECT().
+		GetEventAccess(gomock.Any(), "auth0|ada", "evt").
+		Return(internal.EventAccess{}, internal.ErrNotFound)
+
+	_, err := s.service.GetEventByID(s.ctx, "evt", false)
+
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+}
+
+func (s *AuthorizationTestSuite) TestGetEventByID_NoActor() {
+	_, err := s.service.GetEventByID(internal.WithTenant(context.Background(), "acme"), "evt", false)
+
+	require.ErrorIs(s.T(), err, internal.ErrForbidden)
+}
+
+func (s *AuthorizationTestSuite) TestDeleteEvent_ViewerIsNotEnough() {
+	s.access("team", internal.AccessViewer)
+
+	err := s.service.DeleteEvent(s.ctx, "evt")
+
+	require.ErrorIs(s.T(), err, internal.ErrForbidden)
+}
+
+func (s *AuthorizationTestSuite) TestDeleteEvent_DefaultCalendar() {
+	// Members edit events of the default calendar without a grant.
+	s.access(internal.DefaultCalendarID)
+	s.mockStorage.EXPECT().DeleteEvent(gomock.Any(), "evt").Return(nil)
+
+	err := s.service.DeleteEvent(s.ctx, "evt")
+
+	require.NoError(s.T(), err)
+}
+
+func (s *AuthorizationTestSuite) TestGetEventHistory_Viewer() {
+	s.access("team", internal.AccessViewer)
+	s.mockStorage.EXPECT().GetEventRevisions(gomock.Any(), "evt").Return([]internal.EventRevision{}, nil)
+
+	_, err := s.service.GetEventHistory(s.ctx, "evt")
+
+	require.NoError(s.T(), err)
+}
+
+func (s *AuthorizationTestSuite) TestRevertEvent_ViewerIsNotEnough() {
+	s.access("team", internal.AccessViewer)
+
+	_, err := s.service.RevertEvent(s.ctx, "evt", 1, 0)
+
+	require.ErrorIs(s.T(), err, internal.ErrForbidden)
+}
+
+func (s *AuthorizationTestSuite) TestCreateEvent_Forbidden() {
+	s.mockStorage.EXPECT().GetCalendarAccess(gomock.Any(), "auth0|ada", "team").
+		Return([]internal.AccessRole{internal.AccessViewer}, nil)
+
+	_, err := s.service.CreateEvent(s.ctx, internal.CreateEventRequest{CalendarID: "team"})
+
+	require.ErrorIs(s.T(), err, internal.ErrForbidden)
+}
+
+func (s *AuthorizationTestSuite) TestGetEvents_VisibleTo() {
+	s.mockStorage.EXPECT().
+		GetEvents(gomock.Any(), internal.EventFilter{VisibleTo: "auth0|ada"}, gomock.Any()).
+		Return(nil, nil)
+
+	_, err := s.service.GetEvents(s.ctx, internal.EventFilter{}, internal.PageRequest{})
+
+	require.NoError(s.T(), err)
+}
+
+func (s *AuthorizationTestSuite) TestGetEvents_AdminSeesEverything() {
+	s.mockStorage.EXPECT().GetEvents(gomock.Any(), internal.EventFilter{}, gomock.Any()).Return(nil, nil)
+
+	_, err := s.service.GetEvents(internal.WithAdmin(s.ctx), internal.EventFilter{}, internal.PageRequest{})
+
+	require.NoError(s.T(), err)
+}
+
+func (s *AuthorizationTestSuite) TestGetEvents_CalendarForbidden() {
+	s.mockStorage.EXPECT().GetCalendarAccess(gomock.Any(), "auth0|ada", "team").Return(nil, nil)
+
+	_, err := s.service.GetEvents(s.ctx, internal.EventFilter{CalendarID: "team"}, internal.PageRequest{})
+
+	require.ErrorIs(s.T(), err, internal.ErrForbidden)
+}
+
+func (s *AuthorizationTestSuite) TestImportEvents_RejectsEventsOfOthers() {
+	start := time.Date(2030, 1, 1, 10, 0, 0, 0, time.UTC)
+	event := internal.CreateEventRequest{Title: strings.Repeat("a", 101), Description: "Daily", StartTime: start,
+		EndTime: start.Add(time.Hour), TimeZone: "UTC"}
+
+	s.mockStorage.EXPECT().GetCalendarAccess(gomock.Any(), "auth0|ada", internal.DefaultCalendarID).Return(nil, nil)
+	s.mockStorage.EXPECT().
+		GetEventAccessByUID(gomock.Any(), "auth0|ada", "theirs@example.com").
+		Return(internal.EventAccess{CalendarID: "team", Roles: []internal.AccessRole{internal.AccessViewer}}, nil)
+	s.mockStorage.EXPECT().
+		GetEventAccessByUID(gomock.Any(), "auth0|ada", "new@example.com").
+		Return(internal.EventAccess{}, internal.ErrNotFound)
+	s.mockStorage.EXPECT().
+		ImportEvents(gomock.Any(), []internal.ImportedEvent{{UID: "new@example.com", Event: event}}).
+		Return([]internal.ImportResult{{UID: "new@example.com", ID: "evt", Status: internal.ImportCreated}}, nil)
+
+	results, err := s.service.ImportEvents(s.ctx, []internal.ImportedEvent{
+		{UID: "theirs@example.com", Event: event},
+		{UID: "new@example.com", Event: event},
+	})
+
+	require.NoError(s.T(), err)
+	require.Len(s.T(), results, 2)
+	require.Equal(s.T(), internal.ImportRejected, results[0].Status)
+	require.Contains(s.T(), results[0].Reason, "editor role")
+	require.Equal(s.T(), internal.ImportCreated, results[1].Status)
+}
+
+func TestAuthorizationTestSuite(t *testing.T) {
+	suite.Run(t, new(AuthorizationTestSuite))
+}
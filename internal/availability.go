@@ -0,0 +1,297 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal/interval"
+)
+
+const (
+	DefaultSlotLimit = 10
+	MaxSlotLimit     = 100
+
+	// maxAvailabilityWindow bounds how many days of events a search expands.
+	maxAvailabilityWindow = 62 * 24 * time.Hour
+
+	// slotStep is the granularity of candidate slots inside a free interval.
+	slotStep = 15 * time.Minute
+)
+
+// WorkingHours is the part of each day a slot may use, as offsets from local
+// midnight. Days defaults to Monday to Friday.
+type WorkingHours struct {
+	Start time.Duration
+	End   time.Duration
+	Days  []time.Weekday
+}
+
+// AvailabilityQuery asks when every one of Resources and CalendarIDs is free
+// for Duration inside [From, To). A calendar is busy during each of its events.
+// Without WorkingHours every hour of every day counts.
+type AvailabilityQuery struct {
+	Resources    []string
+	CalendarIDs  []string
+	From         time.Time
+	To           time.Time
+	Duration     time.Duration
+	WorkingHours *WorkingHours
+	// TimeZone is the IANA zone working hours and all-day events are read in. Defaults to UTC.
+	TimeZone string
+	Limit    int
+}
+
+// Availability is the result of a search. Busy and Free are clipped to the
+// window, Slots are ranked best first.
+type Availability struct {
+	Busy  interval.Set
+	Free  interval.Set
+	Slots []interval.Interval
+}
+
+// SearchAvailability finds the intervals where none of the resources is booked
+// and none of the calendars has an event, including occurrences of recurring
+// events, and proposes slots inside them.
+//
+// Every actor of the tenant may search resources, and calendars it can view.
+// A calendar that does not exist is ErrNotFound rather than free time. Busy
+// time counts every event, those the actor cannot view included, but never
+// says which event it is.
+func (s *Service) SearchAvailability(ctx context.Context, query AvailabilityQuery) (Availability, error) {
+	if err := s.authorize.member(ctx); err != nil {
+		return Availability{}, err
+	}
+
+	loc, limit, err := validateAvailabilityQuery(query)
+	if err != nil {
+		return Availability{}, err
+	}
+
+	for _, id := range query.CalendarIDs {
+		if err := s.authorize.calendar(ctx, id, AccessViewer); err != nil {
+			return Availability{}, err
+		}
+
+		if _, err := s.storage.GetCalendar(ctx, id); err != nil {
+			return Availability{}, fmt.Errorf("getting calendar %q: %w", id, err)
+		}
+	}
+
+	from, to := query.From.In(loc), query.To.In(loc)
+
+	// An event booking a resource in one of the calendars is found twice.
+	var events []CreateEventResponse
+
+	seen := make(map[string]bool)
+
+	for _, filter := range []EventFilter{
+		{From: from, To: to, Resources: query.Resources},
+		{From: from, To: to, CalendarIDs: query.CalendarIDs},
+	} {
+		if len(filter.Resources) == 0 && len(filter.CalendarIDs) == 0 {
+			continue
+		}
+
+		batch, err := s.allEvents(ctx, filter)
+		if err != nil {
+			return Availability{}, fmt.Errorf("getting events: %w", err)
+		}
+
+		for _, event := range batch {
+			if !seen[event.ID] {
+				seen[event.ID] = true
+				events = append(events, event)
+			}
+		}
+	}
+
+	occurrences, err := s.expand(ctx, events, from, to)
+	if err != nil {
+		return Availability{}, fmt.Errorf("getting events: %w", err)
+	}
+
+	window := interval.New(interval.Interval{Start: from, End: to})
+
+	busy := make([]interval.Interval, 0, len(occurrences))
+	for _, occurrence := range occurrences {
+		busy = append(busy, busyInterval(occurrence, loc))
+	}
+
+	availability := Availability{Busy: interval.New(busy...).Intersect(window)}
+	availability.Free = workingIntervals(query.WorkingHours, from, to, loc).Intersect(window).Subtract(availability.Busy)
+	availability.Slots = candidateSlots(availability.Free, query.Duration, loc, limit)
+
+	return availability, nil
+}
+
+// allEvents reads every page of the events matching filter.
+func (s *Service) allEvents(ctx context.Context, filter EventFilter) ([]CreateEventResponse, error) {
+	var events []CreateEventResponse
+
+	page := Page{Limit: MaxPageSize}
+
+	for {
+		batch, err := s.storage.GetEvents(ctx, filter, page)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, batch...)
+
+		if len(batch) < page.Limit {
+			return events, nil
+		}
+
+		last := batch[len(batch)-1]
+		page.After = &Cursor{StartTime: last.StartTime, ID: last.ID}
+	}
+}
+
+// busyInterval is the time an occurrence blocks. All-day events block their
+// dates in loc.
+func busyInterval(event CreateEventResponse, loc *time.Location) interval.Interval {
+	if !event.AllDay {
+		return interval.Interval{Start: event.StartTime, End: event.EndTime}
+	}
+
+	midnight := func(t time.Time) time.Time { return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc) }
+
+	return interval.Interval{Start: midnight(event.StartTime), End: midnight(event.EndTime)}
+}
+
+// workingIntervals is the working hours of every day of [from, to) in loc.
+// Hours are read on the local clock, so they stay put across DST changes.
+func workingIntervals(hours *WorkingHours, from, to time.Time, loc *time.Location) interval.Set {
+	if hours == nil {
+		return interval.New(interval.Interval{Start: from, End: to})
+	}
+
+	days := hours.Days
+	if len(days) == 0 {
+		days = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	}
+
+	working := make(map[time.Weekday]bool, len(days))
+	for _, day := range days {
+		working[day] = true
+	}
+
+	clock := func(day time.Time, offset time.Duration) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day(), int(offset/time.Hour), int(offset%time.Hour/time.Minute), 0, 0, loc)
+	}
+
+	var intervals []interval.Interval
+
+	for day := from.In(loc); day.Before(to); day = time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, loc) {
+		if working[day.Weekday()] {
+			intervals = append(intervals, interval.Interval{Start: clock(day, hours.Start), End: clock(day, hours.End)})
+		}
+	}
+
+	return interval.New(intervals...)
+}
+
+// candidateSlots proposes slots of duration inside the free intervals, starting
+// on the quarter hours of loc. Slots that touch the edge of a free interval
+// come first, since they leave the rest of it in one piece, then earlier
+// slots before later ones.
+func candidateSlots(free interval.Set, duration time.Duration, loc *time.Location, limit int) []interval.Interval {
+	type candidate struct {
+		slot interval.Interval
+		edge bool
+	}
+
+	var candidates []candidate
+
+	for _, gap := range free {
+		latestStart := gap.End.Add(-duration)
+		if latestStart.Before(gap.Start) {
+			continue
+		}
+
+		starts := []time.Time{gap.Start}
+		for start := nextStep(gap.Start, loc); start.Before(latestStart); start = start.Add(slotStep) {
+			starts = append(starts, start)
+		}
+
+		if !latestStart.Equal(gap.Start) {
+			starts = append(starts, latestStart)
+		}
+
+		for _, start := range starts {
+			candidates = append(candidates, candidate{
+				slot: interval.Interval{Start: start, End: start.Add(duration)},
+				edge: start.Equal(gap.Start) || start.Equal(latestStart),
+			})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].edge != candidates[j].edge {
+			return candidates[i].edge
+		}
+
+		return candidates[i].slot.Start.Before(candidates[j].slot.Start)
+	})
+
+	slots := make([]interval.Interval, 0, min(limit, len(candidates)))
+	for _, candidate := range candidates[:min(limit, len(candidates))] {
+		slots = append(slots, candidate.slot)
+	}
+
+	return slots
+}
+
+// nextStep is the first quarter hour of loc strictly after t.
+func nextStep(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+	return midnight.Add((local.Sub(midnight)/slotStep + 1) * slotStep)
+}
+
+func validateAvailabilityQuery(query AvailabilityQuery) (*time.Location, int, error) {
+	if len(query.Resources) == 0 && len(query.CalendarIDs) == 0 {
+		return nil, 0, fmt.Errorf("resources and calendar ids cannot both be empty: %w", ErrInput)
+	}
+
+	if query.From.IsZero() || query.To.IsZero() {
+		return nil, 0, fmt.Errorf("from and to should be set: %w", ErrInput)
+	}
+
+	if !query.From.Before(query.To) {
+		return nil, 0, fmt.Errorf("from should be before to: %w", ErrInput)
+	}
+
+	if query.To.Sub(query.From) > maxAvailabilityWindow {
+		return nil, 0, fmt.Errorf("the window should not be longer than %d days: %w", maxAvailabilityWindow/(24*time.Hour), ErrInput)
+	}
+
+	if query.Duration <= 0 {
+		return nil, 0, fmt.Errorf("duration should be positive: %w", ErrInput)
+	}
+
+	if hours := query.WorkingHours; hours != nil {
+		if hours.Start < 0 || hours.End > 24*time.Hour || hours.Start >= hours.End {
+			return nil, 0, fmt.Errorf("working hours should start before they end, within a day: %w", ErrInput)
+		}
+	}
+
+	loc, err := time.LoadLocation(query.TimeZone)
+	if err != nil {
+		return nil, 0, fmt.Errorf("unknown time zone %q: %w", query.TimeZone, ErrInput)
+	}
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = DefaultSlotLimit
+	}
+
+	if limit < 0 || limit > MaxSlotLimit {
+		return nil, 0, fmt.Errorf("limit should be between 1 and %d: %w", MaxSlotLimit, ErrInput)
+	}
+
+	return loc, limit, nil
+}
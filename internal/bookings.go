@@ -0,0 +1,60 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal/interval"
+	"github.com/ObiaNzk/LTK-test-manu/internal/recurrence"
+)
+
+// maxBookedOccurrences bounds the occurrences of a series booking a resource,
+// each of which storage keeps as a booking.
+const maxBookedOccurrences = 1000
+
+// endOfTime is later than any occurrence, to expand a finite series whole.
+var endOfTime = time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+
+// checkBookable reports why a recurrence set cannot book a resource: every
+// occurrence is booked, so the series has to end, and not too far away.
+func checkBookable(set recurrence.Set) error {
+	if set.Rule != nil && set.Rule.Count == 0 && set.Rule.Until.IsZero() {
+		return errors.New("recurring events booking a resource should end, with COUNT or UNTIL")
+	}
+
+	if n := len(set.Between(time.Time{}, endOfTime)); n > maxBookedOccurrences {
+		return fmt.Errorf("recurring events can book a resource for at most %d occurrences, not %d", maxBookedOccurrences, n)
+	}
+
+	return nil
+}
+
+// bookedPeriods is what an event books: its own times, or those of every
+// occurrence of a series with its exceptions applied.
+func bookedPeriods(event CreateEventResponse, exceptions []OccurrenceException) ([]interval.Interval, error) {
+	if !event.isRecurring() {
+		return []interval.Interval{{Start: event.StartTime, End: event.EndTime}}, nil
+	}
+
+	set, err := event.recurrenceSet()
+	if err != nil {
+		return nil, fmt.Errorf("expanding event %s: %w", event.ID, err)
+	}
+
+	if err := checkBookable(set); err != nil {
+		return nil, fmt.Errorf("%s: %w", err.Error(), ErrInput)
+	}
+
+	occurrences, err := seriesOccurrences(event, exceptions, time.Time{}, endOfTime)
+	if err != nil {
+		return nil, fmt.Errorf("expanding event %s: %w", event.ID, err)
+	}
+
+	periods := make([]interval.Interval, 0, len(occurrences))
+	for _, occurrence := range occurrences {
+		periods = append(periods, interval.Interval{Start: occurrence.StartTime, End: occurrence.EndTime})
+	}
+
+	return periods, nil
+}
@@ -0,0 +1,136 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal/validation"
+)
+
+// DefaultCalendarID is the calendar events are created in when none is given.
+// Events that existed before calendars were introduced live in it too.
+const DefaultCalendarID = "default"
+
+type calendarStorage interface {
+	accessStorage
+	CreateCalendar(ctx context.Context, calendar Calendar) (Calendar, error)
+	// GetCalendars lists the calendars visibleTo may view, every one when empty.
+	GetCalendars(ctx context.Context, visibleTo string) ([]Calendar, error)
+	GetCalendar(ctx context.Context, id string) (Calendar, error)
+	UpdateCalendar(ctx context.Context, calendar Calendar) (Calendar, error)
+	DeleteCalendar(ctx context.Context, id string) error
+}
+
+type CalendarService struct {
+	storage   calendarStorage
+	authorize authorizer
+}
+
+func NewCalendarService(storage calendarStorage) *CalendarService {
+	return &CalendarService{
+		storage:   storage,
+		authorize: authorizer{storage: storage},
+	}
+}
+
+// CreateCalendar adds a calendar owned by the actor.
+func (s *CalendarService) CreateCalendar(ctx context.Context, calendar Calendar) (Calendar, error) {
+	if err := s.authorize.member(ctx); err != nil {
+		return Calendar{}, err
+	}
+
+	if err := validateCalendar(calendar); err != nil {
+		return Calendar{}, err
+	}
+
+	calendar.CreatedBy = ActorFromContext(ctx)
+
+	created, err := s.storage.CreateCalendar(ctx, calendar)
+	if err != nil {
+		return Calendar{}, fmt.Errorf("creating calendar: %w", err)
+	}
+
+	return created, nil
+}
+
+// GetCalendars lists the calendars the actor may view.
+func (s *CalendarService) GetCalendars(ctx context.Context) ([]Calendar, error) {
+	visibleTo, err := s.authorize.visibleTo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	calendars, err := s.storage.GetCalendars(ctx, visibleTo)
+	if err != nil {
+		return nil, fmt.Errorf("getting calendars: %w", err)
+	}
+
+	return calendars, nil
+}
+
+func (s *CalendarService) GetCalendar(ctx context.Context, id string) (Calendar, error) {
+	if id == "" {
+		return Calendar{}, fmt.Errorf("empty id: %w", ErrInput)
+	}
+
+	if err := s.authorize.calendar(ctx, id, AccessViewer); err != nil {
+		return Calendar{}, err
+	}
+
+	calendar, err := s.storage.GetCalendar(ctx, id)
+	if err != nil {
+		return Calendar{}, fmt.Errorf("getting calendar: %w", err)
+	}
+
+	return calendar, nil
+}
+
+// UpdateCalendar replaces the name and description of a calendar.
+func (s *CalendarService) UpdateCalendar(ctx context.Context, id string, calendar Calendar) (Calendar, error) {
+	if id == "" {
+		return Calendar{}, fmt.Errorf("empty id: %w", ErrInput)
+	}
+
+	if err := s.authorize.calendar(ctx, id, AccessOwner); err != nil {
+		return Calendar{}, err
+	}
+
+	if err := validateCalendar(calendar); err != nil {
+		return Calendar{}, err
+	}
+
+	calendar.ID = id
+
+	updated, err := s.storage.UpdateCalendar(ctx, calendar)
+	if err != nil {
+		return Calendar{}, fmt.Errorf("updating calendar: %w", err)
+	}
+
+	return updated, nil
+}
+
+// DeleteCalendar removes an empty calendar. Calendars with events, deleted
+// ones included, and the default calendar cannot be deleted.
+func (s *CalendarService) DeleteCalendar(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("empty id: %w", ErrInput)
+	}
+
+	if err := s.authorize.calendar(ctx, id, AccessOwner); err != nil {
+		return err
+	}
+
+	if id == DefaultCalendarID {
+		return fmt.Errorf("the default calendar cannot be deleted: %w", ErrConflict)
+	}
+
+	if err := s.storage.DeleteCalendar(ctx, id); err != nil {
+		return fmt.Errorf("deleting calendar: %w", err)
+	}
+
+	return nil
+}
+
+func validateCalendar(calendar Calendar) error {
+	return inputError(validation.Struct(calendar))
+}
@@ -0,0 +1,94 @@
+package internal_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal"
+	"github.com/ObiaNzk/LTK-test-manu/internal/mocks"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+//go:generate mockgen -source=calendars.go -destination=mocks/mock_calendar_storage.go -package=mocks
+
+type CalendarServiceTestSuite struct {
+	suite.Suite
+	ctx         context.Context
+	ctrl        *gomock.Controller
+	mockStorage *mocks.MockcalendarStorage
+	service     *internal.CalendarService
+}
+
+func (s *CalendarServiceTestSuite) SetupTest() {
+	// Admins hold every role, so these tests need no access lookups.
+	s.ctx = internal.WithAdmin(context.Background())
+	s.ctrl = gomock.NewController(s.T())
+	s.mockStorage = mocks.NewMockcalendarStorage(s.ctrl)
+	s.service = internal.NewCalendarService(s.mockStorage)
+}
+
+func (s *CalendarServiceTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func (s *CalendarServiceTestSuite) TestCreateCalendar_Success() {
+	created := internal.Calendar{ID: "work", Name: "Work", Description: "Team events"}
+
+	s.mockStorage.EXPECT().
+		CreateCalendar(gomock.Any(), internal.Calendar{Name: "Work", Description: "Team events"}).
+		Return(created, nil)
+
+	calendar, err := s.service.CreateCalendar(s.ctx, internal.Calendar{Name: "Work", Description: "Team events"})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), created, calendar)
+}
+
+func (s *CalendarServiceTestSuite) TestCreateCalendar_InvalidName() {
+	for _, name := range []string{"", strings.Repeat("a", 201)} {
+		_, err := s.service.CreateCalendar(s.ctx, internal.Calendar{Name: name})
+
+		require.ErrorIs(s.T(), err, internal.ErrInput)
+	}
+}
+
+func (s *CalendarServiceTestSuite) TestUpdateCalendar_SetsID() {
+	s.mockStorage.EXPECT().
+		UpdateCalendar(gomock.Any(), internal.Calendar{ID: "work", Name: "Office"}).
+		Return(internal.Calendar{ID: "work", Name: "Office"}, nil)
+
+	calendar, err := s.service.UpdateCalendar(s.ctx, "work", internal.Calendar{ID: "other", Name: "Office"})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), "work", calendar.ID)
+}
+
+func (s *CalendarServiceTestSuite) TestGetCalendar_NotFound() {
+	s.mockStorage.EXPECT().GetCalendar(gomock.Any(), "missing").Return(internal.Calendar{}, internal.ErrNotFound)
+
+	_, err := s.service.GetCalendar(s.ctx, "missing")
+
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+}
+
+func (s *CalendarServiceTestSuite) TestDeleteCalendar_Default() {
+	err := s.service.DeleteCalendar(s.ctx, internal.DefaultCalendarID)
+
+	require.ErrorIs(s.T(), err, internal.ErrConflict)
+	require.EqualError(s.T(), err, "the default calendar cannot be deleted: conflict")
+}
+
+func (s *CalendarServiceTestSuite) TestDeleteCalendar_StillHasEvents() {
+	s.mockStorage.EXPECT().DeleteCalendar(gomock.Any(), "work").Return(internal.ErrConflict)
+
+	err := s.service.DeleteCalendar(s.ctx, "work")
+
+	require.ErrorIs(s.T(), err, internal.ErrConflict)
+}
+
+func TestCalendarServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(CalendarServiceTestSuite))
+}
@@ -0,0 +1,218 @@
+package internal_test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// CapacityTestSuite runs concurrent RSVPs against a real Postgres, since the
+// row locks it relies on cannot be checked with sqlmock.
+type CapacityTestSuite struct {
+	postgresSuite
+	ctx context.Context
+}
+
+func (s *CapacityTestSuite) SetupSuite() {
+	s.setupPostgres("capacity")
+	s.ctx = internal.WithTenant(context.Background(), "capacity")
+}
+
+func (s *CapacityTestSuite) createEvent(capacity, invited int) (string, []string) {
+	ctx := s.ctx
+	start := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Hour)
+
+	event, err := s.storage.CreateEvent(ctx, internal.CreateEventRequest{
+		Title:       "Workshop",
+		Description: "Hands on",
+		StartTime:   start,
+		EndTime:     start.Add(2 * time.Hour),
+		Capacity:    capacity,
+		CalendarID:  internal.DefaultCalendarID,
+	})
+	require.NoError(s.T(), err)
+
+	emails := make([]string, 0, invited)
+
+	for i := range invited {
+		email := fmt.Sprintf("attendee%02d@example.com", i)
+		now := time.Now().UTC()
+
+		require.NoError(s.T(), s.storage.AddAttendee(ctx, internal.Attendee{
+			EventID:         event.ID,
+			Email:           email,
+			Role:            internal.RoleRequired,
+			Status:          internal.StatusInvited,
+			InvitedAt:       now,
+			StatusChangedAt: now,
+		}))
+
+		emails = append(emails, email)
+	}
+
+	return event.ID, emails
+}
+
+// watchAccepted counts the accepted attendees until stop is closed and
+// returns the highest count it saw.
+func (s *CapacityTestSuite) watchAccepted(eventID string, stop <-chan struct{}) func() int {
+	var highest atomic.Int64
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+
+			counts, err := s.storage.CountAttendees(s.ctx, eventID)
+			if err == nil && int64(counts[internal.StatusAccepted]) > highest.Load() {
+				highest.Store(int64(counts[internal.StatusAccepted]))
+			}
+		}
+	}()
+
+	return func() int {
+		<-done
+		return int(highest.Load())
+	}
+}
+
+func (s *CapacityTestSuite) byStatus(eventID string) map[internal.AttendeeStatus][]internal.Attendee {
+	attendees, err := s.storage.GetAttendees(s.ctx, eventID)
+	require.NoError(s.T(), err)
+
+	grouped := make(map[internal.AttendeeStatus][]internal.Attendee)
+	for _, attendee := range attendees {
+		grouped[attendee.Status] = append(grouped[attendee.Status], attendee)
+	}
+
+	return grouped
+}
+
+// waitlistOrder sorts attendees the way the waitlist is served.
+func waitlistOrder(attendees []internal.Attendee) []string {
+	sorted := append([]internal.Attendee(nil), attendees...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if !sorted[i].StatusChangedAt.Equal(sorted[j].StatusChangedAt) {
+			return sorted[i].StatusChangedAt.Before(sorted[j].StatusChangedAt)
+		}
+
+		return sorted[i].Email < sorted[j].Email
+	})
+
+	emails := make([]string, 0, len(sorted))
+	for _, attendee := range sorted {
+		emails = append(emails, attendee.Email)
+	}
+
+	return emails
+}
+
+func (s *CapacityTestSuite) TestConcurrentRSVPs_NeverExceedCapacity() {
+	const capacity = 5
+
+	eventID, emails := s.createEvent(capacity, 50)
+
+	stop := make(chan struct{})
+	highest := s.watchAccepted(eventID, stop)
+
+	var wg sync.WaitGroup
+
+	for _, email := range emails {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := s.storage.SetAttendeeStatus(s.ctx, eventID, email, internal.StatusAccepted, time.Now().UTC())
+			s.NoError(err)
+		}()
+	}
+
+	wg.Wait()
+	require.False(s.T(), s.T().Failed())
+
+	grouped := s.byStatus(eventID)
+	require.Len(s.T(), grouped[internal.StatusAccepted], capacity)
+	require.Len(s.T(), grouped[internal.StatusWaitlisted], len(emails)-capacity)
+
+	// Free every seat at once, three by declining and two by removal.
+	waitlist := waitlistOrder(grouped[internal.StatusWaitlisted])
+
+	for i, attendee := range grouped[internal.StatusAccepted] {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if i < 3 {
+				_, err := s.storage.SetAttendeeStatus(s.ctx, eventID, attendee.Email, internal.StatusDeclined, time.Now().UTC())
+				s.NoError(err)
+
+				return
+			}
+
+			s.NoError(s.storage.RemoveAttendee(s.ctx, eventID, attendee.Email))
+		}()
+	}
+
+	wg.Wait()
+	close(stop)
+
+	require.LessOrEqual(s.T(), highest(), capacity)
+
+	grouped = s.byStatus(eventID)
+	require.Len(s.T(), grouped[internal.StatusAccepted], capacity)
+	require.Len(s.T(), grouped[internal.StatusDeclined], 3)
+	require.Equal(s.T(), waitlist[capacity:], waitlistOrder(grouped[internal.StatusWaitlisted]))
+
+	promoted := make([]string, 0, capacity)
+	for _, attendee := range grouped[internal.StatusAccepted] {
+		promoted = append(promoted, attendee.Email)
+	}
+
+	require.ElementsMatch(s.T(), waitlist[:capacity], promoted)
+}
+
+func (s *CapacityTestSuite) TestRaisingCapacity_PromotesWaitlist() {
+	eventID, emails := s.createEvent(2, 4)
+
+	for _, email := range emails {
+		_, err := s.storage.SetAttendeeStatus(s.ctx, eventID, email, internal.StatusAccepted, time.Now().UTC())
+		require.NoError(s.T(), err)
+	}
+
+	event, err := s.storage.GetEventByID(s.ctx, eventID, false)
+	require.NoError(s.T(), err)
+
+	_, err = s.storage.UpdateEvent(s.ctx, eventID, event.Version, internal.CreateEventRequest{
+		Title:       event.Title,
+		Description: event.Description,
+		StartTime:   event.StartTime,
+		EndTime:     event.EndTime,
+		Capacity:    3,
+	})
+	require.NoError(s.T(), err)
+
+	grouped := s.byStatus(eventID)
+	require.Len(s.T(), grouped[internal.StatusAccepted], 3)
+	require.Equal(s.T(), emails[3:], waitlistOrder(grouped[internal.StatusWaitlisted]))
+}
+
+func TestCapacityTestSuite(t *testing.T) {
+	suite.Run(t, new(CapacityTestSuite))
+}
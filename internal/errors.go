@@ -1,9 +1,48 @@
 package internal
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal/validation"
+)
 
 var (
-	ErrPepito   error = errors.New("pepito")
 	ErrInput    error = errors.New("missing input values")
 	ErrNotFound error = errors.New("not found")
+	ErrConflict error = errors.New("conflict")
+	// ErrUnauthorized rejects credentials that are unknown, expired or revoked.
+	ErrUnauthorized error = errors.New("unauthorized")
+	// ErrForbidden denies an actor an action its role does not allow.
+	ErrForbidden error = errors.New("forbidden")
+	// ErrVersionMismatch rejects a write based on a version of an event that is
+	// no longer current.
+	ErrVersionMismatch error = errors.New("version mismatch")
+	// ErrKeyReused rejects an Idempotency-Key sent again with a different request.
+	ErrKeyReused error = errors.New("idempotency key reused")
 )
+
+// inputError reports the violations of a request as one error that matches
+// ErrInput, or nil when there are none.
+func inputError(errs validation.Errors) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %w", errs, ErrInput)
+}
+
+// ConflictError reports the events a booking overlaps with. It matches ErrConflict.
+type ConflictError struct {
+	Resource string
+	EventIDs []string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("resource %q is already booked by %s: %s", e.Resource, strings.Join(e.EventIDs, ", "), ErrConflict.Error())
+}
+
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
@@ -2,40 +2,58 @@ package internal
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal/recurrence"
+	"github.com/ObiaNzk/LTK-test-manu/internal/validation"
 )
 
 type storage interface {
+	accessStorage
 	CreateEvent(ctx context.Context, event CreateEventRequest) (CreateEventResponse, error)
-	GetEvents(ctx context.Context) ([]CreateEventResponse, error)
-	GetEventByID(ctx context.Context, id string) (CreateEventResponse, error)
+	GetEvents(ctx context.Context, filter EventFilter, page Page) ([]CreateEventResponse, error)
+	GetEventByID(ctx context.Context, id string, includeDeleted bool) (CreateEventResponse, error)
+	UpdateEvent(ctx context.Context, id string, version int, event CreateEventRequest) (CreateEventResponse, error)
+	DeleteEvent(ctx context.Context, id string) error
+	RestoreEvent(ctx context.Context, id string) (CreateEventResponse, error)
+	GetOccurrenceExceptions(ctx context.Context, seriesIDs []string) ([]OccurrenceException, error)
+	SaveOccurrenceException(ctx context.Context, exception OccurrenceException) error
+	SplitSeries(ctx context.Context, id string, at time.Time, head, tail CreateEventRequest) (CreateEventResponse, error)
+	ImportEvents(ctx context.Context, events []ImportedEvent) ([]ImportResult, error)
+	GetEventRevisions(ctx context.Context, eventID string) ([]EventRevision, error)
+	GetEventRevision(ctx context.Context, eventID string, revision int) (EventRevision, error)
+	GetCalendar(ctx context.Context, id string) (Calendar, error)
 }
 
 type Service struct {
-	storage storage
+	storage   storage
+	authorize authorizer
 }
 
 func NewService(storage storage) *Service {
 	return &Service{
-		storage: storage,
+		storage:   storage,
+		authorize: authorizer{storage: storage},
 	}
 }
 
 func (s *Service) CreateEvent(ctx context.Context, event CreateEventRequest) (CreateEventResponse, error) {
-	if event.Title == "" {
-		return CreateEventResponse{}, fmt.Errorf("title cannot be empty: %w", ErrInput)
+	if event.CalendarID == "" {
+		event.CalendarID = DefaultCalendarID
 	}
 
-	if event.Description == "" {
-		return CreateEventResponse{}, fmt.Errorf("description cannot be empty: %w", ErrInput)
+	if err := s.authorize.calendar(ctx, event.CalendarID, AccessEditor); err != nil {
+		return CreateEventResponse{}, err
 	}
 
-	if event.StartTime.IsZero() || event.EndTime.IsZero() {
-		return CreateEventResponse{}, fmt.Errorf("start time and end time should be set: %w", ErrInput)
+	if err := validateEvent(event); err != nil {
+		return CreateEventResponse{}, err
 	}
 
-	if len(event.Title) <= 100 {
-		return CreateEventResponse{}, fmt.Errorf("title should have more than 100 words: %w", ErrInput)
+	if err := s.checkCalendar(ctx, event.CalendarID); err != nil {
+		return CreateEventResponse{}, err
 	}
 
 	response, err := s.storage.CreateEvent(ctx, event)
@@ -47,12 +65,16 @@ func (s *Service) CreateEvent(ctx context.Context, event CreateEventRequest) (Cr
 	return response, nil
 }
 
-func (s *Service) GetEventByID(ctx context.Context, id string) (CreateEventResponse, error) {
+func (s *Service) GetEventByID(ctx context.Context, id string, includeDeleted bool) (CreateEventResponse, error) {
 	if id == "" {
 		return CreateEventResponse{}, fmt.Errorf("empty id: %w", ErrInput)
 	}
 
-	event, err := s.storage.GetEventByID(ctx, id)
+	if _, err := s.authorize.event(ctx, id, AccessViewer); err != nil {
+		return CreateEventResponse{}, err
+	}
+
+	event, err := s.storage.GetEventByID(ctx, id, includeDeleted)
 	if err != nil {
 		return CreateEventResponse{}, fmt.Errorf("getting event: %w", err)
 	}
@@ -60,11 +82,484 @@ func (s *Service) GetEventByID(ctx context.Context, id string) (CreateEventRespo
 	return event, nil
 }
 
-func (s *Service) GetEvents(ctx context.Context) ([]CreateEventResponse, error) {
-	events, err := s.storage.GetEvents(ctx)
+func (s *Service) GetEvents(ctx context.Context, filter EventFilter, pageRequest PageRequest) (EventsPage, error) {
+	if err := validateFilter(filter); err != nil {
+		return EventsPage{}, err
+	}
+
+	visibleTo, err := s.authorize.visibleTo(ctx)
+	if err != nil {
+		return EventsPage{}, err
+	}
+
+	filter.VisibleTo = visibleTo
+
+	if filter.CalendarID != "" {
+		if err := s.authorize.calendar(ctx, filter.CalendarID, AccessViewer); err != nil {
+			return EventsPage{}, err
+		}
+
+		if _, err := s.storage.GetCalendar(ctx, filter.CalendarID); err != nil {
+			return EventsPage{}, fmt.Errorf("getting calendar: %w", err)
+		}
+	}
+
+	limit := pageRequest.Limit
+	if limit == 0 {
+		limit = DefaultPageSize
+	}
+
+	if limit < 0 || limit > MaxPageSize {
+		return EventsPage{}, fmt.Errorf("limit should be between 1 and %d: %w", MaxPageSize, ErrInput)
+	}
+
+	page := Page{Limit: limit + 1}
+
+	if pageRequest.Cursor != "" {
+		cursor, err := DecodeCursor(pageRequest.Cursor)
+		if err != nil {
+			return EventsPage{}, err
+		}
+
+		page.After = &cursor
+	}
+
+	// One extra row tells us whether there is a next page without a COUNT.
+	events, err := s.storage.GetEvents(ctx, filter, page)
+	if err != nil {
+		return EventsPage{}, fmt.Errorf("getting events: %w", err)
+	}
+
+	result := EventsPage{Events: events}
+
+	if len(events) > limit {
+		result.Events = events[:limit]
+		last := result.Events[limit-1]
+		result.NextCursor = Cursor{StartTime: last.StartTime, ID: last.ID}.Encode()
+	}
+
+	// Series are only expanded inside a bounded window. Pagination still counts
+	// stored events, so a page holds every occurrence of its series. Outside of
+	// one, series come with their exceptions.
+	if !filter.From.IsZero() && !filter.To.IsZero() {
+		result.Events, err = s.expand(ctx, result.Events, filter.From, filter.To)
+	} else {
+		result.Exceptions, err = s.exceptions(ctx, result.Events)
+	}
+
 	if err != nil {
-		return nil, fmt.Errorf("getting events: %w", err)
+		return EventsPage{}, fmt.Errorf("getting events: %w", err)
+	}
+
+	return result, nil
+}
+
+// UpdateEvent replaces an event if it is still at version. Zero skips the
+// check, a stale version is ErrVersionMismatch.
+func (s *Service) UpdateEvent(ctx context.Context, id string, version int, event CreateEventRequest) (CreateEventResponse, error) {
+	if id == "" {
+		return CreateEventResponse{}, fmt.Errorf("empty id: %w", ErrInput)
+	}
+
+	if version < 0 {
+		return CreateEventResponse{}, fmt.Errorf("version cannot be negative: %w", ErrInput)
+	}
+
+	access, err := s.authorize.event(ctx, id, AccessEditor)
+	if err != nil {
+		return CreateEventResponse{}, err
+	}
+
+	return s.replaceEvent(ctx, access, id, version, event)
+}
+
+// replaceEvent validates and writes an update the actor was authorized for
+// with access.
+func (s *Service) replaceEvent(ctx context.Context, access EventAccess, id string, version int, event CreateEventRequest) (CreateEventResponse, error) {
+	// Moving the event also needs the right to add events to the new calendar.
+	if event.CalendarID != "" && event.CalendarID != access.CalendarID {
+		if err := s.authorize.calendar(ctx, event.CalendarID, AccessEditor); err != nil {
+			return CreateEventResponse{}, err
+		}
+	}
+
+	if err := validateEvent(event); err != nil {
+		return CreateEventResponse{}, err
+	}
+
+	if event.CalendarID != "" {
+		if err := s.checkCalendar(ctx, event.CalendarID); err != nil {
+			return CreateEventResponse{}, err
+		}
+	}
+
+	response, err := s.storage.UpdateEvent(ctx, id, version, event)
+	if err != nil {
+		return CreateEventResponse{}, fmt.Errorf("updating event: %w", err)
+	}
+
+	return response, nil
+}
+
+func (s *Service) DeleteEvent(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("empty id: %w", ErrInput)
+	}
+
+	if _, err := s.authorize.event(ctx, id, AccessEditor); err != nil {
+		return err
+	}
+
+	if err := s.storage.DeleteEvent(ctx, id); err != nil {
+		return fmt.Errorf("deleting event: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) RestoreEvent(ctx context.Context, id string) (CreateEventResponse, error) {
+	if id == "" {
+		return CreateEventResponse{}, fmt.Errorf("empty id: %w", ErrInput)
+	}
+
+	if _, err := s.authorize.event(ctx, id, AccessEditor); err != nil {
+		return CreateEventResponse{}, err
+	}
+
+	event, err := s.storage.RestoreEvent(ctx, id)
+	if err != nil {
+		return CreateEventResponse{}, fmt.Errorf("restoring event: %w", err)
+	}
+
+	return event, nil
+}
+
+// GetOccurrences lists the occurrences of one event overlapping [from, to), with
+// their exceptions applied.
+func (s *Service) GetOccurrences(ctx context.Context, id string, from, to time.Time) ([]CreateEventResponse, error) {
+	if id == "" {
+		return nil, fmt.Errorf("empty id: %w", ErrInput)
+	}
+
+	if from.IsZero() || to.IsZero() {
+		return nil, fmt.Errorf("from and to should be set: %w", ErrInput)
+	}
+
+	if err := validateFilter(EventFilter{From: from, To: to}); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.authorize.event(ctx, id, AccessViewer); err != nil {
+		return nil, err
+	}
+
+	event, err := s.storage.GetEventByID(ctx, id, false)
+	if err != nil {
+		return nil, fmt.Errorf("getting event: %w", err)
+	}
+
+	// A one-off event is its own single occurrence.
+	if !event.isRecurring() {
+		if event.overlaps(from, to) {
+			return []CreateEventResponse{event}, nil
+		}
+
+		return []CreateEventResponse{}, nil
+	}
+
+	occurrences, err := s.expand(ctx, []CreateEventResponse{event}, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("getting occurrences: %w", err)
+	}
+
+	return occurrences, nil
+}
+
+// GetOccurrenceExceptions returns the exceptions of the series id, for the
+// callers that keep the series whole.
+func (s *Service) GetOccurrenceExceptions(ctx context.Context, id string) ([]OccurrenceException, error) {
+	if id == "" {
+		return nil, fmt.Errorf("empty id: %w", ErrInput)
+	}
+
+	if _, err := s.authorize.event(ctx, id, AccessViewer); err != nil {
+		return nil, err
+	}
+
+	exceptions, err := s.storage.GetOccurrenceExceptions(ctx, []string{id})
+	if err != nil {
+		return nil, fmt.Errorf("getting exceptions: %w", err)
+	}
+
+	return exceptions, nil
+}
+
+// UpdateOccurrence moves or edits one occurrence of a series. The master row
+// is left untouched.
+func (s *Service) UpdateOccurrence(ctx context.Context, exception OccurrenceException) (CreateEventResponse, error) {
+	series, err := s.seriesOccurrence(ctx, exception.SeriesID, exception.RecurrenceID)
+	if err != nil {
+		return CreateEventResponse{}, err
+	}
+
+	exception.Cancelled = false
+
+	occurrence := ExceptionOccurrence(series, exception)
+
+	if occurrence.StartTime.After(occurrence.EndTime) {
+		return CreateEventResponse{}, fmt.Errorf("start time should be before end time: %w", ErrInput)
+	}
+
+	if err := s.storage.SaveOccurrenceException(ctx, exception); err != nil {
+		return CreateEventResponse{}, fmt.Errorf("updating occurrence: %w", err)
+	}
+
+	return occurrence, nil
+}
+
+// CancelOccurrence removes one occurrence from a series.
+func (s *Service) CancelOccurrence(ctx context.Context, seriesID string, recurrenceID time.Time) error {
+	if _, err := s.seriesOccurrence(ctx, seriesID, recurrenceID); err != nil {
+		return err
+	}
+
+	exception := OccurrenceException{SeriesID: seriesID, RecurrenceID: recurrenceID, Cancelled: true}
+
+	if err := s.storage.SaveOccurrenceException(ctx, exception); err != nil {
+		return fmt.Errorf("cancelling occurrence: %w", err)
+	}
+
+	return nil
+}
+
+// SplitSeries implements "this and following": the series ends right before
+// the occurrence at recurrenceID and a new series with the changes starts there.
+func (s *Service) SplitSeries(ctx context.Context, id string, recurrenceID time.Time, changes CreateEventRequest) (CreateEventResponse, error) {
+	if id == "" {
+		return CreateEventResponse{}, fmt.Errorf("empty id: %w", ErrInput)
+	}
+
+	access, err := s.authorize.event(ctx, id, AccessEditor)
+	if err != nil {
+		return CreateEventResponse{}, err
+	}
+
+	if changes.CalendarID != "" && changes.CalendarID != access.CalendarID {
+		if err := s.authorize.calendar(ctx, changes.CalendarID, AccessEditor); err != nil {
+			return CreateEventResponse{}, err
+		}
+	}
+
+	series, err := s.storage.GetEventByID(ctx, id, false)
+	if err != nil {
+		return CreateEventResponse{}, fmt.Errorf("getting event: %w", err)
+	}
+
+	head, tail, err := splitSeries(series, recurrenceID, changes)
+	if err != nil {
+		return CreateEventResponse{}, err
+	}
+
+	if err := validateEvent(tail); err != nil {
+		return CreateEventResponse{}, err
+	}
+
+	if changes.CalendarID != "" {
+		if err := s.checkCalendar(ctx, changes.CalendarID); err != nil {
+			return CreateEventResponse{}, err
+		}
+	}
+
+	created, err := s.storage.SplitSeries(ctx, id, recurrenceID, head, tail)
+	if err != nil {
+		return CreateEventResponse{}, fmt.Errorf("splitting series: %w", err)
+	}
+
+	return created, nil
+}
+
+// ImportEvents upserts events by UID. Events that fail validation, or that
+// would overwrite an event the actor cannot edit, are rejected and reported.
+// The rest are written in one transaction.
+func (s *Service) ImportEvents(ctx context.Context, events []ImportedEvent) ([]ImportResult, error) {
+	// New events go to the default calendar.
+	if err := s.authorize.calendar(ctx, DefaultCalendarID, AccessEditor); err != nil {
+		return nil, err
+	}
+
+	results := make([]ImportResult, len(events))
+	valid := make([]ImportedEvent, 0, len(events))
+	positions := make([]int, 0, len(events))
+	seen := make(map[string]bool, len(events))
+
+	for i, imported := range events {
+		reason := ""
+
+		switch {
+		case imported.Err != nil:
+			reason = imported.Err.Error()
+		case seen[imported.UID]:
+			reason = "duplicated UID"
+		default:
+			if err := validateEvent(imported.Event); err != nil {
+				reason = err.Error()
+				break
+			}
+
+			err := s.authorize.importedEvent(ctx, imported.UID)
+
+			switch {
+			case errors.Is(err, ErrForbidden):
+				reason = err.Error()
+			case err != nil && !errors.Is(err, ErrNotFound):
+				return nil, err
+			}
+		}
+
+		if reason != "" {
+			results[i] = ImportResult{UID: imported.UID, Status: ImportRejected, Reason: reason}
+			continue
+		}
+
+		seen[imported.UID] = true
+		valid = append(valid, imported)
+		positions = append(positions, i)
+	}
+
+	if len(valid) == 0 {
+		return results, nil
+	}
+
+	written, err := s.storage.ImportEvents(ctx, valid)
+	if err != nil {
+		return nil, fmt.Errorf("importing events: %w", err)
+	}
+
+	for i, result := range written {
+		results[positions[i]] = result
+	}
+
+	return results, nil
+}
+
+// checkCalendar makes sure an event is not put in a calendar that does not exist.
+func (s *Service) checkCalendar(ctx context.Context, id string) error {
+	if _, err := s.storage.GetCalendar(ctx, id); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return fmt.Errorf("calendar %q does not exist: %w", id, ErrInput)
+		}
+
+		return fmt.Errorf("getting calendar: %w", err)
+	}
+
+	return nil
+}
+
+// seriesOccurrence loads a series the actor can edit and checks recurrenceID
+// is one of its occurrences.
+func (s *Service) seriesOccurrence(ctx context.Context, seriesID string, recurrenceID time.Time) (CreateEventResponse, error) {
+	if seriesID == "" {
+		return CreateEventResponse{}, fmt.Errorf("empty id: %w", ErrInput)
+	}
+
+	if _, err := s.authorize.event(ctx, seriesID, AccessEditor); err != nil {
+		return CreateEventResponse{}, err
+	}
+
+	series, err := s.storage.GetEventByID(ctx, seriesID, false)
+	if err != nil {
+		return CreateEventResponse{}, fmt.Errorf("getting event: %w", err)
+	}
+
+	if !series.isRecurring() {
+		return CreateEventResponse{}, fmt.Errorf("event is not recurring: %w", ErrInput)
+	}
+
+	set, err := series.recurrenceSet()
+	if err != nil {
+		return CreateEventResponse{}, fmt.Errorf("getting event: %w", err)
+	}
+
+	if !isOccurrence(set, recurrenceID) {
+		return CreateEventResponse{}, fmt.Errorf("occurrence not found: %w", ErrNotFound)
+	}
+
+	return series, nil
+}
+
+// expand loads the exceptions of the recurring events and expands them.
+func (s *Service) expand(ctx context.Context, events []CreateEventResponse, from, to time.Time) ([]CreateEventResponse, error) {
+	exceptions, err := s.exceptions(ctx, events)
+	if err != nil {
+		return nil, err
+	}
+
+	return expandOccurrences(events, exceptions, from, to)
+}
+
+// exceptions loads the exceptions of the recurring events, without a query
+// when there are none.
+func (s *Service) exceptions(ctx context.Context, events []CreateEventResponse) ([]OccurrenceException, error) {
+	var seriesIDs []string
+
+	for _, event := range events {
+		if event.isRecurring() {
+			seriesIDs = append(seriesIDs, event.ID)
+		}
+	}
+
+	if len(seriesIDs) == 0 {
+		return nil, nil
+	}
+
+	return s.storage.GetOccurrenceExceptions(ctx, seriesIDs)
+}
+
+// validateEvent checks the validate tags of event and the rules they cannot
+// express, reporting every violation.
+func validateEvent(event CreateEventRequest) error {
+	errs := validation.Struct(event)
+
+	if event.AllDay {
+		if !isFloatingDate(event.StartTime) || !isFloatingDate(event.EndTime) {
+			errs.Add("start_time", "all-day events should start and end at midnight UTC")
+		} else if !event.EndTime.After(event.StartTime) {
+			errs.Add("end_time", "end date should be after start date")
+		}
+	}
+
+	if event.TimeZone != "" {
+		if _, err := time.LoadLocation(event.TimeZone); err != nil {
+			errs.Add("time_zone", fmt.Sprintf("unknown time zone %q", event.TimeZone))
+		}
+	}
+
+	if event.RRule != "" {
+		if _, err := recurrence.ParseRule(event.RRule); err != nil {
+			errs.Add("rrule", "invalid rrule: "+err.Error())
+		}
+	}
+
+	// Every occurrence of a series is booked.
+	if event.Resource != "" && event.isRecurring() {
+		if set, err := event.recurrenceSet(); err == nil {
+			if err := checkBookable(set); err != nil {
+				errs.Add("rrule", err.Error())
+			}
+		}
+	}
+
+	return inputError(errs)
+}
+
+func validateFilter(filter EventFilter) error {
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return fmt.Errorf("from should be before to: %w", ErrInput)
+	}
+
+	if !filter.CreatedFrom.IsZero() && !filter.CreatedTo.IsZero() && !filter.CreatedFrom.Before(filter.CreatedTo) {
+		return fmt.Errorf("created_from should be before created_to: %w", ErrInput)
 	}
 
-	return events, nil
+	return nil
 }
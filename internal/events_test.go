@@ -3,12 +3,15 @@ package internal_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/ObiaNzk/LTK-test-manu/internal"
+	"github.com/ObiaNzk/LTK-test-manu/internal/interval"
 	"github.com/ObiaNzk/LTK-test-manu/internal/mocks"
+	"github.com/ObiaNzk/LTK-test-manu/internal/validation"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/mock/gomock"
@@ -18,12 +21,15 @@ import (
 
 type ServiceTestSuite struct {
 	suite.Suite
+	ctx         context.Context
 	ctrl        *gomock.Controller
 	mockStorage *mocks.Mockstorage
 	service     *internal.Service
 }
 
 func (s *ServiceTestSuite) SetupTest() {
+	// Admins hold every role, so these tests need no access lookups.
+	s.ctx = internal.WithAdmin(context.Background())
 	s.ctrl = gomock.NewController(s.T())
 	s.mockStorage = mocks.NewMockstorage(s.ctrl)
 	s.service = internal.NewService(s.mockStorage)
@@ -39,7 +45,7 @@ func (s *ServiceTestSuite) TestNewService() {
 }
 
 func (s *ServiceTestSuite) TestCreateEvent_Success() {
-	ctx := context.Background()
+	ctx := s.ctx
 	now := time.Now()
 	title := strings.Repeat("a", 101)
 
@@ -59,8 +65,12 @@ func (s *ServiceTestSuite) TestCreateEvent_Success() {
 		CreatedAt:   now,
 	}
 
+	stored := request
+	stored.CalendarID = internal.DefaultCalendarID
+
+	s.mockStorage.EXPECT().GetCalendar(gomock.Any(), internal.DefaultCalendarID).Return(internal.Calendar{ID: internal.DefaultCalendarID}, nil)
 	s.mockStorage.EXPECT().
-		CreateEvent(gomock.Any(), request).
+		CreateEvent(gomock.Any(), stored).
 		Return(expectedResponse, nil)
 
 	result, err := s.service.CreateEvent(ctx, request)
@@ -71,7 +81,7 @@ func (s *ServiceTestSuite) TestCreateEvent_Success() {
 }
 
 func (s *ServiceTestSuite) TestCreateEvent_EmptyTitle() {
-	ctx := context.Background()
+	ctx := s.ctx
 	now := time.Now()
 
 	request := internal.CreateEventRequest{
@@ -89,7 +99,7 @@ func (s *ServiceTestSuite) TestCreateEvent_EmptyTitle() {
 }
 
 func (s *ServiceTestSuite) TestCreateEvent_EmptyDescription() {
-	ctx := context.Background()
+	ctx := s.ctx
 	now := time.Now()
 	title := strings.Repeat("a", 101)
 
@@ -108,7 +118,7 @@ func (s *ServiceTestSuite) TestCreateEvent_EmptyDescription() {
 }
 
 func (s *ServiceTestSuite) TestCreateEvent_MissingStartTime() {
-	ctx := context.Background()
+	ctx := s.ctx
 	title := strings.Repeat("a", 101)
 
 	request := internal.CreateEventRequest{
@@ -122,11 +132,11 @@ func (s *ServiceTestSuite) TestCreateEvent_MissingStartTime() {
 
 	require.Error(s.T(), err)
 	require.ErrorIs(s.T(), err, internal.ErrInput)
-	require.EqualError(s.T(), err, "start time and end time should be set: missing input values")
+	require.EqualError(s.T(), err, "start_time cannot be empty: missing input values")
 }
 
 func (s *ServiceTestSuite) TestCreateEvent_MissingEndTime() {
-	ctx := context.Background()
+	ctx := s.ctx
 	title := strings.Repeat("a", 101)
 
 	request := internal.CreateEventRequest{
@@ -140,11 +150,11 @@ func (s *ServiceTestSuite) TestCreateEvent_MissingEndTime() {
 
 	require.Error(s.T(), err)
 	require.ErrorIs(s.T(), err, internal.ErrInput)
-	require.EqualError(s.T(), err, "start time and end time should be set: missing input values")
+	require.EqualError(s.T(), err, "end_time cannot be empty: missing input values")
 }
 
 func (s *ServiceTestSuite) TestCreateEvent_TitleTooShort() {
-	ctx := context.Background()
+	ctx := s.ctx
 	now := time.Now()
 
 	request := internal.CreateEventRequest{
@@ -158,11 +168,182 @@ func (s *ServiceTestSuite) TestCreateEvent_TitleTooShort() {
 
 	require.Error(s.T(), err)
 	require.ErrorIs(s.T(), err, internal.ErrInput)
-	require.EqualError(s.T(), err, "title should have more than 100 words: missing input values")
+	require.EqualError(s.T(), err, "title should have at least 101 characters: missing input values")
+}
+
+func (s *ServiceTestSuite) TestCreateEvent_EveryViolation() {
+	now := time.Now()
+
+	request := internal.CreateEventRequest{
+		Title:     "Short",
+		StartTime: now,
+		EndTime:   now.Add(-time.Hour),
+		TimeZone:  "Mars/Olympus",
+	}
+
+	_, err := s.service.CreateEvent(s.ctx, request)
+
+	require.ErrorIs(s.T(), err, internal.ErrInput)
+
+	var violations validation.Errors
+	require.ErrorAs(s.T(), err, &violations)
+	require.Equal(s.T(), validation.Errors{
+		{Field: "title", Message: "title should have at least 101 characters"},
+		{Field: "description", Message: "description cannot be empty"},
+		{Field: "end_time", Message: "end_time should not be before start_time"},
+		{Field: "time_zone", Message: `unknown time zone "Mars/Olympus"`},
+	}, violations)
+}
+
+func (s *ServiceTestSuite) TestCreateEvent_InvalidRRule() {
+	ctx := s.ctx
+	now := time.Now()
+
+	request := internal.CreateEventRequest{
+		Title:       strings.Repeat("a", 101),
+		Description: "Test Description",
+		StartTime:   now,
+		EndTime:     now.Add(time.Hour),
+		RRule:       "FREQ=HOURLY",
+	}
+
+	_, err := s.service.CreateEvent(ctx, request)
+
+	require.Error(s.T(), err)
+	require.ErrorIs(s.T(), err, internal.ErrInput)
+}
+
+func (s *ServiceTestSuite) TestCreateEvent_UnknownTimeZone() {
+	ctx := s.ctx
+	now := time.Now()
+
+	request := internal.CreateEventRequest{
+		Title:       strings.Repeat("a", 101),
+		Description: "Test Description",
+		StartTime:   now,
+		EndTime:     now.Add(time.Hour),
+		TimeZone:    "Mars/Olympus",
+	}
+
+	_, err := s.service.CreateEvent(ctx, request)
+
+	require.ErrorIs(s.T(), err, internal.ErrInput)
+	require.EqualError(s.T(), err, `unknown time zone "Mars/Olympus": missing input values`)
+}
+
+func (s *ServiceTestSuite) TestCreateEvent_AllDayNotADate() {
+	ctx := s.ctx
+	start := time.Date(2025, 12, 25, 9, 0, 0, 0, time.UTC)
+
+	request := internal.CreateEventRequest{
+		Title:       strings.Repeat("a", 101),
+		Description: "Test Description",
+		StartTime:   start,
+		EndTime:     start.AddDate(0, 0, 1),
+		AllDay:      true,
+	}
+
+	_, err := s.service.CreateEvent(ctx, request)
+
+	require.ErrorIs(s.T(), err, internal.ErrInput)
+	require.EqualError(s.T(), err, "all-day events should start and end at midnight UTC: missing input values")
+}
+
+func (s *ServiceTestSuite) TestCreateEvent_NegativeCapacity() {
+	ctx := s.ctx
+	now := time.Now()
+
+	request := internal.CreateEventRequest{
+		Title:       strings.Repeat("a", 101),
+		Description: "Test Description",
+		StartTime:   now,
+		EndTime:     now.Add(time.Hour),
+		Capacity:    -1,
+	}
+
+	_, err := s.service.CreateEvent(ctx, request)
+
+	require.ErrorIs(s.T(), err, internal.ErrInput)
+	require.EqualError(s.T(), err, "capacity should be at least 0: missing input values")
+}
+
+func (s *ServiceTestSuite) TestCreateEvent_AllDayEmpty() {
+	ctx := s.ctx
+	day := time.Date(2025, 12, 25, 0, 0, 0, 0, time.UTC)
+
+	request := internal.CreateEventRequest{
+		Title:       strings.Repeat("a", 101),
+		Description: "Test Description",
+		StartTime:   day,
+		EndTime:     day,
+		AllDay:      true,
+	}
+
+	_, err := s.service.CreateEvent(ctx, request)
+
+	require.ErrorIs(s.T(), err, internal.ErrInput)
+	require.EqualError(s.T(), err, "end date should be after start date: missing input values")
+}
+
+func (s *ServiceTestSuite) TestCreateEvent_RecurringResource() {
+	now := time.Now()
+
+	request := internal.CreateEventRequest{
+		Title:       strings.Repeat("a", 101),
+		Description: "Test Description",
+		StartTime:   now,
+		EndTime:     now.Add(time.Hour),
+		RRule:       "FREQ=WEEKLY;COUNT=52",
+		Resource:    "room-4b",
+	}
+
+	stored := request
+	stored.CalendarID = internal.DefaultCalendarID
+
+	s.mockStorage.EXPECT().GetCalendar(gomock.Any(), internal.DefaultCalendarID).Return(internal.Calendar{ID: internal.DefaultCalendarID}, nil)
+	s.mockStorage.EXPECT().CreateEvent(gomock.Any(), stored).Return(internal.CreateEventResponse{ID: "test-id"}, nil)
+
+	_, err := s.service.CreateEvent(s.ctx, request)
+
+	require.NoError(s.T(), err)
+}
+
+func (s *ServiceTestSuite) TestCreateEvent_UnboundedRecurringResource() {
+	now := time.Now()
+
+	tests := map[string]struct {
+		rrule string
+		err   string
+	}{
+		"endless": {
+			rrule: "FREQ=DAILY",
+			err:   "recurring events booking a resource should end, with COUNT or UNTIL: missing input values",
+		},
+		"too many occurrences": {
+			rrule: "FREQ=DAILY;COUNT=1001",
+			err:   "recurring events can book a resource for at most 1000 occurrences, not 1001: missing input values",
+		},
+	}
+
+	for name, tt := range tests {
+		s.Run(name, func() {
+			_, err := s.service.CreateEvent(s.ctx, internal.CreateEventRequest{
+				Title:       strings.Repeat("a", 101),
+				Description: "Test Description",
+				StartTime:   now,
+				EndTime:     now.Add(time.Hour),
+				RRule:       tt.rrule,
+				Resource:    "room-4b",
+			})
+
+			require.ErrorIs(s.T(), err, internal.ErrInput)
+			require.EqualError(s.T(), err, tt.err)
+		})
+	}
 }
 
 func (s *ServiceTestSuite) TestCreateEvent_StorageError() {
-	ctx := context.Background()
+	ctx := s.ctx
 	now := time.Now()
 	title := strings.Repeat("a", 101)
 
@@ -174,8 +355,9 @@ func (s *ServiceTestSuite) TestCreateEvent_StorageError() {
 	}
 
 	storageError := errors.New("database error")
+	s.mockStorage.EXPECT().GetCalendar(gomock.Any(), internal.DefaultCalendarID).Return(internal.Calendar{ID: internal.DefaultCalendarID}, nil)
 	s.mockStorage.EXPECT().
-		CreateEvent(gomock.Any(), request).
+		CreateEvent(gomock.Any(), gomock.Any()).
 		Return(internal.CreateEventResponse{}, storageError)
 
 	_, err := s.service.CreateEvent(ctx, request)
@@ -185,8 +367,73 @@ func (s *ServiceTestSuite) TestCreateEvent_StorageError() {
 	require.ErrorIs(s.T(), err, storageError)
 }
 
+func (s *ServiceTestSuite) TestCreateEvent_UnknownCalendar() {
+	now := time.Now()
+
+	request := internal.CreateEventRequest{
+		Title:       strings.Repeat("a", 101),
+		Description: "pepito",
+		StartTime:   now,
+		EndTime:     now.Add(time.Hour),
+		CalendarID:  "work",
+	}
+
+	s.mockStorage.EXPECT().GetCalendar(gomock.Any(), "work").Return(internal.Calendar{}, fmt.Errorf("calendar not found: %w", internal.ErrNotFound))
+
+	_, err := s.service.CreateEvent(s.ctx, request)
+
+	require.ErrorIs(s.T(), err, internal.ErrInput)
+	require.EqualError(s.T(), err, `calendar "work" does not exist: missing input values`)
+}
+
+func (s *ServiceTestSuite) TestUpdateEvent_MoveToUnknownCalendar() {
+	now := time.Now()
+
+	request := internal.CreateEventRequest{
+		Title:       strings.Repeat("a", 101),
+		Description: "pepito",
+		StartTime:   now,
+		EndTime:     now.Add(time.Hour),
+		CalendarID:  "work",
+	}
+
+	s.mockStorage.EXPECT().GetCalendar(gomock.Any(), "work").Return(internal.Calendar{}, fmt.Errorf("calendar not found: %w", internal.ErrNotFound))
+
+	_, err := s.service.UpdateEvent(s.ctx, "event", 3, request)
+
+	require.ErrorIs(s.T(), err, internal.ErrInput)
+}
+
+func (s *ServiceTestSuite) TestUpdateEvent_MoveToCalendar() {
+	now := time.Now()
+
+	request := internal.CreateEventRequest{
+		Title:       strings.Repeat("a", 101),
+		Description: "pepito",
+		StartTime:   now,
+		EndTime:     now.Add(time.Hour),
+		CalendarID:  "work",
+	}
+
+	s.mockStorage.EXPECT().GetCalendar(gomock.Any(), "work").Return(internal.Calendar{ID: "work"}, nil)
+	s.mockStorage.EXPECT().UpdateEvent(gomock.Any(), "event", 3, request).Return(internal.CreateEventResponse{ID: "event", CalendarID: "work"}, nil)
+
+	event, err := s.service.UpdateEvent(s.ctx, "event", 3, request)
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), "work", event.CalendarID)
+}
+
+func (s *ServiceTestSuite) TestGetEvents_UnknownCalendar() {
+	s.mockStorage.EXPECT().GetCalendar(gomock.Any(), "work").Return(internal.Calendar{}, fmt.Errorf("calendar not found: %w", internal.ErrNotFound))
+
+	_, err := s.service.GetEvents(s.ctx, internal.EventFilter{CalendarID: "work"}, internal.PageRequest{})
+
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+}
+
 func (s *ServiceTestSuite) TestGetEventByID_Success() {
-	ctx := context.Background()
+	ctx := s.ctx
 	eventID := "test-id-123"
 	now := time.Now()
 
@@ -200,10 +447,10 @@ func (s *ServiceTestSuite) TestGetEventByID_Success() {
 	}
 
 	s.mockStorage.EXPECT().
-		GetEventByID(gomock.Any(), eventID).
+		GetEventByID(gomock.Any(), eventID, false).
 		Return(expectedEvent, nil)
 
-	result, err := s.service.GetEventByID(ctx, eventID)
+	result, err := s.service.GetEventByID(ctx, eventID, false)
 
 	require.NoError(s.T(), err)
 	require.Equal(s.T(), expectedEvent.ID, result.ID)
@@ -215,9 +462,9 @@ func (s *ServiceTestSuite) TestGetEventByID_Success() {
 }
 
 func (s *ServiceTestSuite) TestGetEventByID_EmptyID() {
-	ctx := context.Background()
+	ctx := s.ctx
 
-	_, err := s.service.GetEventByID(ctx, "")
+	_, err := s.service.GetEventByID(ctx, "", false)
 
 	require.Error(s.T(), err)
 	require.ErrorIs(s.T(), err, internal.ErrInput)
@@ -225,14 +472,14 @@ func (s *ServiceTestSuite) TestGetEventByID_EmptyID() {
 }
 
 func (s *ServiceTestSuite) TestGetEventByID_NotFound() {
-	ctx := context.Background()
+	ctx := s.ctx
 	eventID := "nonexistent-id"
 
 	s.mockStorage.EXPECT().
-		GetEventByID(gomock.Any(), eventID).
+		GetEventByID(gomock.Any(), eventID, false).
 		Return(internal.CreateEventResponse{}, internal.ErrNotFound)
 
-	_, err := s.service.GetEventByID(ctx, eventID)
+	_, err := s.service.GetEventByID(ctx, eventID, false)
 
 	require.Error(s.T(), err)
 	require.ErrorIs(s.T(), err, internal.ErrNotFound)
@@ -240,21 +487,1120 @@ func (s *ServiceTestSuite) TestGetEventByID_NotFound() {
 }
 
 func (s *ServiceTestSuite) TestGetEventByID_StorageError() {
-	ctx := context.Background()
+	ctx := s.ctx
 	eventID := "test-id"
 
 	storageError := errors.New("database connection error")
 	s.mockStorage.EXPECT().
-		GetEventByID(gomock.Any(), eventID).
+		GetEventByID(gomock.Any(), eventID, false).
 		Return(internal.CreateEventResponse{}, storageError)
 
-	_, err := s.service.GetEventByID(ctx, eventID)
+	_, err := s.service.GetEventByID(ctx, eventID, false)
 
 	require.Error(s.T(), err)
 	require.EqualError(s.T(), err, "getting event: database connection error")
 	require.ErrorIs(s.T(), err, storageError)
 }
 
+func (s *ServiceTestSuite) TestCreateEvent_StartTimeAfterEndTime() {
+	ctx := s.ctx
+	now := time.Now()
+
+	request := internal.CreateEventRequest{
+		Title:       strings.Repeat("a", 101),
+		Description: "pepito",
+		StartTime:   now.Add(time.Hour),
+		EndTime:     now,
+	}
+
+	_, err := s.service.CreateEvent(ctx, request)
+
+	require.Error(s.T(), err)
+	require.ErrorIs(s.T(), err, internal.ErrInput)
+	require.EqualError(s.T(), err, "end_time should not be before start_time: missing input values")
+}
+
+func (s *ServiceTestSuite) TestUpdateEvent_Success() {
+	ctx := s.ctx
+	eventID := "test-id-123"
+	now := time.Now()
+
+	request := internal.CreateEventRequest{
+		Title:       strings.Repeat("a", 101),
+		Description: "pepito",
+		StartTime:   now,
+		EndTime:     now.Add(time.Hour),
+	}
+
+	expectedResponse := internal.CreateEventResponse{
+		ID:          eventID,
+		Title:       request.Title,
+		Description: request.Description,
+		StartTime:   request.StartTime,
+		EndTime:     request.EndTime,
+		CreatedAt:   now,
+	}
+
+	s.mockStorage.EXPECT().
+		UpdateEvent(gomock.Any(), eventID, 3, request).
+		Return(expectedResponse, nil)
+
+	result, err := s.service.UpdateEvent(ctx, eventID, 3, request)
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), expectedResponse, result)
+}
+
+func (s *ServiceTestSuite) TestUpdateEvent_EmptyID() {
+	ctx := s.ctx
+
+	_, err := s.service.UpdateEvent(ctx, "", 0, internal.CreateEventRequest{})
+
+	require.Error(s.T(), err)
+	require.ErrorIs(s.T(), err, internal.ErrInput)
+	require.EqualError(s.T(), err, "empty id: missing input values")
+}
+
+func (s *ServiceTestSuite) TestUpdateEvent_NegativeVersion() {
+	_, err := s.service.UpdateEvent(s.ctx, "test-id", -1, internal.CreateEventRequest{})
+
+	require.ErrorIs(s.T(), err, internal.ErrInput)
+}
+
+func (s *ServiceTestSuite) TestUpdateEvent_VersionMismatch() {
+	now := time.Now()
+
+	request := internal.CreateEventRequest{
+		Title:       strings.Repeat("a", 101),
+		Description: "pepito",
+		StartTime:   now,
+		EndTime:     now.Add(time.Hour),
+	}
+
+	s.mockStorage.EXPECT().
+		UpdateEvent(gomock.Any(), "event", 2, request).
+		Return(internal.CreateEventResponse{}, fmt.Errorf("event is at version 3, not 2: %w", internal.ErrVersionMismatch))
+
+	_, err := s.service.UpdateEvent(s.ctx, "event", 2, request)
+
+	require.ErrorIs(s.T(), err, internal.ErrVersionMismatch)
+}
+
+func (s *ServiceTestSuite) TestUpdateEvent_TitleTooShort() {
+	ctx := s.ctx
+	now := time.Now()
+
+	request := internal.CreateEventRequest{
+		Title:       "Short",
+		Description: "pepito",
+		StartTime:   now,
+		EndTime:     now.Add(time.Hour),
+	}
+
+	_, err := s.service.UpdateEvent(ctx, "test-id", 0, request)
+
+	require.Error(s.T(), err)
+	require.ErrorIs(s.T(), err, internal.ErrInput)
+	require.EqualError(s.T(), err, "title should have at least 101 characters: missing input values")
+}
+
+func (s *ServiceTestSuite) TestUpdateEvent_NotFound() {
+	ctx := s.ctx
+	now := time.Now()
+
+	request := internal.CreateEventRequest{
+		Title:       strings.Repeat("a", 101),
+		Description: "pepito",
+		StartTime:   now,
+		EndTime:     now.Add(time.Hour),
+	}
+
+	s.mockStorage.EXPECT().
+		UpdateEvent(gomock.Any(), "nonexistent-id", 0, request).
+		Return(internal.CreateEventResponse{}, internal.ErrNotFound)
+
+	_, err := s.service.UpdateEvent(ctx, "nonexistent-id", 0, request)
+
+	require.Error(s.T(), err)
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+	require.EqualError(s.T(), err, "updating event: not found")
+}
+
+func (s *ServiceTestSuite) TestGetEvents_IncludeDeleted() {
+	ctx := s.ctx
+	deletedAt := time.Now()
+
+	expectedEvents := []internal.CreateEventResponse{{ID: "test-id", DeletedAt: &deletedAt}}
+
+	s.mockStorage.EXPECT().
+		GetEvents(gomock.Any(), internal.EventFilter{IncludeDeleted: true}, internal.Page{Limit: internal.DefaultPageSize + 1}).
+		Return(expectedEvents, nil)
+
+	result, err := s.service.GetEvents(ctx, internal.EventFilter{IncludeDeleted: true}, internal.PageRequest{})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), expectedEvents, result.Events)
+	require.Empty(s.T(), result.NextCursor)
+}
+
+func (s *ServiceTestSuite) TestGetEvents_NextCursor() {
+	ctx := s.ctx
+	now := time.Now().UTC()
+
+	storedEvents := []internal.CreateEventResponse{
+		{ID: "id-1", StartTime: now},
+		{ID: "id-2", StartTime: now.Add(time.Hour)},
+		{ID: "id-3", StartTime: now.Add(2 * time.Hour)},
+	}
+
+	s.mockStorage.EXPECT().
+		GetEvents(gomock.Any(), internal.EventFilter{}, internal.Page{Limit: 3}).
+		Return(storedEvents, nil)
+
+	result, err := s.service.GetEvents(ctx, internal.EventFilter{}, internal.PageRequest{Limit: 2})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), storedEvents[:2], result.Events)
+
+	cursor, err := internal.DecodeCursor(result.NextCursor)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), "id-2", cursor.ID)
+	require.True(s.T(), now.Add(time.Hour).Equal(cursor.StartTime))
+}
+
+func (s *ServiceTestSuite) TestGetEvents_FollowsCursor() {
+	ctx := s.ctx
+	cursor := internal.Cursor{StartTime: time.Now().UTC(), ID: "id-2"}
+
+	s.mockStorage.EXPECT().
+		GetEvents(gomock.Any(), internal.EventFilter{}, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ internal.EventFilter, page internal.Page) ([]internal.CreateEventResponse, error) {
+			require.NotNil(s.T(), page.After)
+			require.Equal(s.T(), cursor.ID, page.After.ID)
+			require.True(s.T(), cursor.StartTime.Equal(page.After.StartTime))
+
+			return []internal.CreateEventResponse{}, nil
+		})
+
+	result, err := s.service.GetEvents(ctx, internal.EventFilter{}, internal.PageRequest{Cursor: cursor.Encode()})
+
+	require.NoError(s.T(), err)
+	require.Empty(s.T(), result.Events)
+	require.Empty(s.T(), result.NextCursor)
+}
+
+func (s *ServiceTestSuite) TestGetEvents_InvalidCursor() {
+	ctx := s.ctx
+
+	_, err := s.service.GetEvents(ctx, internal.EventFilter{}, internal.PageRequest{Cursor: "not-a-cursor"})
+
+	require.Error(s.T(), err)
+	require.ErrorIs(s.T(), err, internal.ErrInput)
+}
+
+func (s *ServiceTestSuite) TestGetEvents_Filter() {
+	ctx := s.ctx
+	from := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	filter := internal.EventFilter{From: from, To: from.AddDate(0, 0, 7), Title: "standup"}
+
+	s.mockStorage.EXPECT().
+		GetEvents(gomock.Any(), filter, internal.Page{Limit: internal.DefaultPageSize + 1}).
+		Return([]internal.CreateEventResponse{}, nil)
+
+	_, err := s.service.GetEvents(ctx, filter, internal.PageRequest{})
+
+	require.NoError(s.T(), err)
+}
+
+func (s *ServiceTestSuite) TestGetEvents_ExpandsSeries() {
+	ctx := s.ctx
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	filter := internal.EventFilter{From: start.AddDate(0, 0, 1), To: start.AddDate(0, 0, 4)}
+
+	series := internal.CreateEventResponse{
+		ID:        "series",
+		StartTime: start,
+		EndTime:   start.Add(time.Hour),
+		RRule:     "FREQ=DAILY;COUNT=10",
+		ExDates:   []time.Time{start.AddDate(0, 0, 2)},
+	}
+	single := internal.CreateEventResponse{
+		ID:        "single",
+		StartTime: start.AddDate(0, 0, 2).Add(2 * time.Hour),
+		EndTime:   start.AddDate(0, 0, 2).Add(3 * time.Hour),
+	}
+
+	s.mockStorage.EXPECT().
+		GetEvents(gomock.Any(), filter, internal.Page{Limit: internal.DefaultPageSize + 1}).
+		Return([]internal.CreateEventResponse{series, single}, nil)
+
+	s.mockStorage.EXPECT().
+		GetOccurrenceExceptions(gomock.Any(), []string{"series"}).
+		Return(nil, nil)
+
+	result, err := s.service.GetEvents(ctx, filter, internal.PageRequest{})
+
+	require.NoError(s.T(), err)
+	require.Len(s.T(), result.Events, 3)
+	require.Equal(s.T(), start.AddDate(0, 0, 1), result.Events[0].StartTime)
+	require.Equal(s.T(), start.AddDate(0, 0, 1), *result.Events[0].RecurrenceID)
+	require.Equal(s.T(), "single", result.Events[1].ID)
+	require.Nil(s.T(), result.Events[1].RecurrenceID)
+	require.Equal(s.T(), start.AddDate(0, 0, 3), result.Events[2].StartTime)
+	require.Equal(s.T(), start.AddDate(0, 0, 3).Add(time.Hour), result.Events[2].EndTime)
+}
+
+func (s *ServiceTestSuite) TestGetEvents_ExpandsAcrossDST() {
+	ctx := s.ctx
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	require.NoError(s.T(), err)
+
+	start := time.Date(2025, 3, 24, 9, 0, 0, 0, madrid)
+	filter := internal.EventFilter{From: start, To: start.AddDate(0, 0, 14)}
+
+	series := internal.CreateEventResponse{
+		ID:        "series",
+		StartTime: start,
+		EndTime:   start.Add(time.Hour),
+		RRule:     "FREQ=WEEKLY",
+		TimeZone:  "Europe/Madrid",
+	}
+
+	s.mockStorage.EXPECT().
+		GetEvents(gomock.Any(), filter, internal.Page{Limit: internal.DefaultPageSize + 1}).
+		Return([]internal.CreateEventResponse{series}, nil)
+
+	s.mockStorage.EXPECT().
+		GetOccurrenceExceptions(gomock.Any(), []string{"series"}).
+		Return(nil, nil)
+
+	result, err := s.service.GetEvents(ctx, filter, internal.PageRequest{})
+
+	require.NoError(s.T(), err)
+	require.Len(s.T(), result.Events, 2)
+	// Clocks move forward on March 30th: the second occurrence is still at 09:00
+	// local time, one hour earlier in UTC.
+	require.Equal(s.T(), 8, result.Events[0].StartTime.UTC().Hour())
+	require.Equal(s.T(), 7, result.Events[1].StartTime.UTC().Hour())
+	require.Equal(s.T(), 9, result.Events[1].StartTime.In(madrid).Hour())
+}
+
+func (s *ServiceTestSuite) TestGetEvents_AppliesExceptions() {
+	ctx := s.ctx
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	day := func(n int) time.Time { return start.AddDate(0, 0, n) }
+	filter := internal.EventFilter{From: day(1), To: day(4)}
+
+	series := internal.CreateEventResponse{
+		ID:        "series",
+		Title:     "standup",
+		StartTime: start,
+		EndTime:   start.Add(time.Hour),
+		RRule:     "FREQ=DAILY;COUNT=10",
+	}
+
+	s.mockStorage.EXPECT().
+		GetEvents(gomock.Any(), filter, internal.Page{Limit: internal.DefaultPageSize + 1}).
+		Return([]internal.CreateEventResponse{series}, nil)
+
+	s.mockStorage.EXPECT().
+		GetOccurrenceExceptions(gomock.Any(), []string{"series"}).
+		Return([]internal.OccurrenceException{
+			{SeriesID: "series", RecurrenceID: day(2), Cancelled: true},
+			{SeriesID: "series", RecurrenceID: day(3), Title: "moved", StartTime: day(3).Add(6 * time.Hour), EndTime: day(3).Add(7 * time.Hour)},
+			{SeriesID: "series", RecurrenceID: day(7), StartTime: day(1).Add(5 * time.Hour), EndTime: day(1).Add(6 * time.Hour)},
+		}, nil)
+
+	result, err := s.service.GetEvents(ctx, filter, internal.PageRequest{})
+
+	require.NoError(s.T(), err)
+	require.Len(s.T(), result.Events, 3)
+	require.Equal(s.T(), day(1), result.Events[0].StartTime)
+	require.Equal(s.T(), day(1).Add(5*time.Hour), result.Events[1].StartTime)
+	require.Equal(s.T(), day(7), *result.Events[1].RecurrenceID)
+	require.Equal(s.T(), "moved", result.Events[2].Title)
+	require.Equal(s.T(), day(3).Add(6*time.Hour), result.Events[2].StartTime)
+	require.Equal(s.T(), day(3), *result.Events[2].RecurrenceID)
+}
+
+func (s *ServiceTestSuite) TestGetEvents_SeriesWithoutWindow() {
+	ctx := s.ctx
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	series := internal.CreateEventResponse{ID: "series", StartTime: start, EndTime: start.Add(time.Hour), RRule: "FREQ=DAILY"}
+
+	cancelled := internal.OccurrenceException{SeriesID: "series", RecurrenceID: start.AddDate(0, 0, 1), Cancelled: true}
+
+	s.mockStorage.EXPECT().
+		GetEvents(gomock.Any(), internal.EventFilter{}, internal.Page{Limit: internal.DefaultPageSize + 1}).
+		Return([]internal.CreateEventResponse{series}, nil)
+
+	s.mockStorage.EXPECT().
+		GetOccurrenceExceptions(gomock.Any(), []string{"series"}).
+		Return([]internal.OccurrenceException{cancelled}, nil)
+
+	result, err := s.service.GetEvents(ctx, internal.EventFilter{}, internal.PageRequest{})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), []internal.CreateEventResponse{series}, result.Events)
+	require.Equal(s.T(), []internal.OccurrenceException{cancelled}, result.Exceptions)
+}
+
+func (s *ServiceTestSuite) TestGetEvents_InvalidWindow() {
+	ctx := s.ctx
+	from := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := s.service.GetEvents(ctx, internal.EventFilter{From: from, To: from.Add(-time.Hour)}, internal.PageRequest{})
+
+	require.Error(s.T(), err)
+	require.ErrorIs(s.T(), err, internal.ErrInput)
+	require.EqualError(s.T(), err, "from should be before to: missing input values")
+}
+
+func (s *ServiceTestSuite) TestGetEvents_InvalidCreatedRange() {
+	ctx := s.ctx
+	createdFrom := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := s.service.GetEvents(ctx, internal.EventFilter{CreatedFrom: createdFrom, CreatedTo: createdFrom}, internal.PageRequest{})
+
+	require.Error(s.T(), err)
+	require.ErrorIs(s.T(), err, internal.ErrInput)
+	require.EqualError(s.T(), err, "created_from should be before created_to: missing input values")
+}
+
+func (s *ServiceTestSuite) TestGetEvents_LimitTooLarge() {
+	ctx := s.ctx
+
+	_, err := s.service.GetEvents(ctx, internal.EventFilter{}, internal.PageRequest{Limit: internal.MaxPageSize + 1})
+
+	require.Error(s.T(), err)
+	require.ErrorIs(s.T(), err, internal.ErrInput)
+}
+
+func (s *ServiceTestSuite) TestGetOccurrences_OneOff() {
+	ctx := s.ctx
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	event := internal.CreateEventResponse{ID: "single", StartTime: start, EndTime: start.Add(time.Hour)}
+
+	s.mockStorage.EXPECT().
+		GetEventByID(gomock.Any(), "single", false).
+		Return(event, nil).
+		Times(2)
+
+	result, err := s.service.GetOccurrences(ctx, "single", start, start.AddDate(0, 0, 1))
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), []internal.CreateEventResponse{event}, result)
+
+	result, err = s.service.GetOccurrences(ctx, "single", start.AddDate(0, 0, 1), start.AddDate(0, 0, 2))
+	require.NoError(s.T(), err)
+	require.Empty(s.T(), result)
+}
+
+func (s *ServiceTestSuite) TestGetOccurrenceExceptions() {
+	exception := internal.OccurrenceException{SeriesID: "series", RecurrenceID: time.Date(2025, 12, 2, 9, 0, 0, 0, time.UTC), Cancelled: true}
+
+	s.mockStorage.EXPECT().
+		GetOccurrenceExceptions(gomock.Any(), []string{"series"}).
+		Return([]internal.OccurrenceException{exception}, nil)
+
+	result, err := s.service.GetOccurrenceExceptions(s.ctx, "series")
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), []internal.OccurrenceException{exception}, result)
+
+	_, err = s.service.GetOccurrenceExceptions(s.ctx, "")
+	require.ErrorIs(s.T(), err, internal.ErrInput)
+}
+
+func (s *ServiceTestSuite) TestGetOccurrences_MissingWindow() {
+	_, err := s.service.GetOccurrences(s.ctx, "series", time.Time{}, time.Now())
+
+	require.ErrorIs(s.T(), err, internal.ErrInput)
+}
+
+func (s *ServiceTestSuite) TestGetOccurrences_AllDayInCallerZone() {
+	ctx := s.ctx
+	christmas := time.Date(2025, 12, 25, 0, 0, 0, 0, time.UTC)
+	event := internal.CreateEventResponse{ID: "holiday", StartTime: christmas, EndTime: christmas.AddDate(0, 0, 1), AllDay: true}
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(s.T(), err)
+
+	s.mockStorage.EXPECT().GetEventByID(gomock.Any(), "holiday", false).Return(event, nil).Times(2)
+
+	// Christmas morning in Tokyo is still December 24th in UTC.
+	from := time.Date(2025, 12, 25, 8, 0, 0, 0, tokyo)
+	result, err := s.service.GetOccurrences(ctx, "holiday", from, from.Add(time.Hour))
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), []internal.CreateEventResponse{event}, result)
+
+	// Christmas Eve evening in Tokyo is already the 25th in UTC, but not there.
+	from = time.Date(2025, 12, 24, 20, 0, 0, 0, tokyo)
+	result, err = s.service.GetOccurrences(ctx, "holiday", from, from.Add(time.Hour))
+
+	require.NoError(s.T(), err)
+	require.Empty(s.T(), result)
+}
+
+func (s *ServiceTestSuite) TestGetOccurrences_AllDaySeries() {
+	ctx := s.ctx
+	start := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	series := internal.CreateEventResponse{
+		ID:        "series",
+		StartTime: start,
+		EndTime:   start.AddDate(0, 0, 1),
+		RRule:     "FREQ=WEEKLY",
+		AllDay:    true,
+	}
+
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(s.T(), err)
+
+	s.mockStorage.EXPECT().GetEventByID(gomock.Any(), "series", false).Return(series, nil)
+	s.mockStorage.EXPECT().GetOccurrenceExceptions(gomock.Any(), []string{"series"}).Return(nil, nil)
+
+	// From Monday the 8th at 20:00 to Tuesday at 08:00 in New York: only Monday's occurrence.
+	from := time.Date(2025, 12, 8, 20, 0, 0, 0, newYork)
+	result, err := s.service.GetOccurrences(ctx, "series", from, from.Add(12*time.Hour))
+
+	require.NoError(s.T(), err)
+	require.Len(s.T(), result, 1)
+	require.Equal(s.T(), start.AddDate(0, 0, 7), result[0].StartTime)
+	require.Equal(s.T(), start.AddDate(0, 0, 8), result[0].EndTime)
+}
+
+func (s *ServiceTestSuite) TestUpdateOccurrence_Success() {
+	ctx := s.ctx
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	recurrenceID := start.AddDate(0, 0, 7)
+	series := internal.CreateEventResponse{ID: "series", Title: "standup", StartTime: start, EndTime: start.Add(time.Hour), RRule: "FREQ=WEEKLY"}
+	exception := internal.OccurrenceException{SeriesID: "series", RecurrenceID: recurrenceID, Title: "retro"}
+
+	s.mockStorage.EXPECT().
+		GetEventByID(gomock.Any(), "series", false).
+		Return(series, nil)
+
+	s.mockStorage.EXPECT().
+		SaveOccurrenceException(gomock.Any(), exception).
+		Return(nil)
+
+	result, err := s.service.UpdateOccurrence(ctx, exception)
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), "retro", result.Title)
+	require.Equal(s.T(), recurrenceID, result.StartTime)
+	require.Equal(s.T(), recurrenceID.Add(time.Hour), result.EndTime)
+	require.Equal(s.T(), recurrenceID, *result.RecurrenceID)
+}
+
+func (s *ServiceTestSuite) TestUpdateOccurrence_NotAnOccurrence() {
+	ctx := s.ctx
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	series := internal.CreateEventResponse{ID: "series", StartTime: start, EndTime: start.Add(time.Hour), RRule: "FREQ=WEEKLY"}
+
+	s.mockStorage.EXPECT().
+		GetEventByID(gomock.Any(), "series", false).
+		Return(series, nil)
+
+	_, err := s.service.UpdateOccurrence(ctx, internal.OccurrenceException{SeriesID: "series", RecurrenceID: start.AddDate(0, 0, 1)})
+
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+}
+
+func (s *ServiceTestSuite) TestUpdateOccurrence_NotRecurring() {
+	ctx := s.ctx
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+
+	s.mockStorage.EXPECT().
+		GetEventByID(gomock.Any(), "single", false).
+		Return(internal.CreateEventResponse{ID: "single", StartTime: start, EndTime: start.Add(time.Hour)}, nil)
+
+	_, err := s.service.UpdateOccurrence(ctx, internal.OccurrenceException{SeriesID: "single", RecurrenceID: start})
+
+	require.ErrorIs(s.T(), err, internal.ErrInput)
+}
+
+func (s *ServiceTestSuite) TestCancelOccurrence_Success() {
+	ctx := s.ctx
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	series := internal.CreateEventResponse{ID: "series", StartTime: start, EndTime: start.Add(time.Hour), RRule: "FREQ=DAILY"}
+
+	s.mockStorage.EXPECT().
+		GetEventByID(gomock.Any(), "series", false).
+		Return(series, nil)
+
+	s.mockStorage.EXPECT().
+		SaveOccurrenceException(gomock.Any(), internal.OccurrenceException{SeriesID: "series", RecurrenceID: start.AddDate(0, 0, 3), Cancelled: true}).
+		Return(nil)
+
+	err := s.service.CancelOccurrence(ctx, "series", start.AddDate(0, 0, 3))
+
+	require.NoError(s.T(), err)
+}
+
+func (s *ServiceTestSuite) TestSplitSeries_Success() {
+	ctx := s.ctx
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	at := start.AddDate(0, 0, 14)
+	title := strings.Repeat("a", 101)
+
+	series := internal.CreateEventResponse{
+		ID:          "series",
+		Title:       title,
+		Description: "weekly sync",
+		StartTime:   start,
+		EndTime:     start.Add(time.Hour),
+		RRule:       "FREQ=WEEKLY;COUNT=10",
+		ExDates:     []time.Time{start.AddDate(0, 0, 7), start.AddDate(0, 0, 21)},
+	}
+
+	head := internal.CreateEventRequest{
+		Title:       title,
+		Description: "weekly sync",
+		StartTime:   start,
+		EndTime:     start.Add(time.Hour),
+		RRule:       "FREQ=WEEKLY;UNTIL=20251215T085959Z",
+		ExDates:     []time.Time{start.AddDate(0, 0, 7)},
+	}
+
+	tail := internal.CreateEventRequest{
+		Title:       title,
+		Description: "weekly sync",
+		StartTime:   at.Add(time.Hour),
+		EndTime:     at.Add(2 * time.Hour),
+		RRule:       "FREQ=WEEKLY;COUNT=8",
+		ExDates:     []time.Time{start.AddDate(0, 0, 21).Add(time.Hour)},
+	}
+
+	s.mockStorage.EXPECT().
+		GetEventByID(gomock.Any(), "series", false).
+		Return(series, nil)
+
+	s.mockStorage.EXPECT().
+		SplitSeries(gomock.Any(), "series", at, head, tail).
+		Return(internal.CreateEventResponse{ID: "tail"}, nil)
+
+	result, err := s.service.SplitSeries(ctx, "series", at, internal.CreateEventRequest{StartTime: at.Add(time.Hour)})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), "tail", result.ID)
+}
+
+func (s *ServiceTestSuite) TestSplitSeries_FirstOccurrence() {
+	ctx := s.ctx
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	series := internal.CreateEventResponse{ID: "series", StartTime: start, EndTime: start.Add(time.Hour), RRule: "FREQ=WEEKLY"}
+
+	s.mockStorage.EXPECT().
+		GetEventByID(gomock.Any(), "series", false).
+		Return(series, nil)
+
+	_, err := s.service.SplitSeries(ctx, "series", start, internal.CreateEventRequest{})
+
+	require.ErrorIs(s.T(), err, internal.ErrInput)
+}
+
+func (s *ServiceTestSuite) TestImportEvents_Report() {
+	ctx := s.ctx
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+
+	valid := internal.CreateEventRequest{
+		Title:       strings.Repeat("a", 101),
+		Description: "imported",
+		StartTime:   start,
+		EndTime:     start.Add(time.Hour),
+	}
+
+	events := []internal.ImportedEvent{
+		{UID: "broken", Err: errors.New("missing DTSTART")},
+		{UID: "first", Event: valid},
+		{UID: "short", Event: internal.CreateEventRequest{Title: "short", Description: "imported", StartTime: start, EndTime: start.Add(time.Hour)}},
+		{UID: "first", Event: valid},
+		{UID: "second", Event: valid},
+	}
+
+	s.mockStorage.EXPECT().
+		ImportEvents(gomock.Any(), []internal.ImportedEvent{events[1], events[4]}).
+		Return([]internal.ImportResult{
+			{UID: "first", ID: "id-1", Status: internal.ImportCreated},
+			{UID: "second", ID: "id-2", Status: internal.ImportUpdated},
+		}, nil)
+
+	results, err := s.service.ImportEvents(ctx, events)
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), []internal.ImportResult{
+		{UID: "broken", Status: internal.ImportRejected, Reason: "missing DTSTART"},
+		{UID: "first", ID: "id-1", Status: internal.ImportCreated},
+		{UID: "short", Status: internal.ImportRejected, Reason: "title should have at least 101 characters: missing input values"},
+		{UID: "first", Status: internal.ImportRejected, Reason: "duplicated UID"},
+		{UID: "second", ID: "id-2", Status: internal.ImportUpdated},
+	}, results)
+}
+
+func (s *ServiceTestSuite) TestImportEvents_NothingValid() {
+	results, err := s.service.ImportEvents(s.ctx, []internal.ImportedEvent{{UID: "broken", Err: errors.New("missing UID")}})
+
+	require.NoError(s.T(), err)
+	require.Len(s.T(), results, 1)
+	require.Equal(s.T(), internal.ImportRejected, results[0].Status)
+}
+
+func (s *ServiceTestSuite) TestImportEvents_StorageError() {
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	event := internal.CreateEventRequest{Title: strings.Repeat("a", 101), Description: "imported", StartTime: start, EndTime: start.Add(time.Hour)}
+
+	s.mockStorage.EXPECT().
+		ImportEvents(gomock.Any(), gomock.Any()).
+		Return(nil, errors.New("database error"))
+
+	_, err := s.service.ImportEvents(s.ctx, []internal.ImportedEvent{{UID: "first", Event: event}})
+
+	require.Error(s.T(), err)
+}
+
+func (s *ServiceTestSuite) TestDeleteEvent_Success() {
+	ctx := s.ctx
+
+	s.mockStorage.EXPECT().
+		DeleteEvent(gomock.Any(), "test-id").
+		Return(nil)
+
+	err := s.service.DeleteEvent(ctx, "test-id")
+
+	require.NoError(s.T(), err)
+}
+
+func (s *ServiceTestSuite) TestDeleteEvent_EmptyID() {
+	ctx := s.ctx
+
+	err := s.service.DeleteEvent(ctx, "")
+
+	require.Error(s.T(), err)
+	require.ErrorIs(s.T(), err, internal.ErrInput)
+}
+
+func (s *ServiceTestSuite) TestDeleteEvent_NotFound() {
+	ctx := s.ctx
+
+	s.mockStorage.EXPECT().
+		DeleteEvent(gomock.Any(), "nonexistent-id").
+		Return(internal.ErrNotFound)
+
+	err := s.service.DeleteEvent(ctx, "nonexistent-id")
+
+	require.Error(s.T(), err)
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+	require.EqualError(s.T(), err, "deleting event: not found")
+}
+
+func (s *ServiceTestSuite) TestRestoreEvent_Success() {
+	ctx := s.ctx
+	expectedEvent := internal.CreateEventResponse{ID: "test-id"}
+
+	s.mockStorage.EXPECT().
+		RestoreEvent(gomock.Any(), "test-id").
+		Return(expectedEvent, nil)
+
+	result, err := s.service.RestoreEvent(ctx, "test-id")
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), expectedEvent, result)
+}
+
+func (s *ServiceTestSuite) TestRestoreEvent_NotFound() {
+	ctx := s.ctx
+
+	s.mockStorage.EXPECT().
+		RestoreEvent(gomock.Any(), "nonexistent-id").
+		Return(internal.CreateEventResponse{}, internal.ErrNotFound)
+
+	_, err := s.service.RestoreEvent(ctx, "nonexistent-id")
+
+	require.Error(s.T(), err)
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+	require.EqualError(s.T(), err, "restoring event: not found")
+}
+
+func (s *ServiceTestSuite) TestGetEventHistory_Success() {
+	revisions := []internal.EventRevision{
+		{EventID: "test-id", Revision: 1, Action: internal.RevisionCreated},
+		{EventID: "test-id", Revision: 2, Action: internal.RevisionDeleted},
+	}
+
+	s.mockStorage.EXPECT().GetEventRevisions(gomock.Any(), "test-id").Return(revisions, nil)
+
+	result, err := s.service.GetEventHistory(s.ctx, "test-id")
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), revisions, result)
+}
+
+func (s *ServiceTestSuite) TestGetEventHistory_NotFound() {
+	s.mockStorage.EXPECT().
+		GetEventRevisions(gomock.Any(), "nonexistent-id").
+		Return(nil, fmt.Errorf("event not found: %w", internal.ErrNotFound))
+
+	_, err := s.service.GetEventHistory(s.ctx, "nonexistent-id")
+
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+}
+
+func (s *ServiceTestSuite) TestRevertEvent_Success() {
+	now := time.Now()
+
+	snapshot := internal.CreateEventRequest{
+		Title:       strings.Repeat("a", 101),
+		Description: "pepito",
+		StartTime:   now,
+		EndTime:     now.Add(time.Hour),
+		CalendarID:  "team",
+	}
+
+	s.mockStorage.EXPECT().
+		GetEventRevision(gomock.Any(), "test-id", 2).
+		Return(internal.EventRevision{EventID: "test-id", Revision: 2, Snapshot: snapshot}, nil)
+	s.mockStorage.EXPECT().
+		GetEventRevisions(gomock.Any(), "test-id").
+		Return([]internal.EventRevision{
+			// An exception changed before the revision is part of it.
+			{EventID: "test-id", Revision: 1, Changes: map[string]internal.FieldChange{"occurrences/2025-12-02T09:00:00Z": {To: []byte(`{}`)}}},
+			{EventID: "test-id", Revision: 2, Snapshot: snapshot},
+			{EventID: "test-id", Revision: 3, Changes: map[string]internal.FieldChange{"title": {To: []byte(`"Retro"`)}}},
+		}, nil)
+	s.mockStorage.EXPECT().GetCalendar(gomock.Any(), "team").Return(internal.Calendar{ID: "team"}, nil)
+	s.mockStorage.EXPECT().
+		UpdateEvent(gomock.Any(), "test-id", 5, snapshot).
+		Return(internal.CreateEventResponse{ID: "test-id", Version: 6}, nil)
+
+	result, err := s.service.RevertEvent(s.ctx, "test-id", 2, 5)
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 6, result.Version)
+}
+
+func (s *ServiceTestSuite) TestRevertEvent_OccurrencesChanged() {
+	s.mockStorage.EXPECT().
+		GetEventRevision(gomock.Any(), "test-id", 1).
+		Return(internal.EventRevision{EventID: "test-id", Revision: 1}, nil)
+	s.mockStorage.EXPECT().
+		GetEventRevisions(gomock.Any(), "test-id").
+		Return([]internal.EventRevision{
+			{EventID: "test-id", Revision: 1},
+			{EventID: "test-id", Revision: 2, Changes: map[string]internal.FieldChange{"occurrences/2025-12-02T09:00:00Z": {To: []byte(`{"cancelled":true}`)}}},
+		}, nil)
+
+	_, err := s.service.RevertEvent(s.ctx, "test-id", 1, 0)
+
+	require.ErrorIs(s.T(), err, internal.ErrConflict)
+	require.EqualError(s.T(), err, "revision 2 changed occurrences after revision 1, which cannot be reverted: conflict")
+}
+
+func (s *ServiceTestSuite) TestRevertEvent_InvalidRevision() {
+	_, err := s.service.RevertEvent(s.ctx, "test-id", 0, 5)
+
+	require.ErrorIs(s.T(), err, internal.ErrInput)
+	require.EqualError(s.T(), err, "revision should be positive: missing input values")
+}
+
+func (s *ServiceTestSuite) TestRevertEvent_RevisionNotFound() {
+	s.mockStorage.EXPECT().
+		GetEventRevision(gomock.Any(), "test-id", 9).
+		Return(internal.EventRevision{}, fmt.Errorf("revision not found: %w", internal.ErrNotFound))
+
+	_, err := s.service.RevertEvent(s.ctx, "test-id", 9, 0)
+
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+}
+
+func (s *ServiceTestSuite) TestRevertEvent_InvalidSnapshot() {
+	// Rules tightened since the revision was written still apply.
+	s.mockStorage.EXPECT().
+		GetEventRevision(gomock.Any(), "test-id", 1).
+		Return(internal.EventRevision{EventID: "test-id", Revision: 1, Snapshot: internal.CreateEventRequest{Title: "Short"}}, nil)
+	s.mockStorage.EXPECT().
+		GetEventRevisions(gomock.Any(), "test-id").
+		Return([]internal.EventRevision{{EventID: "test-id", Revision: 1}}, nil)
+
+	_, err := s.service.RevertEvent(s.ctx, "test-id", 1, 0)
+
+	require.ErrorIs(s.T(), err, internal.ErrInput)
+}
+
+func madridDay(s *ServiceTestSuite, day, hour, minute int) time.Time {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	require.NoError(s.T(), err)
+
+	return time.Date(2025, 12, day, hour, minute, 0, 0, madrid)
+}
+
+func (s *ServiceTestSuite) TestSearchAvailability_Slots() {
+	ctx := s.ctx
+	meeting := internal.CreateEventResponse{ID: "meeting", StartTime: madridDay(s, 1, 9, 0), EndTime: madridDay(s, 1, 10, 0), Resource: "room-a"}
+	standup := internal.CreateEventResponse{
+		ID:        "standup",
+		StartTime: madridDay(s, 1, 11, 0).UTC(),
+		EndTime:   madridDay(s, 1, 11, 30).UTC(),
+		RRule:     "FREQ=DAILY",
+		TimeZone:  "Europe/Madrid",
+		Resource:  "room-b",
+	}
+
+	s.mockStorage.EXPECT().GetEvents(gomock.Any(), gomock.Any(), internal.Page{Limit: internal.MaxPageSize}).
+		DoAndReturn(func(_ context.Context, filter internal.EventFilter, _ internal.Page) ([]internal.CreateEventResponse, error) {
+			require.Equal(s.T(), []string{"room-a", "room-b"}, filter.Resources)
+			require.Equal(s.T(), "Europe/Madrid", filter.From.Location().String())
+
+			return []internal.CreateEventResponse{meeting, standup}, nil
+		})
+	s.mockStorage.EXPECT().GetOccurrenceExceptions(gomock.Any(), []string{"standup"}).Return(nil, nil)
+
+	result, err := s.service.SearchAvailability(ctx, internal.AvailabilityQuery{
+		Resources:    []string{"room-a", "room-b"},
+		From:         madridDay(s, 1, 0, 0),
+		To:           madridDay(s, 2, 0, 0),
+		Duration:     45 * time.Minute,
+		WorkingHours: &internal.WorkingHours{Start: 9 * time.Hour, End: 12 * time.Hour},
+		TimeZone:     "Europe/Madrid",
+	})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), interval.Set{
+		{Start: madridDay(s, 1, 10, 0), End: madridDay(s, 1, 11, 0)},
+		{Start: madridDay(s, 1, 11, 30), End: madridDay(s, 1, 12, 0)},
+	}, result.Free)
+	require.Len(s.T(), result.Busy, 2)
+	require.Len(s.T(), result.Slots, 2)
+	require.True(s.T(), result.Slots[0].Start.Equal(madridDay(s, 1, 10, 0)))
+	require.True(s.T(), result.Slots[1].Start.Equal(madridDay(s, 1, 10, 15)))
+}
+
+func (s *ServiceTestSuite) TestSearchAvailability_Calendars() {
+	// Ada's daily standup blocks her calendar. The review books room-a in her
+	// calendar, so both searches find it, and it is only counted once.
+	review := internal.CreateEventResponse{ID: "review", StartTime: madridDay(s, 1, 9, 0), EndTime: madridDay(s, 1, 10, 0), Resource: "room-a", CalendarID: "ada"}
+	standup := internal.CreateEventResponse{
+		ID:         "standup",
+		StartTime:  madridDay(s, 1, 11, 0).UTC(),
+		EndTime:    madridDay(s, 1, 11, 30).UTC(),
+		RRule:      "FREQ=DAILY",
+		TimeZone:   "Europe/Madrid",
+		CalendarID: "ada",
+	}
+
+	s.mockStorage.EXPECT().GetCalendar(gomock.Any(), "ada").Return(internal.Calendar{ID: "ada"}, nil)
+	gomock.InOrder(
+		s.mockStorage.EXPECT().GetEvents(gomock.Any(), gomock.Any(), internal.Page{Limit: internal.MaxPageSize}).
+			DoAndReturn(func(_ context.Context, filter internal.EventFilter, _ internal.Page) ([]internal.CreateEventResponse, error) {
+				require.Equal(s.T(), []string{"room-a"}, filter.Resources)
+				require.Empty(s.T(), filter.CalendarIDs)
+
+				return []internal.CreateEventResponse{review}, nil
+			}),
+		s.mockStorage.EXPECT().GetEvents(gomock.Any(), gomock.Any(), internal.Page{Limit: internal.MaxPageSize}).
+			DoAndReturn(func(_ context.Context, filter internal.EventFilter, _ internal.Page) ([]internal.CreateEventResponse, error) {
+				require.Empty(s.T(), filter.Resources)
+				require.Equal(s.T(), []string{"ada"}, filter.CalendarIDs)
+
+				return []internal.CreateEventResponse{review, standup}, nil
+			}),
+	)
+	s.mockStorage.EXPECT().GetOccurrenceExceptions(gomock.Any(), []string{"standup"}).Return(nil, nil)
+
+	result, err := s.service.SearchAvailability(s.ctx, internal.AvailabilityQuery{
+		Resources:    []string{"room-a"},
+		CalendarIDs:  []string{"ada"},
+		From:         madridDay(s, 1, 0, 0),
+		To:           madridDay(s, 3, 0, 0),
+		Duration:     45 * time.Minute,
+		WorkingHours: &internal.WorkingHours{Start: 9 * time.Hour, End: 12 * time.Hour},
+		TimeZone:     "Europe/Madrid",
+	})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), interval.Set{
+		{Start: madridDay(s, 1, 10, 0), End: madridDay(s, 1, 11, 0)},
+		{Start: madridDay(s, 1, 11, 30), End: madridDay(s, 1, 12, 0)},
+		{Start: madridDay(s, 2, 9, 0), End: madridDay(s, 2, 11, 0)},
+		{Start: madridDay(s, 2, 11, 30), End: madridDay(s, 2, 12, 0)},
+	}, result.Free)
+}
+
+func (s *ServiceTestSuite) TestSearchAvailability_CalendarsOnly() {
+	s.mockStorage.EXPECT().GetCalendar(gomock.Any(), "ada").Return(internal.Calendar{ID: "ada"}, nil)
+	s.mockStorage.EXPECT().GetEvents(gomock.Any(), internal.EventFilter{
+		From:        madridDay(s, 1, 0, 0),
+		To:          madridDay(s, 2, 0, 0),
+		CalendarIDs: []string{"ada"},
+	}, gomock.Any()).Return(nil, nil)
+
+	result, err := s.service.SearchAvailability(s.ctx, internal.AvailabilityQuery{
+		CalendarIDs: []string{"ada"},
+		From:        madridDay(s, 1, 0, 0),
+		To:          madridDay(s, 2, 0, 0),
+		Duration:    time.Hour,
+		TimeZone:    "Europe/Madrid",
+	})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), interval.Set{{Start: madridDay(s, 1, 0, 0), End: madridDay(s, 2, 0, 0)}}, result.Free)
+}
+
+func (s *ServiceTestSuite) TestSearchAvailability_UnknownCalendar() {
+	s.mockStorage.EXPECT().GetCalendar(gomock.Any(), "ada").Return(internal.Calendar{ID: "ada"}, nil)
+	s.mockStorage.EXPECT().GetCalendar(gomock.Any(), "bob").Return(internal.Calendar{}, fmt.Errorf("calendar not found: %w", internal.ErrNotFound))
+
+	// Without the check, the events of bob would be none and all the week free.
+	_, err := s.service.SearchAvailability(s.ctx, internal.AvailabilityQuery{
+		CalendarIDs: []string{"ada", "bob"},
+		From:        madridDay(s, 1, 0, 0),
+		To:          madridDay(s, 8, 0, 0),
+		Duration:    time.Hour,
+	})
+
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+}
+
+func (s *ServiceTestSuite) TestSearchAvailability_CalendarNotVisible() {
+	ctx := internal.WithActor(context.Background(), "auth0|bob")
+
+	s.mockStorage.EXPECT().GetCalendarAccess(gomock.Any(), "auth0|bob", "ada").Return(nil, nil)
+
+	_, err := s.service.SearchAvailability(ctx, internal.AvailabilityQuery{
+		CalendarIDs: []string{"ada"},
+		From:        madridDay(s, 1, 0, 0),
+		To:          madridDay(s, 8, 0, 0),
+		Duration:    time.Hour,
+	})
+
+	require.ErrorIs(s.T(), err, internal.ErrForbidden)
+}
+
+func (s *ServiceTestSuite) TestSearchAvailability_RanksEdgesFirst() {
+	ctx := s.ctx
+
+	s.mockStorage.EXPECT().GetEvents(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+
+	result, err := s.service.SearchAvailability(ctx, internal.AvailabilityQuery{
+		Resources:    []string{"room-a"},
+		From:         madridDay(s, 1, 0, 0),
+		To:           madridDay(s, 2, 0, 0),
+		Duration:     time.Hour,
+		WorkingHours: &internal.WorkingHours{Start: 9 * time.Hour, End: 12 * time.Hour},
+		TimeZone:     "Europe/Madrid",
+		Limit:        3,
+	})
+
+	require.NoError(s.T(), err)
+
+	starts := make([]time.Time, 0, len(result.Slots))
+	for _, slot := range result.Slots {
+		starts = append(starts, slot.Start)
+	}
+
+	require.Equal(s.T(), []time.Time{madridDay(s, 1, 9, 0), madridDay(s, 1, 11, 0), madridDay(s, 1, 9, 15)}, starts)
+}
+
+func (s *ServiceTestSuite) TestSearchAvailability_WorkingHoursAcrossDST() {
+	ctx := s.ctx
+	madrid := madridDay(s, 1, 0, 0).Location()
+
+	s.mockStorage.EXPECT().GetEvents(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+
+	// Clocks go forward on Sunday March 30th, the weekend has no working hours.
+	result, err := s.service.SearchAvailability(ctx, internal.AvailabilityQuery{
+		Resources:    []string{"room-a"},
+		From:         time.Date(2025, 3, 28, 0, 0, 0, 0, madrid),
+		To:           time.Date(2025, 4, 1, 0, 0, 0, 0, madrid),
+		Duration:     time.Hour,
+		WorkingHours: &internal.WorkingHours{Start: 9 * time.Hour, End: 10 * time.Hour},
+		TimeZone:     "Europe/Madrid",
+	})
+
+	require.NoError(s.T(), err)
+	require.Len(s.T(), result.Free, 2)
+	require.Equal(s.T(), time.Date(2025, 3, 28, 8, 0, 0, 0, time.UTC), result.Free[0].Start.UTC())
+	require.Equal(s.T(), time.Date(2025, 3, 31, 7, 0, 0, 0, time.UTC), result.Free[1].Start.UTC())
+}
+
+func (s *ServiceTestSuite) TestSearchAvailability_AllDayInQueryZone() {
+	ctx := s.ctx
+	first := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	offsite := internal.CreateEventResponse{ID: "offsite", StartTime: first, EndTime: first.AddDate(0, 0, 1), AllDay: true, Resource: "room-a"}
+
+	s.mockStorage.EXPECT().GetEvents(gomock.Any(), gomock.Any(), gomock.Any()).Return([]internal.CreateEventResponse{offsite}, nil)
+
+	result, err := s.service.SearchAvailability(ctx, internal.AvailabilityQuery{
+		Resources: []string{"room-a"},
+		From:      madridDay(s, 1, 0, 0),
+		To:        madridDay(s, 3, 0, 0),
+		Duration:  time.Hour,
+		TimeZone:  "Europe/Madrid",
+	})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), interval.Set{{Start: madridDay(s, 1, 0, 0), End: madridDay(s, 2, 0, 0)}}, result.Busy)
+	require.Equal(s.T(), interval.Set{{Start: madridDay(s, 2, 0, 0), End: madridDay(s, 3, 0, 0)}}, result.Free)
+}
+
+func (s *ServiceTestSuite) TestSearchAvailability_WalksPages() {
+	ctx := s.ctx
+	start := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	full := make([]internal.CreateEventResponse, internal.MaxPageSize)
+	for i := range full {
+		full[i] = internal.CreateEventResponse{ID: fmt.Sprintf("event-%03d", i), StartTime: start.Add(time.Duration(i) * time.Minute), Resource: "room-a"}
+		full[i].EndTime = full[i].StartTime.Add(time.Minute)
+	}
+
+	last := full[len(full)-1]
+
+	gomock.InOrder(
+		s.mockStorage.EXPECT().GetEvents(gomock.Any(), gomock.Any(), internal.Page{Limit: internal.MaxPageSize}).Return(full, nil),
+		s.mockStorage.EXPECT().GetEvents(gomock.Any(), gomock.Any(), internal.Page{
+			Limit: internal.MaxPageSize,
+			After: &internal.Cursor{StartTime: last.StartTime, ID: last.ID},
+		}).Return(nil, nil),
+	)
+
+	result, err := s.service.SearchAvailability(ctx, internal.AvailabilityQuery{
+		Resources: []string{"room-a"},
+		From:      start,
+		To:        start.AddDate(0, 0, 1),
+		Duration:  time.Hour,
+	})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), interval.Set{{Start: start, End: last.EndTime}}, result.Busy)
+}
+
+func (s *ServiceTestSuite) TestSearchAvailability_InvalidQuery() {
+	from := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	valid := internal.AvailabilityQuery{Resources: []string{"room-a"}, From: from, To: from.AddDate(0, 0, 7), Duration: time.Hour}
+
+	cases := map[string]func(q *internal.AvailabilityQuery){
+		"nothing to search": func(q *internal.AvailabilityQuery) { q.Resources = nil },
+		"no window":         func(q *internal.AvailabilityQuery) { q.To = time.Time{} },
+		"reversed window":   func(q *internal.AvailabilityQuery) { q.From, q.To = q.To, q.From },
+		"window too long":   func(q *internal.AvailabilityQuery) { q.To = from.AddDate(0, 3, 0) },
+		"no duration":       func(q *internal.AvailabilityQuery) { q.Duration = 0 },
+		"unknown zone":      func(q *internal.AvailabilityQuery) { q.TimeZone = "Mars/Olympus" },
+		"limit too large":   func(q *internal.AvailabilityQuery) { q.Limit = internal.MaxSlotLimit + 1 },
+		"inverted working": func(q *internal.AvailabilityQuery) {
+			q.WorkingHours = &internal.WorkingHours{Start: 17 * time.Hour, End: 9 * time.Hour}
+		},
+	}
+
+	for name, mutate := range cases {
+		query := valid
+		mutate(&query)
+
+		_, err := s.service.SearchAvailability(s.ctx, query)
+
+		require.ErrorIs(s.T(), err, internal.ErrInput, name)
+	}
+}
+
 func TestServiceTestSuite(t *testing.T) {
 	suite.Run(t, new(ServiceTestSuite))
 }
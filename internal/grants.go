@@ -0,0 +1,106 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal/validation"
+)
+
+type grantStorage interface {
+	accessStorage
+	GetGrants(ctx context.Context, objectType ObjectType, objectID string) ([]Grant, error)
+	SetGrant(ctx context.Context, grant Grant) error
+	DeleteGrant(ctx context.Context, objectType ObjectType, objectID, principal string) error
+}
+
+// GrantService manages who holds which role on calendars and events. Only
+// owners of the calendar or event manage its grants.
+type GrantService struct {
+	storage   grantStorage
+	authorize authorizer
+}
+
+func NewGrantService(storage grantStorage) *GrantService {
+	return &GrantService{
+		storage:   storage,
+		authorize: authorizer{storage: storage},
+	}
+}
+
+// GetGrants lists the grants on a calendar or event, oldest first.
+func (s *GrantService) GetGrants(ctx context.Context, objectType ObjectType, objectID string) ([]Grant, error) {
+	if err := validateObject(objectType, objectID); err != nil {
+		return nil, err
+	}
+
+	if err := s.authorize.object(ctx, objectType, objectID, AccessOwner); err != nil {
+		return nil, err
+	}
+
+	grants, err := s.storage.GetGrants(ctx, objectType, objectID)
+	if err != nil {
+		return nil, fmt.Errorf("getting grants: %w", err)
+	}
+
+	return grants, nil
+}
+
+// SetGrant gives a principal a role, replacing the one it had.
+func (s *GrantService) SetGrant(ctx context.Context, grant Grant) (Grant, error) {
+	if err := validateObject(grant.ObjectType, grant.ObjectID); err != nil {
+		return Grant{}, err
+	}
+
+	if err := inputError(validation.Struct(grant)); err != nil {
+		return Grant{}, err
+	}
+
+	if err := s.authorize.object(ctx, grant.ObjectType, grant.ObjectID, AccessOwner); err != nil {
+		return Grant{}, err
+	}
+
+	grant.GrantedBy = ActorFromContext(ctx)
+	grant.CreatedAt = time.Now().UTC()
+
+	if err := s.storage.SetGrant(ctx, grant); err != nil {
+		return Grant{}, fmt.Errorf("setting grant: %w", err)
+	}
+
+	return grant, nil
+}
+
+// DeleteGrant takes the role of a principal away. Creators keep owning what
+// they created.
+func (s *GrantService) DeleteGrant(ctx context.Context, objectType ObjectType, objectID, principal string) error {
+	if err := validateObject(objectType, objectID); err != nil {
+		return err
+	}
+
+	if principal == "" {
+		return fmt.Errorf("empty principal: %w", ErrInput)
+	}
+
+	if err := s.authorize.object(ctx, objectType, objectID, AccessOwner); err != nil {
+		return err
+	}
+
+	if err := s.storage.DeleteGrant(ctx, objectType, objectID, principal); err != nil {
+		return fmt.Errorf("deleting grant: %w", err)
+	}
+
+	return nil
+}
+
+func validateObject(objectType ObjectType, objectID string) error {
+	if objectType != ObjectCalendar && objectType != ObjectEvent {
+		return fmt.Errorf("grants are on a calendar or an event: %w", ErrInput)
+	}
+
+	if objectID == "" {
+		return fmt.Errorf("empty id: %w", ErrInput)
+	}
+
+	return nil
+}
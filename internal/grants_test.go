@@ -0,0 +1,185 @@
+package internal_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal"
+	"github.com/ObiaNzk/LTK-test-manu/internal/mocks"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+//go:generate mockgen -source=grants.go -destination=mocks/mock_grant_storage.go -package=mocks
+
+type GrantServiceTestSuite struct {
+	suite.Suite
+	ctx         context.Context
+	ctrl        *gomock.Controller
+	mockStorage *mocks.MockgrantStorage
+	service     *internal.GrantService
+}
+
+func (s *GrantServiceTestSuite) SetupTest() {
+	s.ctx = internal.WithActor(internal.WithTenant(context.Background(), "acme"), "auth0|ada")
+	s.ctrl = gomock.NewController(s.T())
+	s.mockStorage = mocks.NewMockgrantStorage(s.ctrl)
+	s.service = internal.NewGrantService(s.mockStorage)
+}
+
+func (s *GrantServiceTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func (s *GrantServiceTestSuite) TestGetGrants_Success() {
+	grants := []internal.Grant{{ObjectType: internal.ObjectCalendar, ObjectID: "team", Principal: "auth0|bob",
+		Role: internal.AccessViewer}}
+
+	s.mockStorage.EXPECT().
+		GetCalendarAccess(gomock.Any(), "auth0|ada", "team").
+		Return([]internal.AccessRole{internal.AccessOwner}, nil)
+	s.mockStorage.EXPECT().GetGrants(gomock.Any(), internal.ObjectCalendar, "team").Return(grants, nil)
+
+	result, err := s.service.GetGrants(s.ctx, internal.ObjectCalendar, "team")
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), grants, result)
+}
+
+func (s *GrantServiceTestSuite) TestGetGrants_Forbidden() {
+	s.mockStorage.EXPECT().
+		GetEventAccess(gomock.Any(), "auth0|ada", "evt").
+		Return(internal.EventAccess{CalendarID: "team", Roles: []internal.AccessRole{internal.AccessEditor}}, nil)
+
+	_, err := s.service.GetGrants(s.ctx, internal.ObjectEvent, "evt")
+
+	require.ErrorIs(s.T(), err, internal.ErrForbidden)
+}
+
+func (s *GrantServiceTestSuite) TestGetGrants_DefaultCalendarNeedsOwner() {
+	// Every member edits the default calendar, but that does not make them owner.
+	s.mockStorage.EXPECT().GetCalendarAccess(gomock.Any(), "auth0|ada", internal.DefaultCalendarID).Return(nil, nil)
+
+	_, err := s.service.GetGrants(s.ctx, internal.ObjectCalendar, internal.DefaultCalendarID)
+
+	require.ErrorIs(s.T(), err, internal.ErrForbidden)
+}
+
+func (s *GrantServiceTestSuite) TestGetGrants_NotFound() {
+	s.mockStorage.EXPECT().
+		GetEventAccess(gomock.Any(), "auth0|ada", "evt").
+		Return(internal.EventAccess{}, internal.ErrNotFound)
+
+	_, err := s.service.GetGrants(s.ctx, internal.ObjectEvent, "evt")
+
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+}
+
+func (s *GrantServiceTestSuite) TestSetGrant_Success() {
+	var stored internal.Grant
+
+	s.mockStorage.EXPECT().
+		GetCalendarAccess(gomock.Any(), "auth0|ada", "team").
+		Return([]internal.AccessRole{internal.AccessOwner}, nil)
+	s.mockStorage.EXPECT().
+		SetGrant(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, grant internal.Grant) error {
+			stored = grant
+			return nil
+		})
+
+	grant, err := s.service.SetGrant(s.ctx, internal.Grant{ObjectType: internal.ObjectCalendar, ObjectID: "team",
+		Principal: "auth0|bob", Role: internal.AccessEditor})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), stored, grant)
+	require.Equal(s.T(), "auth0|bob", grant.Principal)
+	require.Equal(s.T(), internal.AccessEditor, grant.Role)
+	require.Equal(s.T(), "auth0|ada", grant.GrantedBy)
+	require.WithinDuration(s.T(), time.Now(), grant.CreatedAt, time.Minute)
+}
+
+func (s *GrantServiceTestSuite) TestSetGrant_Invalid() {
+	for name, grant := range map[string]internal.Grant{
+		"unknown object": {ObjectType: "attendee", ObjectID: "team", Principal: "auth0|bob", Role: internal.AccessViewer},
+		"no id":          {ObjectType: internal.ObjectCalendar, Principal: "auth0|bob", Role: internal.AccessViewer},
+		"no principal":   {ObjectType: internal.ObjectCalendar, ObjectID: "team", Role: internal.AccessViewer},
+		"long principal": {ObjectType: internal.ObjectCalendar, ObjectID: "team", Principal: strings.Repeat("a", 201),
+			Role: internal.AccessViewer},
+		"unknown role": {ObjectType: internal.ObjectCalendar, ObjectID: "team", Principal: "auth0|bob", Role: "admin"},
+	} {
+		_, err := s.service.SetGrant(s.ctx, grant)
+
+		require.ErrorIs(s.T(), err, internal.ErrInput, name)
+	}
+}
+
+func (s *GrantServiceTestSuite) TestSetGrant_Forbidden() {
+	s.mockStorage.EXPECT().
+		GetEventAccess(gomock.Any(), "auth0|ada", "evt").
+		Return(internal.EventAccess{CalendarID: "team", Roles: []internal.AccessRole{internal.AccessViewer}}, nil)
+
+	_, err := s.service.SetGrant(s.ctx, internal.Grant{ObjectType: internal.ObjectEvent, ObjectID: "evt",
+		Principal: "auth0|ada", Role: internal.AccessOwner})
+
+	require.ErrorIs(s.T(), err, internal.ErrForbidden)
+}
+
+func (s *GrantServiceTestSuite) TestSetGrant_NoActor() {
+	_, err := s.service.SetGrant(context.Background(), internal.Grant{ObjectType: internal.ObjectEvent, ObjectID: "evt",
+		Principal: "auth0|ada", Role: internal.AccessOwner})
+
+	require.ErrorIs(s.T(), err, internal.ErrForbidden)
+}
+
+func (s *GrantServiceTestSuite) TestSetGrant_Admin() {
+	s.mockStorage.EXPECT().SetGrant(gomock.Any(), gomock.Any()).Return(nil)
+
+	_, err := s.service.SetGrant(internal.WithAdmin(s.ctx), internal.Grant{ObjectType: internal.ObjectEvent,
+		ObjectID: "evt", Principal: "auth0|bob", Role: internal.AccessOwner})
+
+	require.NoError(s.T(), err)
+}
+
+func (s *GrantServiceTestSuite) TestDeleteGrant_Success() {
+	s.mockStorage.EXPECT().
+		GetEventAccess(gomock.Any(), "auth0|ada", "evt").
+		Return(internal.EventAccess{CalendarID: "team", Roles: []internal.AccessRole{internal.AccessOwner}}, nil)
+	s.mockStorage.EXPECT().DeleteGrant(gomock.Any(), internal.ObjectEvent, "evt", "auth0|bob").Return(nil)
+
+	err := s.service.DeleteGrant(s.ctx, internal.ObjectEvent, "evt", "auth0|bob")
+
+	require.NoError(s.T(), err)
+}
+
+func (s *GrantServiceTestSuite) TestDeleteGrant_StorageError() {
+	s.mockStorage.EXPECT().
+		GetCalendarAccess(gomock.Any(), "auth0|ada", "team").
+		Return([]internal.AccessRole{internal.AccessOwner}, nil)
+	s.mockStorage.EXPECT().
+		DeleteGrant(gomock.Any(), internal.ObjectCalendar, "team", "auth0|bob").
+		Return(internal.ErrNotFound)
+
+	err := s.service.DeleteGrant(s.ctx, internal.ObjectCalendar, "team", "auth0|bob")
+
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+}
+
+func (s *GrantServiceTestSuite) TestDeleteGrant_AccessError() {
+	s.mockStorage.EXPECT().
+		GetCalendarAccess(gomock.Any(), "auth0|ada", "team").
+		Return(nil, errors.New("connection refused"))
+
+	err := s.service.DeleteGrant(s.ctx, internal.ObjectCalendar, "team", "auth0|bob")
+
+	require.Error(s.T(), err)
+	require.NotErrorIs(s.T(), err, internal.ErrForbidden)
+}
+
+func TestGrantServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(GrantServiceTestSuite))
+}
@@ -0,0 +1,367 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal/recurrence"
+)
+
+var ErrInvalidCalendar = errors.New("invalid calendar")
+
+// Decoded is one VEVENT read from a calendar. Err is set when the VEVENT could
+// not be read, and Event.UID is kept when it was present.
+type Decoded struct {
+	Event Event
+	Err   error
+}
+
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Decode reads every VEVENT of a VCALENDAR. A broken VEVENT is reported in its
+// Decoded entry; only a malformed calendar fails as a whole.
+func Decode(r io.Reader) ([]Decoded, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		decoded []Decoded
+		stack   []string
+		props   []property
+	)
+
+	for i, line := range lines {
+		prop, err := parseLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s: %w", i+1, err.Error(), ErrInvalidCalendar)
+		}
+
+		switch prop.name {
+		case "BEGIN":
+			if len(stack) == 0 && !strings.EqualFold(prop.value, "VCALENDAR") {
+				return nil, fmt.Errorf("line %d: expected BEGIN:VCALENDAR: %w", i+1, ErrInvalidCalendar)
+			}
+
+			stack = append(stack, strings.ToUpper(prop.value))
+
+			if stack[len(stack)-1] == "VEVENT" {
+				props = nil
+			}
+
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1] != strings.ToUpper(prop.value) {
+				return nil, fmt.Errorf("line %d: unexpected END:%s: %w", i+1, prop.value, ErrInvalidCalendar)
+			}
+
+			if stack[len(stack)-1] == "VEVENT" {
+				event, err := newEvent(props)
+				decoded = append(decoded, Decoded{Event: event, Err: err})
+			}
+
+			stack = stack[:len(stack)-1]
+
+			if len(stack) == 0 {
+				return decoded, nil
+			}
+
+		default:
+			// Only the properties of the VEVENT itself matter, not those of a
+			// nested VALARM.
+			if len(stack) > 0 && stack[len(stack)-1] == "VEVENT" {
+				props = append(props, prop)
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("missing END:VCALENDAR: %w", ErrInvalidCalendar)
+}
+
+// unfold joins folded content lines back together.
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var lines []string
+
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			if len(lines) == 0 {
+				return nil, fmt.Errorf("calendar starts with a continuation line: %w", ErrInvalidCalendar)
+			}
+
+			lines[len(lines)-1] += line[1:]
+
+			continue
+		}
+
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading calendar: %w", err)
+	}
+
+	return lines, nil
+}
+
+// parseLine splits a content line into its name, parameters and value. Parameter
+// values may be quoted to hold ':', ';' or ','.
+func parseLine(line string) (property, error) {
+	prop := property{params: map[string]string{}}
+
+	end := strings.IndexAny(line, ";:")
+	if end <= 0 {
+		return property{}, fmt.Errorf("malformed content line %q", line)
+	}
+
+	prop.name = strings.ToUpper(line[:end])
+	rest := line[end:]
+
+	for strings.HasPrefix(rest, ";") {
+		rest = rest[1:]
+
+		eq := strings.IndexByte(rest, '=')
+		if eq <= 0 {
+			return property{}, fmt.Errorf("malformed parameter in %q", line)
+		}
+
+		name := strings.ToUpper(rest[:eq])
+		rest = rest[eq+1:]
+
+		var value string
+
+		if strings.HasPrefix(rest, `"`) {
+			closing := strings.IndexByte(rest[1:], '"')
+			if closing < 0 {
+				return property{}, fmt.Errorf("unterminated quote in %q", line)
+			}
+
+			value = rest[1 : closing+1]
+			rest = rest[closing+2:]
+		} else {
+			stop := strings.IndexAny(rest, ";:")
+			if stop < 0 {
+				return property{}, fmt.Errorf("missing value in %q", line)
+			}
+
+			value = rest[:stop]
+			rest = rest[stop:]
+		}
+
+		prop.params[name] = value
+	}
+
+	if !strings.HasPrefix(rest, ":") {
+		return property{}, fmt.Errorf("missing value in %q", line)
+	}
+
+	prop.value = rest[1:]
+
+	return prop, nil
+}
+
+func newEvent(props []property) (Event, error) {
+	var (
+		event    Event
+		end      time.Time
+		duration *time.Duration
+		start    bool
+	)
+
+	for _, prop := range props {
+		if prop.name == "UID" {
+			event.UID = unescapeText(prop.value)
+		}
+	}
+
+	for _, prop := range props {
+		var err error
+
+		switch prop.name {
+		case "SUMMARY":
+			event.Summary = unescapeText(prop.value)
+		case "DESCRIPTION":
+			event.Description = unescapeText(prop.value)
+		case "DTSTAMP":
+			event.Stamp, _, err = parseTime(prop, prop.value)
+		case "DTSTART":
+			event.Start, event.AllDay, err = parseTime(prop, prop.value)
+			start = true
+		case "DTEND":
+			end, _, err = parseTime(prop, prop.value)
+		case "DURATION":
+			var d time.Duration
+			d, err = parseDuration(prop.value)
+			duration = &d
+		case "RRULE":
+			if event.RRule != "" {
+				err = errors.New("only one RRULE is supported")
+			}
+			event.RRule = prop.value
+		case "EXDATE":
+			var dates []time.Time
+			dates, err = parseTimeList(prop)
+			event.ExDates = append(event.ExDates, dates...)
+		case "RDATE":
+			var dates []time.Time
+			dates, err = parseTimeList(prop)
+			event.RDates = append(event.RDates, dates...)
+		case "RECURRENCE-ID":
+			var id time.Time
+			id, _, err = parseTime(prop, prop.value)
+			event.RecurrenceID = &id
+		}
+
+		if err != nil {
+			return event, fmt.Errorf("%s: %w", prop.name, err)
+		}
+	}
+
+	switch {
+	case event.UID == "":
+		return event, errors.New("missing UID")
+	case !start:
+		return event, errors.New("missing DTSTART")
+	case !end.IsZero() && duration != nil:
+		return event, errors.New("DTEND and DURATION cannot be combined")
+	}
+
+	switch {
+	case !end.IsZero():
+		event.End = end
+	case duration != nil:
+		event.End = event.Start.Add(*duration)
+	case event.AllDay:
+		event.End = event.Start.AddDate(0, 0, 1)
+	default:
+		event.End = event.Start
+	}
+
+	return event, nil
+}
+
+// parseTime reads a DATE or DATE-TIME value. DATE-TIMEs with a TZID are read
+// in that zone and floating ones as UTC. It reports whether the value is a DATE.
+func parseTime(prop property, raw string) (time.Time, bool, error) {
+	if prop.params["VALUE"] == "DATE" || len(raw) == len(dateLayout) {
+		date, err := time.Parse(dateLayout, raw)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid date %q", raw)
+		}
+
+		return date, true, nil
+	}
+
+	loc := time.UTC
+
+	if tzid := prop.params["TZID"]; tzid != "" {
+		var err error
+
+		loc, err = time.LoadLocation(strings.TrimPrefix(tzid, "/"))
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("unknown TZID %q", tzid)
+		}
+	}
+
+	t, err := recurrence.ParseDateTime(raw, loc)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid date-time %q", raw)
+	}
+
+	return t, false, nil
+}
+
+func parseTimeList(prop property) ([]time.Time, error) {
+	if prop.params["VALUE"] == "PERIOD" {
+		return nil, errors.New("PERIOD values are not supported")
+	}
+
+	var dates []time.Time
+
+	for _, raw := range strings.Split(prop.value, ",") {
+		date, _, err := parseTime(prop, raw)
+		if err != nil {
+			return nil, err
+		}
+
+		dates = append(dates, date)
+	}
+
+	return dates, nil
+}
+
+// parseDuration reads an RFC 5545 DURATION such as PT1H30M, P1D or P2W.
+func parseDuration(raw string) (time.Duration, error) {
+	value := raw
+	sign := time.Duration(1)
+
+	switch {
+	case strings.HasPrefix(value, "-"):
+		sign = -1
+		value = value[1:]
+	case strings.HasPrefix(value, "+"):
+		value = value[1:]
+	}
+
+	if !strings.HasPrefix(value, "P") || len(value) < 3 {
+		return 0, fmt.Errorf("invalid duration %q", raw)
+	}
+
+	units := map[byte]time.Duration{'W': 7 * 24 * time.Hour, 'D': 24 * time.Hour}
+	timeUnits := map[byte]time.Duration{'H': time.Hour, 'M': time.Minute, 'S': time.Second}
+
+	var total time.Duration
+
+	number := ""
+
+	for i := 1; i < len(value); i++ {
+		c := value[i]
+
+		switch {
+		case c >= '0' && c <= '9':
+			number += string(c)
+		case c == 'T':
+			units = timeUnits
+		default:
+			unit, ok := units[c]
+			if !ok || number == "" {
+				return 0, fmt.Errorf("invalid duration %q", raw)
+			}
+
+			n, err := strconv.Atoi(number)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", raw)
+			}
+
+			total += time.Duration(n) * unit
+			number = ""
+		}
+	}
+
+	if number != "" {
+		return 0, fmt.Errorf("invalid duration %q", raw)
+	}
+
+	return sign * total, nil
+}
+
+var textUnescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+
+func unescapeText(value string) string {
+	return textUnescaper.Replace(value)
+}
@@ -0,0 +1,212 @@
+package ical_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal/ical"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type DecodeTestSuite struct {
+	suite.Suite
+}
+
+func calendar(lines ...string) *strings.Reader {
+	all := append([]string{"BEGIN:VCALENDAR", "VERSION:2.0", "PRODID:-//test//EN"}, lines...)
+	all = append(all, "END:VCALENDAR", "")
+
+	return strings.NewReader(strings.Join(all, "\r\n"))
+}
+
+func (s *DecodeTestSuite) TestDecode_Event() {
+	decoded, err := ical.Decode(calendar(
+		"BEGIN:VEVENT",
+		"UID:abc@example.com",
+		"DTSTAMP:20251127T103000Z",
+		"DTSTART;TZID=Europe/Madrid:20251201T090000",
+		"DTEND;TZID=\"Europe/Madrid\":20251201T100000",
+		"SUMMARY:Planning\\, Q1",
+		"DESCRIPTION:first line\\nsecond ",
+		" line",
+		"RRULE:FREQ=WEEKLY;COUNT=4",
+		"EXDATE;TZID=Europe/Madrid:20251208T090000,20251215T090000",
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"DESCRIPTION:reminder",
+		"TRIGGER:-PT15M",
+		"END:VALARM",
+		"END:VEVENT",
+	))
+
+	require.NoError(s.T(), err)
+	require.Len(s.T(), decoded, 1)
+	require.NoError(s.T(), decoded[0].Err)
+
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	require.NoError(s.T(), err)
+
+	event := decoded[0].Event
+	require.Equal(s.T(), "abc@example.com", event.UID)
+	require.Equal(s.T(), "Planning, Q1", event.Summary)
+	require.Equal(s.T(), "first line\nsecond line", event.Description)
+	require.True(s.T(), event.Start.Equal(time.Date(2025, 12, 1, 9, 0, 0, 0, madrid)))
+	require.Equal(s.T(), madrid, event.Start.Location())
+	require.True(s.T(), event.End.Equal(time.Date(2025, 12, 1, 10, 0, 0, 0, madrid)))
+	require.True(s.T(), event.Stamp.Equal(time.Date(2025, 11, 27, 10, 30, 0, 0, time.UTC)))
+	require.Equal(s.T(), "FREQ=WEEKLY;COUNT=4", event.RRule)
+	require.Len(s.T(), event.ExDates, 2)
+	require.True(s.T(), event.ExDates[1].Equal(time.Date(2025, 12, 15, 9, 0, 0, 0, madrid)))
+}
+
+func (s *DecodeTestSuite) TestDecode_AllDay() {
+	decoded, err := ical.Decode(calendar(
+		"BEGIN:VEVENT",
+		"UID:holiday",
+		"DTSTART;VALUE=DATE:20251225",
+		"SUMMARY:Christmas",
+		"END:VEVENT",
+	))
+
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), decoded[0].Err)
+	require.True(s.T(), decoded[0].Event.AllDay)
+	require.Equal(s.T(), time.Date(2025, 12, 25, 0, 0, 0, 0, time.UTC), decoded[0].Event.Start)
+	require.Equal(s.T(), time.Date(2025, 12, 26, 0, 0, 0, 0, time.UTC), decoded[0].Event.End)
+}
+
+func (s *DecodeTestSuite) TestDecode_Duration() {
+	decoded, err := ical.Decode(calendar(
+		"BEGIN:VEVENT",
+		"UID:call",
+		"DTSTART:20251201T090000Z",
+		"DURATION:PT1H30M",
+		"END:VEVENT",
+	))
+
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), decoded[0].Err)
+	require.Equal(s.T(), time.Date(2025, 12, 1, 10, 30, 0, 0, time.UTC), decoded[0].Event.End)
+}
+
+func (s *DecodeTestSuite) TestDecode_RejectsBrokenEvents() {
+	decoded, err := ical.Decode(calendar(
+		"BEGIN:VEVENT",
+		"UID:no-start",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:bad-zone",
+		"DTSTART;TZID=Mars/Olympus:20251201T090000",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART:20251201T090000Z",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:bad-recurrence-id",
+		"RECURRENCE-ID:yesterday",
+		"DTSTART:20251201T100000Z",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:good",
+		"DTSTART:20251201T090000Z",
+		"END:VEVENT",
+	))
+
+	require.NoError(s.T(), err)
+	require.Len(s.T(), decoded, 5)
+	require.EqualError(s.T(), decoded[0].Err, "missing DTSTART")
+	require.EqualError(s.T(), decoded[1].Err, `DTSTART: unknown TZID "Mars/Olympus"`)
+	require.EqualError(s.T(), decoded[2].Err, "missing UID")
+	require.Equal(s.T(), "bad-recurrence-id", decoded[3].Event.UID)
+	require.Error(s.T(), decoded[3].Err)
+	require.NoError(s.T(), decoded[4].Err)
+}
+
+func (s *DecodeTestSuite) TestDecode_InvalidCalendar() {
+	inputs := map[string]string{
+		"not a calendar":   "hello",
+		"missing end":      "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nEND:VEVENT\r\n",
+		"mismatched end":   "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nEND:VCALENDAR\r\n",
+		"other component":  "BEGIN:VCARD\r\nEND:VCARD\r\n",
+		"unterminated tag": "BEGIN:VCALENDAR\r\nDTSTART;TZID=\"Europe/Madrid:20251201T090000\r\nEND:VCALENDAR\r\n",
+	}
+
+	for name, input := range inputs {
+		_, err := ical.Decode(strings.NewReader(input))
+
+		require.ErrorIs(s.T(), err, ical.ErrInvalidCalendar, name)
+	}
+}
+
+func (s *DecodeTestSuite) TestDecode_RoundTrip() {
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	event := ical.Event{
+		UID:         "e4f5c6d7-8e9f-4a5b-9c8d-7e6f5a4b3c2d",
+		Stamp:       start,
+		Summary:     strings.Repeat("long; summary, ", 10),
+		Description: "multi\nline",
+		Start:       start,
+		End:         start.Add(time.Hour),
+		RRule:       "FREQ=DAILY;COUNT=5",
+		ExDates:     []time.Time{start.AddDate(0, 0, 1)},
+		RDates:      []time.Time{start.AddDate(0, 0, 10)},
+	}
+
+	var out bytes.Buffer
+	require.NoError(s.T(), ical.Encode(&out, []ical.Event{event}))
+
+	decoded, err := ical.Decode(&out)
+
+	require.NoError(s.T(), err)
+	require.Len(s.T(), decoded, 1)
+	require.NoError(s.T(), decoded[0].Err)
+	require.Equal(s.T(), event, decoded[0].Event)
+}
+
+func (s *DecodeTestSuite) TestDecode_RoundTripAllDay() {
+	start := time.Date(2025, 12, 24, 0, 0, 0, 0, time.UTC)
+	event := ical.Event{
+		UID:     "holidays",
+		Start:   start,
+		End:     start.AddDate(0, 0, 2),
+		AllDay:  true,
+		RRule:   "FREQ=YEARLY",
+		ExDates: []time.Time{start.AddDate(1, 0, 0)},
+	}
+
+	var out bytes.Buffer
+	require.NoError(s.T(), ical.Encode(&out, []ical.Event{event}))
+
+	decoded, err := ical.Decode(&out)
+
+	require.NoError(s.T(), err)
+	require.Len(s.T(), decoded, 1)
+	require.NoError(s.T(), decoded[0].Err)
+	require.Equal(s.T(), event, decoded[0].Event)
+}
+
+func (s *DecodeTestSuite) TestDecode_RoundTripTimeZone() {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	require.NoError(s.T(), err)
+
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, madrid)
+	event := ical.Event{UID: "series", Start: start, End: start.Add(time.Hour), RRule: "FREQ=DAILY"}
+
+	var out bytes.Buffer
+	require.NoError(s.T(), ical.Encode(&out, []ical.Event{event}))
+
+	decoded, err := ical.Decode(&out)
+
+	require.NoError(s.T(), err)
+	require.Len(s.T(), decoded, 1)
+	require.NoError(s.T(), decoded[0].Err)
+	require.Equal(s.T(), "Europe/Madrid", decoded[0].Event.Start.Location().String())
+	require.True(s.T(), start.Equal(decoded[0].Event.Start))
+}
+
+func TestDecodeTestSuite(t *testing.T) {
+	suite.Run(t, new(DecodeTestSuite))
+}
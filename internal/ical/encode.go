@@ -0,0 +1,209 @@
+// Package ical reads and writes the RFC 5545 iCalendar format for events.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal/recurrence"
+)
+
+const (
+	prodID = "-//LTK-test-manu//events//EN"
+
+	// maxLineLength is the limit of a content line in octets, without the CRLF.
+	maxLineLength = 75
+
+	dateLayout = "20060102"
+)
+
+// Event is a VEVENT. RecurrenceID is set when the event is a single occurrence
+// of the series identified by UID. AllDay means Start and End were DATE values.
+type Event struct {
+	UID          string
+	Stamp        time.Time
+	Summary      string
+	Description  string
+	Start        time.Time
+	End          time.Time
+	AllDay       bool
+	RRule        string
+	ExDates      []time.Time
+	RDates       []time.Time
+	RecurrenceID *time.Time
+}
+
+// Encode writes the events as one VCALENDAR.
+func Encode(w io.Writer, events []Event) error {
+	buffered := bufio.NewWriter(w)
+	enc := encoder{w: buffered}
+
+	enc.line("BEGIN", "VCALENDAR")
+	enc.line("VERSION", "2.0")
+	enc.line("PRODID", prodID)
+	enc.line("CALSCALE", "GREGORIAN")
+
+	// One VTIMEZONE per zone, with the rules of the earliest year it is used in.
+	years := map[string]int{}
+
+	for _, event := range events {
+		if name := tzid(event.Start); name != "" && !event.AllDay {
+			if year, ok := years[name]; !ok || event.Start.Year() < year {
+				years[name] = event.Start.Year()
+			}
+		}
+	}
+
+	names := make([]string, 0, len(years))
+	for name := range years {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		enc.timeZone(name, years[name])
+	}
+
+	for _, event := range events {
+		enc.event(event)
+	}
+
+	enc.line("END", "VCALENDAR")
+
+	if enc.err != nil {
+		return enc.err
+	}
+
+	return buffered.Flush()
+}
+
+type encoder struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (e *encoder) event(event Event) {
+	e.line("BEGIN", "VEVENT")
+	e.line("UID", escapeText(event.UID))
+	e.line("DTSTAMP", recurrence.FormatDateTime(event.Stamp))
+
+	// Times of an event in a named zone are written as local times with a TZID,
+	// so clients expand its RRULE across DST changes the way the API does.
+	zone := tzid(event.Start)
+	times := func(name string, times ...time.Time) { e.dateTimes(name, zone, times...) }
+
+	if event.AllDay {
+		times = e.dates
+	}
+
+	if event.RecurrenceID != nil {
+		times("RECURRENCE-ID", *event.RecurrenceID)
+	}
+
+	times("DTSTART", event.Start)
+	times("DTEND", event.End)
+	e.line("SUMMARY", escapeText(event.Summary))
+
+	if event.Description != "" {
+		e.line("DESCRIPTION", escapeText(event.Description))
+	}
+
+	if event.RRule != "" {
+		rrule := event.RRule
+		if event.AllDay {
+			rrule = dateUntil(rrule)
+		}
+
+		e.line("RRULE", rrule)
+	}
+
+	if len(event.ExDates) > 0 {
+		times("EXDATE", event.ExDates...)
+	}
+
+	if len(event.RDates) > 0 {
+		times("RDATE", event.RDates...)
+	}
+
+	e.line("END", "VEVENT")
+}
+
+// dateTimes writes a DATE-TIME property, in UTC or as local times of zone.
+func (e *encoder) dateTimes(name, zone string, times ...time.Time) {
+	if zone == "" {
+		e.line(name, recurrence.FormatDateList(times))
+		return
+	}
+
+	loc, _ := time.LoadLocation(zone)
+
+	values := make([]string, 0, len(times))
+	for _, t := range times {
+		values = append(values, t.In(loc).Format(localDateTimeLayout))
+	}
+
+	e.line(name+";TZID="+zone, strings.Join(values, ","))
+}
+
+// dates writes a DATE property, for all-day events.
+func (e *encoder) dates(name string, dates ...time.Time) {
+	values := make([]string, 0, len(dates))
+	for _, date := range dates {
+		values = append(values, date.Format(dateLayout))
+	}
+
+	e.line(name+";VALUE=DATE", strings.Join(values, ","))
+}
+
+// dateUntil rewrites the UNTIL of a rule as a DATE, which RFC 5545 requires
+// when DTSTART is a DATE.
+func dateUntil(rrule string) string {
+	parts := strings.Split(rrule, ";")
+
+	for i, part := range parts {
+		if until, ok := strings.CutPrefix(part, "UNTIL="); ok && len(until) > len(dateLayout) {
+			parts[i] = "UNTIL=" + until[:len(dateLayout)]
+		}
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// line writes a content line, folded at 75 octets without splitting a UTF-8 sequence.
+func (e *encoder) line(name, value string) {
+	if e.err != nil {
+		return
+	}
+
+	content := name + ":" + value
+	limit := maxLineLength
+
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+
+		if _, e.err = e.w.WriteString(content[:cut] + "\r\n "); e.err != nil {
+			return
+		}
+
+		content = content[cut:]
+		// Continuation lines start with a space, which counts towards the limit.
+		limit = maxLineLength - 1
+	}
+
+	_, e.err = e.w.WriteString(content + "\r\n")
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// escapeText escapes a TEXT value (RFC 5545 section 3.3.11).
+func escapeText(value string) string {
+	return textEscaper.Replace(value)
+}
@@ -0,0 +1,197 @@
+package ical_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal/ical"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type EncodeTestSuite struct {
+	suite.Suite
+}
+
+func (s *EncodeTestSuite) TestEncode_Event() {
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+
+	var out bytes.Buffer
+
+	err := ical.Encode(&out, []ical.Event{{
+		UID:         "e4f5c6d7-8e9f-4a5b-9c8d-7e6f5a4b3c2d",
+		Stamp:       time.Date(2025, 11, 27, 10, 30, 0, 0, time.UTC),
+		Summary:     "Standup",
+		Description: "Daily sync",
+		Start:       start,
+		End:         start.Add(15 * time.Minute),
+		RRule:       "FREQ=WEEKLY;BYDAY=MO,WE,FR",
+		ExDates:     []time.Time{start.AddDate(0, 0, 2)},
+	}})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//LTK-test-manu//events//EN",
+		"CALSCALE:GREGORIAN",
+		"BEGIN:VEVENT",
+		"UID:e4f5c6d7-8e9f-4a5b-9c8d-7e6f5a4b3c2d",
+		"DTSTAMP:20251127T103000Z",
+		"DTSTART:20251201T090000Z",
+		"DTEND:20251201T091500Z",
+		"SUMMARY:Standup",
+		"DESCRIPTION:Daily sync",
+		"RRULE:FREQ=WEEKLY;BYDAY=MO,WE,FR",
+		"EXDATE:20251203T090000Z",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n"), out.String())
+}
+
+func (s *EncodeTestSuite) TestEncode_Occurrence() {
+	start := time.Date(2025, 12, 3, 9, 0, 0, 0, time.UTC)
+
+	var out bytes.Buffer
+
+	err := ical.Encode(&out, []ical.Event{{UID: "series", Start: start, End: start.Add(time.Hour), RecurrenceID: &start}})
+
+	require.NoError(s.T(), err)
+	require.Contains(s.T(), out.String(), "UID:series\r\nDTSTAMP:00010101T000000Z\r\nRECURRENCE-ID:20251203T090000Z\r\n")
+}
+
+func (s *EncodeTestSuite) TestEncode_RoundTripsExceptions() {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	require.NoError(s.T(), err)
+
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, madrid)
+	moved := start.AddDate(0, 0, 2)
+	events := []ical.Event{
+		{UID: "series", Summary: "Standup", Start: start, End: start.Add(time.Hour), RRule: "FREQ=DAILY;COUNT=5",
+			ExDates: []time.Time{start.AddDate(0, 0, 1)}},
+		{UID: "series", Summary: "Late standup", Start: moved.Add(2 * time.Hour), End: moved.Add(3 * time.Hour), RecurrenceID: &moved},
+	}
+
+	var out bytes.Buffer
+
+	require.NoError(s.T(), ical.Encode(&out, events))
+
+	decoded, err := ical.Decode(&out)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), decoded, 2)
+
+	series, override := decoded[0].Event, decoded[1].Event
+	require.NoError(s.T(), decoded[0].Err)
+	require.NoError(s.T(), decoded[1].Err)
+	require.Len(s.T(), series.ExDates, 1)
+	require.True(s.T(), series.ExDates[0].Equal(start.AddDate(0, 0, 1)))
+	require.Nil(s.T(), series.RecurrenceID)
+	require.Equal(s.T(), "series", override.UID)
+	require.Equal(s.T(), "Late standup", override.Summary)
+	require.NotNil(s.T(), override.RecurrenceID)
+	require.True(s.T(), override.RecurrenceID.Equal(moved))
+	require.True(s.T(), override.Start.Equal(moved.Add(2*time.Hour)))
+	require.Empty(s.T(), override.RRule)
+}
+
+func (s *EncodeTestSuite) TestEncode_EscapesText() {
+	var out bytes.Buffer
+
+	err := ical.Encode(&out, []ical.Event{{UID: "1", Summary: "a,b;c\\d", Description: "line one\nline two"}})
+
+	require.NoError(s.T(), err)
+	require.Contains(s.T(), out.String(), "SUMMARY:a\\,b\\;c\\\\d\r\n")
+	require.Contains(s.T(), out.String(), "DESCRIPTION:line one\\nline two\r\n")
+}
+
+func (s *EncodeTestSuite) TestEncode_FoldsLongLines() {
+	summary := strings.Repeat("pepito ", 20) + strings.Repeat("ñ", 60)
+
+	var out bytes.Buffer
+
+	err := ical.Encode(&out, []ical.Event{{UID: "1", Summary: summary}})
+	require.NoError(s.T(), err)
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\r\n"), "\r\n")
+
+	for _, line := range lines {
+		require.LessOrEqual(s.T(), len(line), 75)
+		require.True(s.T(), utf8.ValidString(line), line)
+	}
+
+	unfolded := strings.ReplaceAll(out.String(), "\r\n ", "")
+	require.Contains(s.T(), unfolded, "SUMMARY:"+summary+"\r\n")
+}
+
+func (s *EncodeTestSuite) TestEncode_TimeZone() {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	require.NoError(s.T(), err)
+
+	start := time.Date(2025, 3, 24, 9, 0, 0, 0, madrid)
+
+	var out bytes.Buffer
+
+	err = ical.Encode(&out, []ical.Event{{
+		UID:     "series",
+		Start:   start,
+		End:     start.Add(time.Hour),
+		RRule:   "FREQ=WEEKLY",
+		ExDates: []time.Time{start.AddDate(0, 0, 7).UTC()},
+	}})
+
+	require.NoError(s.T(), err)
+	require.Contains(s.T(), out.String(), strings.Join([]string{
+		"BEGIN:VTIMEZONE",
+		"TZID:Europe/Madrid",
+		"BEGIN:DAYLIGHT",
+		"DTSTART:20250330T020000",
+		"RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=-1SU",
+		"TZOFFSETFROM:+0100",
+		"TZOFFSETTO:+0200",
+		"TZNAME:CEST",
+		"END:DAYLIGHT",
+		"BEGIN:STANDARD",
+		"DTSTART:20251026T030000",
+		"RRULE:FREQ=YEARLY;BYMONTH=10;BYDAY=-1SU",
+		"TZOFFSETFROM:+0200",
+		"TZOFFSETTO:+0100",
+		"TZNAME:CET",
+		"END:STANDARD",
+		"END:VTIMEZONE",
+	}, "\r\n"))
+	require.Contains(s.T(), out.String(), "DTSTART;TZID=Europe/Madrid:20250324T090000\r\n")
+	require.Contains(s.T(), out.String(), "EXDATE;TZID=Europe/Madrid:20250331T090000\r\n")
+}
+
+func (s *EncodeTestSuite) TestEncode_AllDay() {
+	start := time.Date(2025, 12, 24, 0, 0, 0, 0, time.UTC)
+
+	var out bytes.Buffer
+
+	err := ical.Encode(&out, []ical.Event{{
+		UID:     "holidays",
+		Start:   start,
+		End:     start.AddDate(0, 0, 2),
+		AllDay:  true,
+		RRule:   "FREQ=YEARLY;UNTIL=20301224T235959Z",
+		ExDates: []time.Time{start.AddDate(1, 0, 0)},
+	}})
+
+	require.NoError(s.T(), err)
+	require.NotContains(s.T(), out.String(), "VTIMEZONE")
+	require.Contains(s.T(), out.String(), strings.Join([]string{
+		"DTSTART;VALUE=DATE:20251224",
+		"DTEND;VALUE=DATE:20251226",
+		"SUMMARY:",
+		"RRULE:FREQ=YEARLY;UNTIL=20301224",
+		"EXDATE;VALUE=DATE:20261224",
+	}, "\r\n"))
+}
+
+func TestEncodeTestSuite(t *testing.T) {
+	suite.Run(t, new(EncodeTestSuite))
+}
@@ -0,0 +1,118 @@
+package ical
+
+import (
+	"fmt"
+	"time"
+)
+
+const localDateTimeLayout = "20060102T150405"
+
+// tzid is the TZID to write t with, or "" when t is written in UTC. Only IANA
+// zones are used, since clients resolve them without the VTIMEZONE.
+func tzid(t time.Time) string {
+	name := t.Location().String()
+
+	switch name {
+	case "", "UTC", "Local":
+		return ""
+	}
+
+	if _, err := time.LoadLocation(name); err != nil {
+		return ""
+	}
+
+	return name
+}
+
+// timeZone writes a VTIMEZONE with the offsets loc used during year. DST
+// transitions are written as yearly rules, e.g. the last Sunday of March.
+func (e *encoder) timeZone(name string, year int) {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return
+	}
+
+	e.line("BEGIN", "VTIMEZONE")
+	e.line("TZID", name)
+
+	transitions := zoneTransitions(loc, year)
+
+	if len(transitions) == 0 {
+		abbreviation, offset := time.Date(year, time.January, 1, 0, 0, 0, 0, loc).Zone()
+
+		e.line("BEGIN", "STANDARD")
+		e.line("DTSTART", "19700101T000000")
+		e.line("TZOFFSETFROM", formatOffset(offset))
+		e.line("TZOFFSETTO", formatOffset(offset))
+		e.line("TZNAME", escapeText(abbreviation))
+		e.line("END", "STANDARD")
+	}
+
+	for _, at := range transitions {
+		_, from := at.Add(-time.Second).Zone()
+		abbreviation, to := at.Zone()
+
+		kind := "STANDARD"
+		if at.IsDST() {
+			kind = "DAYLIGHT"
+		}
+
+		// The observance starts at the wall clock time of the old offset.
+		start := at.In(time.FixedZone("", from))
+
+		e.line("BEGIN", kind)
+		e.line("DTSTART", start.Format(localDateTimeLayout))
+		e.line("RRULE", yearlyRule(start))
+		e.line("TZOFFSETFROM", formatOffset(from))
+		e.line("TZOFFSETTO", formatOffset(to))
+		e.line("TZNAME", escapeText(abbreviation))
+		e.line("END", kind)
+	}
+
+	e.line("END", "VTIMEZONE")
+}
+
+func zoneTransitions(loc *time.Location, year int) []time.Time {
+	var transitions []time.Time
+
+	t := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	end := time.Date(year+1, time.January, 1, 0, 0, 0, 0, loc)
+
+	for {
+		_, next := t.ZoneBounds()
+		if next.IsZero() || !next.Before(end) {
+			return transitions
+		}
+
+		transitions = append(transitions, next)
+		t = next
+	}
+}
+
+// yearlyRule describes the date of start as the nth, or last, weekday of its month.
+func yearlyRule(start time.Time) string {
+	weekday := [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}[start.Weekday()]
+	last := time.Date(start.Year(), start.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+
+	n := (start.Day()-1)/7 + 1
+	if start.Day()+7 > last {
+		n = -1
+	}
+
+	return fmt.Sprintf("FREQ=YEARLY;BYMONTH=%d;BYDAY=%d%s", int(start.Month()), n, weekday)
+}
+
+func formatOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+
+	offset := fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds%3600/60)
+	if seconds%60 != 0 {
+		offset += fmt.Sprintf("%02d", seconds%60)
+	}
+
+	return offset
+}
@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+	"unicode/utf8"
+)
+
+// DefaultIdempotencyTTL is how long a response is replayed when no TTL is configured.
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+const maxIdempotencyKey = 255
+
+type idempotencyStorage interface {
+	// ReserveIdempotencyKey stores record unless an unexpired record has its key.
+	// It returns that record and false, or record and true when it was stored.
+	ReserveIdempotencyKey(ctx context.Context, record IdempotencyRecord) (IdempotencyRecord, bool, error)
+	SaveIdempotentResponse(ctx context.Context, principal, key string, status int, body []byte) error
+	ReleaseIdempotencyKey(ctx context.Context, principal, key string) error
+}
+
+type IdempotencyService struct {
+	storage idempotencyStorage
+	ttl     time.Duration
+}
+
+// NewIdempotencyService keeps responses for ttl, DefaultIdempotencyTTL when
+// ttl is not positive.
+func NewIdempotencyService(storage idempotencyStorage, ttl time.Duration) *IdempotencyService {
+	if ttl <= 0 {
+		ttl = DefaultIdempotencyTTL
+	}
+
+	return &IdempotencyService{
+		storage: storage,
+		ttl:     ttl,
+	}
+}
+
+// Begin claims key for the actor of ctx and a request whose fingerprint is
+// given. When the key already answered that request it returns the response to
+// replay and true. A key used with another request is ErrKeyReused, and one
+// whose request is still running is ErrConflict.
+func (s *IdempotencyService) Begin(ctx context.Context, key string, fingerprint []byte) (IdempotencyRecord, bool, error) {
+	if key == "" {
+		return IdempotencyRecord{}, false, fmt.Errorf("empty idempotency key: %w", ErrInput)
+	}
+
+	if utf8.RuneCountInString(key) > maxIdempotencyKey {
+		return IdempotencyRecord{}, false, fmt.Errorf("idempotency key should have at most %d characters: %w", maxIdempotencyKey, ErrInput)
+	}
+
+	now := time.Now().UTC()
+
+	record, reserved, err := s.storage.ReserveIdempotencyKey(ctx, IdempotencyRecord{
+		Principal:   ActorFromContext(ctx),
+		Key:         key,
+		Fingerprint: fingerprint,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.ttl),
+	})
+	if err != nil {
+		return IdempotencyRecord{}, false, fmt.Errorf("reserving idempotency key: %w", err)
+	}
+
+	if reserved {
+		return IdempotencyRecord{}, false, nil
+	}
+
+	if !bytes.Equal(record.Fingerprint, fingerprint) {
+		return IdempotencyRecord{}, false, fmt.Errorf("idempotency key %q was used with another request: %w", key, ErrKeyReused)
+	}
+
+	if !record.completed() {
+		return IdempotencyRecord{}, false, fmt.Errorf("a request with idempotency key %q is in progress: %w", key, ErrConflict)
+	}
+
+	return record, true, nil
+}
+
+// Complete stores the response to replay for key.
+func (s *IdempotencyService) Complete(ctx context.Context, key string, status int, body []byte) error {
+	if err := s.storage.SaveIdempotentResponse(ctx, ActorFromContext(ctx), key, status, body); err != nil {
+		return fmt.Errorf("saving idempotent response: %w", err)
+	}
+
+	return nil
+}
+
+// Release frees key after a request that failed, so that a retry runs again.
+func (s *IdempotencyService) Release(ctx context.Context, key string) error {
+	if err := s.storage.ReleaseIdempotencyKey(ctx, ActorFromContext(ctx), key); err != nil {
+		return fmt.Errorf("releasing idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+// completed tells whether the request of r has a response yet.
+func (r IdempotencyRecord) completed() bool {
+	return r.Status != 0
+}
@@ -0,0 +1,124 @@
+package internal_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal"
+	"github.com/ObiaNzk/LTK-test-manu/internal/mocks"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+//go:generate mockgen -source=idempotency.go -destination=mocks/mock_idempotency_storage.go -package=mocks
+
+type IdempotencyServiceTestSuite struct {
+	suite.Suite
+	ctx         context.Context
+	ctrl        *gomock.Controller
+	mockStorage *mocks.MockidempotencyStorage
+	service     *internal.IdempotencyService
+}
+
+func (s *IdempotencyServiceTestSuite) SetupTest() {
+	s.ctx = internal.WithActor(internal.WithTenant(context.Background(), "acme"), "auth0|ada")
+	s.ctrl = gomock.NewController(s.T())
+	s.mockStorage = mocks.NewMockidempotencyStorage(s.ctrl)
+	s.service = internal.NewIdempotencyService(s.mockStorage, time.Hour)
+}
+
+func (s *IdempotencyServiceTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func (s *IdempotencyServiceTestSuite) TestBegin_Reserves() {
+	var reserved internal.IdempotencyRecord
+
+	s.mockStorage.EXPECT().
+		ReserveIdempotencyKey(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, record internal.IdempotencyRecord) (internal.IdempotencyRecord, bool, error) {
+			reserved = record
+			return record, true, nil
+		})
+
+	_, replay, err := s.service.Begin(s.ctx, "retry-1", []byte("fingerprint"))
+
+	require.NoError(s.T(), err)
+	require.False(s.T(), replay)
+	require.Equal(s.T(), "auth0|ada", reserved.Principal)
+	require.Equal(s.T(), "retry-1", reserved.Key)
+	require.Equal(s.T(), []byte("fingerprint"), reserved.Fingerprint)
+	require.Equal(s.T(), time.Hour, reserved.ExpiresAt.Sub(reserved.CreatedAt))
+}
+
+func (s *IdempotencyServiceTestSuite) TestBegin_DefaultTTL() {
+	service := internal.NewIdempotencyService(s.mockStorage, 0)
+
+	s.mockStorage.EXPECT().
+		ReserveIdempotencyKey(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, record internal.IdempotencyRecord) (internal.IdempotencyRecord, bool, error) {
+			require.Equal(s.T(), internal.DefaultIdempotencyTTL, record.ExpiresAt.Sub(record.CreatedAt))
+			return record, true, nil
+		})
+
+	_, _, err := service.Begin(s.ctx, "retry-1", []byte("fingerprint"))
+
+	require.NoError(s.T(), err)
+}
+
+func (s *IdempotencyServiceTestSuite) TestBegin_Replays() {
+	stored := internal.IdempotencyRecord{Key: "retry-1", Fingerprint: []byte("fingerprint"), Status: 201, Body: []byte(`{"id":"e1"}`)}
+
+	s.mockStorage.EXPECT().
+		ReserveIdempotencyKey(gomock.Any(), gomock.Any()).
+		Return(stored, false, nil)
+
+	record, replay, err := s.service.Begin(s.ctx, "retry-1", []byte("fingerprint"))
+
+	require.NoError(s.T(), err)
+	require.True(s.T(), replay)
+	require.Equal(s.T(), stored, record)
+}
+
+func (s *IdempotencyServiceTestSuite) TestBegin_OtherRequest() {
+	s.mockStorage.EXPECT().
+		ReserveIdempotencyKey(gomock.Any(), gomock.Any()).
+		Return(internal.IdempotencyRecord{Fingerprint: []byte("other"), Status: 201}, false, nil)
+
+	_, _, err := s.service.Begin(s.ctx, "retry-1", []byte("fingerprint"))
+
+	require.ErrorIs(s.T(), err, internal.ErrKeyReused)
+}
+
+func (s *IdempotencyServiceTestSuite) TestBegin_InProgress() {
+	s.mockStorage.EXPECT().
+		ReserveIdempotencyKey(gomock.Any(), gomock.Any()).
+		Return(internal.IdempotencyRecord{Fingerprint: []byte("fingerprint")}, false, nil)
+
+	_, _, err := s.service.Begin(s.ctx, "retry-1", []byte("fingerprint"))
+
+	require.ErrorIs(s.T(), err, internal.ErrConflict)
+}
+
+func (s *IdempotencyServiceTestSuite) TestBegin_InvalidKey() {
+	for _, key := range []string{"", strings.Repeat("k", 256)} {
+		_, _, err := s.service.Begin(s.ctx, key, []byte("fingerprint"))
+
+		require.ErrorIs(s.T(), err, internal.ErrInput)
+	}
+}
+
+func (s *IdempotencyServiceTestSuite) TestCompleteAndRelease() {
+	s.mockStorage.EXPECT().SaveIdempotentResponse(gomock.Any(), "auth0|ada", "retry-1", 201, []byte(`{}`)).Return(nil)
+	s.mockStorage.EXPECT().ReleaseIdempotencyKey(gomock.Any(), "auth0|ada", "retry-2").Return(nil)
+
+	require.NoError(s.T(), s.service.Complete(s.ctx, "retry-1", 201, []byte(`{}`)))
+	require.NoError(s.T(), s.service.Release(s.ctx, "retry-2"))
+}
+
+func TestIdempotencyServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(IdempotencyServiceTestSuite))
+}
@@ -0,0 +1,157 @@
+// Package interval implements set algebra over half-open time intervals.
+package interval
+
+import (
+	"sort"
+	"time"
+)
+
+// Interval is the half-open range [Start, End).
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+func (i Interval) Empty() bool {
+	return !i.Start.Before(i.End)
+}
+
+func (i Interval) Duration() time.Duration {
+	if i.Empty() {
+		return 0
+	}
+
+	return i.End.Sub(i.Start)
+}
+
+// Set is a normalized union of intervals: sorted, without empty intervals, and
+// without two intervals that overlap or touch. Build it with New.
+type Set []Interval
+
+// New normalizes intervals into a Set.
+func New(intervals ...Interval) Set {
+	sorted := make([]Interval, 0, len(intervals))
+	for _, i := range intervals {
+		if !i.Empty() {
+			sorted = append(sorted, i)
+		}
+	}
+
+	sort.Slice(sorted, func(a, b int) bool { return sorted[a].Start.Before(sorted[b].Start) })
+
+	var set Set
+
+	for _, i := range sorted {
+		last := len(set) - 1
+
+		if last >= 0 && !i.Start.After(set[last].End) {
+			if i.End.After(set[last].End) {
+				set[last].End = i.End
+			}
+
+			continue
+		}
+
+		set = append(set, i)
+	}
+
+	return set
+}
+
+// Union is every instant in s or other.
+func (s Set) Union(other Set) Set {
+	return New(append(append([]Interval{}, s...), other...)...)
+}
+
+// Intersect is every instant in both s and other.
+func (s Set) Intersect(other Set) Set {
+	var result Set
+
+	for a, b := 0, 0; a < len(s) && b < len(other); {
+		start := latest(s[a].Start, other[b].Start)
+		end := earliest(s[a].End, other[b].End)
+
+		if start.Before(end) {
+			result = append(result, Interval{Start: start, End: end})
+		}
+
+		// Drop whichever interval ends first, it cannot meet anything else.
+		if s[a].End.Before(other[b].End) {
+			a++
+		} else {
+			b++
+		}
+	}
+
+	return result
+}
+
+// Subtract is every instant in s and not in other.
+func (s Set) Subtract(other Set) Set {
+	var result Set
+
+	b := 0
+
+	for _, i := range s {
+		start := i.Start
+
+		for b < len(other) && !other[b].End.After(start) {
+			b++
+		}
+
+		for j := b; j < len(other) && other[j].Start.Before(i.End); j++ {
+			if other[j].Start.After(start) {
+				result = append(result, Interval{Start: start, End: other[j].Start})
+			}
+
+			start = latest(start, other[j].End)
+		}
+
+		if start.Before(i.End) {
+			result = append(result, Interval{Start: start, End: i.End})
+		}
+	}
+
+	return result
+}
+
+// Contains reports whether every instant of i is in s.
+func (s Set) Contains(i Interval) bool {
+	if i.Empty() {
+		return true
+	}
+
+	for _, candidate := range s {
+		if !candidate.Start.After(i.Start) && !candidate.End.Before(i.End) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Duration is the total length of s.
+func (s Set) Duration() time.Duration {
+	var total time.Duration
+	for _, i := range s {
+		total += i.Duration()
+	}
+
+	return total
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+
+	return b
+}
+
+func earliest(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,158 @@
+package interval_test
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal/interval"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type IntervalTestSuite struct {
+	suite.Suite
+}
+
+var base = time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+
+// at is base plus the given number of hours.
+func at(hours float64) time.Time {
+	return base.Add(time.Duration(hours * float64(time.Hour)))
+}
+
+func span(from, to float64) interval.Interval {
+	return interval.Interval{Start: at(from), End: at(to)}
+}
+
+func (s *IntervalTestSuite) TestNew_MergesOverlappingAndTouching() {
+	set := interval.New(span(5, 6), span(1, 2), span(2, 3), span(1.5, 2.5), span(4, 4), span(7, 6))
+
+	require.Equal(s.T(), interval.Set{span(1, 3), span(5, 6)}, set)
+}
+
+func (s *IntervalTestSuite) TestNew_Empty() {
+	require.Empty(s.T(), interval.New())
+	require.Empty(s.T(), interval.New(span(3, 3)))
+}
+
+func (s *IntervalTestSuite) TestUnion() {
+	set := interval.New(span(1, 2), span(6, 8)).Union(interval.New(span(2, 3), span(7, 9)))
+
+	require.Equal(s.T(), interval.Set{span(1, 3), span(6, 9)}, set)
+}
+
+func (s *IntervalTestSuite) TestIntersect() {
+	set := interval.New(span(0, 4), span(6, 10)).Intersect(interval.New(span(3, 7), span(8, 9), span(10, 11)))
+
+	require.Equal(s.T(), interval.Set{span(3, 4), span(6, 7), span(8, 9)}, set)
+}
+
+func (s *IntervalTestSuite) TestSubtract() {
+	working := interval.New(span(9, 17))
+	busy := interval.New(span(8, 9.5), span(12, 13), span(16.5, 18))
+
+	require.Equal(s.T(), interval.Set{span(9.5, 12), span(13, 16.5)}, working.Subtract(busy))
+}
+
+func (s *IntervalTestSuite) TestSubtract_Everything() {
+	require.Empty(s.T(), interval.New(span(9, 17)).Subtract(interval.New(span(0, 24))))
+}
+
+func (s *IntervalTestSuite) TestContains() {
+	set := interval.New(span(9, 12), span(13, 17))
+
+	require.True(s.T(), set.Contains(span(9, 12)))
+	require.True(s.T(), set.Contains(span(14, 15)))
+	require.False(s.T(), set.Contains(span(11, 14)))
+	require.True(s.T(), set.Contains(span(20, 20)))
+}
+
+func (s *IntervalTestSuite) TestDuration() {
+	require.Equal(s.T(), 5*time.Hour, interval.New(span(1, 3), span(5, 8)).Duration())
+	require.Zero(s.T(), span(3, 1).Duration())
+}
+
+// gridSize is the number of minutes random sets live on, small enough to check
+// every minute against a plain boolean model.
+const gridSize = 240
+
+type randomSets struct {
+	A, B interval.Set
+}
+
+func randomSet(r *rand.Rand) interval.Set {
+	intervals := make([]interval.Interval, r.Intn(6))
+
+	for i := range intervals {
+		start := r.Intn(gridSize)
+		end := start + r.Intn(gridSize/4)
+		intervals[i] = interval.Interval{Start: base.Add(time.Duration(start) * time.Minute), End: base.Add(time.Duration(end) * time.Minute)}
+	}
+
+	return interval.New(intervals...)
+}
+
+func (randomSets) Generate(r *rand.Rand, _ int) reflect.Value {
+	return reflect.ValueOf(randomSets{A: randomSet(r), B: randomSet(r)})
+}
+
+// covers reports whether minute m of the grid is inside the set.
+func covers(set interval.Set, m int) bool {
+	t := base.Add(time.Duration(m) * time.Minute)
+
+	for _, i := range set {
+		if !t.Before(i.Start) && t.Before(i.End) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func normalized(set interval.Set) bool {
+	for i, current := range set {
+		if current.Empty() || (i > 0 && !set[i-1].End.Before(current.Start)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *IntervalTestSuite) check(operation func(a, b interval.Set) interval.Set, expected func(a, b bool) bool) {
+	property := func(input randomSets) bool {
+		result := operation(input.A, input.B)
+		if !normalized(result) {
+			return false
+		}
+
+		for m := 0; m < gridSize*2; m++ {
+			if covers(result, m) != expected(covers(input.A, m), covers(input.B, m)) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	s.Require().NoError(quick.Check(property, &quick.Config{MaxCount: 500}))
+}
+
+func (s *IntervalTestSuite) TestProperty_Union() {
+	s.check(interval.Set.Union, func(a, b bool) bool { return a || b })
+}
+
+func (s *IntervalTestSuite) TestProperty_Intersect() {
+	s.check(interval.Set.Intersect, func(a, b bool) bool { return a && b })
+}
+
+func (s *IntervalTestSuite) TestProperty_Subtract() {
+	s.check(interval.Set.Subtract, func(a, b bool) bool { return a && !b })
+}
+
+func TestIntervalTestSuite(t *testing.T) {
+	suite.Run(t, new(IntervalTestSuite))
+}
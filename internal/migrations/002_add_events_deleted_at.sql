@@ -0,0 +1 @@
+ALTER TABLE events ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
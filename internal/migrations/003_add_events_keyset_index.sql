@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_events_start_time_id ON events (start_time, id);
@@ -0,0 +1,3 @@
+-- start_time is covered by idx_events_start_time_id, and the window filters on
+-- series_end, not end_time.
+CREATE INDEX IF NOT EXISTS idx_events_created_at ON events (created_at);
@@ -0,0 +1,10 @@
+-- rrule, exdate and rdate keep their RFC 5545 value syntax. series_end is the end of
+-- the last occurrence and stays NULL for series that repeat forever.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS rrule TEXT;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS exdate TEXT;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS rdate TEXT;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS series_end TIMESTAMP;
+
+UPDATE events SET series_end = end_time WHERE rrule IS NULL AND series_end IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_events_series_end ON events (series_end);
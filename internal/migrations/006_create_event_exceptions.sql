@@ -0,0 +1,15 @@
+-- An exception changes one occurrence of a series without touching the master row.
+-- recurrence_id is the original start of the occurrence. NULL columns keep the
+-- series value.
+CREATE TABLE IF NOT EXISTS event_exceptions
+(
+    series_id     VARCHAR(36) NOT NULL REFERENCES events (id),
+    recurrence_id TIMESTAMP   NOT NULL,
+    cancelled     BOOLEAN     NOT NULL DEFAULT FALSE,
+    title         TEXT,
+    description   TEXT,
+    start_time    TIMESTAMP,
+    end_time      TIMESTAMP,
+    created_at    TIMESTAMP   NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (series_id, recurrence_id)
+);
@@ -0,0 +1,4 @@
+-- UID of events imported from iCalendar files, so a re-import updates them.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS ical_uid TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_events_ical_uid ON events (ical_uid);
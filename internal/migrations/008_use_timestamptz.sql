@@ -0,0 +1,16 @@
+-- TIMESTAMP dropped the offset of every time it stored. Existing values are read
+-- as UTC. time_zone keeps the IANA zone the event was created in, which also
+-- anchors the wall clock of recurring events across DST changes.
+ALTER TABLE events
+    ALTER COLUMN start_time TYPE TIMESTAMPTZ USING start_time AT TIME ZONE 'UTC',
+    ALTER COLUMN end_time TYPE TIMESTAMPTZ USING end_time AT TIME ZONE 'UTC',
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC',
+    ALTER COLUMN deleted_at TYPE TIMESTAMPTZ USING deleted_at AT TIME ZONE 'UTC',
+    ALTER COLUMN series_end TYPE TIMESTAMPTZ USING series_end AT TIME ZONE 'UTC',
+    ADD COLUMN IF NOT EXISTS time_zone TEXT NOT NULL DEFAULT 'UTC';
+
+ALTER TABLE event_exceptions
+    ALTER COLUMN recurrence_id TYPE TIMESTAMPTZ USING recurrence_id AT TIME ZONE 'UTC',
+    ALTER COLUMN start_time TYPE TIMESTAMPTZ USING start_time AT TIME ZONE 'UTC',
+    ALTER COLUMN end_time TYPE TIMESTAMPTZ USING end_time AT TIME ZONE 'UTC',
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC';
@@ -0,0 +1,3 @@
+-- All-day events keep floating dates: start_time and end_time hold them at
+-- midnight UTC, with an exclusive end, and are never shifted to a zone.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS all_day BOOLEAN NOT NULL DEFAULT FALSE;
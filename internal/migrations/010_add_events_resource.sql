@@ -0,0 +1,12 @@
+-- resource is what an event books, e.g. a meeting room. period mirrors the
+-- event times so the exclusion constraint can reject overlapping bookings of
+-- the same resource atomically. Deleted events free their resource.
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS resource TEXT;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS period TSTZRANGE
+    GENERATED ALWAYS AS (tstzrange(start_time, end_time, '[)')) STORED;
+
+ALTER TABLE events ADD CONSTRAINT events_resource_no_overlap
+    EXCLUDE USING gist (resource WITH =, period WITH &&)
+    WHERE (resource IS NOT NULL AND deleted_at IS NULL);
@@ -0,0 +1,13 @@
+-- Attendees of an event, keyed by their lower cased email. status_changed_at
+-- is when status last changed, invited_at until they answer.
+CREATE TABLE IF NOT EXISTS attendees
+(
+    event_id          VARCHAR(36) NOT NULL REFERENCES events (id),
+    email             TEXT        NOT NULL,
+    name              TEXT        NOT NULL DEFAULT '',
+    role              TEXT        NOT NULL DEFAULT 'required' CHECK (role IN ('chair', 'required', 'optional')),
+    status            TEXT        NOT NULL DEFAULT 'invited' CHECK (status IN ('invited', 'accepted', 'declined', 'tentative')),
+    invited_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    status_changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (event_id, email)
+);
@@ -0,0 +1,10 @@
+-- capacity caps the accepted attendees of an event, NULL means no limit.
+-- Accepting a full event waitlists the attendee. The waitlist is served in
+-- status_changed_at order, the time each attendee joined it.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS capacity INTEGER CHECK (capacity > 0);
+
+ALTER TABLE attendees DROP CONSTRAINT IF EXISTS attendees_status_check;
+ALTER TABLE attendees ADD CONSTRAINT attendees_status_check
+    CHECK (status IN ('invited', 'accepted', 'declined', 'tentative', 'waitlisted'));
+
+CREATE INDEX IF NOT EXISTS attendees_status_idx ON attendees (event_id, status, status_changed_at, email);
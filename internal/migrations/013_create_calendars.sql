@@ -0,0 +1,16 @@
+-- Every event belongs to one calendar. Existing events, and events created or
+-- imported without a calendar, go to the default one, which cannot be deleted.
+CREATE TABLE IF NOT EXISTS calendars
+(
+    id          VARCHAR(36) PRIMARY KEY,
+    name        TEXT        NOT NULL,
+    description TEXT        NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO calendars (id, name) VALUES ('default', 'Default') ON CONFLICT (id) DO NOTHING;
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS calendar_id VARCHAR(36) NOT NULL DEFAULT 'default'
+    CONSTRAINT events_calendar_id_fkey REFERENCES calendars (id);
+
+CREATE INDEX IF NOT EXISTS events_calendar_keyset_idx ON events (calendar_id, start_time, id);
@@ -0,0 +1,86 @@
+-- Every row belongs to a tenant, one organization hosted on the deployment.
+-- Storage sets app.tenant_id at the start of each transaction and row level
+-- security only lets it see and write the rows of that tenant. Rows that
+-- existed before tenants belong to the 'default' tenant.
+--
+-- Superusers and roles with BYPASSRLS skip the policies, so the API connects
+-- as events_app. Give it a password with ALTER ROLE events_app LOGIN PASSWORD.
+DO
+$$
+BEGIN
+    IF NOT EXISTS (SELECT FROM pg_roles WHERE rolname = 'events_app') THEN
+        CREATE ROLE events_app NOLOGIN;
+    END IF;
+END
+$$;
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON calendars, events, event_exceptions, attendees TO events_app;
+ALTER DEFAULT PRIVILEGES GRANT SELECT, INSERT, UPDATE, DELETE ON TABLES TO events_app;
+
+ALTER TABLE calendars ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE events ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE event_exceptions ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE attendees ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+
+-- New rows take the tenant of the transaction. Without one the insert fails.
+ALTER TABLE calendars ALTER COLUMN tenant_id SET DEFAULT current_setting('app.tenant_id');
+ALTER TABLE events ALTER COLUMN tenant_id SET DEFAULT current_setting('app.tenant_id');
+ALTER TABLE event_exceptions ALTER COLUMN tenant_id SET DEFAULT current_setting('app.tenant_id');
+ALTER TABLE attendees ALTER COLUMN tenant_id SET DEFAULT current_setting('app.tenant_id');
+
+-- Constraints are checked across tenants, so every key and reference includes
+-- the tenant. Each tenant has its own default calendar and iCalendar UIDs, a
+-- row can only reference rows of its own tenant, and resources are only booked
+-- within a tenant.
+ALTER TABLE events DROP CONSTRAINT IF EXISTS events_calendar_id_fkey;
+ALTER TABLE calendars DROP CONSTRAINT IF EXISTS calendars_pkey;
+ALTER TABLE calendars ADD CONSTRAINT calendars_pkey PRIMARY KEY (tenant_id, id);
+ALTER TABLE events ADD CONSTRAINT events_calendar_id_fkey
+    FOREIGN KEY (tenant_id, calendar_id) REFERENCES calendars (tenant_id, id);
+
+ALTER TABLE events ADD CONSTRAINT events_tenant_id_id_key UNIQUE (tenant_id, id);
+
+ALTER TABLE event_exceptions DROP CONSTRAINT IF EXISTS event_exceptions_series_id_fkey;
+ALTER TABLE event_exceptions ADD CONSTRAINT event_exceptions_series_id_fkey
+    FOREIGN KEY (tenant_id, series_id) REFERENCES events (tenant_id, id);
+
+ALTER TABLE attendees DROP CONSTRAINT IF EXISTS attendees_event_id_fkey;
+ALTER TABLE attendees ADD CONSTRAINT attendees_event_id_fkey
+    FOREIGN KEY (tenant_id, event_id) REFERENCES events (tenant_id, id);
+
+DROP INDEX IF EXISTS idx_events_ical_uid;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_events_ical_uid ON events (tenant_id, ical_uid);
+
+ALTER TABLE events DROP CONSTRAINT IF EXISTS events_resource_no_overlap;
+ALTER TABLE events ADD CONSTRAINT events_resource_no_overlap
+    EXCLUDE USING gist (tenant_id WITH =, resource WITH =, period WITH &&)
+    WHERE (resource IS NOT NULL AND deleted_at IS NULL);
+
+DROP INDEX IF EXISTS events_calendar_keyset_idx;
+CREATE INDEX IF NOT EXISTS events_calendar_keyset_idx ON events (tenant_id, calendar_id, start_time, id);
+CREATE INDEX IF NOT EXISTS events_tenant_keyset_idx ON events (tenant_id, start_time, id);
+
+-- current_setting(..., true) is NULL without a tenant, which matches no row.
+ALTER TABLE calendars ENABLE ROW LEVEL SECURITY;
+ALTER TABLE calendars FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON calendars
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
+
+ALTER TABLE events ENABLE ROW LEVEL SECURITY;
+ALTER TABLE events FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON events
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
+
+ALTER TABLE event_exceptions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE event_exceptions FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON event_exceptions
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
+
+ALTER TABLE attendees ENABLE ROW LEVEL SECURITY;
+ALTER TABLE attendees FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON attendees
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
@@ -0,0 +1,3 @@
+-- The subject of the token that created the event. Events created before
+-- authentication have none.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS created_by TEXT;
@@ -0,0 +1,25 @@
+-- API keys let services call the API without a user token. Only the SHA-256 of
+-- a key is stored. Keys carry their tenant, so they are looked up under row
+-- level security like everything else.
+CREATE TABLE IF NOT EXISTS api_keys
+(
+    tenant_id    TEXT        NOT NULL DEFAULT current_setting('app.tenant_id'),
+    id           VARCHAR(36) PRIMARY KEY,
+    name         TEXT        NOT NULL,
+    prefix       TEXT        NOT NULL,
+    hash         BYTEA       NOT NULL UNIQUE,
+    scopes       TEXT[]      NOT NULL,
+    created_by   TEXT,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at   TIMESTAMPTZ NOT NULL,
+    last_used_at TIMESTAMPTZ,
+    revoked_at   TIMESTAMPTZ
+);
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON api_keys TO events_app;
+
+ALTER TABLE api_keys ENABLE ROW LEVEL SECURITY;
+ALTER TABLE api_keys FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON api_keys
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
@@ -0,0 +1,27 @@
+-- Roles per calendar or event. A role on a calendar applies to all of its
+-- events. Creators own what they created without a grant, which is why
+-- calendars now record who created them.
+ALTER TABLE calendars ADD COLUMN IF NOT EXISTS created_by TEXT;
+
+CREATE TABLE IF NOT EXISTS grants
+(
+    tenant_id   TEXT        NOT NULL DEFAULT current_setting('app.tenant_id'),
+    object_type TEXT        NOT NULL CHECK (object_type IN ('calendar', 'event')),
+    object_id   VARCHAR(36) NOT NULL,
+    principal   TEXT        NOT NULL,
+    role        TEXT        NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+    granted_by  TEXT,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, object_type, object_id, principal)
+);
+
+-- Listings look up every grant of one principal.
+CREATE INDEX IF NOT EXISTS idx_grants_principal ON grants (tenant_id, principal);
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON grants TO events_app;
+
+ALTER TABLE grants ENABLE ROW LEVEL SECURITY;
+ALTER TABLE grants FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON grants
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
@@ -0,0 +1,26 @@
+-- Responses to requests sent with an Idempotency-Key, replayed when a client
+-- retries the same request. A key belongs to the principal that sent it.
+-- status is NULL while the first request runs. Expired keys are removed when
+-- the next key of their tenant is reserved.
+CREATE TABLE IF NOT EXISTS idempotency_keys
+(
+    tenant_id   TEXT        NOT NULL DEFAULT current_setting('app.tenant_id'),
+    principal   TEXT        NOT NULL,
+    key         TEXT        NOT NULL,
+    fingerprint BYTEA       NOT NULL,
+    status      INTEGER,
+    body        BYTEA,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at  TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (tenant_id, principal, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (tenant_id, expires_at);
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON idempotency_keys TO events_app;
+
+ALTER TABLE idempotency_keys ENABLE ROW LEVEL SECURITY;
+ALTER TABLE idempotency_keys FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON idempotency_keys
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
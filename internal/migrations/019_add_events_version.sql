@@ -0,0 +1,3 @@
+-- Optimistic concurrency: every write to an event increments its version,
+-- which the API exposes as its ETag.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,29 @@
+-- Audit history of events. Storage appends a revision in the transaction of
+-- every create, update, delete and restore of an event, numbered from 1 per
+-- event. changes maps each field that changed to its "from" and "to" values,
+-- snapshot is the event right after the write. Events written before this
+-- migration start their history at their next write.
+CREATE TABLE IF NOT EXISTS event_revisions
+(
+    tenant_id  TEXT        NOT NULL DEFAULT current_setting('app.tenant_id'),
+    event_id   VARCHAR(36) NOT NULL,
+    revision   INTEGER     NOT NULL,
+    action     TEXT        NOT NULL CHECK (action IN ('created', 'updated', 'deleted', 'restored')),
+    actor      TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    changes    JSONB       NOT NULL,
+    snapshot   JSONB       NOT NULL,
+    PRIMARY KEY (tenant_id, event_id, revision),
+    FOREIGN KEY (tenant_id, event_id) REFERENCES events (tenant_id, id)
+);
+
+-- Append-only: the default privileges of migration 014 also grant UPDATE and
+-- DELETE, which the history never needs.
+GRANT SELECT, INSERT ON event_revisions TO events_app;
+REVOKE UPDATE, DELETE, TRUNCATE ON event_revisions FROM events_app;
+
+ALTER TABLE event_revisions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE event_revisions FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON event_revisions
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
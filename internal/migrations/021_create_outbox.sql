@@ -0,0 +1,40 @@
+-- Transactional outbox. Storage writes a message in the transaction of every
+-- change to an event, and the relay publishes the messages downstream at least
+-- once, marking each one with published_at. attempts and last_error record
+-- failed deliveries, which are retried from next_attempt_at with a backoff,
+-- until too many failures set the message aside with dead_at.
+CREATE TABLE IF NOT EXISTS outbox
+(
+    id           BIGSERIAL PRIMARY KEY,
+    tenant_id    TEXT        NOT NULL DEFAULT current_setting('app.tenant_id'),
+    topic        TEXT        NOT NULL,
+    key          TEXT        NOT NULL,
+    payload      JSONB       NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    published_at TIMESTAMPTZ,
+    attempts     INTEGER     NOT NULL DEFAULT 0,
+    last_error   TEXT,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    dead_at      TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS outbox_pending_idx ON outbox (next_attempt_at, id) WHERE published_at IS NULL AND dead_at IS NULL;
+-- The relay only claims the oldest pending message of a key.
+CREATE INDEX IF NOT EXISTS outbox_pending_key_idx ON outbox (tenant_id, key, id) WHERE published_at IS NULL AND dead_at IS NULL;
+
+GRANT SELECT, INSERT, UPDATE ON outbox TO events_app;
+REVOKE DELETE, TRUNCATE ON outbox FROM events_app;
+GRANT USAGE ON SEQUENCE outbox_id_seq TO events_app;
+
+-- A tenant only writes its own messages and cannot read any. The relay
+-- publishes the messages of every tenant, so it reads and marks them in a
+-- transaction without a tenant. Once set in a session the setting reads as ''
+-- after its transaction ends, rather than NULL.
+ALTER TABLE outbox ENABLE ROW LEVEL SECURITY;
+ALTER TABLE outbox FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_insert ON outbox FOR INSERT
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
+CREATE POLICY relay_select ON outbox FOR SELECT
+    USING (COALESCE(current_setting('app.tenant_id', true), '') = '');
+CREATE POLICY relay_update ON outbox FOR UPDATE
+    USING (COALESCE(current_setting('app.tenant_id', true), '') = '');
@@ -0,0 +1,39 @@
+-- Bookings are the times an event holds its resource: one range per
+-- occurrence, so a series cannot overlap another booking with any of its
+-- occurrences. They replace the exclusion constraint on events, which only
+-- sees the times of the first occurrence. Storage rewrites the bookings of an
+-- event whenever its times, resource or exceptions change, and drops them when
+-- it is deleted.
+CREATE TABLE IF NOT EXISTS event_bookings
+(
+    tenant_id TEXT        NOT NULL DEFAULT current_setting('app.tenant_id'),
+    event_id  VARCHAR(36) NOT NULL,
+    resource  TEXT        NOT NULL,
+    period    TSTZRANGE   NOT NULL,
+    FOREIGN KEY (tenant_id, event_id) REFERENCES events (tenant_id, id)
+);
+
+-- The occurrences of one series may overlap each other, but no other booking.
+ALTER TABLE event_bookings ADD CONSTRAINT event_bookings_no_overlap
+    EXCLUDE USING gist (tenant_id WITH =, resource WITH =, period WITH &&, event_id WITH <>);
+
+CREATE INDEX IF NOT EXISTS event_bookings_event_idx ON event_bookings (tenant_id, event_id);
+
+-- Until now only one-off events could book a resource, without overlapping.
+INSERT INTO event_bookings (tenant_id, event_id, resource, period)
+SELECT tenant_id, id, resource, period
+FROM events
+WHERE resource IS NOT NULL
+  AND deleted_at IS NULL;
+
+ALTER TABLE events DROP CONSTRAINT IF EXISTS events_resource_no_overlap;
+
+-- Bookings are only ever replaced, never updated.
+GRANT SELECT, INSERT, DELETE ON event_bookings TO events_app;
+REVOKE UPDATE, TRUNCATE ON event_bookings FROM events_app;
+
+ALTER TABLE event_bookings ENABLE ROW LEVEL SECURITY;
+ALTER TABLE event_bookings FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON event_bookings
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
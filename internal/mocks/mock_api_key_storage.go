@@ -0,0 +1,115 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: apikeys.go
+//
+// Generated by this command:
+//
+//	mockgen -source=apikeys.go -destination=mocks/mock_api_key_storage.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	internal "github.com/ObiaNzk/LTK-test-manu/internal"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockapiKeyStorage is a mock of apiKeyStorage interface.
+type MockapiKeyStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockapiKeyStorageMockRecorder
+	isgomock struct{}
+}
+
+// MockapiKeyStorageMockRecorder is the mock recorder for MockapiKeyStorage.
+type MockapiKeyStorageMockRecorder struct {
+	mock *MockapiKeyStorage
+}
+
+// NewMockapiKeyStorage creates a new mock instance.
+func NewMockapiKeyStorage(ctrl *gomock.Controller) *MockapiKeyStorage {
+	mock := &MockapiKeyStorage{ctrl: ctrl}
+	mock.recorder = &MockapiKeyStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockapiKeyStorage) EXPECT() *MockapiKeyStorageMockRecorder {
+	return m.recorder
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockapiKeyStorage) CreateAPIKey(ctx context.Context, key internal.APIKey, hash []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, key, hash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockapiKeyStorageMockRecorder) CreateAPIKey(ctx, key, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockapiKeyStorage)(nil).CreateAPIKey), ctx, key, hash)
+}
+
+// GetAPIKeyByHash mocks base method.
+func (m *MockapiKeyStorage) GetAPIKeyByHash(ctx context.Context, hash []byte) (internal.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeyByHash", ctx, hash)
+	ret0, _ := ret[0].(internal.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeyByHash indicates an expected call of GetAPIKeyByHash.
+func (mr *MockapiKeyStorageMockRecorder) GetAPIKeyByHash(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeyByHash", reflect.TypeOf((*MockapiKeyStorage)(nil).GetAPIKeyByHash), ctx, hash)
+}
+
+// GetAPIKeys mocks base method.
+func (m *MockapiKeyStorage) GetAPIKeys(ctx context.Context) ([]internal.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeys", ctx)
+	ret0, _ := ret[0].([]internal.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeys indicates an expected call of GetAPIKeys.
+func (mr *MockapiKeyStorageMockRecorder) GetAPIKeys(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeys", reflect.TypeOf((*MockapiKeyStorage)(nil).GetAPIKeys), ctx)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockapiKeyStorage) RevokeAPIKey(ctx context.Context, id string, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, id, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockapiKeyStorageMockRecorder) RevokeAPIKey(ctx, id, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockapiKeyStorage)(nil).RevokeAPIKey), ctx, id, at)
+}
+
+// TouchAPIKey mocks base method.
+func (m *MockapiKeyStorage) TouchAPIKey(ctx context.Context, id string, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchAPIKey", ctx, id, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchAPIKey indicates an expected call of TouchAPIKey.
+func (mr *MockapiKeyStorageMockRecorder) TouchAPIKey(ctx, id, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchAPIKey", reflect.TypeOf((*MockapiKeyStorage)(nil).TouchAPIKey), ctx, id, at)
+}
@@ -0,0 +1,161 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: attendees.go
+//
+// Generated by this command:
+//
+//	mockgen -source=attendees.go -destination=mocks/mock_attendee_storage.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	internal "github.com/ObiaNzk/LTK-test-manu/internal"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockattendeeStorage is a mock of attendeeStorage interface.
+type MockattendeeStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockattendeeStorageMockRecorder
+	isgomock struct{}
+}
+
+// MockattendeeStorageMockRecorder is the mock recorder for MockattendeeStorage.
+type MockattendeeStorageMockRecorder struct {
+	mock *MockattendeeStorage
+}
+
+// NewMockattendeeStorage creates a new mock instance.
+func NewMockattendeeStorage(ctrl *gomock.Controller) *MockattendeeStorage {
+	mock := &MockattendeeStorage{ctrl: ctrl}
+	mock.recorder = &MockattendeeStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockattendeeStorage) EXPECT() *MockattendeeStorageMockRecorder {
+	return m.recorder
+}
+
+// AddAttendee mocks base method.
+func (m *MockattendeeStorage) AddAttendee(ctx context.Context, attendee internal.Attendee) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAttendee", ctx, attendee)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddAttendee indicates an expected call of AddAttendee.
+func (mr *MockattendeeStorageMockRecorder) AddAttendee(ctx, attendee any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAttendee", reflect.TypeOf((*MockattendeeStorage)(nil).AddAttendee), ctx, attendee)
+}
+
+// CountAttendees mocks base method.
+func (m *MockattendeeStorage) CountAttendees(ctx context.Context, eventID string) (map[internal.AttendeeStatus]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAttendees", ctx, eventID)
+	ret0, _ := ret[0].(map[internal.AttendeeStatus]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAttendees indicates an expected call of CountAttendees.
+func (mr *MockattendeeStorageMockRecorder) CountAttendees(ctx, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAttendees", reflect.TypeOf((*MockattendeeStorage)(nil).CountAttendees), ctx, eventID)
+}
+
+// GetAttendees mocks base method.
+func (m *MockattendeeStorage) GetAttendees(ctx context.Context, eventID string) ([]internal.Attendee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAttendees", ctx, eventID)
+	ret0, _ := ret[0].([]internal.Attendee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAttendees indicates an expected call of GetAttendees.
+func (mr *MockattendeeStorageMockRecorder) GetAttendees(ctx, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttendees", reflect.TypeOf((*MockattendeeStorage)(nil).GetAttendees), ctx, eventID)
+}
+
+// GetCalendarAccess mocks base method.
+func (m *MockattendeeStorage) GetCalendarAccess(ctx context.Context, principal, calendarID string) ([]internal.AccessRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCalendarAccess", ctx, principal, calendarID)
+	ret0, _ := ret[0].([]internal.AccessRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCalendarAccess indicates an expected call of GetCalendarAccess.
+func (mr *MockattendeeStorageMockRecorder) GetCalendarAccess(ctx, principal, calendarID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalendarAccess", reflect.TypeOf((*MockattendeeStorage)(nil).GetCalendarAccess), ctx, principal, calendarID)
+}
+
+// GetEventAccess mocks base method.
+func (m *MockattendeeStorage) GetEventAccess(ctx context.Context, principal, eventID string) (internal.EventAccess, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventAccess", ctx, principal, eventID)
+	ret0, _ := ret[0].(internal.EventAccess)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventAccess indicates an expected call of GetEventAccess.
+func (mr *MockattendeeStorageMockRecorder) GetEventAccess(ctx, principal, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventAccess", reflect.TypeOf((*MockattendeeStorage)(nil).GetEventAccess), ctx, principal, eventID)
+}
+
+// GetEventAccessByUID mocks base method.
+func (m *MockattendeeStorage) GetEventAccessByUID(ctx context.Context, principal, uid string) (internal.EventAccess, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventAccessByUID", ctx, principal, uid)
+	ret0, _ := ret[0].(internal.EventAccess)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventAccessByUID indicates an expected call of GetEventAccessByUID.
+func (mr *MockattendeeStorageMockRecorder) GetEventAccessByUID(ctx, principal, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventAccessByUID", reflect.TypeOf((*MockattendeeStorage)(nil).GetEventAccessByUID), ctx, principal, uid)
+}
+
+// RemoveAttendee mocks base method.
+func (m *MockattendeeStorage) RemoveAttendee(ctx context.Context, eventID, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveAttendee", ctx, eventID, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveAttendee indicates an expected call of RemoveAttendee.
+func (mr *MockattendeeStorageMockRecorder) RemoveAttendee(ctx, eventID, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAttendee", reflect.TypeOf((*MockattendeeStorage)(nil).RemoveAttendee), ctx, eventID, email)
+}
+
+// SetAttendeeStatus mocks base method.
+func (m *MockattendeeStorage) SetAttendeeStatus(ctx context.Context, eventID, email string, status internal.AttendeeStatus, at time.Time) (internal.Attendee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAttendeeStatus", ctx, eventID, email, status, at)
+	ret0, _ := ret[0].(internal.Attendee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetAttendeeStatus indicates an expected call of SetAttendeeStatus.
+func (mr *MockattendeeStorageMockRecorder) SetAttendeeStatus(ctx, eventID, email, status, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAttendeeStatus", reflect.TypeOf((*MockattendeeStorage)(nil).SetAttendeeStatus), ctx, eventID, email, status, at)
+}
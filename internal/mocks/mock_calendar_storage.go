@@ -0,0 +1,161 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: calendars.go
+//
+// Generated by this command:
+//
+//	mockgen -source=calendars.go -destination=mocks/mock_calendar_storage.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	internal "github.com/ObiaNzk/LTK-test-manu/internal"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockcalendarStorage is a mock of calendarStorage interface.
+type MockcalendarStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockcalendarStorageMockRecorder
+	isgomock struct{}
+}
+
+// MockcalendarStorageMockRecorder is the mock recorder for MockcalendarStorage.
+type MockcalendarStorageMockRecorder struct {
+	mock *MockcalendarStorage
+}
+
+// NewMockcalendarStorage creates a new mock instance.
+func NewMockcalendarStorage(ctrl *gomock.Controller) *MockcalendarStorage {
+	mock := &MockcalendarStorage{ctrl: ctrl}
+	mock.recorder = &MockcalendarStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockcalendarStorage) EXPECT() *MockcalendarStorageMockRecorder {
+	return m.recorder
+}
+
+// CreateCalendar mocks base method.
+func (m *MockcalendarStorage) CreateCalendar(ctx context.Context, calendar internal.Calendar) (internal.Calendar, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCalendar", ctx, calendar)
+	ret0, _ := ret[0].(internal.Calendar)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCalendar indicates an expected call of CreateCalendar.
+func (mr *MockcalendarStorageMockRecorder) CreateCalendar(ctx, calendar any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCalendar", reflect.TypeOf((*MockcalendarStorage)(nil).CreateCalendar), ctx, calendar)
+}
+
+// DeleteCalendar mocks base method.
+func (m *MockcalendarStorage) DeleteCalendar(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCalendar", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCalendar indicates an expected call of DeleteCalendar.
+func (mr *MockcalendarStorageMockRecorder) DeleteCalendar(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCalendar", reflect.TypeOf((*MockcalendarStorage)(nil).DeleteCalendar), ctx, id)
+}
+
+// GetCalendar mocks base method.
+func (m *MockcalendarStorage) GetCalendar(ctx context.Context, id string) (internal.Calendar, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCalendar", ctx, id)
+	ret0, _ := ret[0].(internal.Calendar)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCalendar indicates an expected call of GetCalendar.
+func (mr *MockcalendarStorageMockRecorder) GetCalendar(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalendar", reflect.TypeOf((*MockcalendarStorage)(nil).GetCalendar), ctx, id)
+}
+
+// GetCalendarAccess mocks base method.
+func (m *MockcalendarStorage) GetCalendarAccess(ctx context.Context, principal, calendarID string) ([]internal.AccessRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCalendarAccess", ctx, principal, calendarID)
+	ret0, _ := ret[0].([]internal.AccessRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCalendarAccess indicates an expected call of GetCalendarAccess.
+func (mr *MockcalendarStorageMockRecorder) GetCalendarAccess(ctx, principal, calendarID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalendarAccess", reflect.TypeOf((*MockcalendarStorage)(nil).GetCalendarAccess), ctx, principal, calendarID)
+}
+
+// GetCalendars mocks base method.
+func (m *MockcalendarStorage) GetCalendars(ctx context.Context, visibleTo string) ([]internal.Calendar, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCalendars", ctx, visibleTo)
+	ret0, _ := ret[0].([]internal.Calendar)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCalendars indicates an expected call of GetCalendars.
+func (mr *MockcalendarStorageMockRecorder) GetCalendars(ctx, visibleTo any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalendars", reflect.TypeOf((*MockcalendarStorage)(nil).GetCalendars), ctx, visibleTo)
+}
+
+// GetEventAccess mocks base method.
+func (m *MockcalendarStorage) GetEventAccess(ctx context.Context, principal, eventID string) (internal.EventAccess, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventAccess", ctx, principal, eventID)
+	ret0, _ := ret[0].(internal.EventAccess)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventAccess indicates an expected call of GetEventAccess.
+func (mr *MockcalendarStorageMockRecorder) GetEventAccess(ctx, principal, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventAccess", reflect.TypeOf((*MockcalendarStorage)(nil).GetEventAccess), ctx, principal, eventID)
+}
+
+// GetEventAccessByUID mocks base method.
+func (m *MockcalendarStorage) GetEventAccessByUID(ctx context.Context, principal, uid string) (internal.EventAccess, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventAccessByUID", ctx, principal, uid)
+	ret0, _ := ret[0].(internal.EventAccess)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventAccessByUID indicates an expected call of GetEventAccessByUID.
+func (mr *MockcalendarStorageMockRecorder) GetEventAccessByUID(ctx, principal, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventAccessByUID", reflect.TypeOf((*MockcalendarStorage)(nil).GetEventAccessByUID), ctx, principal, uid)
+}
+
+// UpdateCalendar mocks base method.
+func (m *MockcalendarStorage) UpdateCalendar(ctx context.Context, calendar internal.Calendar) (internal.Calendar, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCalendar", ctx, calendar)
+	ret0, _ := ret[0].(internal.Calendar)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCalendar indicates an expected call of UpdateCalendar.
+func (mr *MockcalendarStorageMockRecorder) UpdateCalendar(ctx, calendar any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCalendar", reflect.TypeOf((*MockcalendarStorage)(nil).UpdateCalendar), ctx, calendar)
+}
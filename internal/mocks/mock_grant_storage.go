@@ -0,0 +1,130 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: grants.go
+//
+// Generated by this command:
+//
+//	mockgen -source=grants.go -destination=mocks/mock_grant_storage.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	internal "github.com/ObiaNzk/LTK-test-manu/internal"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockgrantStorage is a mock of grantStorage interface.
+type MockgrantStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockgrantStorageMockRecorder
+	isgomock struct{}
+}
+
+// MockgrantStorageMockRecorder is the mock recorder for MockgrantStorage.
+type MockgrantStorageMockRecorder struct {
+	mock *MockgrantStorage
+}
+
+// NewMockgrantStorage creates a new mock instance.
+func NewMockgrantStorage(ctrl *gomock.Controller) *MockgrantStorage {
+	mock := &MockgrantStorage{ctrl: ctrl}
+	mock.recorder = &MockgrantStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockgrantStorage) EXPECT() *MockgrantStorageMockRecorder {
+	return m.recorder
+}
+
+// DeleteGrant mocks base method.
+func (m *MockgrantStorage) DeleteGrant(ctx context.Context, objectType internal.ObjectType, objectID, principal string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGrant", ctx, objectType, objectID, principal)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteGrant indicates an expected call of DeleteGrant.
+func (mr *MockgrantStorageMockRecorder) DeleteGrant(ctx, objectType, objectID, principal any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGrant", reflect.TypeOf((*MockgrantStorage)(nil).DeleteGrant), ctx, objectType, objectID, principal)
+}
+
+// GetCalendarAccess mocks base method.
+func (m *MockgrantStorage) GetCalendarAccess(ctx context.Context, principal, calendarID string) ([]internal.AccessRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCalendarAccess", ctx, principal, calendarID)
+	ret0, _ := ret[0].([]internal.AccessRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCalendarAccess indicates an expected call of GetCalendarAccess.
+func (mr *MockgrantStorageMockRecorder) GetCalendarAccess(ctx, principal, calendarID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalendarAccess", reflect.TypeOf((*MockgrantStorage)(nil).GetCalendarAccess), ctx, principal, calendarID)
+}
+
+// GetEventAccess mocks base method.
+func (m *MockgrantStorage) GetEventAccess(ctx context.Context, principal, eventID string) (internal.EventAccess, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventAccess", ctx, principal, eventID)
+	ret0, _ := ret[0].(internal.EventAccess)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventAccess indicates an expected call of GetEventAccess.
+func (mr *MockgrantStorageMockRecorder) GetEventAccess(ctx, principal, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventAccess", reflect.TypeOf((*MockgrantStorage)(nil).GetEventAccess), ctx, principal, eventID)
+}
+
+// GetEventAccessByUID mocks base method.
+func (m *MockgrantStorage) GetEventAccessByUID(ctx context.Context, principal, uid string) (internal.EventAccess, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventAccessByUID", ctx, principal, uid)
+	ret0, _ := ret[0].(internal.EventAccess)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventAccessByUID indicates an expected call of GetEventAccessByUID.
+func (mr *MockgrantStorageMockRecorder) GetEventAccessByUID(ctx, principal, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventAccessByUID", reflect.TypeOf((*MockgrantStorage)(nil).GetEventAccessByUID), ctx, principal, uid)
+}
+
+// GetGrants mocks base method.
+func (m *MockgrantStorage) GetGrants(ctx context.Context, objectType internal.ObjectType, objectID string) ([]internal.Grant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGrants", ctx, objectType, objectID)
+	ret0, _ := ret[0].([]internal.Grant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGrants indicates an expected call of GetGrants.
+func (mr *MockgrantStorageMockRecorder) GetGrants(ctx, objectType, objectID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGrants", reflect.TypeOf((*MockgrantStorage)(nil).GetGrants), ctx, objectType, objectID)
+}
+
+// SetGrant mocks base method.
+func (m *MockgrantStorage) SetGrant(ctx context.Context, grant internal.Grant) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetGrant", ctx, grant)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetGrant indicates an expected call of SetGrant.
+func (mr *MockgrantStorageMockRecorder) SetGrant(ctx, grant any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetGrant", reflect.TypeOf((*MockgrantStorage)(nil).SetGrant), ctx, grant)
+}
@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: idempotency.go
+//
+// Generated by this command:
+//
+//	mockgen -source=idempotency.go -destination=mocks/mock_idempotency_storage.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	internal "github.com/ObiaNzk/LTK-test-manu/internal"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockidempotencyStorage is a mock of idempotencyStorage interface.
+type MockidempotencyStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockidempotencyStorageMockRecorder
+	isgomock struct{}
+}
+
+// MockidempotencyStorageMockRecorder is the mock recorder for MockidempotencyStorage.
+type MockidempotencyStorageMockRecorder struct {
+	mock *MockidempotencyStorage
+}
+
+// NewMockidempotencyStorage creates a new mock instance.
+func NewMockidempotencyStorage(ctrl *gomock.Controller) *MockidempotencyStorage {
+	mock := &MockidempotencyStorage{ctrl: ctrl}
+	mock.recorder = &MockidempotencyStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockidempotencyStorage) EXPECT() *MockidempotencyStorageMockRecorder {
+	return m.recorder
+}
+
+// ReleaseIdempotencyKey mocks base method.
+func (m *MockidempotencyStorage) ReleaseIdempotencyKey(ctx context.Context, principal, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseIdempotencyKey", ctx, principal, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseIdempotencyKey indicates an expected call of ReleaseIdempotencyKey.
+func (mr *MockidempotencyStorageMockRecorder) ReleaseIdempotencyKey(ctx, principal, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseIdempotencyKey", reflect.TypeOf((*MockidempotencyStorage)(nil).ReleaseIdempotencyKey), ctx, principal, key)
+}
+
+// ReserveIdempotencyKey mocks base method.
+func (m *MockidempotencyStorage) ReserveIdempotencyKey(ctx context.Context, record internal.IdempotencyRecord) (internal.IdempotencyRecord, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveIdempotencyKey", ctx, record)
+	ret0, _ := ret[0].(internal.IdempotencyRecord)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ReserveIdempotencyKey indicates an expected call of ReserveIdempotencyKey.
+func (mr *MockidempotencyStorageMockRecorder) ReserveIdempotencyKey(ctx, record any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveIdempotencyKey", reflect.TypeOf((*MockidempotencyStorage)(nil).ReserveIdempotencyKey), ctx, record)
+}
+
+// SaveIdempotentResponse mocks base method.
+func (m *MockidempotencyStorage) SaveIdempotentResponse(ctx context.Context, principal, key string, status int, body []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveIdempotentResponse", ctx, principal, key, status, body)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveIdempotentResponse indicates an expected call of SaveIdempotentResponse.
+func (mr *MockidempotencyStorageMockRecorder) SaveIdempotentResponse(ctx, principal, key, status, body any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveIdempotentResponse", reflect.TypeOf((*MockidempotencyStorage)(nil).SaveIdempotentResponse), ctx, principal, key, status, body)
+}
@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: outbox.go
+//
+// Generated by this command:
+//
+//	mockgen -source=outbox.go -destination=mocks/mock_outbox.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	internal "github.com/ObiaNzk/LTK-test-manu/internal"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPublisher is a mock of Publisher interface.
+type MockPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockPublisherMockRecorder
+	isgomock struct{}
+}
+
+// MockPublisherMockRecorder is the mock recorder for MockPublisher.
+type MockPublisherMockRecorder struct {
+	mock *MockPublisher
+}
+
+// NewMockPublisher creates a new mock instance.
+func NewMockPublisher(ctrl *gomock.Controller) *MockPublisher {
+	mock := &MockPublisher{ctrl: ctrl}
+	mock.recorder = &MockPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPublisher) EXPECT() *MockPublisherMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockPublisher) Publish(ctx context.Context, message internal.OutboxMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockPublisherMockRecorder) Publish(ctx, message any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockPublisher)(nil).Publish), ctx, message)
+}
+
+// MockoutboxStorage is a mock of outboxStorage interface.
+type MockoutboxStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockoutboxStorageMockRecorder
+	isgomock struct{}
+}
+
+// MockoutboxStorageMockRecorder is the mock recorder for MockoutboxStorage.
+type MockoutboxStorageMockRecorder struct {
+	mock *MockoutboxStorage
+}
+
+// NewMockoutboxStorage creates a new mock instance.
+func NewMockoutboxStorage(ctrl *gomock.Controller) *MockoutboxStorage {
+	mock := &MockoutboxStorage{ctrl: ctrl}
+	mock.recorder = &MockoutboxStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockoutboxStorage) EXPECT() *MockoutboxStorageMockRecorder {
+	return m.recorder
+}
+
+// DeliverOutbox mocks base method.
+func (m *MockoutboxStorage) DeliverOutbox(ctx context.Context, limit int, deliver func(context.Context, internal.OutboxMessage) error) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeliverOutbox", ctx, limit, deliver)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeliverOutbox indicates an expected call of DeliverOutbox.
+func (mr *MockoutboxStorageMockRecorder) DeliverOutbox(ctx, limit, deliver any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeliverOutbox", reflect.TypeOf((*MockoutboxStorage)(nil).DeliverOutbox), ctx, limit, deliver)
+}
@@ -12,6 +12,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	internal "github.com/ObiaNzk/LTK-test-manu/internal"
 	gomock "go.uber.org/mock/gomock"
@@ -56,32 +57,225 @@ func (mr *MockstorageMockRecorder) CreateEvent(ctx, event any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEvent", reflect.TypeOf((*Mockstorage)(nil).CreateEvent), ctx, event)
 }
 
+// DeleteEvent mocks base method.
+func (m *Mockstorage) DeleteEvent(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEvent", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEvent indicates an expected call of DeleteEvent.
+func (mr *MockstorageMockRecorder) DeleteEvent(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEvent", reflect.TypeOf((*Mockstorage)(nil).DeleteEvent), ctx, id)
+}
+
+// GetCalendar mocks base method.
+func (m *Mockstorage) GetCalendar(ctx context.Context, id string) (internal.Calendar, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCalendar", ctx, id)
+	ret0, _ := ret[0].(internal.Calendar)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCalendar indicates an expected call of GetCalendar.
+func (mr *MockstorageMockRecorder) GetCalendar(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalendar", reflect.TypeOf((*Mockstorage)(nil).GetCalendar), ctx, id)
+}
+
+// GetCalendarAccess mocks base method.
+func (m *Mockstorage) GetCalendarAccess(ctx context.Context, principal, calendarID string) ([]internal.AccessRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCalendarAccess", ctx, principal, calendarID)
+	ret0, _ := ret[0].([]internal.AccessRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCalendarAccess indicates an expected call of GetCalendarAccess.
+func (mr *MockstorageMockRecorder) GetCalendarAccess(ctx, principal, calendarID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalendarAccess", reflect.TypeOf((*Mockstorage)(nil).GetCalendarAccess), ctx, principal, calendarID)
+}
+
+// GetEventAccess mocks base method.
+func (m *Mockstorage) GetEventAccess(ctx context.Context, principal, eventID string) (internal.EventAccess, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventAccess", ctx, principal, eventID)
+	ret0, _ := ret[0].(internal.EventAccess)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventAccess indicates an expected call of GetEventAccess.
+func (mr *MockstorageMockRecorder) GetEventAccess(ctx, principal, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventAccess", reflect.TypeOf((*Mockstorage)(nil).GetEventAccess), ctx, principal, eventID)
+}
+
+// GetEventAccessByUID mocks base method.
+func (m *Mockstorage) GetEventAccessByUID(ctx context.Context, principal, uid string) (internal.EventAccess, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventAccessByUID", ctx, principal, uid)
+	ret0, _ := ret[0].(internal.EventAccess)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventAccessByUID indicates an expected call of GetEventAccessByUID.
+func (mr *MockstorageMockRecorder) GetEventAccessByUID(ctx, principal, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventAccessByUID", reflect.TypeOf((*Mockstorage)(nil).GetEventAccessByUID), ctx, principal, uid)
+}
+
 // GetEventByID mocks base method.
-func (m *Mockstorage) GetEventByID(ctx context.Context, id string) (internal.CreateEventResponse, error) {
+func (m *Mockstorage) GetEventByID(ctx context.Context, id string, includeDeleted bool) (internal.CreateEventResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEventByID", ctx, id)
+	ret := m.ctrl.Call(m, "GetEventByID", ctx, id, includeDeleted)
 	ret0, _ := ret[0].(internal.CreateEventResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetEventByID indicates an expected call of GetEventByID.
-func (mr *MockstorageMockRecorder) GetEventByID(ctx, id any) *gomock.Call {
+func (mr *MockstorageMockRecorder) GetEventByID(ctx, id, includeDeleted any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventByID", reflect.TypeOf((*Mockstorage)(nil).GetEventByID), ctx, id, includeDeleted)
+}
+
+// GetEventRevision mocks base method.
+func (m *Mockstorage) GetEventRevision(ctx context.Context, eventID string, revision int) (internal.EventRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventRevision", ctx, eventID, revision)
+	ret0, _ := ret[0].(internal.EventRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventRevision indicates an expected call of GetEventRevision.
+func (mr *MockstorageMockRecorder) GetEventRevision(ctx, eventID, revision any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventByID", reflect.TypeOf((*Mockstorage)(nil).GetEventByID), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventRevision", reflect.TypeOf((*Mockstorage)(nil).GetEventRevision), ctx, eventID, revision)
+}
+
+// GetEventRevisions mocks base method.
+func (m *Mockstorage) GetEventRevisions(ctx context.Context, eventID string) ([]internal.EventRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventRevisions", ctx, eventID)
+	ret0, _ := ret[0].([]internal.EventRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventRevisions indicates an expected call of GetEventRevisions.
+func (mr *MockstorageMockRecorder) GetEventRevisions(ctx, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventRevisions", reflect.TypeOf((*Mockstorage)(nil).GetEventRevisions), ctx, eventID)
 }
 
 // GetEvents mocks base method.
-func (m *Mockstorage) GetEvents(ctx context.Context) ([]internal.CreateEventResponse, error) {
+func (m *Mockstorage) GetEvents(ctx context.Context, filter internal.EventFilter, page internal.Page) ([]internal.CreateEventResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEvents", ctx)
+	ret := m.ctrl.Call(m, "GetEvents", ctx, filter, page)
 	ret0, _ := ret[0].([]internal.CreateEventResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetEvents indicates an expected call of GetEvents.
-func (mr *MockstorageMockRecorder) GetEvents(ctx any) *gomock.Call {
+func (mr *MockstorageMockRecorder) GetEvents(ctx, filter, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvents", reflect.TypeOf((*Mockstorage)(nil).GetEvents), ctx, filter, page)
+}
+
+// GetOccurrenceExceptions mocks base method.
+func (m *Mockstorage) GetOccurrenceExceptions(ctx context.Context, seriesIDs []string) ([]internal.OccurrenceException, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOccurrenceExceptions", ctx, seriesIDs)
+	ret0, _ := ret[0].([]internal.OccurrenceException)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOccurrenceExceptions indicates an expected call of GetOccurrenceExceptions.
+func (mr *MockstorageMockRecorder) GetOccurrenceExceptions(ctx, seriesIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOccurrenceExceptions", reflect.TypeOf((*Mockstorage)(nil).GetOccurrenceExceptions), ctx, seriesIDs)
+}
+
+// ImportEvents mocks base method.
+func (m *Mockstorage) ImportEvents(ctx context.Context, events []internal.ImportedEvent) ([]internal.ImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportEvents", ctx, events)
+	ret0, _ := ret[0].([]internal.ImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportEvents indicates an expected call of ImportEvents.
+func (mr *MockstorageMockRecorder) ImportEvents(ctx, events any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportEvents", reflect.TypeOf((*Mockstorage)(nil).ImportEvents), ctx, events)
+}
+
+// RestoreEvent mocks base method.
+func (m *Mockstorage) RestoreEvent(ctx context.Context, id string) (internal.CreateEventResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreEvent", ctx, id)
+	ret0, _ := ret[0].(internal.CreateEventResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreEvent indicates an expected call of RestoreEvent.
+func (mr *MockstorageMockRecorder) RestoreEvent(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreEvent", reflect.TypeOf((*Mockstorage)(nil).RestoreEvent), ctx, id)
+}
+
+// SaveOccurrenceException mocks base method.
+func (m *Mockstorage) SaveOccurrenceException(ctx context.Context, exception internal.OccurrenceException) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveOccurrenceException", ctx, exception)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveOccurrenceException indicates an expected call of SaveOccurrenceException.
+func (mr *MockstorageMockRecorder) SaveOccurrenceException(ctx, exception any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOccurrenceException", reflect.TypeOf((*Mockstorage)(nil).SaveOccurrenceException), ctx, exception)
+}
+
+// SplitSeries mocks base method.
+func (m *Mockstorage) SplitSeries(ctx context.Context, id string, at time.Time, head, tail internal.CreateEventRequest) (internal.CreateEventResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SplitSeries", ctx, id, at, head, tail)
+	ret0, _ := ret[0].(internal.CreateEventResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SplitSeries indicates an expected call of SplitSeries.
+func (mr *MockstorageMockRecorder) SplitSeries(ctx, id, at, head, tail any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SplitSeries", reflect.TypeOf((*Mockstorage)(nil).SplitSeries), ctx, id, at, head, tail)
+}
+
+// UpdateEvent mocks base method.
+func (m *Mockstorage) UpdateEvent(ctx context.Context, id string, version int, event internal.CreateEventRequest) (internal.CreateEventResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEvent", ctx, id, version, event)
+	ret0, _ := ret[0].(internal.CreateEventResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateEvent indicates an expected call of UpdateEvent.
+func (mr *MockstorageMockRecorder) UpdateEvent(ctx, id, version, event any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvents", reflect.TypeOf((*Mockstorage)(nil).GetEvents), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEvent", reflect.TypeOf((*Mockstorage)(nil).UpdateEvent), ctx, id, version, event)
 }
@@ -1,12 +1,34 @@
 package internal
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type CreateEventRequest struct {
-	Title       string
-	Description string
-	StartTime   time.Time
-	EndTime     time.Time
+	Title       string    `validate:"required,min=101"`
+	Description string    `validate:"required"`
+	StartTime   time.Time `validate:"required"`
+	EndTime     time.Time `validate:"required,gtefield=StartTime"`
+	// RRule is an RFC 5545 RRULE value. StartTime and EndTime describe the first occurrence.
+	RRule   string
+	ExDates []time.Time
+	RDates  []time.Time
+	// TimeZone is the IANA zone of the event. When empty it is taken from StartTime,
+	// or UTC if that only carries an offset.
+	TimeZone string
+	// AllDay events span calendar dates rather than instants. StartTime and
+	// EndTime hold the dates at midnight UTC and EndTime is exclusive.
+	AllDay bool
+	// Resource is what the event books, e.g. a meeting room. Two events cannot
+	// book the same resource at overlapping times.
+	Resource string
+	// Capacity caps the accepted attendees, later ones join a waitlist. Zero
+	// means no limit.
+	Capacity int `validate:"min=0"`
+	// CalendarID is the calendar the event belongs to. Creating without one uses
+	// the default calendar, updating without one keeps the current calendar.
+	CalendarID string
 }
 
 type CreateEventResponse struct {
@@ -16,4 +38,242 @@ type CreateEventResponse struct {
 	StartTime   time.Time
 	EndTime     time.Time
 	CreatedAt   time.Time
+	DeletedAt   *time.Time
+	RRule       string
+	ExDates     []time.Time
+	RDates      []time.Time
+	// RecurrenceID is the start of the occurrence when the event was expanded from a series.
+	RecurrenceID *time.Time
+	TimeZone     string
+	AllDay       bool
+	Resource     string
+	Capacity     int
+	CalendarID   string
+	// CreatedBy is the subject of the token that created the event, empty for
+	// events created before authentication.
+	CreatedBy string
+	// Version starts at 1 and increments on every write to the event or its
+	// attendees.
+	Version int
+}
+
+// OccurrenceException moves, edits or cancels one occurrence of a series. It is
+// keyed by the series id and the original start of the occurrence. Zero fields
+// keep the series value.
+type OccurrenceException struct {
+	SeriesID     string
+	RecurrenceID time.Time
+	Cancelled    bool
+	Title        string
+	Description  string
+	StartTime    time.Time
+	EndTime      time.Time
+}
+
+// ImportedEvent is one event of an iCalendar import. Err is set when it could
+// not be read from the file.
+type ImportedEvent struct {
+	UID   string
+	Event CreateEventRequest
+	Err   error
+}
+
+type ImportStatus string
+
+const (
+	ImportCreated  ImportStatus = "created"
+	ImportUpdated  ImportStatus = "updated"
+	ImportRejected ImportStatus = "rejected"
+)
+
+// ImportResult reports what happened to one imported event. Reason explains a rejection.
+type ImportResult struct {
+	UID    string
+	ID     string
+	Status ImportStatus
+	Reason string
+}
+
+// RevisionAction is the kind of write a revision records.
+type RevisionAction string
+
+const (
+	RevisionCreated  RevisionAction = "created"
+	RevisionUpdated  RevisionAction = "updated"
+	RevisionDeleted  RevisionAction = "deleted"
+	RevisionRestored RevisionAction = "restored"
+)
+
+// FieldChange is the JSON value of an event field before and after a write.
+// A field that was empty has no From, one that became empty no To.
+type FieldChange struct {
+	From json.RawMessage `json:"from,omitempty"`
+	To   json.RawMessage `json:"to,omitempty"`
+}
+
+// EventRevision records one write to an event: who made it, when and what
+// changed. Snapshot holds the editable fields right after the write, what
+// reverting to the revision writes back.
+type EventRevision struct {
+	EventID   string
+	Revision  int
+	Action    RevisionAction
+	Actor     string
+	CreatedAt time.Time
+	Changes   map[string]FieldChange
+	Snapshot  CreateEventRequest
+}
+
+// OutboxMessage is a change waiting in the outbox to be published downstream.
+// Key is what the change is about, e.g. the event id, and Payload its JSON.
+// Attempts counts the deliveries tried so far.
+type OutboxMessage struct {
+	ID        int64
+	TenantID  string
+	Topic     string
+	Key       string
+	Payload   json.RawMessage
+	CreatedAt time.Time
+	Attempts  int
+}
+
+// EventFilter narrows an event listing. Zero values mean "no constraint".
+type EventFilter struct {
+	// From and To select events overlapping the [From, To) window. All-day events
+	// are matched against the dates of From and To in their own location, so
+	// callers pass them in their time zone.
+	From time.Time
+	To   time.Time
+	// Title matches events whose title contains it, case insensitive.
+	Title       string
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	// Resources matches events booking any of them.
+	Resources []string
+	// CalendarID only matches events of that calendar.
+	CalendarID string
+	// CalendarIDs matches events of any of them.
+	CalendarIDs []string
+	// IncludeDeleted also returns soft deleted events.
+	IncludeDeleted bool
+	// VisibleTo only matches events that principal may view. Empty matches
+	// every event.
+	VisibleTo string
+}
+
+// AttendeeRole mirrors the iCalendar ROLE parameter.
+type AttendeeRole string
+
+const (
+	RoleChair    AttendeeRole = "chair"
+	RoleRequired AttendeeRole = "required"
+	RoleOptional AttendeeRole = "optional"
+)
+
+// AttendeeStatus is the answer of an attendee. Everyone starts as invited.
+// Attendees who accept a full event are waitlisted until a seat frees up.
+type AttendeeStatus string
+
+const (
+	StatusInvited    AttendeeStatus = "invited"
+	StatusAccepted   AttendeeStatus = "accepted"
+	StatusDeclined   AttendeeStatus = "declined"
+	StatusTentative  AttendeeStatus = "tentative"
+	StatusWaitlisted AttendeeStatus = "waitlisted"
+)
+
+// Attendee is someone invited to an event, identified by their email.
+type Attendee struct {
+	EventID   string
+	Email     string
+	Name      string
+	Role      AttendeeRole `validate:"oneof=chair required optional"`
+	Status    AttendeeStatus
+	InvitedAt time.Time
+	// StatusChangedAt is when Status last changed, InvitedAt until the first answer.
+	StatusChangedAt time.Time
+}
+
+// Calendar groups events. Every event belongs to exactly one.
+type Calendar struct {
+	ID          string
+	Name        string `validate:"required,max=200"`
+	Description string
+	CreatedAt   time.Time
+	// CreatedBy owns the calendar. Empty for calendars created before roles.
+	CreatedBy string
+}
+
+// AccessRole is what a principal may do with a calendar or an event. Each role
+// includes the ones below it: viewers read, editors also change events and
+// their attendees, owners also manage the calendar and who has access.
+type AccessRole string
+
+const (
+	AccessOwner  AccessRole = "owner"
+	AccessEditor AccessRole = "editor"
+	AccessViewer AccessRole = "viewer"
+)
+
+// ObjectType is what a grant is on.
+type ObjectType string
+
+const (
+	ObjectCalendar ObjectType = "calendar"
+	ObjectEvent    ObjectType = "event"
+)
+
+// Grant gives a principal a role on a calendar, and so on all of its events,
+// or on a single event. Principals are actors: the subject of a user token or
+// "apikey:<id>".
+type Grant struct {
+	ObjectType ObjectType
+	ObjectID   string
+	Principal  string     `validate:"required,max=200"`
+	Role       AccessRole `validate:"required,oneof=owner editor viewer"`
+	GrantedBy  string
+	CreatedAt  time.Time
+}
+
+// EventAccess is what authorizing an event needs: the calendar it is in and
+// the roles a principal holds on the event or on that calendar.
+type EventAccess struct {
+	CalendarID string
+	Roles      []AccessRole
+}
+
+// Scope is what an API key is allowed to do.
+type Scope string
+
+const (
+	ScopeEventsRead  Scope = "events:read"
+	ScopeEventsWrite Scope = "events:write"
+)
+
+// IdempotencyRecord is a request sent with an Idempotency-Key and, once it
+// completed, the response to replay for it. Fingerprint identifies the request.
+type IdempotencyRecord struct {
+	Principal   string
+	Key         string
+	Fingerprint []byte
+	Status      int
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// APIKey lets a service call the API without a user token. Only a hash of the
+// key itself is stored, so it cannot be shown again after creation. Prefix is
+// its start, to tell keys apart.
+type APIKey struct {
+	ID         string
+	TenantID   string
+	Name       string
+	Prefix     string
+	Scopes     []Scope
+	CreatedBy  string
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
 }
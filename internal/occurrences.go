@@ -0,0 +1,317 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal/recurrence"
+)
+
+func (e CreateEventRequest) isRecurring() bool {
+	return e.RRule != "" || len(e.RDates) > 0
+}
+
+func (e CreateEventResponse) isRecurring() bool {
+	return e.RRule != "" || len(e.RDates) > 0
+}
+
+// recurrenceSet expands in the zone of the event, so occurrences keep their wall
+// clock time across DST changes.
+func (e CreateEventRequest) recurrenceSet() (recurrence.Set, error) {
+	return newRecurrenceSet(e.StartTime.In(e.location()), e.RRule, e.ExDates, e.RDates)
+}
+
+func (e CreateEventResponse) recurrenceSet() (recurrence.Set, error) {
+	return newRecurrenceSet(e.StartTime.In(e.location()), e.RRule, e.ExDates, e.RDates)
+}
+
+func newRecurrenceSet(start time.Time, rrule string, exDates, rDates []time.Time) (recurrence.Set, error) {
+	set := recurrence.Set{Start: start, ExDates: exDates, RDates: rDates}
+
+	if rrule != "" {
+		rule, err := recurrence.ParseRule(rrule)
+		if err != nil {
+			return recurrence.Set{}, err
+		}
+
+		set.Rule = &rule
+	}
+
+	return set, nil
+}
+
+// seriesEnd is the end of the last occurrence, or nil when the series never ends.
+// Storage keeps it so window queries can skip finished series.
+func (e CreateEventRequest) seriesEnd() (*time.Time, error) {
+	if !e.isRecurring() {
+		return &e.EndTime, nil
+	}
+
+	set, err := e.recurrenceSet()
+	if err != nil {
+		return nil, err
+	}
+
+	last, ok := set.Last()
+	if !ok {
+		return nil, nil
+	}
+
+	end := last.Add(e.EndTime.Sub(e.StartTime))
+
+	return &end, nil
+}
+
+// expandOccurrences replaces every recurring event by its occurrences overlapping
+// [from, to), with their exceptions applied. One-off events are kept as they are.
+func expandOccurrences(events []CreateEventResponse, exceptions []OccurrenceException, from, to time.Time) ([]CreateEventResponse, error) {
+	bySeries := make(map[string][]OccurrenceException)
+	for _, exception := range exceptions {
+		bySeries[exception.SeriesID] = append(bySeries[exception.SeriesID], exception)
+	}
+
+	result := make([]CreateEventResponse, 0, len(events))
+
+	for _, event := range events {
+		if !event.isRecurring() {
+			result = append(result, event)
+			continue
+		}
+
+		occurrences, err := seriesOccurrences(event, bySeries[event.ID], from, to)
+		if err != nil {
+			return nil, fmt.Errorf("expanding event %s: %w", event.ID, err)
+		}
+
+		result = append(result, occurrences...)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].StartTime.Before(result[j].StartTime) })
+
+	return result, nil
+}
+
+func seriesOccurrences(series CreateEventResponse, exceptions []OccurrenceException, from, to time.Time) ([]CreateEventResponse, error) {
+	set, err := series.recurrenceSet()
+	if err != nil {
+		return nil, err
+	}
+
+	duration := series.EndTime.Sub(series.StartTime)
+	from, to = series.window(from, to)
+
+	byRecurrenceID := make(map[time.Time]OccurrenceException, len(exceptions))
+	for _, exception := range exceptions {
+		byRecurrenceID[exception.RecurrenceID.UTC()] = exception
+	}
+
+	var result []CreateEventResponse
+
+	add := func(start time.Time) {
+		occurrence := series
+		occurrence.StartTime = start
+		occurrence.EndTime = start.Add(duration)
+		occurrence.RecurrenceID = &start
+
+		if exception, ok := byRecurrenceID[start.UTC()]; ok {
+			if exception.Cancelled {
+				return
+			}
+
+			occurrence = ExceptionOccurrence(series, exception)
+		}
+
+		if occurrence.StartTime.Before(to) && occurrence.EndTime.After(from) {
+			result = append(result, occurrence)
+		}
+	}
+
+	seen := make(map[time.Time]bool)
+
+	for _, start := range set.Between(from.Add(-duration), to) {
+		seen[start.UTC()] = true
+		add(start)
+	}
+
+	// Occurrences moved into the window from outside of it.
+	for _, exception := range exceptions {
+		if seen[exception.RecurrenceID.UTC()] || !isOccurrence(set, exception.RecurrenceID) {
+			continue
+		}
+
+		add(exception.RecurrenceID)
+	}
+
+	return result, nil
+}
+
+// ExceptionOccurrence is the occurrence of series that exception moves or
+// edits, identified by its RecurrenceID.
+func ExceptionOccurrence(series CreateEventResponse, exception OccurrenceException) CreateEventResponse {
+	start := exception.RecurrenceID
+
+	occurrence := series
+	occurrence.StartTime = start
+	occurrence.EndTime = start.Add(series.EndTime.Sub(series.StartTime))
+	occurrence.RecurrenceID = &start
+
+	return applyException(occurrence, exception)
+}
+
+func applyException(occurrence CreateEventResponse, exception OccurrenceException) CreateEventResponse {
+	if exception.Title != "" {
+		occurrence.Title = exception.Title
+	}
+
+	if exception.Description != "" {
+		occurrence.Description = exception.Description
+	}
+
+	if !exception.StartTime.IsZero() {
+		occurrence.StartTime = exception.StartTime
+	}
+
+	if !exception.EndTime.IsZero() {
+		occurrence.EndTime = exception.EndTime
+	}
+
+	return occurrence
+}
+
+func isOccurrence(set recurrence.Set, start time.Time) bool {
+	return len(set.Between(start, start.Add(time.Nanosecond))) > 0
+}
+
+// splitSeries cuts a series at one of its occurrences. head is the series
+// truncated right before at, tail is a new series starting at it with the
+// changes applied. Zero fields in changes keep the series value.
+func splitSeries(series CreateEventResponse, at time.Time, changes CreateEventRequest) (CreateEventRequest, CreateEventRequest, error) {
+	if series.RRule == "" {
+		return CreateEventRequest{}, CreateEventRequest{}, fmt.Errorf("only series with an rrule can be split: %w", ErrInput)
+	}
+
+	set, err := series.recurrenceSet()
+	if err != nil {
+		return CreateEventRequest{}, CreateEventRequest{}, err
+	}
+
+	if !isOccurrence(set, at) {
+		return CreateEventRequest{}, CreateEventRequest{}, fmt.Errorf("occurrence not found: %w", ErrNotFound)
+	}
+
+	if !at.After(series.StartTime) {
+		return CreateEventRequest{}, CreateEventRequest{}, fmt.Errorf("cannot split a series at its first occurrence, update the event instead: %w", ErrInput)
+	}
+
+	rule := *set.Rule
+
+	// COUNT covers the whole series, so the tail keeps what the head did not use.
+	tailRule := rule
+	if rule.Count > 0 {
+		used := len(recurrence.Set{Start: set.Start, Rule: set.Rule}.Between(set.Start, at))
+		tailRule.Count = max(rule.Count-used, 1)
+	}
+
+	headRule := rule
+	headRule.Count = 0
+	headRule.Until = at.Add(-time.Second).UTC()
+
+	before, after := partitionDates(series.ExDates, at)
+	rBefore, rAfter := partitionDates(series.RDates, at)
+
+	head := CreateEventRequest{
+		Title:       series.Title,
+		Description: series.Description,
+		StartTime:   series.StartTime,
+		EndTime:     series.EndTime,
+		TimeZone:    series.TimeZone,
+		AllDay:      series.AllDay,
+		RRule:       headRule.String(),
+		ExDates:     before,
+		RDates:      rBefore,
+	}
+
+	tail := CreateEventRequest{
+		Title:       series.Title,
+		Description: series.Description,
+		StartTime:   at.In(set.Start.Location()),
+		EndTime:     at.Add(series.EndTime.Sub(series.StartTime)).In(set.Start.Location()),
+		TimeZone:    series.TimeZone,
+		AllDay:      series.AllDay,
+		Resource:    series.Resource,
+		Capacity:    series.Capacity,
+		CalendarID:  series.CalendarID,
+		RRule:       tailRule.String(),
+		ExDates:     after,
+		RDates:      rAfter,
+	}
+
+	if changes.Title != "" {
+		tail.Title = changes.Title
+	}
+
+	if changes.Description != "" {
+		tail.Description = changes.Description
+	}
+
+	if changes.RRule != "" {
+		tail.RRule = changes.RRule
+	}
+
+	if changes.TimeZone != "" {
+		tail.TimeZone = changes.TimeZone
+	}
+
+	if changes.Resource != "" {
+		tail.Resource = changes.Resource
+	}
+
+	if changes.Capacity != 0 {
+		tail.Capacity = changes.Capacity
+	}
+
+	if changes.CalendarID != "" {
+		tail.CalendarID = changes.CalendarID
+	}
+
+	// Moving the tail moves its EXDATEs and RDATEs along with it, and the new
+	// start says whether it is all-day.
+	if !changes.StartTime.IsZero() {
+		shift := changes.StartTime.Sub(tail.StartTime)
+		tail.StartTime = changes.StartTime
+		tail.AllDay = changes.AllDay
+		tail.EndTime = tail.EndTime.Add(shift)
+		tail.ExDates = shiftDates(tail.ExDates, shift)
+		tail.RDates = shiftDates(tail.RDates, shift)
+	}
+
+	if !changes.EndTime.IsZero() {
+		tail.EndTime = changes.EndTime
+	}
+
+	return head, tail, nil
+}
+
+func partitionDates(dates []time.Time, at time.Time) ([]time.Time, []time.Time) {
+	var before, after []time.Time
+
+	for _, date := range dates {
+		if date.Before(at) {
+			before = append(before, date)
+		} else {
+			after = append(after, date)
+		}
+	}
+
+	return before, after
+}
+
+func shiftDates(dates []time.Time, shift time.Duration) []time.Time {
+	var shifted []time.Time
+	for _, date := range dates {
+		shifted = append(shifted, date.Add(shift))
+	}
+
+	return shifted
+}
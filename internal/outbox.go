@@ -0,0 +1,96 @@
+package internal
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// DefaultRelayInterval is how often the relay polls the outbox when no interval is configured.
+const DefaultRelayInterval = time.Second
+
+const relayBatchSize = 100
+
+// Publisher sends outbox messages downstream. Messages are delivered at least
+// once: after a failure, or a crash between publishing and marking a message
+// delivered, it is published again. Consumers tell repeats apart by the id.
+type Publisher interface {
+	Publish(ctx context.Context, message OutboxMessage) error
+}
+
+type outboxStorage interface {
+	// DeliverOutbox hands the oldest messages due, at most limit and one per
+	// key, to deliver in order and returns how many it delivered. Failed
+	// messages are retried later.
+	DeliverOutbox(ctx context.Context, limit int, deliver func(context.Context, OutboxMessage) error) (int, error)
+}
+
+// eventMessage is the payload of the message written for every revision of an
+// event, on the topic "events.<action>".
+type eventMessage struct {
+	EventID    string                 `json:"event_id"`
+	Revision   int                    `json:"revision"`
+	Action     RevisionAction         `json:"action"`
+	Actor      string                 `json:"actor,omitempty"`
+	OccurredAt time.Time              `json:"occurred_at"`
+	Changes    map[string]FieldChange `json:"changes"`
+	Event      eventState             `json:"event"`
+}
+
+func eventTopic(action RevisionAction) string {
+	return "events." + string(action)
+}
+
+// Relay publishes the messages of the outbox, which storage writes in the
+// transaction of the change they describe.
+type Relay struct {
+	storage   outboxStorage
+	publisher Publisher
+	interval  time.Duration
+}
+
+// NewRelay polls the outbox every interval, DefaultRelayInterval when interval
+// is not positive.
+func NewRelay(storage outboxStorage, publisher Publisher, interval time.Duration) *Relay {
+	if interval <= 0 {
+		interval = DefaultRelayInterval
+	}
+
+	return &Relay{
+		storage:   storage,
+		publisher: publisher,
+		interval:  interval,
+	}
+}
+
+// Run publishes the outbox until ctx is done. A full batch is followed by the
+// next one right away, one with failures by the next poll.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		for ctx.Err() == nil {
+			delivered, err := r.Flush(ctx)
+			if err != nil {
+				log.Printf("outbox relay: %v", err)
+				break
+			}
+
+			if delivered < relayBatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Flush publishes one batch of the outbox and returns how many messages it published.
+func (r *Relay) Flush(ctx context.Context) (int, error) {
+	return r.storage.DeliverOutbox(ctx, relayBatchSize, r.publisher.Publish)
+}
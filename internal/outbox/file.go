@@ -0,0 +1,79 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal"
+)
+
+// Subject is where a message is published: its topic followed by its tenant,
+// e.g. "events.created.acme". It is a valid NATS subject, since tenant ids are
+// letters, digits, dashes and underscores.
+func Subject(message internal.OutboxMessage) string {
+	return message.Topic + "." + message.TenantID
+}
+
+// fileRecord is the line File writes for a message.
+type fileRecord struct {
+	ID        int64           `json:"id"`
+	Subject   string          `json:"subject"`
+	TenantID  string          `json:"tenant_id"`
+	Topic     string          `json:"topic"`
+	Key       string          `json:"key"`
+	CreatedAt time.Time       `json:"created_at"`
+	Payload   json.RawMessage `json:"payload"`
+}
+
+// File appends every message to a file as a line of JSON, with the subject NATS
+// would publish it on. A message is on disk when Publish returns.
+type File struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// OpenFile appends to the file at path, creating it if needed.
+func OpenFile(path string) (*File, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("opening outbox file: %w", err)
+	}
+
+	return &File{file: file}, nil
+}
+
+func (f *File) Publish(_ context.Context, message internal.OutboxMessage) error {
+	line, err := json.Marshal(fileRecord{
+		ID:        message.ID,
+		Subject:   Subject(message),
+		TenantID:  message.TenantID,
+		Topic:     message.Topic,
+		Key:       message.Key,
+		CreatedAt: message.CreatedAt,
+		Payload:   message.Payload,
+	})
+	if err != nil {
+		return fmt.Errorf("encoding message %d: %w", message.ID, err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("writing message %d: %w", message.ID, err)
+	}
+
+	if err := f.file.Sync(); err != nil {
+		return fmt.Errorf("syncing message %d: %w", message.ID, err)
+	}
+
+	return nil
+}
+
+func (f *File) Close() error {
+	return f.file.Close()
+}
@@ -0,0 +1,52 @@
+package outbox_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal"
+	"github.com/ObiaNzk/LTK-test-manu/internal/outbox"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFile_Publish(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	createdAt := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+
+	file, err := outbox.OpenFile(path)
+	require.NoError(t, err)
+
+	require.NoError(t, file.Publish(context.Background(), internal.OutboxMessage{
+		ID: 1, TenantID: "acme", Topic: "events.created", Key: "evt", Payload: []byte(`{"event_id":"evt"}`), CreatedAt: createdAt,
+	}))
+	require.NoError(t, file.Close())
+
+	// Reopening appends.
+	file, err = outbox.OpenFile(path)
+	require.NoError(t, err)
+
+	require.NoError(t, file.Publish(context.Background(), internal.OutboxMessage{
+		ID: 2, TenantID: "globex", Topic: "events.deleted", Key: "evt-2", Payload: []byte(`{"event_id":"evt-2"}`), CreatedAt: createdAt,
+	}))
+	require.NoError(t, file.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	require.Len(t, lines, 2)
+	require.JSONEq(t, `{"id":1,"subject":"events.created.acme","tenant_id":"acme","topic":"events.created","key":"evt",
+		"created_at":"2025-12-01T09:00:00Z","payload":{"event_id":"evt"}}`, lines[0])
+	require.JSONEq(t, `{"id":2,"subject":"events.deleted.globex","tenant_id":"globex","topic":"events.deleted","key":"evt-2",
+		"created_at":"2025-12-01T09:00:00Z","payload":{"event_id":"evt-2"}}`, lines[1])
+}
+
+func TestOpenFile_Error(t *testing.T) {
+	_, err := outbox.OpenFile(filepath.Join(t.TempDir(), "missing", "outbox.jsonl"))
+
+	require.ErrorContains(t, err, "opening outbox file")
+}
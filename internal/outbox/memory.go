@@ -0,0 +1,39 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal"
+)
+
+// Memory keeps the messages it publishes, for tests and local runs without a
+// broker. They are lost when the process exits.
+type Memory struct {
+	mu       sync.Mutex
+	messages []internal.OutboxMessage
+}
+
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+func (m *Memory) Publish(_ context.Context, message internal.OutboxMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, message)
+
+	return nil
+}
+
+// Messages returns the messages published so far, oldest first.
+func (m *Memory) Messages() []internal.OutboxMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	messages := make([]internal.OutboxMessage, len(m.messages))
+	copy(messages, m.messages)
+
+	return messages
+}
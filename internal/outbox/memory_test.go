@@ -0,0 +1,27 @@
+package outbox_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal"
+	"github.com/ObiaNzk/LTK-test-manu/internal/outbox"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemory_Publish(t *testing.T) {
+	memory := outbox.NewMemory()
+
+	first := internal.OutboxMessage{ID: 1, TenantID: "acme", Topic: "events.created", Key: "evt"}
+	second := internal.OutboxMessage{ID: 2, TenantID: "acme", Topic: "events.updated", Key: "evt"}
+
+	require.NoError(t, memory.Publish(context.Background(), first))
+	require.NoError(t, memory.Publish(context.Background(), second))
+
+	messages := memory.Messages()
+	require.Equal(t, []internal.OutboxMessage{first, second}, messages)
+
+	// The returned slice is a copy.
+	messages[0].Key = "changed"
+	require.Equal(t, "evt", memory.Messages()[0].Key)
+}
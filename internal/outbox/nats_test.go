@@ -0,0 +1,202 @@
+package outbox_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal"
+	"github.com/ObiaNzk/LTK-test-manu/internal/outbox"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/require"
+)
+
+// runNATS starts an embedded NATS server with JetStream and a stream taking
+// the events subjects, created by a client connecting with connOpts.
+func runNATS(t *testing.T, opts server.Options, connOpts ...nats.Option) (*server.Server, jetstream.Stream) {
+	opts.Host = "127.0.0.1"
+	opts.Port = -1
+	opts.JetStream = true
+	opts.StoreDir = t.TempDir()
+	opts.NoLog = true
+	opts.NoSigs = true
+
+	srv, err := server.NewServer(&opts)
+	require.NoError(t, err)
+
+	go srv.Start()
+	t.Cleanup(srv.Shutdown)
+	require.True(t, srv.ReadyForConnections(5*time.Second))
+
+	conn, err := nats.Connect(srv.ClientURL(), connOpts...)
+	require.NoError(t, err)
+	t.Cleanup(conn.Close)
+
+	js, err := jetstream.New(conn)
+	require.NoError(t, err)
+
+	stream, err := js.CreateStream(context.Background(), jetstream.StreamConfig{
+		Name:       "EVENTS",
+		Subjects:   []string{"events.>"},
+		Duplicates: time.Minute,
+	})
+	require.NoError(t, err)
+
+	return srv, stream
+}
+
+func TestNATS_Publish(t *testing.T) {
+	srv, stream := runNATS(t, server.Options{})
+
+	publisher, err := outbox.NewNATS(outbox.NATSConfig{URL: srv.ClientURL()})
+	require.NoError(t, err)
+	defer publisher.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	created := internal.OutboxMessage{ID: 7, TenantID: "acme", Topic: "events.created", Key: "evt", Payload: []byte(`{"event_id":"evt"}`)}
+
+	require.NoError(t, publisher.Publish(ctx, created))
+	require.NoError(t, publisher.Publish(ctx, internal.OutboxMessage{
+		ID: 8, TenantID: "acme", Topic: "events.updated", Key: "evt", Payload: []byte(`{}`),
+	}))
+
+	// A message published again after a lost mark is dropped by the stream.
+	require.NoError(t, publisher.Publish(ctx, created))
+
+	info, err := stream.Info(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), info.State.Msgs)
+
+	first, err := stream.GetMsg(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, "events.created.acme", first.Subject)
+	require.Equal(t, "7", first.Header.Get(jetstream.MsgIDHeader))
+	require.Equal(t, `{"event_id":"evt"}`, string(first.Data))
+
+	second, err := stream.GetMsg(ctx, 2)
+	require.NoError(t, err)
+	require.Equal(t, "events.updated.acme", second.Subject)
+	require.Equal(t, "8", second.Header.Get(jetstream.MsgIDHeader))
+}
+
+func TestNATS_NoStream(t *testing.T) {
+	srv, _ := runNATS(t, server.Options{})
+
+	publisher, err := outbox.NewNATS(outbox.NATSConfig{URL: srv.ClientURL()})
+	require.NoError(t, err)
+	defer publisher.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Nothing stores the message, so it was not published.
+	err = publisher.Publish(ctx, internal.OutboxMessage{ID: 1, TenantID: "acme", Topic: "audit.created", Payload: []byte(`{}`)})
+
+	require.ErrorContains(t, err, "publishing message 1 to nats")
+}
+
+func TestNATS_Credentials(t *testing.T) {
+	srv, stream := runNATS(t, server.Options{Username: "relay", Password: "secret"}, nats.UserInfo("relay", "secret"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	message := internal.OutboxMessage{ID: 1, TenantID: "acme", Topic: "events.created", Payload: []byte(`{}`)}
+
+	url := "nats://relay:secret@" + srv.Addr().String()
+
+	publisher, err := outbox.NewNATS(outbox.NATSConfig{URL: url})
+	require.NoError(t, err)
+	defer publisher.Close()
+
+	require.NoError(t, publisher.Publish(ctx, message))
+
+	info, err := stream.Info(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), info.State.Msgs)
+}
+
+func TestNATS_TLS(t *testing.T) {
+	cert, caFile := selfSigned(t)
+
+	srv, stream := runNATS(t, server.Options{
+		TLS:        true,
+		TLSTimeout: 2,
+		TLSConfig:  &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12},
+	}, nats.RootCAs(caFile))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	publisher, err := outbox.NewNATS(outbox.NATSConfig{URL: "tls://" + srv.Addr().String(), CAFile: caFile})
+	require.NoError(t, err)
+	defer publisher.Close()
+
+	require.NoError(t, publisher.Publish(ctx, internal.OutboxMessage{ID: 1, TenantID: "acme", Topic: "events.created", Payload: []byte(`{}`)}))
+
+	info, err := stream.Info(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), info.State.Msgs)
+}
+
+// selfSigned makes a certificate for 127.0.0.1 and writes it to a file, for
+// clients to trust it.
+func selfSigned(t *testing.T) (tls.Certificate, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "nats"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, caFile
+}
+
+func TestNATS_Unreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	addr := listener.Addr().String()
+	listener.Close()
+
+	// The connection is retried in the background; publishing fails meanwhile.
+	publisher, err := outbox.NewNATS(outbox.NATSConfig{URL: "nats://" + addr})
+	require.NoError(t, err)
+	defer publisher.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	err = publisher.Publish(ctx, internal.OutboxMessage{ID: 1, TenantID: "acme", Topic: "events.created"})
+
+	require.ErrorContains(t, err, "publishing message 1 to nats")
+}
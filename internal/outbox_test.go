@@ -0,0 +1,118 @@
+package internal_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal"
+	"github.com/ObiaNzk/LTK-test-manu/internal/mocks"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+//go:generate mockgen -source=outbox.go -destination=mocks/mock_outbox.go -package=mocks
+
+type RelayTestSuite struct {
+	suite.Suite
+	ctx           context.Context
+	ctrl          *gomock.Controller
+	mockStorage   *mocks.MockoutboxStorage
+	mockPublisher *mocks.MockPublisher
+	relay         *internal.Relay
+}
+
+func (s *RelayTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.ctrl = gomock.NewController(s.T())
+	s.mockStorage = mocks.NewMockoutboxStorage(s.ctrl)
+	s.mockPublisher = mocks.NewMockPublisher(s.ctrl)
+	s.relay = internal.NewRelay(s.mockStorage, s.mockPublisher, time.Millisecond)
+}
+
+func (s *RelayTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+// deliver stands in for the storage: it hands messages to the relay the way
+// DeliverOutbox does, one after another until the first failure.
+func deliver(messages ...internal.OutboxMessage) func(context.Context, int, func(context.Context, internal.OutboxMessage) error) (int, error) {
+	return func(ctx context.Context, _ int, publish func(context.Context, internal.OutboxMessage) error) (int, error) {
+		for i, message := range messages {
+			if err := publish(ctx, message); err != nil {
+				return i, err
+			}
+		}
+
+		return len(messages), nil
+	}
+}
+
+func (s *RelayTestSuite) TestFlush_Publishes() {
+	first := internal.OutboxMessage{ID: 1, TenantID: "acme", Topic: "events.created", Key: "evt"}
+	second := internal.OutboxMessage{ID: 2, TenantID: "acme", Topic: "events.updated", Key: "evt"}
+
+	s.mockStorage.EXPECT().
+		DeliverOutbox(gomock.Any(), 100, gomock.Any()).
+		DoAndReturn(deliver(first, second))
+
+	gomock.InOrder(
+		s.mockPublisher.EXPECT().Publish(gomock.Any(), first).Return(nil),
+		s.mockPublisher.EXPECT().Publish(gomock.Any(), second).Return(nil),
+	)
+
+	delivered, err := s.relay.Flush(s.ctx)
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 2, delivered)
+}
+
+func (s *RelayTestSuite) TestFlush_PublishError() {
+	message := internal.OutboxMessage{ID: 1, TenantID: "acme", Topic: "events.created", Key: "evt"}
+
+	s.mockStorage.EXPECT().
+		DeliverOutbox(gomock.Any(), 100, gomock.Any()).
+		DoAndReturn(deliver(message))
+	s.mockPublisher.EXPECT().Publish(gomock.Any(), message).Return(errors.New("broker down"))
+
+	delivered, err := s.relay.Flush(s.ctx)
+
+	require.EqualError(s.T(), err, "broker down")
+	require.Zero(s.T(), delivered)
+}
+
+func (s *RelayTestSuite) TestRun_RetriesUntilCancelled() {
+	ctx, cancel := context.WithCancel(s.ctx)
+	defer cancel()
+
+	// A full batch is followed by the next one right away. The failure waits for
+	// the next poll, which delivers the rest and stops the relay.
+	gomock.InOrder(
+		s.mockStorage.EXPECT().DeliverOutbox(gomock.Any(), 100, gomock.Any()).Return(100, nil),
+		s.mockStorage.EXPECT().DeliverOutbox(gomock.Any(), 100, gomock.Any()).Return(3, errors.New("broker down")),
+		s.mockStorage.EXPECT().DeliverOutbox(gomock.Any(), 100, gomock.Any()).
+			DoAndReturn(func(context.Context, int, func(context.Context, internal.OutboxMessage) error) (int, error) {
+				cancel()
+				return 1, nil
+			}),
+	)
+
+	done := make(chan struct{})
+
+	go func() {
+		s.relay.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		s.T().Fatal("relay did not stop")
+	}
+}
+
+func TestRelayTestSuite(t *testing.T) {
+	suite.Run(t, new(RelayTestSuite))
+}
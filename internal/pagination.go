@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+// Cursor is the keyset position of the last event of a page. Listings are
+// ordered by (start_time, id), so the pair is unique and stable.
+type Cursor struct {
+	StartTime time.Time `json:"s"`
+	ID        string    `json:"i"`
+}
+
+// Encode returns the opaque representation handed to clients.
+func (c Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func DecodeCursor(value string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("invalid cursor: %w", ErrInput)
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.ID == "" || cursor.StartTime.IsZero() {
+		return Cursor{}, fmt.Errorf("invalid cursor: %w", ErrInput)
+	}
+
+	return cursor, nil
+}
+
+// PageRequest is what callers ask for: a page size and the opaque cursor
+// returned by the previous page, if any.
+type PageRequest struct {
+	Limit  int
+	Cursor string
+}
+
+// Page is the bounded read storage performs.
+type Page struct {
+	Limit int
+	After *Cursor
+}
+
+// EventsPage is one page of events. When its series were not expanded into
+// occurrences, Exceptions holds their occurrence exceptions.
+type EventsPage struct {
+	Events     []CreateEventResponse
+	NextCursor string
+	Exceptions []OccurrenceException
+}
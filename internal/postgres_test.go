@@ -0,0 +1,112 @@
+package internal_test
+
+import (
+	"database/sql"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// appRole is the role the API connects as. Unlike the superuser running the
+// migrations, it is subject to row level security.
+const appRole = "events_app"
+
+// postgresSuite runs against a real Postgres, for what sqlmock cannot check.
+// It is skipped unless TEST_DATABASE_URL is set, and works in a schema of its
+// own that is dropped afterwards. Migrations run as the TEST_DATABASE_URL user
+// and storage as appRole.
+type postgresSuite struct {
+	suite.Suite
+	admin   *sql.DB
+	db      *sql.DB
+	schema  string
+	storage *internal.Storage
+}
+
+func (s *postgresSuite) setupPostgres(prefix string) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		s.T().Skip("TEST_DATABASE_URL is not set")
+	}
+
+	admin, err := sql.Open("postgres", dsn)
+	require.NoError(s.T(), err)
+
+	s.admin = admin
+	s.schema = prefix + "_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+
+	_, err = admin.Exec("CREATE SCHEMA " + s.schema)
+	require.NoError(s.T(), err)
+
+	migrator, err := sql.Open("postgres", withSettings(dsn, map[string]string{"search_path": s.schema + ",public"}))
+	require.NoError(s.T(), err)
+
+	defer migrator.Close()
+
+	migrations, err := filepath.Glob("migrations/*.sql")
+	require.NoError(s.T(), err)
+
+	sort.Strings(migrations)
+
+	for _, migration := range migrations {
+		query, err := os.ReadFile(migration)
+		require.NoError(s.T(), err)
+
+		_, err = migrator.Exec(string(query))
+		require.NoError(s.T(), err, migration)
+	}
+
+	_, err = admin.Exec("GRANT USAGE ON SCHEMA " + s.schema + " TO " + appRole)
+	require.NoError(s.T(), err)
+
+	db, err := sql.Open("postgres", withSettings(dsn, map[string]string{"search_path": s.schema + ",public", "role": appRole}))
+	require.NoError(s.T(), err)
+
+	db.SetMaxOpenConns(25)
+	s.db = db
+	s.storage = internal.NewStorage(db)
+}
+
+func (s *postgresSuite) TearDownSuite() {
+	if s.db != nil {
+		s.db.Close()
+	}
+
+	if s.admin != nil {
+		s.admin.Exec("DROP SCHEMA " + s.schema + " CASCADE")
+		s.admin.Close()
+	}
+}
+
+// withSettings adds run-time settings to a URL or key=value DSN. lib/pq sends
+// unknown parameters to the server as settings of the session.
+func withSettings(dsn string, settings map[string]string) string {
+	if !strings.HasPrefix(dsn, "postgres://") && !strings.HasPrefix(dsn, "postgresql://") {
+		for name, value := range settings {
+			dsn += " " + name + "=" + value
+		}
+
+		return dsn
+	}
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return dsn
+	}
+
+	query := u.Query()
+	for name, value := range settings {
+		query.Set(name, value)
+	}
+
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
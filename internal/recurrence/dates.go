@@ -0,0 +1,56 @@
+package recurrence
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	dateTimeUTCLayout = "20060102T150405Z"
+	dateTimeLayout    = "20060102T150405"
+)
+
+// FormatDateTime renders an instant as an RFC 5545 UTC DATE-TIME.
+func FormatDateTime(t time.Time) string {
+	return t.UTC().Format(dateTimeUTCLayout)
+}
+
+// ParseDateTime parses an RFC 5545 DATE-TIME. Values without the "Z" suffix are
+// floating and read in loc.
+func ParseDateTime(raw string, loc *time.Location) (time.Time, error) {
+	if strings.HasSuffix(raw, "Z") {
+		return time.Parse(dateTimeUTCLayout, raw)
+	}
+
+	return time.ParseInLocation(dateTimeLayout, raw, loc)
+}
+
+// FormatDateList renders instants the way EXDATE and RDATE list them.
+func FormatDateList(dates []time.Time) string {
+	items := make([]string, 0, len(dates))
+	for _, date := range dates {
+		items = append(items, FormatDateTime(date))
+	}
+
+	return strings.Join(items, ",")
+}
+
+func ParseDateList(raw string) ([]time.Time, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	var dates []time.Time
+
+	for _, item := range strings.Split(raw, ",") {
+		date, err := ParseDateTime(strings.TrimSpace(item), time.UTC)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q: %w", item, err)
+		}
+
+		dates = append(dates, date)
+	}
+
+	return dates, nil
+}
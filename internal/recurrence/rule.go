@@ -0,0 +1,297 @@
+// Package recurrence implements the subset of RFC 5545 recurrence rules the
+// events API supports: DAILY, WEEKLY, MONTHLY and YEARLY rules with INTERVAL,
+// COUNT, UNTIL, BYDAY, BYMONTHDAY, BYMONTH and WKST.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// WeekdayNum is a BYDAY entry. N selects the nth weekday of the month (or year)
+// and counts from the end when negative. Zero means every such weekday.
+type WeekdayNum struct {
+	Weekday time.Weekday
+	N       int
+}
+
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int
+	Until      time.Time
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []time.Month
+	WeekStart  time.Weekday
+}
+
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+var weekdayNames = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// ParseWeekday reads a weekday code such as "MO".
+func ParseWeekday(code string) (time.Weekday, bool) {
+	weekday, ok := weekdayCodes[code]
+
+	return weekday, ok
+}
+
+// ParseRule parses an RRULE value such as "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10".
+// A leading "RRULE:" is accepted.
+func ParseRule(value string) (Rule, error) {
+	value = strings.TrimSpace(value)
+	if len(value) >= 6 && strings.EqualFold(value[:6], "RRULE:") {
+		value = value[6:]
+	}
+
+	rule := Rule{Interval: 1, WeekStart: time.Monday}
+	seen := map[string]bool{}
+
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+
+		key, raw, ok := strings.Cut(part, "=")
+		if !ok || raw == "" {
+			return Rule{}, fmt.Errorf("malformed part %q: %w", part, ErrInvalidRule)
+		}
+
+		key = strings.ToUpper(key)
+		raw = strings.ToUpper(raw)
+
+		if seen[key] {
+			return Rule{}, fmt.Errorf("duplicated %s: %w", key, ErrInvalidRule)
+		}
+
+		seen[key] = true
+
+		var err error
+
+		switch key {
+		case "FREQ":
+			rule.Freq, err = parseFrequency(raw)
+		case "INTERVAL":
+			rule.Interval, err = parsePositive(raw)
+		case "COUNT":
+			rule.Count, err = parsePositive(raw)
+		case "UNTIL":
+			rule.Until, err = parseUntil(raw)
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(raw)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseIntList(raw, -31, 31)
+		case "BYMONTH":
+			var months []int
+			months, err = parseIntList(raw, 1, 12)
+			for _, month := range months {
+				rule.ByMonth = append(rule.ByMonth, time.Month(month))
+			}
+		case "WKST":
+			weekday, ok := weekdayCodes[raw]
+			if !ok {
+				err = fmt.Errorf("unknown weekday %q", raw)
+			}
+			rule.WeekStart = weekday
+		default:
+			return Rule{}, fmt.Errorf("unsupported part %s: %w", key, ErrInvalidRule)
+		}
+
+		if err != nil {
+			return Rule{}, fmt.Errorf("%s: %s: %w", key, err.Error(), ErrInvalidRule)
+		}
+	}
+
+	if err := rule.validate(); err != nil {
+		return Rule{}, err
+	}
+
+	return rule, nil
+}
+
+func (r Rule) validate() error {
+	switch r.Freq {
+	case Daily, Weekly, Monthly, Yearly:
+	case "":
+		return fmt.Errorf("FREQ is required: %w", ErrInvalidRule)
+	default:
+		return fmt.Errorf("unsupported FREQ %s: %w", r.Freq, ErrInvalidRule)
+	}
+
+	if r.Count > 0 && !r.Until.IsZero() {
+		return fmt.Errorf("COUNT and UNTIL cannot be combined: %w", ErrInvalidRule)
+	}
+
+	if r.Freq == Daily || r.Freq == Weekly {
+		for _, day := range r.ByDay {
+			if day.N != 0 {
+				return fmt.Errorf("BYDAY ordinals need a MONTHLY or YEARLY rule: %w", ErrInvalidRule)
+			}
+		}
+	}
+
+	if r.Freq == Weekly && len(r.ByMonthDay) > 0 {
+		return fmt.Errorf("BYMONTHDAY cannot be used with a WEEKLY rule: %w", ErrInvalidRule)
+	}
+
+	return nil
+}
+
+// String formats the rule back to its RRULE value, without the "RRULE:" prefix.
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+FormatDateTime(r.Until))
+	}
+
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, day := range r.ByDay {
+			code := weekdayNames[day.Weekday]
+			if day.N != 0 {
+				code = strconv.Itoa(day.N) + code
+			}
+			days = append(days, code)
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+
+	if len(r.ByMonthDay) > 0 {
+		parts = append(parts, "BYMONTHDAY="+joinInts(r.ByMonthDay))
+	}
+
+	if len(r.ByMonth) > 0 {
+		months := make([]int, 0, len(r.ByMonth))
+		for _, month := range r.ByMonth {
+			months = append(months, int(month))
+		}
+		parts = append(parts, "BYMONTH="+joinInts(months))
+	}
+
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+weekdayNames[r.WeekStart])
+	}
+
+	return strings.Join(parts, ";")
+}
+
+func parseFrequency(raw string) (Frequency, error) {
+	switch Frequency(raw) {
+	case Daily, Weekly, Monthly, Yearly:
+		return Frequency(raw), nil
+	}
+
+	return "", fmt.Errorf("unsupported frequency %q", raw)
+}
+
+func parsePositive(raw string) (int, error) {
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 1 {
+		return 0, fmt.Errorf("%q should be a positive integer", raw)
+	}
+
+	return value, nil
+}
+
+// parseUntil accepts a UTC DATE-TIME, a floating DATE-TIME (read as UTC) or a
+// DATE, which includes the whole day.
+func parseUntil(raw string) (time.Time, error) {
+	if len(raw) == len("20060102") {
+		date, err := time.Parse("20060102", raw)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		return date.Add(24*time.Hour - time.Second), nil
+	}
+
+	return ParseDateTime(raw, time.UTC)
+}
+
+func parseByDay(raw string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+
+	for _, item := range strings.Split(raw, ",") {
+		if len(item) < 2 {
+			return nil, fmt.Errorf("invalid weekday %q", item)
+		}
+
+		weekday, ok := weekdayCodes[item[len(item)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday %q", item)
+		}
+
+		day := WeekdayNum{Weekday: weekday}
+
+		if prefix := item[:len(item)-2]; prefix != "" {
+			n, err := strconv.Atoi(prefix)
+			if err != nil || n == 0 || n < -53 || n > 53 {
+				return nil, fmt.Errorf("invalid weekday ordinal %q", item)
+			}
+			day.N = n
+		}
+
+		days = append(days, day)
+	}
+
+	return days, nil
+}
+
+func parseIntList(raw string, min, max int) ([]int, error) {
+	var values []int
+
+	for _, item := range strings.Split(raw, ",") {
+		value, err := strconv.Atoi(item)
+		if err != nil || value == 0 || value < min || value > max {
+			return nil, fmt.Errorf("%q should be between %d and %d", item, min, max)
+		}
+
+		values = append(values, value)
+	}
+
+	sort.Ints(values)
+
+	return values, nil
+}
+
+func joinInts(values []int) string {
+	items := make([]string, 0, len(values))
+	for _, value := range values {
+		items = append(items, strconv.Itoa(value))
+	}
+
+	return strings.Join(items, ",")
+}
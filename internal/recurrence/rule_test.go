@@ -0,0 +1,107 @@
+package recurrence_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal/recurrence"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type RuleTestSuite struct {
+	suite.Suite
+}
+
+func (s *RuleTestSuite) TestParseRule_Full() {
+	rule, err := recurrence.ParseRule("RRULE:FREQ=MONTHLY;INTERVAL=2;COUNT=10;BYDAY=1FR,-1MO;BYMONTHDAY=1,-1;BYMONTH=6,7;WKST=SU")
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), recurrence.Monthly, rule.Freq)
+	require.Equal(s.T(), 2, rule.Interval)
+	require.Equal(s.T(), 10, rule.Count)
+	require.Equal(s.T(), []recurrence.WeekdayNum{{Weekday: time.Friday, N: 1}, {Weekday: time.Monday, N: -1}}, rule.ByDay)
+	require.Equal(s.T(), []int{-1, 1}, rule.ByMonthDay)
+	require.Equal(s.T(), []time.Month{time.June, time.July}, rule.ByMonth)
+	require.Equal(s.T(), time.Sunday, rule.WeekStart)
+}
+
+func (s *RuleTestSuite) TestParseRule_Defaults() {
+	rule, err := recurrence.ParseRule("freq=weekly")
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), recurrence.Weekly, rule.Freq)
+	require.Equal(s.T(), 1, rule.Interval)
+	require.Equal(s.T(), time.Monday, rule.WeekStart)
+}
+
+func (s *RuleTestSuite) TestParseRule_Until() {
+	rule, err := recurrence.ParseRule("FREQ=DAILY;UNTIL=20251224T090000Z")
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), time.Date(2025, 12, 24, 9, 0, 0, 0, time.UTC), rule.Until)
+
+	rule, err = recurrence.ParseRule("FREQ=DAILY;UNTIL=20251224")
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), time.Date(2025, 12, 24, 23, 59, 59, 0, time.UTC), rule.Until)
+}
+
+func (s *RuleTestSuite) TestParseRule_Errors() {
+	cases := map[string]string{
+		"missing freq":           "COUNT=3",
+		"unknown freq":           "FREQ=HOURLY",
+		"count and until":        "FREQ=DAILY;COUNT=3;UNTIL=20251224T090000Z",
+		"zero interval":          "FREQ=DAILY;INTERVAL=0",
+		"bad weekday":            "FREQ=WEEKLY;BYDAY=XX",
+		"ordinal in weekly rule": "FREQ=WEEKLY;BYDAY=1MO",
+		"monthday in weekly":     "FREQ=WEEKLY;BYMONTHDAY=1",
+		"monthday out of range":  "FREQ=MONTHLY;BYMONTHDAY=32",
+		"month out of range":     "FREQ=YEARLY;BYMONTH=13",
+		"unsupported part":       "FREQ=MONTHLY;BYSETPOS=-1",
+		"duplicated part":        "FREQ=DAILY;FREQ=WEEKLY",
+		"malformed part":         "FREQ=DAILY;COUNT",
+	}
+
+	for name, value := range cases {
+		_, err := recurrence.ParseRule(value)
+
+		require.ErrorIs(s.T(), err, recurrence.ErrInvalidRule, name)
+	}
+}
+
+func (s *RuleTestSuite) TestRuleString_RoundTrip() {
+	values := []string{
+		"FREQ=DAILY",
+		"FREQ=WEEKLY;INTERVAL=2;UNTIL=20251224T000000Z;BYDAY=MO,WE,FR;WKST=SU",
+		"FREQ=MONTHLY;COUNT=10;BYDAY=1FR",
+		"FREQ=MONTHLY;BYMONTHDAY=-1",
+		"FREQ=YEARLY;COUNT=10;BYMONTH=6,7",
+	}
+
+	for _, value := range values {
+		rule, err := recurrence.ParseRule(value)
+		require.NoError(s.T(), err)
+
+		require.Equal(s.T(), value, rule.String())
+	}
+}
+
+func (s *RuleTestSuite) TestDateList_RoundTrip() {
+	dates := []time.Time{
+		time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC),
+		time.Date(2025, 12, 8, 9, 0, 0, 0, time.UTC),
+	}
+
+	formatted := recurrence.FormatDateList(dates)
+	require.Equal(s.T(), "20251201T090000Z,20251208T090000Z", formatted)
+
+	parsed, err := recurrence.ParseDateList(formatted)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), dates, parsed)
+
+	_, err = recurrence.ParseDateList("yesterday")
+	require.Error(s.T(), err)
+}
+
+func TestRuleTestSuite(t *testing.T) {
+	suite.Run(t, new(RuleTestSuite))
+}
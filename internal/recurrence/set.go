@@ -0,0 +1,358 @@
+package recurrence
+
+import (
+	"sort"
+	"time"
+)
+
+// maxPeriods bounds the expansion of rules that can never produce another
+// occurrence, e.g. FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30.
+const maxPeriods = 100000
+
+// Set is a recurrence set: DTSTART, an optional RRULE and the RDATE/EXDATE lists.
+// Occurrences keep the wall clock time of Start in its location, so a weekly
+// 09:00 meeting stays at 09:00 across DST changes.
+type Set struct {
+	Start   time.Time
+	Rule    *Rule
+	RDates  []time.Time
+	ExDates []time.Time
+}
+
+// Between returns the occurrence start times in [from, to), in ascending order.
+func (s Set) Between(from, to time.Time) []time.Time {
+	var occurrences []time.Time
+
+	s.each(to, func(occurrence time.Time) bool {
+		if !occurrence.Before(from) {
+			occurrences = append(occurrences, occurrence)
+		}
+
+		return true
+	})
+
+	for _, date := range s.RDates {
+		if !date.Before(from) && date.Before(to) {
+			occurrences = append(occurrences, date)
+		}
+	}
+
+	return s.finish(occurrences)
+}
+
+// Last returns the last occurrence of a finite set. It reports false when the
+// rule repeats forever.
+func (s Set) Last() (time.Time, bool) {
+	if s.Rule != nil && s.Rule.Count == 0 && s.Rule.Until.IsZero() {
+		return time.Time{}, false
+	}
+
+	var occurrences []time.Time
+
+	s.each(time.Time{}, func(occurrence time.Time) bool {
+		occurrences = append(occurrences, occurrence)
+		return true
+	})
+
+	occurrences = s.finish(append(occurrences, s.RDates...))
+	if len(occurrences) == 0 {
+		return time.Time{}, false
+	}
+
+	return occurrences[len(occurrences)-1], true
+}
+
+// finish sorts occurrences, drops duplicates and removes the EXDATEs.
+func (s Set) finish(occurrences []time.Time) []time.Time {
+	sort.Slice(occurrences, func(i, j int) bool { return occurrences[i].Before(occurrences[j]) })
+
+	result := occurrences[:0]
+
+	for _, occurrence := range occurrences {
+		if len(result) > 0 && result[len(result)-1].Equal(occurrence) {
+			continue
+		}
+
+		if s.excluded(occurrence) {
+			continue
+		}
+
+		result = append(result, occurrence)
+	}
+
+	return result
+}
+
+func (s Set) excluded(occurrence time.Time) bool {
+	for _, date := range s.ExDates {
+		if date.Equal(occurrence) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// each yields DTSTART and then every rule occurrence in ascending order until
+// COUNT or UNTIL is reached, yield returns false, or an occurrence would start
+// at or after to. A zero to means no upper bound.
+func (s Set) each(to time.Time, yield func(time.Time) bool) {
+	bounded := !to.IsZero()
+
+	if bounded && !s.Start.Before(to) {
+		return
+	}
+
+	if s.Rule == nil {
+		yield(s.Start)
+		return
+	}
+
+	rule := *s.Rule
+	emitted := 1
+
+	if !yield(s.Start) || (rule.Count > 0 && emitted >= rule.Count) {
+		return
+	}
+
+	for period := 0; period < maxPeriods; period++ {
+		periodStart, candidates := rule.period(s.Start, period)
+
+		if bounded && !periodStart.Before(to) {
+			return
+		}
+
+		for _, candidate := range candidates {
+			if !candidate.After(s.Start) {
+				continue
+			}
+
+			if !rule.Until.IsZero() && candidate.After(rule.Until) {
+				return
+			}
+
+			if bounded && !candidate.Before(to) {
+				return
+			}
+
+			emitted++
+
+			if !yield(candidate) {
+				return
+			}
+
+			if rule.Count > 0 && emitted >= rule.Count {
+				return
+			}
+		}
+	}
+}
+
+// period returns the start of the nth period of the rule and its candidate
+// occurrences, sorted.
+func (r Rule) period(start time.Time, n int) (time.Time, []time.Time) {
+	year, month, day := start.Date()
+	loc := start.Location()
+	step := n * r.Interval
+
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), loc)
+	}
+
+	var candidates []time.Time
+
+	switch r.Freq {
+	case Daily:
+		date := time.Date(year, month, day+step, 0, 0, 0, 0, loc)
+		if r.matchesMonth(date.Month()) && r.matchesMonthDay(date) && r.matchesWeekday(date.Weekday()) {
+			candidates = append(candidates, at(date.Year(), date.Month(), date.Day()))
+		}
+
+		return date, candidates
+
+	case Weekly:
+		offset := (int(start.Weekday()) - int(r.WeekStart) + 7) % 7
+		weekStart := time.Date(year, month, day-offset+7*step, 0, 0, 0, 0, loc)
+
+		for i := 0; i < 7; i++ {
+			date := weekStart.AddDate(0, 0, i)
+
+			wanted := date.Weekday() == start.Weekday()
+			if len(r.ByDay) > 0 {
+				wanted = r.matchesWeekday(date.Weekday())
+			}
+
+			if wanted && r.matchesMonth(date.Month()) {
+				candidates = append(candidates, at(date.Year(), date.Month(), date.Day()))
+			}
+		}
+
+		return weekStart, candidates
+
+	case Monthly:
+		first := time.Date(year, month+time.Month(step), 1, 0, 0, 0, 0, loc)
+
+		for _, d := range r.monthDays(first.Year(), first.Month(), day) {
+			candidates = append(candidates, at(first.Year(), first.Month(), d))
+		}
+
+		return first, candidates
+
+	default:
+		first := time.Date(year+step, time.January, 1, 0, 0, 0, 0, loc)
+
+		for _, date := range r.yearDays(first.Year(), month, day) {
+			candidates = append(candidates, at(date.Year(), date.Month(), date.Day()))
+		}
+
+		// BYMONTH may list months in any order or more than once.
+		sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+
+		unique := candidates[:0]
+		for _, candidate := range candidates {
+			if len(unique) == 0 || !unique[len(unique)-1].Equal(candidate) {
+				unique = append(unique, candidate)
+			}
+		}
+
+		return first, unique
+	}
+}
+
+// monthDays expands a month into the days the rule selects. startDay is the day
+// of DTSTART, used when neither BYMONTHDAY nor BYDAY is set.
+func (r Rule) monthDays(year int, month time.Month, startDay int) []int {
+	if !r.matchesMonth(month) {
+		return nil
+	}
+
+	last := daysIn(year, month)
+
+	var days []int
+
+	switch {
+	case len(r.ByMonthDay) > 0:
+		for d := 1; d <= last; d++ {
+			date := time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+			if r.matchesMonthDay(date) && (len(r.ByDay) == 0 || r.matchesOrdinal(date.Weekday(), (d-1)/7+1, -((last-d)/7+1))) {
+				days = append(days, d)
+			}
+		}
+
+	case len(r.ByDay) > 0:
+		for d := 1; d <= last; d++ {
+			weekday := time.Date(year, month, d, 0, 0, 0, 0, time.UTC).Weekday()
+			if r.matchesOrdinal(weekday, (d-1)/7+1, -((last-d)/7 + 1)) {
+				days = append(days, d)
+			}
+		}
+
+	default:
+		if startDay <= last {
+			days = append(days, startDay)
+		}
+	}
+
+	return days
+}
+
+// yearDays expands a year into the dates the rule selects.
+func (r Rule) yearDays(year int, startMonth time.Month, startDay int) []time.Time {
+	var dates []time.Time
+
+	// BYDAY alone is relative to the whole year, e.g. 20MO is the 20th Monday.
+	if len(r.ByDay) > 0 && len(r.ByMonth) == 0 && len(r.ByMonthDay) == 0 {
+		first := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		total := first.AddDate(1, 0, 0).Sub(first).Hours() / 24
+
+		for i := 0; i < int(total); i++ {
+			date := first.AddDate(0, 0, i)
+			if r.matchesOrdinal(date.Weekday(), i/7+1, -((int(total)-1-i)/7 + 1)) {
+				dates = append(dates, date)
+			}
+		}
+
+		return dates
+	}
+
+	months := r.ByMonth
+	if len(months) == 0 {
+		if len(r.ByMonthDay) > 0 || len(r.ByDay) > 0 {
+			months = []time.Month{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+		} else {
+			months = []time.Month{startMonth}
+		}
+	}
+
+	for _, month := range months {
+		for _, d := range r.monthDays(year, month, startDay) {
+			dates = append(dates, time.Date(year, month, d, 0, 0, 0, 0, time.UTC))
+		}
+	}
+
+	return dates
+}
+
+func (r Rule) matchesMonth(month time.Month) bool {
+	if len(r.ByMonth) == 0 {
+		return true
+	}
+
+	for _, m := range r.ByMonth {
+		if m == month {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r Rule) matchesMonthDay(date time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+
+	last := daysIn(date.Year(), date.Month())
+
+	for _, d := range r.ByMonthDay {
+		if d == date.Day() || last+d+1 == date.Day() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r Rule) matchesWeekday(weekday time.Weekday) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+
+	for _, day := range r.ByDay {
+		if day.Weekday == weekday {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesOrdinal checks BYDAY entries against a weekday that is the nth one of
+// its month or year, counted from the start (nth) and from the end (nthFromEnd).
+func (r Rule) matchesOrdinal(weekday time.Weekday, nth, nthFromEnd int) bool {
+	for _, day := range r.ByDay {
+		if day.Weekday != weekday {
+			continue
+		}
+
+		if day.N == 0 || day.N == nth || day.N == nthFromEnd {
+			return true
+		}
+	}
+
+	return false
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
@@ -0,0 +1,402 @@
+package recurrence_test
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal/recurrence"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type SetTestSuite struct {
+	suite.Suite
+	newYork *time.Location
+}
+
+func (s *SetTestSuite) SetupTest() {
+	loc, err := time.LoadLocation("America/New_York")
+	s.Require().NoError(err)
+
+	s.newYork = loc
+}
+
+func (s *SetTestSuite) expand(start time.Time, rule string, limit int) []time.Time {
+	parsed, err := recurrence.ParseRule(rule)
+	s.Require().NoError(err)
+
+	occurrences := recurrence.Set{Start: start, Rule: &parsed}.Between(start, start.AddDate(10, 0, 0))
+	if len(occurrences) > limit {
+		occurrences = occurrences[:limit]
+	}
+
+	return occurrences
+}
+
+func (s *SetTestSuite) dates(values ...string) []time.Time {
+	var dates []time.Time
+
+	for _, value := range values {
+		date, err := time.ParseInLocation("2006-01-02 15:04", value, s.newYork)
+		s.Require().NoError(err)
+
+		dates = append(dates, date)
+	}
+
+	return dates
+}
+
+// The expectations below are the examples of RFC 5545 section 3.8.5.3.
+
+func (s *SetTestSuite) TestDailyCount() {
+	start := s.dates("1997-09-02 09:00")[0]
+
+	occurrences := s.expand(start, "FREQ=DAILY;COUNT=10", 100)
+
+	require.Equal(s.T(), s.dates(
+		"1997-09-02 09:00", "1997-09-03 09:00", "1997-09-04 09:00", "1997-09-05 09:00", "1997-09-06 09:00",
+		"1997-09-07 09:00", "1997-09-08 09:00", "1997-09-09 09:00", "1997-09-10 09:00", "1997-09-11 09:00",
+	), occurrences)
+}
+
+func (s *SetTestSuite) TestWeeklyByDayUntil() {
+	start := s.dates("1997-09-02 09:00")[0]
+
+	occurrences := s.expand(start, "FREQ=WEEKLY;UNTIL=19971007T000000Z;WKST=SU;BYDAY=TU,TH", 100)
+
+	require.Equal(s.T(), s.dates(
+		"1997-09-02 09:00", "1997-09-04 09:00", "1997-09-09 09:00", "1997-09-11 09:00", "1997-09-16 09:00",
+		"1997-09-18 09:00", "1997-09-23 09:00", "1997-09-25 09:00", "1997-09-30 09:00", "1997-10-02 09:00",
+	), occurrences)
+}
+
+func (s *SetTestSuite) TestEveryOtherWeek() {
+	start := s.dates("1997-09-01 09:00")[0]
+
+	occurrences := s.expand(start, "FREQ=WEEKLY;INTERVAL=2;UNTIL=19971224T000000Z;WKST=SU;BYDAY=MO,WE,FR", 100)
+
+	require.Len(s.T(), occurrences, 25)
+	require.Equal(s.T(), s.dates(
+		"1997-09-01 09:00", "1997-09-03 09:00", "1997-09-05 09:00", "1997-09-15 09:00", "1997-09-17 09:00",
+	), occurrences[:5])
+	require.Equal(s.T(), s.dates("1997-12-22 09:00")[0], occurrences[24])
+}
+
+func (s *SetTestSuite) TestMonthlyFirstFriday() {
+	start := s.dates("1997-09-05 09:00")[0]
+
+	occurrences := s.expand(start, "FREQ=MONTHLY;COUNT=10;BYDAY=1FR", 100)
+
+	require.Equal(s.T(), s.dates(
+		"1997-09-05 09:00", "1997-10-03 09:00", "1997-11-07 09:00", "1997-12-05 09:00", "1998-01-02 09:00",
+		"1998-02-06 09:00", "1998-03-06 09:00", "1998-04-03 09:00", "1998-05-01 09:00", "1998-06-05 09:00",
+	), occurrences)
+}
+
+func (s *SetTestSuite) TestMonthlyLastDay() {
+	start := s.dates("1997-09-30 09:00")[0]
+
+	occurrences := s.expand(start, "FREQ=MONTHLY;BYMONTHDAY=-1", 6)
+
+	require.Equal(s.T(), s.dates(
+		"1997-09-30 09:00", "1997-10-31 09:00", "1997-11-30 09:00", "1997-12-31 09:00", "1998-01-31 09:00",
+		"1998-02-28 09:00",
+	), occurrences)
+}
+
+func (s *SetTestSuite) TestMonthlySkipsShortMonths() {
+	start := s.dates("1997-01-31 09:00")[0]
+
+	occurrences := s.expand(start, "FREQ=MONTHLY;COUNT=4", 100)
+
+	require.Equal(s.T(), s.dates(
+		"1997-01-31 09:00", "1997-03-31 09:00", "1997-05-31 09:00", "1997-07-31 09:00",
+	), occurrences)
+}
+
+func (s *SetTestSuite) TestYearlyByMonth() {
+	start := s.dates("1997-06-10 09:00")[0]
+
+	occurrences := s.expand(start, "FREQ=YEARLY;COUNT=10;BYMONTH=6,7", 100)
+
+	require.Equal(s.T(), s.dates(
+		"1997-06-10 09:00", "1997-07-10 09:00", "1998-06-10 09:00", "1998-07-10 09:00", "1999-06-10 09:00",
+		"1999-07-10 09:00", "2000-06-10 09:00", "2000-07-10 09:00", "2001-06-10 09:00", "2001-07-10 09:00",
+	), occurrences)
+}
+
+func (s *SetTestSuite) TestYearlyTwentiethMonday() {
+	start := s.dates("1997-05-19 09:00")[0]
+
+	occurrences := s.expand(start, "FREQ=YEARLY;BYDAY=20MO", 3)
+
+	require.Equal(s.T(), s.dates("1997-05-19 09:00", "1998-05-18 09:00", "1999-05-17 09:00"), occurrences)
+}
+
+func (s *SetTestSuite) TestKeepsWallClockAcrossDST() {
+	start := s.dates("1997-10-21 09:00")[0]
+
+	occurrences := s.expand(start, "FREQ=WEEKLY;COUNT=3", 100)
+
+	require.Equal(s.T(), s.dates("1997-10-21 09:00", "1997-10-28 09:00", "1997-11-04 09:00"), occurrences)
+	// Daylight saving time ended on 1997-10-26, so that week is one hour longer.
+	require.Equal(s.T(), 7*24*time.Hour+time.Hour, occurrences[1].Sub(occurrences[0]))
+}
+
+func (s *SetTestSuite) TestRDatesAndExDates() {
+	start := s.dates("2025-12-01 09:00")[0]
+	rule, err := recurrence.ParseRule("FREQ=WEEKLY;COUNT=3")
+	s.Require().NoError(err)
+
+	set := recurrence.Set{
+		Start:   start,
+		Rule:    &rule,
+		ExDates: s.dates("2025-12-08 09:00"),
+		RDates:  s.dates("2025-12-10 15:00", "2025-12-15 09:00"),
+	}
+
+	occurrences := set.Between(start, start.AddDate(1, 0, 0))
+
+	require.Equal(s.T(), s.dates("2025-12-01 09:00", "2025-12-10 15:00", "2025-12-15 09:00"), occurrences)
+
+	last, ok := set.Last()
+	require.True(s.T(), ok)
+	require.Equal(s.T(), s.dates("2025-12-15 09:00")[0], last)
+}
+
+func (s *SetTestSuite) TestLast() {
+	start := s.dates("2025-12-01 09:00")[0]
+
+	endless, err := recurrence.ParseRule("FREQ=DAILY")
+	s.Require().NoError(err)
+
+	_, ok := recurrence.Set{Start: start, Rule: &endless}.Last()
+	require.False(s.T(), ok)
+
+	single, ok := recurrence.Set{Start: start}.Last()
+	require.True(s.T(), ok)
+	require.Equal(s.T(), start, single)
+}
+
+func (s *SetTestSuite) TestImpossibleRuleTerminates() {
+	start := s.dates("2025-01-30 09:00")[0]
+
+	occurrences := s.expand(start, "FREQ=YEARLY;COUNT=2;BYMONTH=2;BYMONTHDAY=30", 100)
+
+	require.Equal(s.T(), []time.Time{start}, occurrences)
+}
+
+// randomSet generates arbitrary supported rules for the property tests.
+type randomSet struct {
+	Set  recurrence.Set
+	From time.Time
+	To   time.Time
+}
+
+func (randomSet) Generate(r *rand.Rand, _ int) reflect.Value {
+	locations := []string{"UTC", "America/New_York", "Europe/Madrid", "Australia/Sydney"}
+	loc, _ := time.LoadLocation(locations[r.Intn(len(locations))])
+
+	start := time.Date(2020+r.Intn(6), time.Month(1+r.Intn(12)), 1+r.Intn(28), r.Intn(24), 15*r.Intn(4), 0, 0, loc)
+
+	frequencies := []recurrence.Frequency{recurrence.Daily, recurrence.Weekly, recurrence.Monthly, recurrence.Yearly}
+	rule := recurrence.Rule{
+		Freq:      frequencies[r.Intn(len(frequencies))],
+		Interval:  1 + r.Intn(3),
+		WeekStart: time.Weekday(r.Intn(7)),
+	}
+
+	switch r.Intn(3) {
+	case 0:
+		rule.Count = 1 + r.Intn(30)
+	case 1:
+		rule.Until = start.AddDate(0, 1+r.Intn(36), 0)
+	}
+
+	if r.Intn(2) == 0 {
+		for _, weekday := range r.Perm(7)[:1+r.Intn(3)] {
+			day := recurrence.WeekdayNum{Weekday: time.Weekday(weekday)}
+			if (rule.Freq == recurrence.Monthly || rule.Freq == recurrence.Yearly) && r.Intn(2) == 0 {
+				day.N = []int{1, 2, -1}[r.Intn(3)]
+			}
+			rule.ByDay = append(rule.ByDay, day)
+		}
+	}
+
+	if rule.Freq != recurrence.Weekly && r.Intn(4) == 0 {
+		rule.ByMonthDay = []int{1 + r.Intn(28)}
+	}
+
+	if r.Intn(4) == 0 {
+		rule.ByMonth = []time.Month{time.Month(1 + r.Intn(12)), time.Month(1 + r.Intn(12))}
+	}
+
+	from := start.AddDate(0, r.Intn(12)-2, r.Intn(30))
+	to := from.AddDate(0, 1+r.Intn(24), 0)
+
+	return reflect.ValueOf(randomSet{Set: recurrence.Set{Start: start, Rule: &rule}, From: from, To: to})
+}
+
+func (s *SetTestSuite) check(property interface{}) {
+	s.Require().NoError(quick.Check(property, &quick.Config{MaxCount: 300}))
+}
+
+func (s *SetTestSuite) TestProperty_SortedWithinWindow() {
+	s.check(func(input randomSet) bool {
+		occurrences := input.Set.Between(input.From, input.To)
+
+		for i, occurrence := range occurrences {
+			if occurrence.Before(input.From) || !occurrence.Before(input.To) || occurrence.Before(input.Set.Start) {
+				return false
+			}
+
+			if i > 0 && !occurrences[i-1].Before(occurrence) {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+func (s *SetTestSuite) TestProperty_StartsWithDTStart() {
+	s.check(func(input randomSet) bool {
+		occurrences := input.Set.Between(input.Set.Start, input.Set.Start.AddDate(5, 0, 0))
+
+		return len(occurrences) > 0 && occurrences[0].Equal(input.Set.Start)
+	})
+}
+
+func (s *SetTestSuite) TestProperty_RespectsCountAndUntil() {
+	s.check(func(input randomSet) bool {
+		rule := input.Set.Rule
+		occurrences := input.Set.Between(input.Set.Start, input.Set.Start.AddDate(10, 0, 0))
+
+		if rule.Count > 0 && len(occurrences) > rule.Count {
+			return false
+		}
+
+		for _, occurrence := range occurrences {
+			if !rule.Until.IsZero() && occurrence.After(rule.Until) {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+func (s *SetTestSuite) TestProperty_WindowsCompose() {
+	s.check(func(input randomSet) bool {
+		middle := input.From.Add(input.To.Sub(input.From) / 3)
+
+		whole := input.Set.Between(input.From, input.To)
+		split := append(input.Set.Between(input.From, middle), input.Set.Between(middle, input.To)...)
+
+		if len(whole) != len(split) {
+			return false
+		}
+
+		for i := range whole {
+			if !whole[i].Equal(split[i]) {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+func (s *SetTestSuite) TestProperty_MatchesByRules() {
+	s.check(func(input randomSet) bool {
+		rule := input.Set.Rule
+		start := input.Set.Start
+
+		for _, occurrence := range input.Set.Between(input.From, input.To) {
+			if occurrence.Equal(start) {
+				continue
+			}
+
+			local := occurrence.In(start.Location())
+
+			if local.Hour() != start.Hour() && !inDSTGap(start, local) {
+				return false
+			}
+
+			if !weekdayListed(rule.ByDay, local.Weekday()) || !monthListed(rule.ByMonth, local.Month()) {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+func (s *SetTestSuite) TestProperty_ExDatesRemoveOccurrences() {
+	s.check(func(input randomSet) bool {
+		occurrences := input.Set.Between(input.From, input.To)
+		if len(occurrences) == 0 {
+			return true
+		}
+
+		excluded := occurrences[len(occurrences)/2]
+		input.Set.ExDates = []time.Time{excluded}
+
+		remaining := input.Set.Between(input.From, input.To)
+		if len(remaining) != len(occurrences)-1 {
+			return false
+		}
+
+		for _, occurrence := range remaining {
+			if occurrence.Equal(excluded) {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+// inDSTGap reports whether the wall clock of start does not exist on that day,
+// in which case time.Date moves the occurrence forward.
+func inDSTGap(start, occurrence time.Time) bool {
+	y, m, d := occurrence.Date()
+	wanted := time.Date(y, m, d, start.Hour(), start.Minute(), 0, 0, start.Location())
+
+	return wanted.Hour() != start.Hour()
+}
+
+func weekdayListed(days []recurrence.WeekdayNum, weekday time.Weekday) bool {
+	if len(days) == 0 {
+		return true
+	}
+
+	for _, day := range days {
+		if day.Weekday == weekday {
+			return true
+		}
+	}
+
+	return false
+}
+
+func monthListed(months []time.Month, month time.Month) bool {
+	if len(months) == 0 {
+		return true
+	}
+
+	for _, m := range months {
+		if m == month {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestSetTestSuite(t *testing.T) {
+	suite.Run(t, new(SetTestSuite))
+}
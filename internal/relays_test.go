@@ -0,0 +1,159 @@
+package internal_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// RelaysTestSuite runs several relays on one outbox against a real Postgres,
+// since the claim query keeping each event's messages in order cannot be
+// checked with sqlmock.
+type RelaysTestSuite struct {
+	postgresSuite
+	ctx context.Context
+}
+
+func (s *RelaysTestSuite) SetupSuite() {
+	s.setupPostgres("relays")
+	s.ctx = internal.WithTenant(context.Background(), "relays")
+}
+
+// writeMessages creates events and updates each of them, which writes
+// 1+updates messages per event to the outbox.
+func (s *RelaysTestSuite) writeMessages(events, updates int) []string {
+	start := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Hour)
+
+	var ids []string
+
+	for range events {
+		event, err := s.storage.CreateEvent(s.ctx, internal.CreateEventRequest{
+			Title:       "Standup",
+			Description: "Daily sync",
+			StartTime:   start,
+			EndTime:     start.Add(15 * time.Minute),
+			CalendarID:  internal.DefaultCalendarID,
+		})
+		require.NoError(s.T(), err)
+
+		for i := range updates {
+			event, err = s.storage.UpdateEvent(s.ctx, event.ID, event.Version, internal.CreateEventRequest{
+				Title:       event.Title,
+				Description: event.Description,
+				StartTime:   event.StartTime.Add(time.Duration(i+1) * time.Minute),
+				EndTime:     event.EndTime.Add(time.Duration(i+1) * time.Minute),
+				CalendarID:  internal.DefaultCalendarID,
+			})
+			require.NoError(s.T(), err)
+		}
+
+		ids = append(ids, event.ID)
+	}
+
+	return ids
+}
+
+func (s *RelaysTestSuite) TestConcurrentRelaysKeepOrder() {
+	events := s.writeMessages(3, 4)
+
+	var pending int
+	require.NoError(s.T(), s.admin.QueryRow("SELECT COUNT(*) FROM "+s.schema+".outbox WHERE published_at IS NULL").Scan(&pending))
+	require.Equal(s.T(), 15, pending)
+
+	var (
+		mu        sync.Mutex
+		published = map[string][]int64{}
+		inFlight  = map[string]bool{}
+		overlaps  int
+		failed    bool
+	)
+
+	errBroker := errors.New("broker down")
+
+	// The first update of the first event fails once, so the later messages of
+	// that event wait out its backoff while the other relay keeps going.
+	deliver := func(_ context.Context, message internal.OutboxMessage) error {
+		mu.Lock()
+		if inFlight[message.Key] {
+			overlaps++
+		}
+
+		inFlight[message.Key] = true
+
+		fail := !failed && message.Key == events[0] && message.Topic == "events.updated"
+		if fail {
+			failed = true
+		}
+		mu.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		inFlight[message.Key] = false
+
+		if fail {
+			return errBroker
+		}
+
+		published[message.Key] = append(published[message.Key], message.ID)
+
+		return nil
+	}
+
+	total := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+
+		n := 0
+		for _, ids := range published {
+			n += len(ids)
+		}
+
+		return n
+	}
+
+	deadline := time.Now().Add(15 * time.Second)
+
+	var wg sync.WaitGroup
+
+	for range 2 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for total() < pending && time.Now().Before(deadline) {
+				if _, err := s.storage.DeliverOutbox(context.Background(), 2, deliver); err != nil && !errors.Is(err, errBroker) {
+					s.T().Errorf("delivering: %v", err)
+					return
+				}
+
+				time.Sleep(10 * time.Millisecond)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	require.True(s.T(), failed)
+	require.Zero(s.T(), overlaps, "two relays published the messages of one event at once")
+	require.Len(s.T(), published, len(events))
+
+	for _, id := range events {
+		ids := published[id]
+		require.Len(s.T(), ids, 5, id)
+		require.IsIncreasing(s.T(), ids, id)
+	}
+}
+
+func TestRelaysTestSuite(t *testing.T) {
+	suite.Run(t, new(RelaysTestSuite))
+}
@@ -0,0 +1,261 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// eventState is what a revision records of an event, in the JSON stored in
+// its snapshot. Times are in UTC at the precision Postgres keeps, so an event
+// read back compares equal to the one written.
+type eventState struct {
+	Title       string      `json:"title,omitempty"`
+	Description string      `json:"description,omitempty"`
+	StartTime   time.Time   `json:"start_time"`
+	EndTime     time.Time   `json:"end_time"`
+	RRule       string      `json:"rrule,omitempty"`
+	ExDates     []time.Time `json:"exdates,omitempty"`
+	RDates      []time.Time `json:"rdates,omitempty"`
+	TimeZone    string      `json:"time_zone,omitempty"`
+	AllDay      bool        `json:"all_day,omitempty"`
+	Resource    string      `json:"resource,omitempty"`
+	Capacity    int         `json:"capacity,omitempty"`
+	CalendarID  string      `json:"calendar_id,omitempty"`
+	DeletedAt   *time.Time  `json:"deleted_at,omitempty"`
+}
+
+func newEventState(event CreateEventResponse) eventState {
+	state := eventState{
+		Title:       event.Title,
+		Description: event.Description,
+		StartTime:   storedTime(event.StartTime),
+		EndTime:     storedTime(event.EndTime),
+		RRule:       event.RRule,
+		TimeZone:    event.TimeZone,
+		AllDay:      event.AllDay,
+		Resource:    event.Resource,
+		Capacity:    event.Capacity,
+		CalendarID:  event.CalendarID,
+	}
+
+	for _, date := range event.ExDates {
+		state.ExDates = append(state.ExDates, storedTime(date))
+	}
+
+	for _, date := range event.RDates {
+		state.RDates = append(state.RDates, storedTime(date))
+	}
+
+	if event.DeletedAt != nil {
+		deletedAt := storedTime(*event.DeletedAt)
+		state.DeletedAt = &deletedAt
+	}
+
+	return state
+}
+
+// request is the update that writes the state back. Deletion is not part of it.
+func (st eventState) request() CreateEventRequest {
+	return CreateEventRequest{
+		Title:       st.Title,
+		Description: st.Description,
+		StartTime:   st.StartTime,
+		EndTime:     st.EndTime,
+		RRule:       st.RRule,
+		ExDates:     st.ExDates,
+		RDates:      st.RDates,
+		TimeZone:    st.TimeZone,
+		AllDay:      st.AllDay,
+		Resource:    st.Resource,
+		Capacity:    st.Capacity,
+		CalendarID:  st.CalendarID,
+	}
+}
+
+// diffStates maps every field that differs between before and after to its
+// values. A nil before is an event that did not exist, so every field of after
+// is new.
+func diffStates(before *eventState, after eventState) (map[string]FieldChange, error) {
+	from := map[string]json.RawMessage{}
+
+	if before != nil {
+		if err := remarshal(*before, &from); err != nil {
+			return nil, err
+		}
+	}
+
+	to := map[string]json.RawMessage{}
+
+	if err := remarshal(after, &to); err != nil {
+		return nil, err
+	}
+
+	changes := map[string]FieldChange{}
+
+	for field, value := range to {
+		if !bytes.Equal(from[field], value) {
+			changes[field] = FieldChange{From: from[field], To: value}
+		}
+	}
+
+	for field, value := range from {
+		if _, ok := to[field]; !ok {
+			changes[field] = FieldChange{From: value}
+		}
+	}
+
+	return changes, nil
+}
+
+// occurrenceState is what a revision records of an occurrence exception.
+type occurrenceState struct {
+	Cancelled   bool       `json:"cancelled,omitempty"`
+	Title       string     `json:"title,omitempty"`
+	Description string     `json:"description,omitempty"`
+	StartTime   *time.Time `json:"start_time,omitempty"`
+	EndTime     *time.Time `json:"end_time,omitempty"`
+}
+
+func newOccurrenceState(exception OccurrenceException) occurrenceState {
+	state := occurrenceState{
+		Cancelled:   exception.Cancelled,
+		Title:       exception.Title,
+		Description: exception.Description,
+	}
+
+	if !exception.StartTime.IsZero() {
+		startTime := storedTime(exception.StartTime)
+		state.StartTime = &startTime
+	}
+
+	if !exception.EndTime.IsZero() {
+		endTime := storedTime(exception.EndTime)
+		state.EndTime = &endTime
+	}
+
+	return state
+}
+
+// diffOccurrence records a change to one occurrence of a series as the change
+// of the field "occurrences/<recurrence id>", from the exception it replaces,
+// nil when there was none.
+func diffOccurrence(before *OccurrenceException, after OccurrenceException) (map[string]FieldChange, error) {
+	var change FieldChange
+
+	if before != nil {
+		from, err := json.Marshal(newOccurrenceState(*before))
+		if err != nil {
+			return nil, fmt.Errorf("encoding exception: %w", err)
+		}
+
+		change.From = from
+	}
+
+	to, err := json.Marshal(newOccurrenceState(after))
+	if err != nil {
+		return nil, fmt.Errorf("encoding exception: %w", err)
+	}
+
+	change.To = to
+
+	field := "occurrences/" + storedTime(after.RecurrenceID).Format(time.RFC3339Nano)
+
+	return map[string]FieldChange{field: change}, nil
+}
+
+// remarshal reads the fields of state into fields.
+func remarshal(state eventState, fields *map[string]json.RawMessage) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("encoding event: %w", err)
+	}
+
+	if err := json.Unmarshal(data, fields); err != nil {
+		return fmt.Errorf("decoding event: %w", err)
+	}
+
+	return nil
+}
+
+// storedTime is t as Postgres keeps it, in UTC to the microsecond.
+func storedTime(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Microsecond)
+}
+
+// GetEventHistory lists the revisions of an event, deleted or not, oldest first.
+func (s *Service) GetEventHistory(ctx context.Context, id string) ([]EventRevision, error) {
+	if id == "" {
+		return nil, fmt.Errorf("empty id: %w", ErrInput)
+	}
+
+	if _, err := s.authorize.event(ctx, id, AccessViewer); err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.storage.GetEventRevisions(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("getting history: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// RevertEvent writes back the fields an event had at revision. It is an update
+// like UpdateEvent, checked the same way and recorded as a new revision.
+//
+// Revisions only snapshot the series, not its occurrence exceptions, so an
+// event whose occurrences changed after revision cannot be put back the way it
+// was and reverting it is ErrConflict.
+func (s *Service) RevertEvent(ctx context.Context, id string, revision, version int) (CreateEventResponse, error) {
+	if id == "" {
+		return CreateEventResponse{}, fmt.Errorf("empty id: %w", ErrInput)
+	}
+
+	if revision <= 0 {
+		return CreateEventResponse{}, fmt.Errorf("revision should be positive: %w", ErrInput)
+	}
+
+	if version < 0 {
+		return CreateEventResponse{}, fmt.Errorf("version cannot be negative: %w", ErrInput)
+	}
+
+	access, err := s.authorize.event(ctx, id, AccessEditor)
+	if err != nil {
+		return CreateEventResponse{}, err
+	}
+
+	target, err := s.storage.GetEventRevision(ctx, id, revision)
+	if err != nil {
+		return CreateEventResponse{}, fmt.Errorf("getting revision: %w", err)
+	}
+
+	history, err := s.storage.GetEventRevisions(ctx, id)
+	if err != nil {
+		return CreateEventResponse{}, fmt.Errorf("getting history: %w", err)
+	}
+
+	for _, later := range history {
+		if later.Revision > revision && changesOccurrences(later) {
+			return CreateEventResponse{}, fmt.Errorf("revision %d changed occurrences after revision %d, which cannot be reverted: %w",
+				later.Revision, revision, ErrConflict)
+		}
+	}
+
+	return s.replaceEvent(ctx, access, id, version, target.Snapshot)
+}
+
+// changesOccurrences reports whether revision changed an occurrence exception,
+// which diffOccurrence records as an "occurrences/" field.
+func changesOccurrences(revision EventRevision) bool {
+	for field := range revision.Changes {
+		if strings.HasPrefix(field, "occurrences/") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,14 +1,63 @@
 package internal
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"github.com/google/uuid"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal/recurrence"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// eventColumns is the column list scanEvent expects.
+const eventColumns = "id, title, description, start_time, end_time, created_at, deleted_at, rrule, exdate, rdate, time_zone, all_day, resource, capacity, calendar_id, created_by, version"
+
+const insertEventQuery = "INSERT INTO events (id,title, description, start_time, end_time, created_at, rrule, exdate, rdate, series_end, time_zone, all_day, resource, capacity, calendar_id, created_by) " +
+	"VALUES ($1,$2, $3, $4, $5,$6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)"
+
+// attendeeColumns is the column list scanAttendee expects.
+const attendeeColumns = "event_id, email, name, role, status, invited_at, status_changed_at"
+
+// grantColumns is the column list of grants, in the order GetGrants scans it.
+const grantColumns = "object_type, object_id, principal, role, granted_by, created_at"
+
+// exceptionColumns is the column list scanOccurrenceException expects.
+const exceptionColumns = "series_id, recurrence_id, cancelled, title, description, start_time, end_time"
+
+// revisionColumns is the column list scanRevision expects.
+const revisionColumns = "event_id, revision, action, actor, created_at, changes, snapshot"
+
+// outboxColumns is the column list DeliverOutbox scans.
+const outboxColumns = "id, tenant_id, topic, key, payload, created_at, attempts"
+
+// outboxMaxAttempts is how many times a message is published before it is set
+// aside as dead, and outboxLease how long a relay has to publish the messages
+// it claimed before another one may.
+const (
+	outboxMaxAttempts = 10
+	outboxLease       = time.Minute
 )
 
+// calendarColumns is the column list scanCalendar expects.
+const calendarColumns = "id, name, description, created_at, created_by"
+
+// apiKeyColumns is the column list scanAPIKey expects.
+const apiKeyColumns = "id, tenant_id, name, prefix, scopes, created_by, created_at, expires_at, last_used_at, revoked_at"
+
+// resourceConstraint rejects overlapping bookings of the same resource.
+const resourceConstraint = "event_bookings_no_overlap"
+
+// calendarConstraint is the foreign key from an event to its calendar.
+const calendarConstraint = "events_calendar_id_fkey"
+
 type Storage struct {
 	db *sql.DB
 }
@@ -20,10 +69,9 @@ func NewStorage(db *sql.DB) *Storage {
 }
 
 func (s *Storage) CreateEvent(ctx context.Context, event CreateEventRequest) (CreateEventResponse, error) {
-	trx, err := s.db.BeginTx(ctx, nil)
-
+	trx, err := s.begin(ctx)
 	if err != nil {
-		return CreateEventResponse{}, fmt.Errorf("creating transaction :%w", err)
+		return CreateEventResponse{}, err
 	}
 
 	defer trx.Rollback()
@@ -32,28 +80,132 @@ func (s *Storage) CreateEvent(ctx context.Context, event CreateEventRequest) (Cr
 	id := uuid.NewString()
 	createdAt := time.Now().UTC()
 
-	query := "INSERT INTO events (id,title, description, start_time, end_time, created_at) VALUES ($1,$2, $3, $4, $5,$6)"
+	seriesEnd, err := event.seriesEnd()
+	if err != nil {
+		return CreateEventResponse{}, fmt.Errorf("computing series end: %w", err)
+	}
+
+	if event.CalendarID == DefaultCalendarID {
+		if err := ensureDefaultCalendar(ctx, trx); err != nil {
+			return CreateEventResponse{}, err
+		}
+	}
+
+	if _, err := trx.ExecContext(ctx, insertEventQuery, id, event.Title, event.Description, event.StartTime, event.EndTime, createdAt,
+		nullString(event.RRule), nullString(recurrence.FormatDateList(event.ExDates)), nullString(recurrence.FormatDateList(event.RDates)), seriesEnd,
+		event.zoneName(), event.AllDay, nullString(event.Resource), nullInt(event.Capacity), event.CalendarID, nullString(ActorFromContext(ctx))); err != nil {
+		if isCalendarViolation(err) {
+			err = fmt.Errorf("calendar %q does not exist: %w", event.CalendarID, ErrInput)
+		}
 
-	if _, err := s.db.ExecContext(ctx, query, id, event.Title, event.Description, event.StartTime, event.EndTime, createdAt); err != nil {
 		return CreateEventResponse{}, fmt.Errorf("creating event: %w", err)
 	}
 
-	result := CreateEventResponse{
-		ID:          id,
-		Title:       event.Title,
-		Description: event.Description,
-		StartTime:   event.StartTime,
-		EndTime:     event.EndTime,
-		CreatedAt:   createdAt,
+	created := newStoredEvent(id, 1, createdAt, ActorFromContext(ctx), event)
+
+	if err := s.book(ctx, trx, created, false); err != nil {
+		return CreateEventResponse{}, fmt.Errorf("creating event: %w", err)
+	}
+
+	if err := recordRevision(ctx, trx, RevisionCreated, nil, created); err != nil {
+		return CreateEventResponse{}, err
 	}
 
-	return result, trx.Commit()
+	return created, trx.Commit()
 }
 
-func (s *Storage) GetEvents(ctx context.Context) ([]CreateEventResponse, error) {
-	query := "SELECT id,title, description, start_time, end_time, created_at FROM events ORDER BY start_time ASC"
+// GetEvents reads one page of events ordered by (start_time, id). The limit is
+// mandatory so a caller can never load the whole table.
+func (s *Storage) GetEvents(ctx context.Context, filter EventFilter, page Page) ([]CreateEventResponse, error) {
+	if page.Limit <= 0 {
+		return []CreateEventResponse{}, fmt.Errorf("page limit should be positive: %w", ErrInput)
+	}
+
+	var conditions []string
+	var args []any
+
+	arg := func(value any) string {
+		args = append(args, value)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if !filter.IncludeDeleted {
+		conditions = append(conditions, "deleted_at IS NULL")
+	}
+
+	// Overlap test: the event (or its first occurrence) starts before the window
+	// ends and the event (or its last occurrence) ends after it starts. Series
+	// without an end have a NULL series_end. All-day events compare their dates
+	// with the dates the window covers. Each branch compares the bare column, so
+	// the start_time and series_end indexes serve it.
+	floatingFrom, floatingTo := floatingWindow(filter.From, filter.To)
+
+	if !filter.To.IsZero() {
+		conditions = append(conditions, fmt.Sprintf("((all_day AND start_time < %s) OR (NOT all_day AND start_time < %s))",
+			arg(floatingTo), arg(filter.To)))
+	}
+
+	if !filter.From.IsZero() {
+		conditions = append(conditions, fmt.Sprintf("(series_end IS NULL OR (all_day AND series_end > %s) OR (NOT all_day AND series_end > %s))",
+			arg(floatingFrom), arg(filter.From)))
+	}
+
+	if filter.Title != "" {
+		conditions = append(conditions, "title ILIKE '%' || "+arg(likeEscaper.Replace(filter.Title))+" || '%'")
+	}
+
+	if len(filter.Resources) > 0 {
+		conditions = append(conditions, "resource = ANY("+arg(pq.Array(filter.Resources))+")")
+	}
+
+	if filter.CalendarID != "" {
+		conditions = append(conditions, "calendar_id = "+arg(filter.CalendarID))
+	}
+
+	if len(filter.CalendarIDs) > 0 {
+		conditions = append(conditions, "calendar_id = ANY("+arg(pq.Array(filter.CalendarIDs))+")")
+	}
+
+	if !filter.CreatedFrom.IsZero() {
+		conditions = append(conditions, "created_at >= "+arg(filter.CreatedFrom))
+	}
+
+	if !filter.CreatedTo.IsZero() {
+		conditions = append(conditions, "created_at < "+arg(filter.CreatedTo))
+	}
+
+	// The principal views the shared default calendar, what it created and what
+	// it was granted, directly or through the calendar.
+	if filter.VisibleTo != "" {
+		principal := arg(filter.VisibleTo)
+
+		conditions = append(conditions, fmt.Sprintf("(calendar_id = %s OR created_by = %s "+
+			"OR calendar_id IN (SELECT id FROM calendars WHERE created_by = %s) "+
+			"OR EXISTS (SELECT 1 FROM grants WHERE principal = %s AND ((object_type = 'event' AND object_id = events.id) "+
+			"OR (object_type = 'calendar' AND object_id = events.calendar_id))))",
+			arg(DefaultCalendarID), principal, principal, principal))
+	}
+
+	if page.After != nil {
+		conditions = append(conditions, fmt.Sprintf("(start_time, id) > (%s, %s)", arg(page.After.StartTime), arg(page.After.ID)))
+	}
+
+	query := "SELECT " + eventColumns + " FROM events"
 
-	rows, err := s.db.QueryContext(ctx, query)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query += " ORDER BY start_time ASC, id ASC LIMIT " + arg(page.Limit)
+
+	trx, err := s.begin(ctx)
+	if err != nil {
+		return []CreateEventResponse{}, err
+	}
+
+	defer trx.Rollback()
+
+	rows, err := trx.QueryContext(ctx, query, args...)
 	if err != nil {
 		return []CreateEventResponse{}, fmt.Errorf("creating event: %w", err)
 	}
@@ -63,30 +215,32 @@ func (s *Storage) GetEvents(ctx context.Context) ([]CreateEventResponse, error)
 	var results []CreateEventResponse
 
 	for rows.Next() {
-		var event CreateEventResponse
-		if err := rows.Scan(&event.ID, &event.Title, &event.Description, &event.StartTime, &event.EndTime, &event.CreatedAt); err != nil {
+		event, err := scanEvent(rows)
+		if err != nil {
 			return []CreateEventResponse{}, fmt.Errorf("scanning event: %w", err)
 		}
 
 		results = append(results, event)
 	}
 
-	return results, nil
+	return results, rows.Err()
 }
 
-func (s *Storage) GetEventByID(ctx context.Context, id string) (CreateEventResponse, error) {
-	query := "SELECT id, title, description, start_time, end_time, created_at FROM events WHERE id = $1"
+func (s *Storage) GetEventByID(ctx context.Context, id string, includeDeleted bool) (CreateEventResponse, error) {
+	query := "SELECT " + eventColumns + " FROM events WHERE id = $1"
 
-	var event CreateEventResponse
-	err := s.db.QueryRowContext(ctx, query, id).Scan(
-		&event.ID,
-		&event.Title,
-		&event.Description,
-		&event.StartTime,
-		&event.EndTime,
-		&event.CreatedAt,
-	)
+	if !includeDeleted {
+		query += " AND deleted_at IS NULL"
+	}
+
+	trx, err := s.begin(ctx)
+	if err != nil {
+		return CreateEventResponse{}, err
+	}
+
+	defer trx.Rollback()
 
+	event, err := scanEvent(trx.QueryRowContext(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return CreateEventResponse{}, fmt.Errorf("event not found: %w", ErrNotFound)
@@ -96,3 +250,1770 @@ func (s *Storage) GetEventByID(ctx context.Context, id string) (CreateEventRespo
 
 	return event, nil
 }
+
+// UpdateEvent replaces an event that is at version, or at any version when
+// version is zero, and increments its version.
+func (s *Storage) UpdateEvent(ctx context.Context, id string, version int, event CreateEventRequest) (CreateEventResponse, error) {
+	seriesEnd, err := event.seriesEnd()
+	if err != nil {
+		return CreateEventResponse{}, fmt.Errorf("computing series end: %w", err)
+	}
+
+	trx, err := s.begin(ctx)
+	if err != nil {
+		return CreateEventResponse{}, err
+	}
+
+	defer trx.Rollback()
+
+	before, err := lockedEvent(ctx, trx, id, false)
+	if err != nil {
+		return CreateEventResponse{}, err
+	}
+
+	if version != 0 && before.Version != version {
+		return CreateEventResponse{}, fmt.Errorf("event is at version %d, not %d: %w", before.Version, version, ErrVersionMismatch)
+	}
+
+	query := "UPDATE events SET title = $1, description = $2, start_time = $3, end_time = $4, rrule = $5, exdate = $6, rdate = $7, series_end = $8, " +
+		"time_zone = $9, all_day = $10, resource = $11, capacity = $12, calendar_id = COALESCE($13, calendar_id), version = version + 1 " +
+		"WHERE id = $14 RETURNING calendar_id, version"
+
+	err = trx.QueryRowContext(ctx, query, event.Title, event.Description, event.StartTime, event.EndTime,
+		nullString(event.RRule), nullString(recurrence.FormatDateList(event.ExDates)), nullString(recurrence.FormatDateList(event.RDates)), seriesEnd,
+		event.zoneName(), event.AllDay, nullString(event.Resource), nullInt(event.Capacity), nullString(event.CalendarID), id).
+		Scan(&event.CalendarID, &version)
+
+	if err != nil {
+		if isCalendarViolation(err) {
+			err = fmt.Errorf("calendar %q does not exist: %w", event.CalendarID, ErrInput)
+		}
+
+		return CreateEventResponse{}, fmt.Errorf("updating event: %w", err)
+	}
+
+	updated := newStoredEvent(id, version, before.CreatedAt, before.CreatedBy, event)
+
+	if err := s.book(ctx, trx, updated, before.Resource != ""); err != nil {
+		return CreateEventResponse{}, fmt.Errorf("updating event: %w", err)
+	}
+
+	// A larger capacity frees seats for the waitlist.
+	if err := promoteWaitlist(ctx, trx, id, time.Now().UTC()); err != nil {
+		return CreateEventResponse{}, err
+	}
+
+	if err := recordRevision(ctx, trx, RevisionUpdated, &before, updated); err != nil {
+		return CreateEventResponse{}, err
+	}
+
+	return updated, trx.Commit()
+}
+
+// DeleteEvent tombstones an event. The row is kept so it can be restored later.
+func (s *Storage) DeleteEvent(ctx context.Context, id string) error {
+	query := "UPDATE events SET deleted_at = $1, version = version + 1 WHERE id = $2"
+
+	trx, err := s.begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer trx.Rollback()
+
+	before, err := lockedEvent(ctx, trx, id, false)
+	if err != nil {
+		return err
+	}
+
+	deletedAt := time.Now().UTC()
+
+	if _, err := trx.ExecContext(ctx, query, deletedAt, id); err != nil {
+		return fmt.Errorf("deleting event: %w", err)
+	}
+
+	deleted := before
+	deleted.DeletedAt = &deletedAt
+	deleted.Version++
+
+	if err := s.book(ctx, trx, deleted, before.Resource != ""); err != nil {
+		return fmt.Errorf("deleting event: %w", err)
+	}
+
+	if err := recordRevision(ctx, trx, RevisionDeleted, &before, deleted); err != nil {
+		return err
+	}
+
+	return trx.Commit()
+}
+
+// RestoreEvent clears the tombstone of a deleted event.
+func (s *Storage) RestoreEvent(ctx context.Context, id string) (CreateEventResponse, error) {
+	query := "UPDATE events SET deleted_at = NULL, version = version + 1 WHERE id = $1 RETURNING " + eventColumns
+
+	trx, err := s.begin(ctx)
+	if err != nil {
+		return CreateEventResponse{}, err
+	}
+
+	defer trx.Rollback()
+
+	before, err := lockedEvent(ctx, trx, id, true)
+	if err != nil {
+		return CreateEventResponse{}, err
+	}
+
+	event, err := scanEvent(trx.QueryRowContext(ctx, query, id))
+	if err != nil {
+		return CreateEventResponse{}, fmt.Errorf("restoring event: %w", err)
+	}
+
+	// Someone else may have booked the resource while the event was deleted.
+	if err := s.book(ctx, trx, event, false); err != nil {
+		return CreateEventResponse{}, fmt.Errorf("restoring event: %w", err)
+	}
+
+	if err := recordRevision(ctx, trx, RevisionRestored, &before, event); err != nil {
+		return CreateEventResponse{}, err
+	}
+
+	return event, trx.Commit()
+}
+
+// GetOccurrenceExceptions returns the exceptions of the given series.
+func (s *Storage) GetOccurrenceExceptions(ctx context.Context, seriesIDs []string) ([]OccurrenceException, error) {
+	trx, err := s.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer trx.Rollback()
+
+	return occurrenceExceptions(ctx, trx, seriesIDs)
+}
+
+func occurrenceExceptions(ctx context.Context, trx *sql.Tx, seriesIDs []string) ([]OccurrenceException, error) {
+	query := "SELECT " + exceptionColumns + " FROM event_exceptions WHERE series_id = ANY($1) ORDER BY series_id, recurrence_id"
+
+	rows, err := trx.QueryContext(ctx, query, pq.Array(seriesIDs))
+	if err != nil {
+		return nil, fmt.Errorf("getting exceptions: %w", err)
+	}
+
+	defer rows.Close()
+
+	var results []OccurrenceException
+
+	for rows.Next() {
+		exception, err := scanOccurrenceException(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning exception: %w", err)
+		}
+
+		results = append(results, exception)
+	}
+
+	return results, rows.Err()
+}
+
+// SaveOccurrenceException creates or replaces the exception of one occurrence.
+func (s *Storage) SaveOccurrenceException(ctx context.Context, exception OccurrenceException) error {
+	query := "INSERT INTO event_exceptions (series_id, recurrence_id, cancelled, title, description, start_time, end_time) " +
+		"VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT (series_id, recurrence_id) DO UPDATE SET " +
+		"cancelled = EXCLUDED.cancelled, title = EXCLUDED.title, description = EXCLUDED.description, " +
+		"start_time = EXCLUDED.start_time, end_time = EXCLUDED.end_time"
+
+	trx, err := s.begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer trx.Rollback()
+
+	series, err := lockedEvent(ctx, trx, exception.SeriesID, false)
+	if err != nil {
+		return err
+	}
+
+	// The exception this one replaces, if any, is what the revision changes from.
+	var before *OccurrenceException
+
+	previous, err := scanOccurrenceException(trx.QueryRowContext(ctx, "SELECT "+exceptionColumns+" FROM event_exceptions "+
+		"WHERE series_id = $1 AND recurrence_id = $2", exception.SeriesID, exception.RecurrenceID))
+	switch {
+	case err == nil:
+		before = &previous
+	case !errors.Is(err, sql.ErrNoRows):
+		return fmt.Errorf("getting exception: %w", err)
+	}
+
+	_, err = trx.ExecContext(ctx, query, exception.SeriesID, exception.RecurrenceID, exception.Cancelled,
+		nullString(exception.Title), nullString(exception.Description), nullTime(exception.StartTime), nullTime(exception.EndTime))
+	if err != nil {
+		return fmt.Errorf("saving exception: %w", err)
+	}
+
+	if _, err := trx.ExecContext(ctx, "UPDATE events SET version = version + 1 WHERE id = $1", exception.SeriesID); err != nil {
+		return fmt.Errorf("saving exception: %w", err)
+	}
+
+	// A moved or cancelled occurrence moves or frees its booking.
+	if err := s.book(ctx, trx, series, series.Resource != ""); err != nil {
+		return fmt.Errorf("saving exception: %w", err)
+	}
+
+	changes, err := diffOccurrence(before, exception)
+	if err != nil {
+		return fmt.Errorf("recording revision: %w", err)
+	}
+
+	if err := appendRevision(ctx, trx, RevisionUpdated, exception.SeriesID, changes, newEventState(series)); err != nil {
+		return err
+	}
+
+	return trx.Commit()
+}
+
+// SplitSeries truncates a series to head, drops its exceptions from at onwards
+// and creates tail as a new series, in one transaction.
+func (s *Storage) SplitSeries(ctx context.Context, id string, at time.Time, head, tail CreateEventRequest) (CreateEventResponse, error) {
+	trx, err := s.begin(ctx)
+	if err != nil {
+		return CreateEventResponse{}, err
+	}
+
+	defer trx.Rollback()
+
+	headEnd, err := head.seriesEnd()
+	if err != nil {
+		return CreateEventResponse{}, fmt.Errorf("computing series end: %w", err)
+	}
+
+	tailEnd, err := tail.seriesEnd()
+	if err != nil {
+		return CreateEventResponse{}, fmt.Errorf("computing series end: %w", err)
+	}
+
+	before, err := lockedEvent(ctx, trx, id, false)
+	if err != nil {
+		return CreateEventResponse{}, err
+	}
+
+	query := "UPDATE events SET rrule = $1, exdate = $2, rdate = $3, series_end = $4, version = version + 1 WHERE id = $5"
+
+	if _, err := trx.ExecContext(ctx, query, nullString(head.RRule), nullString(recurrence.FormatDateList(head.ExDates)),
+		nullString(recurrence.FormatDateList(head.RDates)), headEnd, id); err != nil {
+		return CreateEventResponse{}, fmt.Errorf("truncating series: %w", err)
+	}
+
+	truncated := before
+	truncated.RRule, truncated.ExDates, truncated.RDates = head.RRule, head.ExDates, head.RDates
+	truncated.Version++
+
+	if err := recordRevision(ctx, trx, RevisionUpdated, &before, truncated); err != nil {
+		return CreateEventResponse{}, err
+	}
+
+	if _, err := trx.ExecContext(ctx, "DELETE FROM event_exceptions WHERE series_id = $1 AND recurrence_id >= $2", id, at); err != nil {
+		return CreateEventResponse{}, fmt.Errorf("deleting exceptions: %w", err)
+	}
+
+	if err := s.book(ctx, trx, truncated, before.Resource != ""); err != nil {
+		return CreateEventResponse{}, fmt.Errorf("truncating series: %w", err)
+	}
+
+	newID := uuid.NewString()
+	createdAt := time.Now().UTC()
+
+	if _, err := trx.ExecContext(ctx, insertEventQuery, newID, tail.Title, tail.Description, tail.StartTime, tail.EndTime, createdAt,
+		nullString(tail.RRule), nullString(recurrence.FormatDateList(tail.ExDates)), nullString(recurrence.FormatDateList(tail.RDates)), tailEnd,
+		tail.zoneName(), tail.AllDay, nullString(tail.Resource), nullInt(tail.Capacity), tail.CalendarID, nullString(ActorFromContext(ctx))); err != nil {
+		if isCalendarViolation(err) {
+			err = fmt.Errorf("calendar %q does not exist: %w", tail.CalendarID, ErrInput)
+		}
+
+		return CreateEventResponse{}, fmt.Errorf("creating series: %w", err)
+	}
+
+	created := newStoredEvent(newID, 1, createdAt, ActorFromContext(ctx), tail)
+
+	if err := s.book(ctx, trx, created, false); err != nil {
+		return CreateEventResponse{}, fmt.Errorf("creating series: %w", err)
+	}
+
+	if err := recordRevision(ctx, trx, RevisionCreated, nil, created); err != nil {
+		return CreateEventResponse{}, err
+	}
+
+	return created, trx.Commit()
+}
+
+// ImportEvents upserts events by their iCalendar UID in one transaction. A UID
+// matches either an event id, for files exported by this API, or the UID of a
+// previous import. Updating a deleted event restores it.
+func (s *Storage) ImportEvents(ctx context.Context, events []ImportedEvent) ([]ImportResult, error) {
+	trx, err := s.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer trx.Rollback()
+
+	// Imported events go to the default calendar.
+	if err := ensureDefaultCalendar(ctx, trx); err != nil {
+		return nil, err
+	}
+
+	results := make([]ImportResult, 0, len(events))
+
+	for _, imported := range events {
+		event := imported.Event
+
+		seriesEnd, err := event.seriesEnd()
+		if err != nil {
+			return nil, fmt.Errorf("computing series end: %w", err)
+		}
+
+		exDates := nullString(recurrence.FormatDateList(event.ExDates))
+		rDates := nullString(recurrence.FormatDateList(event.RDates))
+
+		before, err := scanEvent(trx.QueryRowContext(ctx,
+			"SELECT "+eventColumns+" FROM events WHERE id = $1 OR ical_uid = $1 LIMIT 1 FOR UPDATE", imported.UID))
+		id := before.ID
+
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			id = uuid.NewString()
+			createdAt := time.Now().UTC()
+
+			query := "INSERT INTO events (id, title, description, start_time, end_time, created_at, rrule, exdate, rdate, series_end, time_zone, all_day, ical_uid, created_by) " +
+				"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)"
+
+			if _, err := trx.ExecContext(ctx, query, id, event.Title, event.Description, event.StartTime, event.EndTime, createdAt,
+				nullString(event.RRule), exDates, rDates, seriesEnd, event.zoneName(), event.AllDay, imported.UID, nullString(ActorFromContext(ctx))); err != nil {
+				return nil, fmt.Errorf("creating event %s: %w", imported.UID, err)
+			}
+
+			event.CalendarID = DefaultCalendarID
+
+			if err := recordRevision(ctx, trx, RevisionCreated, nil, newStoredEvent(id, 1, createdAt, ActorFromContext(ctx), event)); err != nil {
+				return nil, err
+			}
+
+			results = append(results, ImportResult{UID: imported.UID, ID: id, Status: ImportCreated})
+
+		case err != nil:
+			return nil, fmt.Errorf("finding event %s: %w", imported.UID, err)
+
+		default:
+			query := "UPDATE events SET title = $1, description = $2, start_time = $3, end_time = $4, rrule = $5, exdate = $6, rdate = $7, " +
+				"series_end = $8, time_zone = $9, all_day = $10, deleted_at = NULL, version = version + 1 WHERE id = $11"
+
+			if _, err := trx.ExecContext(ctx, query, event.Title, event.Description, event.StartTime, event.EndTime,
+				nullString(event.RRule), exDates, rDates, seriesEnd, event.zoneName(), event.AllDay, id); err != nil {
+				return nil, fmt.Errorf("updating event %s: %w", imported.UID, err)
+			}
+
+			event.Resource, event.Capacity, event.CalendarID = before.Resource, before.Capacity, before.CalendarID
+			updated := newStoredEvent(id, before.Version+1, before.CreatedAt, before.CreatedBy, event)
+
+			// Files carry no resource, so an update keeps the one the event books,
+			// which may now overlap another booking.
+			if err := s.book(ctx, trx, updated, before.Resource != ""); err != nil {
+				return nil, fmt.Errorf("updating event %s: %w", imported.UID, err)
+			}
+
+			if err := recordRevision(ctx, trx, RevisionUpdated, &before, updated); err != nil {
+				return nil, err
+			}
+
+			results = append(results, ImportResult{UID: imported.UID, ID: id, Status: ImportUpdated})
+		}
+	}
+
+	if err := trx.Commit(); err != nil {
+		return nil, fmt.Errorf("committing import: %w", err)
+	}
+
+	return results, nil
+}
+
+// GetEventRevisions returns the history of an event, oldest revision first.
+func (s *Storage) GetEventRevisions(ctx context.Context, eventID string) ([]EventRevision, error) {
+	query := "SELECT " + revisionColumns + " FROM event_revisions WHERE event_id = $1 ORDER BY revision"
+
+	trx, err := s.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer trx.Rollback()
+
+	rows, err := trx.QueryContext(ctx, query, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("getting revisions: %w", err)
+	}
+
+	defer rows.Close()
+
+	revisions := []EventRevision{}
+
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning revision: %w", err)
+		}
+
+		revisions = append(revisions, revision)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("getting revisions: %w", err)
+	}
+
+	// Events written before their history was kept have no revisions yet.
+	if len(revisions) == 0 {
+		var exists bool
+
+		if err := trx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM events WHERE id = $1)", eventID).Scan(&exists); err != nil {
+			return nil, fmt.Errorf("getting event: %w", err)
+		}
+
+		if !exists {
+			return nil, fmt.Errorf("event not found: %w", ErrNotFound)
+		}
+	}
+
+	return revisions, nil
+}
+
+func (s *Storage) GetEventRevision(ctx context.Context, eventID string, revision int) (EventRevision, error) {
+	query := "SELECT " + revisionColumns + " FROM event_revisions WHERE event_id = $1 AND revision = $2"
+
+	trx, err := s.begin(ctx)
+	if err != nil {
+		return EventRevision{}, err
+	}
+
+	defer trx.Rollback()
+
+	result, err := scanRevision(trx.QueryRowContext(ctx, query, eventID, revision))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return EventRevision{}, fmt.Errorf("revision not found: %w", ErrNotFound)
+		}
+
+		return EventRevision{}, fmt.Errorf("getting revision: %w", err)
+	}
+
+	return result, nil
+}
+
+// DeliverOutbox claims the oldest messages of every tenant that are due, at
+// most limit, and hands them to deliver in order. A delivered message is marked
+// published. A failed one is retried after a backoff, and set aside as dead
+// after outboxMaxAttempts attempts. It returns how many messages it delivered
+// and the failures.
+//
+// Only the oldest pending message of a key is claimed, so the messages of one
+// event keep their order: a later one waits while an earlier one is leased by
+// any relay or backing off, until it is published or dead.
+//
+// Nothing is locked while deliver runs: claiming a message only defers it by
+// outboxLease, so concurrent relays skip it and a relay that dies with it
+// claimed lets it be retried once the lease ends. The outbox is read without a
+// tenant, which is the only way its policies let it be read.
+func (s *Storage) DeliverOutbox(ctx context.Context, limit int, deliver func(context.Context, OutboxMessage) error) (int, error) {
+	if limit <= 0 {
+		return 0, fmt.Errorf("outbox limit should be positive: %w", ErrInput)
+	}
+
+	now := time.Now().UTC()
+
+	query := "UPDATE outbox SET next_attempt_at = $1 WHERE id IN (SELECT id FROM outbox " +
+		"WHERE published_at IS NULL AND dead_at IS NULL AND next_attempt_at <= $2 " +
+		"AND NOT EXISTS (SELECT 1 FROM outbox earlier WHERE earlier.tenant_id = outbox.tenant_id AND earlier.key = outbox.key " +
+		"AND earlier.id < outbox.id AND earlier.published_at IS NULL AND earlier.dead_at IS NULL) " +
+		"ORDER BY id LIMIT $3 FOR UPDATE SKIP LOCKED) " +
+		"RETURNING " + outboxColumns
+
+	rows, err := s.db.QueryContext(ctx, query, now.Add(outboxLease), now, limit)
+	if err != nil {
+		return 0, fmt.Errorf("claiming outbox messages: %w", err)
+	}
+
+	var messages []OutboxMessage
+
+	for rows.Next() {
+		var message OutboxMessage
+
+		if err := rows.Scan(&message.ID, &message.TenantID, &message.Topic, &message.Key, &message.Payload,
+			&message.CreatedAt, &message.Attempts); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scanning outbox message: %w", err)
+		}
+
+		messages = append(messages, message)
+	}
+
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("claiming outbox messages: %w", err)
+	}
+
+	// RETURNING does not keep the order of the subquery.
+	slices.SortFunc(messages, func(a, b OutboxMessage) int { return cmp.Compare(a.ID, b.ID) })
+
+	delivered := 0
+
+	var failures []error
+
+	for _, message := range messages {
+		deliverErr := deliver(ctx, message)
+		if deliverErr == nil {
+			if _, err := s.db.ExecContext(ctx, "UPDATE outbox SET attempts = attempts + 1, published_at = $1, last_error = NULL WHERE id = $2",
+				time.Now().UTC(), message.ID); err != nil {
+				return delivered, fmt.Errorf("marking outbox message %d published: %w", message.ID, err)
+			}
+
+			delivered++
+
+			continue
+		}
+
+		failures = append(failures, fmt.Errorf("publishing outbox message %d: %w", message.ID, deliverErr))
+
+		attempts := message.Attempts + 1
+		failedAt := time.Now().UTC()
+		retry := failedAt.Add(outboxBackoff(attempts))
+
+		var deadAt time.Time
+		if attempts >= outboxMaxAttempts {
+			deadAt = failedAt
+		}
+
+		if _, err := s.db.ExecContext(ctx, "UPDATE outbox SET attempts = $1, last_error = $2, next_attempt_at = $3, dead_at = $4 WHERE id = $5",
+			attempts, deliverErr.Error(), retry, nullTime(deadAt), message.ID); err != nil {
+			return delivered, fmt.Errorf("recording outbox failure: %w", err)
+		}
+	}
+
+	return delivered, errors.Join(failures...)
+}
+
+// outboxBackoff is how long a message waits after its attempts-th failure: a
+// second, doubling up to an hour.
+func outboxBackoff(attempts int) time.Duration {
+	if attempts > 12 {
+		return time.Hour
+	}
+
+	return min(time.Second<<(attempts-1), time.Hour)
+}
+
+func (s *Storage) CreateCalendar(ctx context.Context, calendar Calendar) (Calendar, error) {
+	calendar.ID = uuid.NewString()
+	calendar.CreatedAt = time.Now().UTC()
+
+	query := "INSERT INTO calendars (" + calendarColumns + ") VALUES ($1, $2, $3, $4, $5)"
+
+	trx, err := s.begin(ctx)
+	if err != nil {
+		return Calendar{}, err
+	}
+
+	defer trx.Rollback()
+
+	if _, err := trx.ExecContext(ctx, query, calendar.ID, calendar.Name, calendar.Description, calendar.CreatedAt,
+		nullString(calendar.CreatedBy)); err != nil {
+		return Calendar{}, fmt.Errorf("creating calendar: %w", err)
+	}
+
+	return calendar, trx.Commit()
+}
+
+// GetCalendars lists every calendar by name.
+func (s *Storage) GetCalendars(ctx context.Context, visibleTo string) ([]Calendar, error) {
+	trx, err := s.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer trx.Rollback()
+
+	if err := ensureDefaultCalendar(ctx, trx); err != nil {
+		return nil, err
+	}
+
+	query := "SELECT " + calendarColumns + " FROM calendars"
+	var args []any
+
+	if visibleTo != "" {
+		query += " WHERE id = $1 OR created_by = $2 OR id IN " +
+			"(SELECT object_id FROM grants WHERE object_type = 'calendar' AND principal = $2)"
+		args = append(args, DefaultCalendarID, visibleTo)
+	}
+
+	rows, err := trx.QueryContext(ctx, query+" ORDER BY name, id", args...)
+	if err != nil {
+		return nil, fmt.Errorf("getting calendars: %w", err)
+	}
+
+	defer rows.Close()
+
+	calendars := []Calendar{}
+
+	for rows.Next() {
+		calendar, err := scanCalendar(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning calendar: %w", err)
+		}
+
+		calendars = append(calendars, calendar)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("getting calendars: %w", err)
+	}
+
+	return calendars, trx.Commit()
+}
+
+func (s *Storage) GetCalendar(ctx context.Context, id string) (Calendar, error) {
+	trx, err := s.begin(ctx)
+	if err != nil {
+		return Calendar{}, err
+	}
+
+	defer trx.Rollback()
+
+	if id == DefaultCalendarID {
+		if err := ensureDefaultCalendar(ctx, trx); err != nil {
+			return Calendar{}, err
+		}
+	}
+
+	calendar, err := scanCalendar(trx.QueryRowContext(ctx, "SELECT "+calendarColumns+" FROM calendars WHERE id = $1", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Calendar{}, fmt.Errorf("calendar not found: %w", ErrNotFound)
+		}
+
+		return Calendar{}, fmt.Errorf("getting calendar: %w", err)
+	}
+
+	return calendar, trx.Commit()
+}
+
+func (s *Storage) UpdateCalendar(ctx context.Context, calendar Calendar) (Calendar, error) {
+	query := "UPDATE calendars SET name = $1, description = $2 WHERE id = $3 RETURNING " + calendarColumns
+
+	trx, err := s.begin(ctx)
+	if err != nil {
+		return Calendar{}, err
+	}
+
+	defer trx.Rollback()
+
+	updated, err := scanCalendar(trx.QueryRowContext(ctx, query, calendar.Name, calendar.Description, calendar.ID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Calendar{}, fmt.Errorf("calendar not found: %w", ErrNotFound)
+		}
+
+		return Calendar{}, fmt.Errorf("updating calendar: %w", err)
+	}
+
+	return updated, trx.Commit()
+}
+
+// DeleteCalendar removes a calendar. The foreign key of its events, deleted
+// ones included, keeps a calendar that is still in use.
+func (s *Storage) DeleteCalendar(ctx context.Context, id string) error {
+	trx, err := s.begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer trx.Rollback()
+
+	result, err := trx.ExecContext(ctx, "DELETE FROM calendars WHERE id = $1", id)
+	if err != nil {
+		if isCalendarViolation(err) {
+			return fmt.Errorf("calendar still has events: %w", ErrConflict)
+		}
+
+		return fmt.Errorf("deleting calendar: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("deleting calendar: %w", err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("calendar not found: %w", ErrNotFound)
+	}
+
+	if _, err := trx.ExecContext(ctx, "DELETE FROM grants WHERE object_type = 'calendar' AND object_id = $1", id); err != nil {
+		return fmt.Errorf("deleting calendar grants: %w", err)
+	}
+
+	return trx.Commit()
+}
+
+func (s *Storage) CreateAPIKey(ctx context.Context, key APIKey, hash []byte) error {
+	query := "INSERT INTO api_keys (id, name, prefix, hash, scopes, created_by, created_at, expires_at) " +
+		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8)"
+
+	trx, err := s.begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer trx.Rollback()
+
+	if _, err := trx.ExecContext(ctx, query, key.ID, key.Name, key.Prefix, hash, pq.Array(key.Scopes), nullString(key.CreatedBy),
+		key.CreatedAt, key.ExpiresAt); err != nil {
+		return fmt.Errorf("creating api key: %w", err)
+	}
+
+	return trx.Commit()
+}
+
+// GetAPIKeys lists every API key, newest first.
+func (s *Storage) GetAPIKeys(ctx context.Context) ([]APIKey, error) {
+	trx, err := s.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer trx.Rollback()
+
+	rows, err := trx.QueryContext(ctx, "SELECT "+apiKeyColumns+" FROM api_keys ORDER BY created_at DESC, id")
+	if err != nil {
+		return nil, fmt.Errorf("getting api keys: %w", err)
+	}
+
+	defer rows.Close()
+
+	keys := []APIKey{}
+
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning api key: %w", err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+func (s *Storage) GetAPIKeyByHash(ctx context.Context, hash []byte) (APIKey, error) {
+	trx, err := s.begin(ctx)
+	if err != nil {
+		return APIKey{}, err
+	}
+
+	defer trx.Rollback()
+
+	key, err := scanAPIKey(trx.QueryRowContext(ctx, "SELECT "+apiKeyColumns+" FROM api_keys WHERE hash = $1", hash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return APIKey{}, fmt.Errorf("api key not found: %w", ErrNotFound)
+		}
+
+		return APIKey{}, fmt.Errorf("getting api key: %w", err)
+	}
+
+	return key, nil
+}
+
+// RevokeAPIKey marks a key revoked. Revoking it again keeps the first time.
+func (s *Storage) RevokeAPIKey(ctx context.Context, id string, at time.Time) error {
+	trx, err := s.begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer trx.Rollback()
+
+	result, err := trx.ExecContext(ctx, "UPDATE api_keys SET revoked_at = COALESCE(revoked_at, $1) WHERE id = $2", at, id)
+	if err != nil {
+		return fmt.Errorf("revoking api key: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("revoking api key: %w", err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("api key not found: %w", ErrNotFound)
+	}
+
+	return trx.Commit()
+}
+
+func (s *Storage) TouchAPIKey(ctx context.Context, id string, at time.Time) error {
+	trx, err := s.begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer trx.Rollback()
+
+	if _, err := trx.ExecContext(ctx, "UPDATE api_keys SET last_used_at = $1 WHERE id = $2", at, id); err != nil {
+		return fmt.Errorf("recording api key use: %w", err)
+	}
+
+	return trx.Commit()
+}
+
+// ReserveIdempotencyKey removes the expired keys of the tenant, then inserts
+// record unless its key is taken. A concurrent reservation of the same key
+// waits for the first one to commit and then finds it.
+func (s *Storage) ReserveIdempotencyKey(ctx context.Context, record IdempotencyRecord) (IdempotencyRecord, bool, error) {
+	insert := "INSERT INTO idempotency_keys (principal, key, fingerprint, created_at, expires_at) VALUES ($1, $2, $3, $4, $5) " +
+		"ON CONFLICT (tenant_id, principal, key) DO NOTHING"
+	query := "SELECT fingerprint, status, body, created_at, expires_at FROM idempotency_keys WHERE principal = $1 AND key = $2"
+
+	trx, err := s.begin(ctx)
+	if err != nil {
+		return IdempotencyRecord{}, false, err
+	}
+
+	defer trx.Rollback()
+
+	if _, err := trx.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE expires_at <= $1", record.CreatedAt); err != nil {
+		return IdempotencyRecord{}, false, fmt.Errorf("removing expired idempotency keys: %w", err)
+	}
+
+	result, err := trx.ExecContext(ctx, insert, record.Principal, record.Key, record.Fingerprint, record.CreatedAt, record.ExpiresAt)
+	if err != nil {
+		return IdempotencyRecord{}, false, fmt.Errorf("inserting idempotency key: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return IdempotencyRecord{}, false, fmt.Errorf("inserting idempotency key: %w", err)
+	}
+
+	if affected == 1 {
+		return record, true, trx.Commit()
+	}
+
+	stored := IdempotencyRecord{Principal: record.Principal, Key: record.Key}
+
+	var status sql.NullInt64
+
+	if err := trx.QueryRowContext(ctx, query, record.Principal, record.Key).
+		Scan(&stored.Fingerprint, &status, &stored.Body, &stored.CreatedAt, &stored.ExpiresAt); err != nil {
+		return IdempotencyRecord{}, false, fmt.Errorf("getting idempotency key: %w", err)
+	}
+
+	stored.Status = int(status.Int64)
+
+	return stored, false, nil
+}
+
+func (s *Storage) SaveIdempotentResponse(ctx context.Context, principal, key string, status int, body []byte) error {
+	trx, err := s.begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer trx.Rollback()
+
+	if _, err := trx.ExecContext(ctx, "UPDATE idempotency_keys SET status = $1, body = $2 WHERE principal = $3 AND key = $4",
+		status, body, principal, key); err != nil {
+		return fmt.Errorf("saving idempotent response: %w", err)
+	}
+
+	return trx.Commit()
+}
+
+// ReleaseIdempotencyKey removes a key whose request has no response, leaving
+// completed ones alone.
+func (s *Storage) ReleaseIdempotencyKey(ctx context.Context, principal, key string) error {
+	trx, err := s.begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer trx.Rollback()
+
+	if _, err := trx.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE principal = $1 AND key = $2 AND status IS NULL",
+		principal, key); err != nil {
+		return fmt.Errorf("releasing idempotency key: %w", err)
+	}
+
+	return trx.Commit()
+}
+
+// GetCalendarAccess returns the roles principal holds on a calendar: its grants,
+// and owner when it created the calendar.
+func (s *Storage) GetCalendarAccess(ctx context.Context, principal, calendarID string) ([]AccessRole, error) {
+	query := "SELECT ARRAY(SELECT role FROM grants WHERE object_type = 'calendar' AND object_id = $1 AND principal = $2 " +
+		"UNION ALL SELECT 'owner' FROM calendars WHERE id = $1 AND created_by = $2)"
+
+	trx, err := s.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer trx.Rollback()
+
+	var roles []string
+
+	if err := trx.QueryRowContext(ctx, query, calendarID, principal).Scan(pq.Array(&roles)); err != nil {
+		return nil, fmt.Errorf("getting calendar access: %w", err)
+	}
+
+	return accessRoles(roles), nil
+}
+
+// GetEventAccess returns the calendar of an event, deleted or not, and the
+// roles principal holds on the event or its calendar, owner included when it
+// created either.
+func (s *Storage) GetEventAccess(ctx context.Context, principal, eventID string) (EventAccess, error) {
+	return s.eventAccess(ctx, principal, "e.id = $1", eventID)
+}
+
+func (s *Storage) GetEventAccessByUID(ctx context.Context, principal, uid string) (EventAccess, error) {
+	return s.eventAccess(ctx, principal, "(e.id = $1 OR e.ical_uid = $1)", uid)
+}
+
+func (s *Storage) eventAccess(ctx context.Context, principal, condition, value string) (EventAccess, error) {
+	query := "SELECT e.calendar_id, ARRAY(SELECT role FROM grants WHERE principal = $2 AND " +
+		"((object_type = 'event' AND object_id = e.id) OR (object_type = 'calendar' AND object_id = e.calendar_id)) " +
+		"UNION ALL SELECT 'owner' WHERE e.created_by = $2 OR c.created_by = $2) " +
+		"FROM events e JOIN calendars c ON c.tenant_id = e.tenant_id AND c.id = e.calendar_id WHERE " + condition + " LIMIT 1"
+
+	trx, err := s.begin(ctx)
+	if err != nil {
+		return EventAccess{}, err
+	}
+
+	defer trx.Rollback()
+
+	var access EventAccess
+	var roles []string
+
+	if err := trx.QueryRowContext(ctx, query, value, principal).Scan(&access.CalendarID, pq.Array(&roles)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return EventAccess{}, fmt.Errorf("event not found: %w", ErrNotFound)
+		}
+
+		return EventAccess{}, fmt.Errorf("getting event access: %w", err)
+	}
+
+	access.Roles = accessRoles(roles)
+
+	return access, nil
+}
+
+func accessRoles(values []string) []AccessRole {
+	roles := make([]AccessRole, 0, len(values))
+	for _, value := range values {
+		roles = append(roles, AccessRole(value))
+	}
+
+	return roles
+}
+
+// GetGrants lists the grants on a calendar or event, oldest first.
+func (s *Storage) GetGrants(ctx context.Context, objectType ObjectType, objectID string) ([]Grant, error) {
+	trx, err := s.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer trx.Rollback()
+
+	query := "SELECT " + grantColumns + " FROM grants WHERE object_type = $1 AND object_id = $2 ORDER BY created_at, principal"
+
+	rows, err := trx.QueryContext(ctx, query, objectType, objectID)
+	if err != nil {
+		return nil, fmt.Errorf("getting grants: %w", err)
+	}
+
+	defer rows.Close()
+
+	grants := []Grant{}
+
+	for rows.Next() {
+		var grant Grant
+		var grantedBy sql.NullString
+
+		if err := rows.Scan(&grant.ObjectType, &grant.ObjectID, &grant.Principal, &grant.Role, &grantedBy, &grant.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning grant: %w", err)
+		}
+
+		grant.GrantedBy = grantedBy.String
+		grants = append(grants, grant)
+	}
+
+	return grants, rows.Err()
+}
+
+// SetGrant gives a principal a role on a calendar or an event that is not
+// deleted, replacing the role it had.
+func (s *Storage) SetGrant(ctx context.Context, grant Grant) error {
+	exists := "SELECT EXISTS (SELECT 1 FROM calendars WHERE id = $1)"
+	if grant.ObjectType == ObjectEvent {
+		exists = "SELECT EXISTS (SELECT 1 FROM events WHERE id = $1 AND deleted_at IS NULL)"
+	}
+
+	query := "INSERT INTO grants (" + grantColumns + ") VALUES ($1, $2, $3, $4, $5, $6) " +
+		"ON CONFLICT (tenant_id, object_type, object_id, principal) " +
+		"DO UPDATE SET role = EXCLUDED.role, granted_by = EXCLUDED.granted_by, created_at = EXCLUDED.created_at"
+
+	trx, err := s.begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer trx.Rollback()
+
+	if grant.ObjectType == ObjectCalendar && grant.ObjectID == DefaultCalendarID {
+		if err := ensureDefaultCalendar(ctx, trx); err != nil {
+			return err
+		}
+	}
+
+	var found bool
+
+	if err := trx.QueryRowContext(ctx, exists, grant.ObjectID).Scan(&found); err != nil {
+		return fmt.Errorf("getting %s: %w", grant.ObjectType, err)
+	}
+
+	if !found {
+		return fmt.Errorf("%s not found: %w", grant.ObjectType, ErrNotFound)
+	}
+
+	if _, err := trx.ExecContext(ctx, query, grant.ObjectType, grant.ObjectID, grant.Principal, grant.Role,
+		nullString(grant.GrantedBy), grant.CreatedAt); err != nil {
+		return fmt.Errorf("setting grant: %w", err)
+	}
+
+	return trx.Commit()
+}
+
+func (s *Storage) DeleteGrant(ctx context.Context, objectType ObjectType, objectID, principal string) error {
+	trx, err := s.begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer trx.Rollback()
+
+	query := "DELETE FROM grants WHERE object_type = $1 AND object_id = $2 AND principal = $3"
+
+	result, err := trx.ExecContext(ctx, query, objectType, objectID, principal)
+	if err != nil {
+		return fmt.Errorf("deleting grant: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("deleting grant: %w", err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("grant not found: %w", ErrNotFound)
+	}
+
+	return trx.Commit()
+}
+
+// AddAttendee invites someone to an event that exists and is not deleted.
+func (s *Storage) AddAttendee(ctx context.Context, attendee Attendee) error {
+	query := "INSERT INTO attendees (" + attendeeColumns + ") " +
+		"SELECT id, $2, $3, $4, $5, $6, $7 FROM events WHERE id = $1 AND deleted_at IS NULL"
+
+	trx, err := s.begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer trx.Rollback()
+
+	if _, err := lockEvent(ctx, trx, attendee.EventID); err != nil {
+		return err
+	}
+
+	result, err := trx.ExecContext(ctx, query, attendee.EventID, attendee.Email, attendee.Name, attendee.Role, attendee.Status,
+		attendee.InvitedAt, attendee.StatusChangedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("%s is already invited: %w", attendee.Email, ErrConflict)
+		}
+
+		return fmt.Errorf("adding attendee: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("adding attendee: %w", err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("event not found: %w", ErrNotFound)
+	}
+
+	return trx.Commit()
+}
+
+// GetAttendees lists the attendees of an event in the order they were invited.
+func (s *Storage) GetAttendees(ctx context.Context, eventID string) ([]Attendee, error) {
+	trx, err := s.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer trx.Rollback()
+
+	var exists bool
+
+	query := "SELECT EXISTS (SELECT 1 FROM events WHERE id = $1 AND deleted_at IS NULL)"
+	if err := trx.QueryRowContext(ctx, query, eventID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("getting event: %w", err)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("event not found: %w", ErrNotFound)
+	}
+
+	query = "SELECT " + attendeeColumns + " FROM attendees WHERE event_id = $1 ORDER BY invited_at, email"
+
+	rows, err := trx.QueryContext(ctx, query, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("getting attendees: %w", err)
+	}
+
+	defer rows.Close()
+
+	attendees := []Attendee{}
+
+	for rows.Next() {
+		attendee, err := scanAttendee(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning attendee: %w", err)
+		}
+
+		attendees = append(attendees, attendee)
+	}
+
+	return attendees, rows.Err()
+}
+
+// SetAttendeeStatus records an answer. Accepting a full event joins the
+// waitlist instead, and giving up a seat promotes the next waitlisted attendee.
+// status_changed_at only moves when the status does, which keeps the place of
+// a waitlisted attendee who accepts again.
+//
+// Every change to who holds a seat locks the event row first, so concurrent
+// answers to the same event are applied one at a time.
+func (s *Storage) SetAttendeeStatus(ctx context.Context, eventID, email string, status AttendeeStatus, at time.Time) (Attendee, error) {
+	trx, err := s.begin(ctx)
+	if err != nil {
+		return Attendee{}, err
+	}
+
+	defer trx.Rollback()
+
+	capacity, err := lockEvent(ctx, trx, eventID)
+	if err != nil {
+		return Attendee{}, err
+	}
+
+	query := "SELECT " + attendeeColumns + " FROM attendees WHERE event_id = $1 AND email = $2"
+
+	current, err := scanAttendee(trx.QueryRowContext(ctx, query, eventID, email))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Attendee{}, fmt.Errorf("attendee not found: %w", ErrNotFound)
+		}
+
+		return Attendee{}, fmt.Errorf("getting attendee: %w", err)
+	}
+
+	if status == StatusAccepted && current.Status != StatusAccepted && capacity.Valid {
+		var accepted int64
+
+		query = "SELECT COUNT(*) FROM attendees WHERE event_id = $1 AND status = $2"
+		if err := trx.QueryRowContext(ctx, query, eventID, StatusAccepted).Scan(&accepted); err != nil {
+			return Attendee{}, fmt.Errorf("counting attendees: %w", err)
+		}
+
+		if accepted >= capacity.Int64 {
+			status = StatusWaitlisted
+		}
+	}
+
+	query = "UPDATE attendees SET status_changed_at = CASE WHEN status = $1 THEN status_changed_at ELSE $2 END, status = $1 " +
+		"WHERE event_id = $3 AND email = $4 RETURNING " + attendeeColumns
+
+	attendee, err := scanAttendee(trx.QueryRowContext(ctx, query, status, at, eventID, email))
+	if err != nil {
+		return Attendee{}, fmt.Errorf("updating attendee: %w", err)
+	}
+
+	if current.Status == StatusAccepted && status != StatusAccepted {
+		if err := promoteWaitlist(ctx, trx, eventID, at); err != nil {
+			return Attendee{}, err
+		}
+	}
+
+	if err := trx.Commit(); err != nil {
+		return Attendee{}, fmt.Errorf("committing answer: %w", err)
+	}
+
+	return attendee, nil
+}
+
+// RemoveAttendee uninvites someone. The seat of an accepted attendee goes to
+// the next waitlisted one.
+func (s *Storage) RemoveAttendee(ctx context.Context, eventID, email string) error {
+	trx, err := s.begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer trx.Rollback()
+
+	if _, err := lockEvent(ctx, trx, eventID); err != nil {
+		return err
+	}
+
+	var status AttendeeStatus
+
+	query := "DELETE FROM attendees WHERE event_id = $1 AND email = $2 RETURNING status"
+	if err := trx.QueryRowContext(ctx, query, eventID, email).Scan(&status); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("attendee not found: %w", ErrNotFound)
+		}
+
+		return fmt.Errorf("removing attendee: %w", err)
+	}
+
+	if status == StatusAccepted {
+		if err := promoteWaitlist(ctx, trx, eventID, time.Now().UTC()); err != nil {
+			return err
+		}
+	}
+
+	if err := trx.Commit(); err != nil {
+		return fmt.Errorf("committing removal: %w", err)
+	}
+
+	return nil
+}
+
+// lockEvent takes the row lock that serializes seat changes of an event and
+// returns its capacity. The attendee counts are part of the event as it is
+// read, so the change also increments its version.
+func lockEvent(ctx context.Context, trx *sql.Tx, eventID string) (sql.NullInt64, error) {
+	var capacity sql.NullInt64
+
+	query := "UPDATE events SET version = version + 1 WHERE id = $1 AND deleted_at IS NULL RETURNING capacity"
+	if err := trx.QueryRowContext(ctx, query, eventID).Scan(&capacity); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return sql.NullInt64{}, fmt.Errorf("event not found: %w", ErrNotFound)
+		}
+
+		return sql.NullInt64{}, fmt.Errorf("locking event: %w", err)
+	}
+
+	return capacity, nil
+}
+
+// lockedEvent reads an event, deleted or not as asked, and locks it until trx
+// ends. Writes read it first to record what they change.
+func lockedEvent(ctx context.Context, trx *sql.Tx, id string, deleted bool) (CreateEventResponse, error) {
+	query := "SELECT " + eventColumns + " FROM events WHERE id = $1 AND deleted_at IS NULL FOR UPDATE"
+	missing := "event not found"
+
+	if deleted {
+		query = "SELECT " + eventColumns + " FROM events WHERE id = $1 AND deleted_at IS NOT NULL FOR UPDATE"
+		missing = "deleted event not found"
+	}
+
+	event, err := scanEvent(trx.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return CreateEventResponse{}, fmt.Errorf("%s: %w", missing, ErrNotFound)
+		}
+
+		return CreateEventResponse{}, fmt.Errorf("getting event: %w", err)
+	}
+
+	return event, nil
+}
+
+// promoteWaitlist accepts waitlisted attendees, first come first served, until
+// the event is full. Without a capacity the whole waitlist is accepted. The
+// caller holds the lock on the event row.
+func promoteWaitlist(ctx context.Context, trx *sql.Tx, eventID string, at time.Time) error {
+	query := "UPDATE attendees SET status = $1, status_changed_at = $2 WHERE event_id = $3 AND email IN (" +
+		"SELECT email FROM attendees WHERE event_id = $3 AND status = $4 ORDER BY status_changed_at, email " +
+		"LIMIT (SELECT CASE WHEN capacity IS NULL THEN NULL " +
+		"ELSE GREATEST(capacity - (SELECT COUNT(*) FROM attendees WHERE event_id = $3 AND status = $1), 0) END " +
+		"FROM events WHERE id = $3))"
+
+	if _, err := trx.ExecContext(ctx, query, StatusAccepted, at, eventID, StatusWaitlisted); err != nil {
+		return fmt.Errorf("promoting waitlist: %w", err)
+	}
+
+	return nil
+}
+
+// CountAttendees counts the attendees of an event by status. Statuses nobody
+// is in are missing.
+func (s *Storage) CountAttendees(ctx context.Context, eventID string) (map[AttendeeStatus]int, error) {
+	trx, err := s.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer trx.Rollback()
+
+	rows, err := trx.QueryContext(ctx, "SELECT status, COUNT(*) FROM attendees WHERE event_id = $1 GROUP BY status", eventID)
+	if err != nil {
+		return nil, fmt.Errorf("counting attendees: %w", err)
+	}
+
+	defer rows.Close()
+
+	counts := make(map[AttendeeStatus]int)
+
+	for rows.Next() {
+		var status AttendeeStatus
+		var count int
+
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("scanning attendee count: %w", err)
+		}
+
+		counts[status] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// begin starts a transaction scoped to the tenant of ctx. Row level security
+// only lets it see and write the rows of that tenant.
+func (s *Storage) begin(ctx context.Context) (*sql.Tx, error) {
+	tenantID, ok := TenantFromContext(ctx)
+	if !ok {
+		return nil, errNoTenant
+	}
+
+	trx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating transaction :%w", err)
+	}
+
+	// A local setting ends with the transaction, so a pooled connection never
+	// carries it over to another tenant.
+	if _, err := trx.ExecContext(ctx, "SELECT set_config('app.tenant_id', $1, true)", tenantID); err != nil {
+		trx.Rollback()
+		return nil, fmt.Errorf("setting tenant: %w", err)
+	}
+
+	return trx, nil
+}
+
+// ensureDefaultCalendar creates the default calendar of the tenant the first
+// time the tenant needs it.
+func ensureDefaultCalendar(ctx context.Context, trx *sql.Tx) error {
+	query := "INSERT INTO calendars (id, name) VALUES ($1, 'Default') ON CONFLICT (tenant_id, id) DO NOTHING"
+
+	if _, err := trx.ExecContext(ctx, query, DefaultCalendarID); err != nil {
+		return fmt.Errorf("creating default calendar: %w", err)
+	}
+
+	return nil
+}
+
+// recordRevision appends a write to the history of an event, in the
+// transaction of the write. before is nil when the write created the event.
+// The event row is locked, so revisions of one event are numbered in order.
+// Each revision is also written to the outbox, to be published downstream.
+func recordRevision(ctx context.Context, trx *sql.Tx, action RevisionAction, before *CreateEventResponse, after CreateEventResponse) error {
+	var previous *eventState
+
+	if before != nil {
+		state := newEventState(*before)
+		previous = &state
+	}
+
+	snapshot := newEventState(after)
+
+	changes, err := diffStates(previous, snapshot)
+	if err != nil {
+		return fmt.Errorf("recording revision: %w", err)
+	}
+
+	return appendRevision(ctx, trx, action, after.ID, changes, snapshot)
+}
+
+// appendRevision writes a revision with the given changes, and its outbox
+// message. snapshot is the event after the write.
+func appendRevision(ctx context.Context, trx *sql.Tx, action RevisionAction, eventID string, changes map[string]FieldChange,
+	snapshot eventState) error {
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return fmt.Errorf("recording revision: %w", err)
+	}
+
+	snapshotJSON, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("recording revision: %w", err)
+	}
+
+	query := "INSERT INTO event_revisions (event_id, revision, action, actor, created_at, changes, snapshot) " +
+		"SELECT $1, COALESCE(MAX(revision), 0) + 1, $2, $3, $4, $5, $6 FROM event_revisions WHERE event_id = $1 RETURNING revision"
+
+	actor := ActorFromContext(ctx)
+	createdAt := time.Now().UTC()
+
+	var revision int
+
+	if err := trx.QueryRowContext(ctx, query, eventID, action, nullString(actor), createdAt,
+		changesJSON, snapshotJSON).Scan(&revision); err != nil {
+		return fmt.Errorf("recording revision: %w", err)
+	}
+
+	return enqueueOutbox(ctx, trx, eventTopic(action), eventID, eventMessage{
+		EventID:    eventID,
+		Revision:   revision,
+		Action:     action,
+		Actor:      actor,
+		OccurredAt: createdAt,
+		Changes:    changes,
+		Event:      snapshot,
+	})
+}
+
+// enqueueOutbox writes a message to the outbox in the transaction of the change
+// it describes, so it is published if and only if the change commits.
+func enqueueOutbox(ctx context.Context, trx *sql.Tx, topic, key string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encoding outbox message: %w", err)
+	}
+
+	query := "INSERT INTO outbox (topic, key, payload, created_at) VALUES ($1, $2, $3, $4)"
+
+	if _, err := trx.ExecContext(ctx, query, topic, key, data, time.Now().UTC()); err != nil {
+		return fmt.Errorf("writing outbox message: %w", err)
+	}
+
+	return nil
+}
+
+// newStoredEvent is the event as a read would return it right after a write.
+func newStoredEvent(id string, version int, createdAt time.Time, createdBy string, event CreateEventRequest) CreateEventResponse {
+	stored := CreateEventResponse{
+		ID:          id,
+		Version:     version,
+		Title:       event.Title,
+		Description: event.Description,
+		StartTime:   event.StartTime,
+		EndTime:     event.EndTime,
+		CreatedAt:   createdAt,
+		RRule:       event.RRule,
+		ExDates:     event.ExDates,
+		RDates:      event.RDates,
+		TimeZone:    event.zoneName(),
+		AllDay:      event.AllDay,
+		Resource:    event.Resource,
+		Capacity:    event.Capacity,
+		CalendarID:  event.CalendarID,
+		CreatedBy:   createdBy,
+	}
+
+	return stored.In(stored.location())
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+func isBookingConflict(err error) bool {
+	var pqErr *pq.Error
+
+	return errors.As(err, &pqErr) && pqErr.Constraint == resourceConstraint
+}
+
+// isCalendarViolation reports a broken link between an event and its calendar:
+// the event was written into a calendar deleted after Service checked it, or
+// a calendar that still has events was deleted.
+func isCalendarViolation(err error) bool {
+	var pqErr *pq.Error
+
+	return errors.As(err, &pqErr) && pqErr.Constraint == calendarConstraint && pqErr.Code == "23503"
+}
+
+// book replaces the bookings of event by one per occurrence, which the
+// exclusion constraint of event_bookings checks against the bookings of every
+// other event. booked says whether the event may hold bookings to release. A
+// deleted event, or one without a resource, books nothing.
+func (s *Storage) book(ctx context.Context, trx *sql.Tx, event CreateEventResponse, booked bool) error {
+	if booked {
+		if _, err := trx.ExecContext(ctx, "DELETE FROM event_bookings WHERE event_id = $1", event.ID); err != nil {
+			return fmt.Errorf("releasing bookings: %w", err)
+		}
+	}
+
+	if event.Resource == "" || event.DeletedAt != nil {
+		return nil
+	}
+
+	var exceptions []OccurrenceException
+
+	if event.isRecurring() {
+		var err error
+
+		exceptions, err = occurrenceExceptions(ctx, trx, []string{event.ID})
+		if err != nil {
+			return fmt.Errorf("getting exceptions: %w", err)
+		}
+	}
+
+	periods, err := bookedPeriods(event, exceptions)
+	if err != nil {
+		return err
+	}
+
+	ranges := make([]string, 0, len(periods))
+	for _, period := range periods {
+		ranges = append(ranges, "["+period.Start.UTC().Format(time.RFC3339Nano)+","+period.End.UTC().Format(time.RFC3339Nano)+")")
+	}
+
+	query := "INSERT INTO event_bookings (event_id, resource, period) SELECT $1, $2, unnest($3::tstzrange[])"
+
+	if _, err := trx.ExecContext(ctx, query, event.ID, event.Resource, pq.Array(ranges)); err != nil {
+		if isBookingConflict(err) {
+			trx.Rollback()
+			return s.bookingConflict(ctx, event.ID, event.Resource, ranges)
+		}
+
+		return fmt.Errorf("booking resource: %w", err)
+	}
+
+	return nil
+}
+
+// bookingConflict lists the events, other than id, that book resource at a time
+// overlapping any of ranges. The conflict aborts the transaction that hit it, so
+// callers roll that one back and the lookup runs in a transaction of its own.
+func (s *Storage) bookingConflict(ctx context.Context, id, resource string, ranges []string) error {
+	query := "SELECT id FROM events WHERE id <> $2 AND id IN (SELECT event_id FROM event_bookings " +
+		"WHERE resource = $1 AND period && ANY($3::tstzrange[])) ORDER BY start_time, id"
+
+	conflict := &ConflictError{Resource: resource}
+
+	trx, err := s.begin(ctx)
+	if err != nil {
+		return fmt.Errorf("finding conflicting events: %s: %w", err.Error(), conflict)
+	}
+
+	defer trx.Rollback()
+
+	rows, err := trx.QueryContext(ctx, query, resource, id, pq.Array(ranges))
+	if err != nil {
+		return fmt.Errorf("finding conflicting events: %s: %w", err.Error(), conflict)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var conflictID string
+		if err := rows.Scan(&conflictID); err != nil {
+			return fmt.Errorf("finding conflicting events: %s: %w", err.Error(), conflict)
+		}
+
+		conflict.EventIDs = append(conflict.EventIDs, conflictID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("finding conflicting events: %s: %w", err.Error(), conflict)
+	}
+
+	return conflict
+}
+
+// likeEscaper makes user input match literally inside a LIKE pattern.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanEvent(row scanner) (CreateEventResponse, error) {
+	var event CreateEventResponse
+	var deletedAt sql.NullTime
+	var rrule, exDates, rDates, timeZone, resource, createdBy sql.NullString
+	var capacity sql.NullInt64
+
+	err := row.Scan(
+		&event.ID,
+		&event.Title,
+		&event.Description,
+		&event.StartTime,
+		&event.EndTime,
+		&event.CreatedAt,
+		&deletedAt,
+		&rrule,
+		&exDates,
+		&rDates,
+		&timeZone,
+		&event.AllDay,
+		&resource,
+		&capacity,
+		&event.CalendarID,
+		&createdBy,
+		&event.Version,
+	)
+	if err != nil {
+		return CreateEventResponse{}, err
+	}
+
+	if deletedAt.Valid {
+		event.DeletedAt = &deletedAt.Time
+	}
+
+	event.RRule = rrule.String
+	event.Resource = resource.String
+	event.CreatedBy = createdBy.String
+	event.Capacity = int(capacity.Int64)
+
+	if event.ExDates, err = recurrence.ParseDateList(exDates.String); err != nil {
+		return CreateEventResponse{}, fmt.Errorf("parsing exdate: %w", err)
+	}
+
+	if event.RDates, err = recurrence.ParseDateList(rDates.String); err != nil {
+		return CreateEventResponse{}, fmt.Errorf("parsing rdate: %w", err)
+	}
+
+	event.TimeZone = timeZone.String
+	if event.TimeZone == "" {
+		event.TimeZone = "UTC"
+	}
+
+	// The driver hands times back in the session zone. Show them in the event's.
+	return event.In(event.location()), nil
+}
+
+func scanOccurrenceException(row scanner) (OccurrenceException, error) {
+	var exception OccurrenceException
+	var title, description sql.NullString
+	var startTime, endTime sql.NullTime
+
+	err := row.Scan(&exception.SeriesID, &exception.RecurrenceID, &exception.Cancelled, &title, &description, &startTime, &endTime)
+	if err != nil {
+		return OccurrenceException{}, err
+	}
+
+	exception.Title = title.String
+	exception.Description = description.String
+	exception.StartTime = startTime.Time
+	exception.EndTime = endTime.Time
+
+	return exception, nil
+}
+
+func scanRevision(row scanner) (EventRevision, error) {
+	var revision EventRevision
+	var actor sql.NullString
+	var changes, snapshot []byte
+
+	err := row.Scan(&revision.EventID, &revision.Revision, &revision.Action, &actor, &revision.CreatedAt, &changes, &snapshot)
+	if err != nil {
+		return EventRevision{}, err
+	}
+
+	revision.Actor = actor.String
+
+	if err := json.Unmarshal(changes, &revision.Changes); err != nil {
+		return EventRevision{}, fmt.Errorf("decoding changes: %w", err)
+	}
+
+	var state eventState
+
+	if err := json.Unmarshal(snapshot, &state); err != nil {
+		return EventRevision{}, fmt.Errorf("decoding snapshot: %w", err)
+	}
+
+	revision.Snapshot = state.request()
+
+	return revision, nil
+}
+
+func scanCalendar(row scanner) (Calendar, error) {
+	var calendar Calendar
+	var createdBy sql.NullString
+
+	err := row.Scan(&calendar.ID, &calendar.Name, &calendar.Description, &calendar.CreatedAt, &createdBy)
+	calendar.CreatedBy = createdBy.String
+
+	return calendar, err
+}
+
+func scanAPIKey(row scanner) (APIKey, error) {
+	var key APIKey
+	var scopes []string
+	var createdBy sql.NullString
+	var lastUsedAt, revokedAt sql.NullTime
+
+	err := row.Scan(&key.ID, &key.TenantID, &key.Name, &key.Prefix, pq.Array(&scopes), &createdBy, &key.CreatedAt, &key.ExpiresAt,
+		&lastUsedAt, &revokedAt)
+	if err != nil {
+		return APIKey{}, err
+	}
+
+	for _, scope := range scopes {
+		key.Scopes = append(key.Scopes, Scope(scope))
+	}
+
+	key.CreatedBy = createdBy.String
+
+	if lastUsedAt.Valid {
+		key.LastUsedAt = &lastUsedAt.Time
+	}
+
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+
+	return key, nil
+}
+
+func scanAttendee(row scanner) (Attendee, error) {
+	var attendee Attendee
+
+	err := row.Scan(&attendee.EventID, &attendee.Email, &attendee.Name, &attendee.Role, &attendee.Status,
+		&attendee.InvitedAt, &attendee.StatusChangedAt)
+
+	return attendee, err
+}
+
+func nullInt(value int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(value), Valid: value != 0}
+}
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+func nullTime(value time.Time) sql.NullTime {
+	return sql.NullTime{Time: value, Valid: !value.IsZero()}
+}
@@ -3,19 +3,27 @@ package internal_test
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/ObiaNzk/LTK-test-manu/internal"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
 type StorageTestSuite struct {
 	suite.Suite
+	ctx     context.Context
 	db      *sql.DB
 	mock    sqlmock.Sqlmock
 	storage *internal.Storage
@@ -25,6 +33,7 @@ func (s *StorageTestSuite) SetupTest() {
 	db, mock, err := sqlmock.New()
 	s.Require().NoError(err)
 
+	s.ctx = internal.WithTenant(context.Background(), "acme")
 	s.db = db
 	s.mock = mock
 	s.storage = internal.NewStorage(db)
@@ -37,9 +46,118 @@ func (s *StorageTestSuite) TearDownTest() {
 	s.Require().NoError(err)
 }
 
+// expectTenant expects the transaction every storage call runs in, scoped to
+// the tenant of s.ctx.
+func (s *StorageTestSuite) expectTenant() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta("SELECT set_config('app.tenant_id', $1, true)")).
+		WithArgs("acme").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func (s *StorageTestSuite) expectDefaultCalendar() {
+	s.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO calendars (id, name) VALUES ($1, 'Default') ON CONFLICT (tenant_id, id) DO NOTHING")).
+		WithArgs(internal.DefaultCalendarID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+var eventColumns = []string{
+	"id", "title", "description",
+	"start_time", "end_time", "created_at", "deleted_at",
+	"rrule", "exdate", "rdate", "time_zone", "all_day", "resource", "capacity", "calendar_id", "created_by", "version",
+}
+
+func newEventRows() *sqlmock.Rows {
+	return sqlmock.NewRows(eventColumns)
+}
+
+// eventRow pads a row with NULLs for the trailing optional columns, a false
+// all_day, the default calendar and version 1.
+func eventRow(values ...driver.Value) []driver.Value {
+	row := make([]driver.Value, len(eventColumns))
+	row[slices.Index(eventColumns, "all_day")] = false
+	row[slices.Index(eventColumns, "calendar_id")] = internal.DefaultCalendarID
+	row[slices.Index(eventColumns, "version")] = 1
+	copy(row, values)
+
+	return row
+}
+
+// lockedEventQuery is how a write reads the event it changes, and
+// lockedDeletedEventQuery how a restore does.
+var (
+	lockedEventQuery        = regexp.QuoteMeta("SELECT " + strings.Join(eventColumns, ", ") + " FROM events WHERE id = $1 AND deleted_at IS NULL FOR UPDATE")
+	lockedDeletedEventQuery = regexp.QuoteMeta("SELECT " + strings.Join(eventColumns, ", ") + " FROM events WHERE id = $1 AND deleted_at IS NOT NULL FOR UPDATE")
+)
+
+// The queries that replace the bookings of an event, and find the events a
+// booking conflicts with.
+var (
+	bookQuery        = regexp.QuoteMeta("INSERT INTO event_bookings (event_id, resource, period) SELECT $1, $2, unnest($3::tstzrange[])")
+	releaseQuery     = regexp.QuoteMeta("DELETE FROM event_bookings WHERE event_id = $1")
+	seriesExceptions = regexp.QuoteMeta("SELECT series_id, recurrence_id, cancelled, title, description, start_time, end_time FROM event_exceptions " +
+		"WHERE series_id = ANY($1) ORDER BY series_id, recurrence_id")
+	conflictQuery = regexp.QuoteMeta("SELECT id FROM events WHERE id <> $2 AND id IN (SELECT event_id FROM event_bookings " +
+		"WHERE resource = $1 AND period && ANY($3::tstzrange[])) ORDER BY start_time, id")
+)
+
+// bookedRanges is the array of ranges storage books, from start and end pairs.
+func bookedRanges(times ...time.Time) string {
+	var ranges []string
+	for i := 0; i+1 < len(times); i += 2 {
+		ranges = append(ranges, fmt.Sprintf(`"[%s,%s)"`, times[i].Format(time.RFC3339Nano), times[i+1].Format(time.RFC3339Nano)))
+	}
+
+	return "{" + strings.Join(ranges, ",") + "}"
+}
+
+// expectRevision expects a write to append a revision to the history of its
+// event, and to write it to the outbox. changes matches the JSON of the changed
+// fields when given.
+func (s *StorageTestSuite) expectRevision(eventID any, action internal.RevisionAction, changes ...string) {
+	var changesArg any = sqlmock.AnyArg()
+	if len(changes) > 0 {
+		changesArg = jsonArg(changes[0])
+	}
+
+	s.mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO event_revisions (event_id, revision, action, actor, created_at, changes, snapshot) "+
+		"SELECT $1, COALESCE(MAX(revision), 0) + 1, $2, $3, $4, $5, $6 FROM event_revisions WHERE event_id = $1 RETURNING revision")).
+		WithArgs(eventID, string(action), sqlmock.AnyArg(), sqlmock.AnyArg(), changesArg, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"revision"}).AddRow(1))
+
+	s.expectOutbox("events."+string(action), eventID, sqlmock.AnyArg())
+}
+
+func (s *StorageTestSuite) expectOutbox(topic string, key, payload any) {
+	s.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO outbox (topic, key, payload, created_at) VALUES ($1, $2, $3, $4)")).
+		WithArgs(topic, key, payload, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+// jsonArg matches a JSON argument equal to it, whatever the key order.
+type jsonArg string
+
+func (a jsonArg) Match(value driver.Value) bool {
+	data, ok := value.([]byte)
+	if !ok {
+		return false
+	}
+
+	var got, want any
+
+	return json.Unmarshal(data, &got) == nil && json.Unmarshal([]byte(a), &want) == nil && reflect.DeepEqual(got, want)
+}
+
+// argFunc matches the arguments it returns true for.
+type argFunc func(value driver.Value) bool
+
+func (f argFunc) Match(value driver.Value) bool {
+	return f(value)
+}
+
 func (s *StorageTestSuite) TestCreateEvent_Success() {
-	ctx := context.Background()
-	now := time.Now()
+	ctx := s.ctx
+	now := time.Now().UTC()
 	title := strings.Repeat("a", 101)
 
 	request := internal.CreateEventRequest{
@@ -49,9 +167,10 @@ func (s *StorageTestSuite) TestCreateEvent_Success() {
 		EndTime:     now.Add(time.Hour),
 	}
 
-	s.mock.ExpectBegin()
+	s.expectTenant()
 
-	s.mock.ExpectExec("INSERT INTO events \\(id,title, description, start_time, end_time, created_at\\) VALUES \\(\\$1,\\$2, \\$3, \\$4, \\$5,\\$6\\)").
+	s.mock.ExpectExec("INSERT INTO events \\(id,title, description, start_time, end_time, created_at, rrule, exdate, rdate, series_end, time_zone, all_day, resource, capacity, calendar_id, created_by\\) "+
+		"VALUES \\(\\$1,\\$2, \\$3, \\$4, \\$5,\\$6, \\$7, \\$8, \\$9, \\$10, \\$11, \\$12, \\$13, \\$14, \\$15, \\$16\\)").
 		WithArgs(
 			sqlmock.AnyArg(),
 			title,
@@ -59,9 +178,20 @@ func (s *StorageTestSuite) TestCreateEvent_Success() {
 			now,
 			now.Add(time.Hour),
 			sqlmock.AnyArg(),
+			nil,
+			nil,
+			nil,
+			now.Add(time.Hour),
+			"UTC",
+			false,
+			nil,
+			nil,
+			"",
+			nil,
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
+	s.expectRevision(sqlmock.AnyArg(), internal.RevisionCreated)
 	s.mock.ExpectCommit()
 
 	result, err := s.storage.CreateEvent(ctx, request)
@@ -75,9 +205,56 @@ func (s *StorageTestSuite) TestCreateEvent_Success() {
 	require.NotZero(s.T(), result.CreatedAt)
 }
 
+func (s *StorageTestSuite) TestCreateEvent_Recurring() {
+	ctx := s.ctx
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	title := strings.Repeat("a", 101)
+
+	request := internal.CreateEventRequest{
+		Title:       title,
+		Description: "Test Description",
+		StartTime:   start,
+		EndTime:     start.Add(time.Hour),
+		RRule:       "FREQ=WEEKLY;COUNT=3",
+		ExDates:     []time.Time{start.AddDate(0, 0, 7)},
+	}
+
+	s.expectTenant()
+
+	s.mock.ExpectExec("INSERT INTO events").
+		WithArgs(
+			sqlmock.AnyArg(),
+			title,
+			"Test Description",
+			start,
+			start.Add(time.Hour),
+			sqlmock.AnyArg(),
+			"FREQ=WEEKLY;COUNT=3",
+			"20251208T090000Z",
+			nil,
+			start.AddDate(0, 0, 14).Add(time.Hour),
+			"UTC",
+			false,
+			nil,
+			nil,
+			"",
+			nil,
+		).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	s.expectRevision(sqlmock.AnyArg(), internal.RevisionCreated)
+	s.mock.ExpectCommit()
+
+	result, err := s.storage.CreateEvent(ctx, request)
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), "FREQ=WEEKLY;COUNT=3", result.RRule)
+	require.Equal(s.T(), request.ExDates, result.ExDates)
+}
+
 func (s *StorageTestSuite) TestCreateEvent_BeginTxError() {
-	ctx := context.Background()
-	now := time.Now()
+	ctx := s.ctx
+	now := time.Now().UTC()
 	title := strings.Repeat("a", 101)
 
 	request := internal.CreateEventRequest{
@@ -96,8 +273,8 @@ func (s *StorageTestSuite) TestCreateEvent_BeginTxError() {
 }
 
 func (s *StorageTestSuite) TestCreateEvent_ExecError() {
-	ctx := context.Background()
-	now := time.Now()
+	ctx := s.ctx
+	now := time.Now().UTC()
 	title := strings.Repeat("a", 101)
 
 	request := internal.CreateEventRequest{
@@ -107,9 +284,10 @@ func (s *StorageTestSuite) TestCreateEvent_ExecError() {
 		EndTime:     now.Add(time.Hour),
 	}
 
-	s.mock.ExpectBegin()
+	s.expectTenant()
 
-	s.mock.ExpectExec("INSERT INTO events \\(id,title, description, start_time, end_time, created_at\\) VALUES \\(\\$1,\\$2, \\$3, \\$4, \\$5,\\$6\\)").
+	s.mock.ExpectExec("INSERT INTO events \\(id,title, description, start_time, end_time, created_at, rrule, exdate, rdate, series_end, time_zone, all_day, resource, capacity, calendar_id, created_by\\) "+
+		"VALUES \\(\\$1,\\$2, \\$3, \\$4, \\$5,\\$6, \\$7, \\$8, \\$9, \\$10, \\$11, \\$12, \\$13, \\$14, \\$15, \\$16\\)").
 		WithArgs(
 			sqlmock.AnyArg(),
 			title,
@@ -117,6 +295,16 @@ func (s *StorageTestSuite) TestCreateEvent_ExecError() {
 			now,
 			now.Add(time.Hour),
 			sqlmock.AnyArg(),
+			nil,
+			nil,
+			nil,
+			now.Add(time.Hour),
+			"UTC",
+			false,
+			nil,
+			nil,
+			"",
+			nil,
 		).
 		WillReturnError(errors.New("insert failed"))
 
@@ -128,9 +316,117 @@ func (s *StorageTestSuite) TestCreateEvent_ExecError() {
 	require.Contains(s.T(), err.Error(), "creating event")
 }
 
+func (s *StorageTestSuite) TestCreateEvent_ResourceConflict() {
+	ctx := s.ctx
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+
+	request := internal.CreateEventRequest{
+		Title:       strings.Repeat("a", 101),
+		Description: "Test Description",
+		StartTime:   start,
+		EndTime:     start.Add(time.Hour),
+		Resource:    "room-4b",
+	}
+
+	s.expectTenant()
+
+	s.mock.ExpectExec("INSERT INTO events").WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectExec(bookQuery).
+		WithArgs(sqlmock.AnyArg(), "room-4b", bookedRanges(start, start.Add(time.Hour))).
+		WillReturnError(&pq.Error{Code: "23P01", Constraint: "event_bookings_no_overlap"})
+
+	s.mock.ExpectRollback()
+	s.expectTenant()
+
+	s.mock.ExpectQuery(conflictQuery).
+		WithArgs("room-4b", sqlmock.AnyArg(), bookedRanges(start, start.Add(time.Hour))).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("booking-1").AddRow("booking-2"))
+
+	s.mock.ExpectRollback()
+
+	_, err := s.storage.CreateEvent(ctx, request)
+
+	var conflict *internal.ConflictError
+
+	require.ErrorIs(s.T(), err, internal.ErrConflict)
+	require.ErrorAs(s.T(), err, &conflict)
+	require.Equal(s.T(), []string{"booking-1", "booking-2"}, conflict.EventIDs)
+	require.Equal(s.T(), "room-4b", conflict.Resource)
+}
+
+func (s *StorageTestSuite) TestCreateEvent_Resource() {
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+
+	s.expectTenant()
+	s.mock.ExpectExec("INSERT INTO events").WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectExec(bookQuery).
+		WithArgs(sqlmock.AnyArg(), "room-4b", bookedRanges(start, start.Add(time.Hour))).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.expectRevision(sqlmock.AnyArg(), internal.RevisionCreated)
+	s.mock.ExpectCommit()
+
+	_, err := s.storage.CreateEvent(s.ctx, internal.CreateEventRequest{
+		Title:     "review",
+		StartTime: start,
+		EndTime:   start.Add(time.Hour),
+		Resource:  "room-4b",
+	})
+
+	require.NoError(s.T(), err)
+}
+
+func (s *StorageTestSuite) TestCreateEvent_RecurringResource() {
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+
+	// Every occurrence is booked, except the one on the EXDATE.
+	s.expectTenant()
+	s.mock.ExpectExec("INSERT INTO events").WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectQuery(seriesExceptions).
+		WillReturnRows(sqlmock.NewRows([]string{"series_id", "recurrence_id", "cancelled", "title", "description", "start_time", "end_time"}))
+	s.mock.ExpectExec(bookQuery).
+		WithArgs(sqlmock.AnyArg(), "room-4b", bookedRanges(
+			start, start.Add(time.Hour),
+			start.AddDate(0, 0, 14), start.AddDate(0, 0, 14).Add(time.Hour),
+		)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	s.expectRevision(sqlmock.AnyArg(), internal.RevisionCreated)
+	s.mock.ExpectCommit()
+
+	_, err := s.storage.CreateEvent(s.ctx, internal.CreateEventRequest{
+		Title:     "weekly review",
+		StartTime: start,
+		EndTime:   start.Add(time.Hour),
+		RRule:     "FREQ=WEEKLY;COUNT=3",
+		ExDates:   []time.Time{start.AddDate(0, 0, 7)},
+		Resource:  "room-4b",
+	})
+
+	require.NoError(s.T(), err)
+}
+
+func (s *StorageTestSuite) TestCreateEvent_EndlessRecurringResource() {
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+
+	s.expectTenant()
+	s.mock.ExpectExec("INSERT INTO events").WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectQuery(seriesExceptions).
+		WillReturnRows(sqlmock.NewRows([]string{"series_id", "recurrence_id", "cancelled", "title", "description", "start_time", "end_time"}))
+	s.mock.ExpectRollback()
+
+	_, err := s.storage.CreateEvent(s.ctx, internal.CreateEventRequest{
+		Title:     "weekly review",
+		StartTime: start,
+		EndTime:   start.Add(time.Hour),
+		RRule:     "FREQ=WEEKLY",
+		Resource:  "room-4b",
+	})
+
+	require.ErrorIs(s.T(), err, internal.ErrInput)
+}
+
 func (s *StorageTestSuite) TestCreateEvent_CommitError() {
-	ctx := context.Background()
-	now := time.Now()
+	ctx := s.ctx
+	now := time.Now().UTC()
 	title := strings.Repeat("a", 101)
 
 	request := internal.CreateEventRequest{
@@ -140,9 +436,10 @@ func (s *StorageTestSuite) TestCreateEvent_CommitError() {
 		EndTime:     now.Add(time.Hour),
 	}
 
-	s.mock.ExpectBegin()
+	s.expectTenant()
 
-	s.mock.ExpectExec("INSERT INTO events \\(id,title, description, start_time, end_time, created_at\\) VALUES \\(\\$1,\\$2, \\$3, \\$4, \\$5,\\$6\\)").
+	s.mock.ExpectExec("INSERT INTO events \\(id,title, description, start_time, end_time, created_at, rrule, exdate, rdate, series_end, time_zone, all_day, resource, capacity, calendar_id, created_by\\) "+
+		"VALUES \\(\\$1,\\$2, \\$3, \\$4, \\$5,\\$6, \\$7, \\$8, \\$9, \\$10, \\$11, \\$12, \\$13, \\$14, \\$15, \\$16\\)").
 		WithArgs(
 			sqlmock.AnyArg(),
 			title,
@@ -150,9 +447,20 @@ func (s *StorageTestSuite) TestCreateEvent_CommitError() {
 			now,
 			now.Add(time.Hour),
 			sqlmock.AnyArg(),
+			nil,
+			nil,
+			nil,
+			now.Add(time.Hour),
+			"UTC",
+			false,
+			nil,
+			nil,
+			"",
+			nil,
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
+	s.expectRevision(sqlmock.AnyArg(), internal.RevisionCreated)
 	s.mock.ExpectCommit().WillReturnError(errors.New("commit failed"))
 
 	_, err := s.storage.CreateEvent(ctx, request)
@@ -162,34 +470,36 @@ func (s *StorageTestSuite) TestCreateEvent_CommitError() {
 }
 
 func (s *StorageTestSuite) TestGetEvents_Success_MultipleRows() {
-	ctx := context.Background()
-	now := time.Now()
-
-	rows := sqlmock.NewRows([]string{
-		"id", "title", "description",
-		"start_time", "end_time", "created_at",
-	}).
-		AddRow(
+	ctx := s.ctx
+	now := time.Now().UTC()
+
+	rows := newEventRows().
+		AddRow(eventRow(
 			"id-1",
 			strings.Repeat("a", 101),
 			"Description 1",
 			now,
 			now.Add(time.Hour),
 			now,
-		).
-		AddRow(
+			nil,
+		)...).
+		AddRow(eventRow(
 			"id-2",
 			strings.Repeat("b", 101),
 			"Description 2",
 			now.Add(2*time.Hour),
 			now.Add(3*time.Hour),
 			now,
-		)
+			nil,
+		)...)
 
-	s.mock.ExpectQuery("SELECT id,title, description, start_time, end_time, created_at FROM events ORDER BY start_time ASC").
+	s.expectTenant()
+	s.mock.ExpectQuery("SELECT id, title, description, start_time, end_time, created_at, deleted_at, rrule, exdate, rdate, time_zone, all_day, resource, capacity, calendar_id, created_by, version FROM events WHERE deleted_at IS NULL ORDER BY start_time ASC, id ASC LIMIT \\$1").
 		WillReturnRows(rows)
 
-	results, err := s.storage.GetEvents(ctx)
+	s.mock.ExpectRollback()
+
+	results, err := s.storage.GetEvents(ctx, internal.EventFilter{}, internal.Page{Limit: 10})
 
 	require.NoError(s.T(), err)
 	require.Len(s.T(), results, 2)
@@ -202,56 +512,199 @@ func (s *StorageTestSuite) TestGetEvents_Success_MultipleRows() {
 }
 
 func (s *StorageTestSuite) TestGetEvents_Success_EmptyTable() {
-	ctx := context.Background()
+	ctx := s.ctx
 
-	rows := sqlmock.NewRows([]string{
-		"id", "title", "description",
-		"start_time", "end_time", "created_at",
-	})
+	rows := newEventRows()
 
-	s.mock.ExpectQuery("SELECT id,title, description, start_time, end_time, created_at FROM events ORDER BY start_time ASC").
+	s.expectTenant()
+	s.mock.ExpectQuery("SELECT id, title, description, start_time, end_time, created_at, deleted_at, rrule, exdate, rdate, time_zone, all_day, resource, capacity, calendar_id, created_by, version FROM events WHERE deleted_at IS NULL ORDER BY start_time ASC, id ASC LIMIT \\$1").
 		WillReturnRows(rows)
 
-	results, err := s.storage.GetEvents(ctx)
+	s.mock.ExpectRollback()
+
+	results, err := s.storage.GetEvents(ctx, internal.EventFilter{}, internal.Page{Limit: 10})
 
 	require.NoError(s.T(), err)
 	require.Empty(s.T(), results)
 }
 
 func (s *StorageTestSuite) TestGetEvents_QueryError() {
-	ctx := context.Background()
+	ctx := s.ctx
 
-	s.mock.ExpectQuery("SELECT id,title, description, start_time, end_time, created_at FROM events ORDER BY start_time ASC").
+	s.expectTenant()
+	s.mock.ExpectQuery("SELECT id, title, description, start_time, end_time, created_at, deleted_at, rrule, exdate, rdate, time_zone, all_day, resource, capacity, calendar_id, created_by, version FROM events WHERE deleted_at IS NULL ORDER BY start_time ASC, id ASC LIMIT \\$1").
 		WillReturnError(errors.New("database connection lost"))
 
-	_, err := s.storage.GetEvents(ctx)
+	s.mock.ExpectRollback()
+
+	_, err := s.storage.GetEvents(ctx, internal.EventFilter{}, internal.Page{Limit: 10})
 
 	require.Error(s.T(), err)
 	require.Contains(s.T(), err.Error(), "creating event")
 }
 
+func (s *StorageTestSuite) TestGetEvents_AfterCursor() {
+	ctx := s.ctx
+	now := time.Now().UTC()
+
+	rows := newEventRows()
+
+	s.expectTenant()
+	s.mock.ExpectQuery("SELECT id, title, description, start_time, end_time, created_at, deleted_at, rrule, exdate, rdate, time_zone, all_day, resource, capacity, calendar_id, created_by, version FROM events WHERE deleted_at IS NULL AND \\(start_time, id\\) > \\(\\$1, \\$2\\) ORDER BY start_time ASC, id ASC LIMIT \\$3").
+		WithArgs(now, "id-1", 5).
+		WillReturnRows(rows)
+
+	s.mock.ExpectRollback()
+
+	results, err := s.storage.GetEvents(ctx, internal.EventFilter{}, internal.Page{Limit: 5, After: &internal.Cursor{StartTime: now, ID: "id-1"}})
+
+	require.NoError(s.T(), err)
+	require.Empty(s.T(), results)
+}
+
+func (s *StorageTestSuite) TestGetEvents_Filter() {
+	ctx := s.ctx
+	from := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+	createdFrom := from.AddDate(0, -1, 0)
+	createdTo := from
+
+	rows := newEventRows()
+
+	s.expectTenant()
+	s.mock.ExpectQuery("SELECT id, title, description, start_time, end_time, created_at, deleted_at, rrule, exdate, rdate, time_zone, all_day, resource, capacity, calendar_id, created_by, version FROM events "+
+		"WHERE deleted_at IS NULL AND \\(\\(all_day AND start_time < \\$1\\) OR \\(NOT all_day AND start_time < \\$2\\)\\) "+
+		"AND \\(series_end IS NULL OR \\(all_day AND series_end > \\$3\\) OR \\(NOT all_day AND series_end > \\$4\\)\\) AND title ILIKE '%' \\|\\| \\$5 \\|\\| '%' "+
+		"AND created_at >= \\$6 AND created_at < \\$7 ORDER BY start_time ASC, id ASC LIMIT \\$8").
+		WithArgs(to, to, from, from, `50\% off\_sale`, createdFrom, createdTo, 10).
+		WillReturnRows(rows)
+
+	filter := internal.EventFilter{
+		From:        from,
+		To:          to,
+		Title:       "50% off_sale",
+		CreatedFrom: createdFrom,
+		CreatedTo:   createdTo,
+	}
+
+	s.mock.ExpectRollback()
+
+	results, err := s.storage.GetEvents(ctx, filter, internal.Page{Limit: 10})
+
+	require.NoError(s.T(), err)
+	require.Empty(s.T(), results)
+}
+
+func (s *StorageTestSuite) TestGetEvents_AllDayWindow() {
+	ctx := s.ctx
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(s.T(), err)
+
+	from := time.Date(2025, 12, 25, 8, 0, 0, 0, tokyo)
+	to := from.Add(time.Hour)
+	day := time.Date(2025, 12, 25, 0, 0, 0, 0, time.UTC)
+
+	rows := newEventRows().AddRow(eventRow("holiday", "Christmas", "", day, day.AddDate(0, 0, 1), day, nil, nil, nil, nil, "UTC", true)...)
+
+	s.expectTenant()
+	s.mock.ExpectQuery("SELECT "+strings.Join(eventColumns, ", ")+" FROM events "+
+		"WHERE deleted_at IS NULL AND \\(\\(all_day AND start_time < \\$1\\) OR \\(NOT all_day AND start_time < \\$2\\)\\) "+
+		"AND \\(series_end IS NULL OR \\(all_day AND series_end > \\$3\\) OR \\(NOT all_day AND series_end > \\$4\\)\\) ORDER BY start_time ASC, id ASC LIMIT \\$5").
+		WithArgs(day.AddDate(0, 0, 1), to, day, from, 10).
+		WillReturnRows(rows)
+
+	s.mock.ExpectRollback()
+
+	results, err := s.storage.GetEvents(ctx, internal.EventFilter{From: from, To: to}, internal.Page{Limit: 10})
+
+	require.NoError(s.T(), err)
+	require.Len(s.T(), results, 1)
+	require.True(s.T(), results[0].AllDay)
+	require.Equal(s.T(), day, results[0].StartTime)
+}
+
+func (s *StorageTestSuite) TestGetEvents_Resources() {
+	ctx := s.ctx
+
+	s.expectTenant()
+	s.mock.ExpectQuery("SELECT "+strings.Join(eventColumns, ", ")+" FROM events "+
+		"WHERE deleted_at IS NULL AND resource = ANY\\(\\$1\\) ORDER BY start_time ASC, id ASC LIMIT \\$2").
+		WithArgs(pq.Array([]string{"room-a", "room-b"}), 10).
+		WillReturnRows(newEventRows())
+
+	s.mock.ExpectRollback()
+
+	results, err := s.storage.GetEvents(ctx, internal.EventFilter{Resources: []string{"room-a", "room-b"}}, internal.Page{Limit: 10})
+
+	require.NoError(s.T(), err)
+	require.Empty(s.T(), results)
+}
+
+func (s *StorageTestSuite) TestGetEvents_Calendars() {
+	s.expectTenant()
+	s.mock.ExpectQuery("SELECT "+strings.Join(eventColumns, ", ")+" FROM events "+
+		"WHERE deleted_at IS NULL AND calendar_id = ANY\\(\\$1\\) ORDER BY start_time ASC, id ASC LIMIT \\$2").
+		WithArgs(pq.Array([]string{"ada", "team"}), 10).
+		WillReturnRows(newEventRows())
+
+	s.mock.ExpectRollback()
+
+	results, err := s.storage.GetEvents(s.ctx, internal.EventFilter{CalendarIDs: []string{"ada", "team"}}, internal.Page{Limit: 10})
+
+	require.NoError(s.T(), err)
+	require.Empty(s.T(), results)
+}
+
+func (s *StorageTestSuite) TestGetEvents_Calendar() {
+	ctx := s.ctx
+
+	s.expectTenant()
+	s.mock.ExpectQuery("SELECT "+strings.Join(eventColumns, ", ")+" FROM events "+
+		"WHERE deleted_at IS NULL AND calendar_id = \\$1 ORDER BY start_time ASC, id ASC LIMIT \\$2").
+		WithArgs("work", 10).
+		WillReturnRows(newEventRows())
+
+	s.mock.ExpectRollback()
+
+	results, err := s.storage.GetEvents(ctx, internal.EventFilter{CalendarID: "work"}, internal.Page{Limit: 10})
+
+	require.NoError(s.T(), err)
+	require.Empty(s.T(), results)
+}
+
+func (s *StorageTestSuite) TestGetEvents_RequiresLimit() {
+	ctx := s.ctx
+
+	_, err := s.storage.GetEvents(ctx, internal.EventFilter{}, internal.Page{})
+
+	require.Error(s.T(), err)
+	require.ErrorIs(s.T(), err, internal.ErrInput)
+}
+
 func (s *StorageTestSuite) TestGetEventByID_Success() {
-	ctx := context.Background()
+	ctx := s.ctx
 	eventID := "test-id-123"
-	now := time.Now()
+	now := time.Now().UTC()
 
-	rows := sqlmock.NewRows([]string{
-		"id", "title", "description",
-		"start_time", "end_time", "created_at",
-	}).AddRow(
+	rows := newEventRows().AddRow(eventRow(
 		eventID,
 		strings.Repeat("a", 101),
 		"Test Description",
 		now,
 		now.Add(time.Hour),
 		now,
-	)
+		nil,
+	)...)
 
-	s.mock.ExpectQuery("SELECT id, title, description, start_time, end_time, created_at FROM events WHERE id = \\$1").
+	s.expectTenant()
+	s.mock.ExpectQuery("SELECT id, title, description, start_time, end_time, created_at, deleted_at, rrule, exdate, rdate, time_zone, all_day, resource, capacity, calendar_id, created_by, version FROM events WHERE id = \\$1 AND deleted_at IS NULL").
 		WithArgs(eventID).
 		WillReturnRows(rows)
 
-	result, err := s.storage.GetEventByID(ctx, eventID)
+	s.mock.ExpectRollback()
+
+	result, err := s.storage.GetEventByID(ctx, eventID, false)
 
 	require.NoError(s.T(), err)
 	require.Equal(s.T(), eventID, result.ID)
@@ -262,15 +715,95 @@ func (s *StorageTestSuite) TestGetEventByID_Success() {
 	require.Equal(s.T(), now, result.CreatedAt)
 }
 
+func (s *StorageTestSuite) TestGetEventByID_Recurring() {
+	ctx := s.ctx
+	eventID := "test-id-123"
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+
+	rows := newEventRows().AddRow(
+		eventID,
+		strings.Repeat("a", 101),
+		"Test Description",
+		start,
+		start.Add(time.Hour),
+		start,
+		nil,
+		"FREQ=DAILY",
+		"20251202T090000Z",
+		"20251224T180000Z",
+		"UTC",
+		false,
+		nil,
+		nil,
+		internal.DefaultCalendarID,
+		"auth0|ada",
+		2,
+	)
+
+	s.expectTenant()
+	s.mock.ExpectQuery("SELECT id, title, description, start_time, end_time, created_at, deleted_at, rrule, exdate, rdate, time_zone, all_day, resource, capacity, calendar_id, created_by, version FROM events WHERE id = \\$1 AND deleted_at IS NULL").
+		WithArgs(eventID).
+		WillReturnRows(rows)
+
+	s.mock.ExpectRollback()
+
+	result, err := s.storage.GetEventByID(ctx, eventID, false)
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), "FREQ=DAILY", result.RRule)
+	require.Equal(s.T(), []time.Time{time.Date(2025, 12, 2, 9, 0, 0, 0, time.UTC)}, result.ExDates)
+	require.Equal(s.T(), []time.Time{time.Date(2025, 12, 24, 18, 0, 0, 0, time.UTC)}, result.RDates)
+	require.Equal(s.T(), "auth0|ada", result.CreatedBy)
+	require.Equal(s.T(), 2, result.Version)
+}
+
+func (s *StorageTestSuite) TestGetEventByID_TimeZone() {
+	ctx := s.ctx
+	eventID := "test-id-123"
+	start := time.Date(2025, 12, 1, 8, 0, 0, 0, time.UTC)
+
+	rows := newEventRows().AddRow(eventRow(
+		eventID,
+		strings.Repeat("a", 101),
+		"Test Description",
+		start,
+		start.Add(time.Hour),
+		start,
+		nil,
+		nil,
+		nil,
+		nil,
+		"Europe/Madrid",
+	)...)
+
+	s.expectTenant()
+	s.mock.ExpectQuery("SELECT id, title, description, start_time, end_time, created_at, deleted_at, rrule, exdate, rdate, time_zone, all_day, resource, capacity, calendar_id, created_by, version FROM events WHERE id = \\$1 AND deleted_at IS NULL").
+		WithArgs(eventID).
+		WillReturnRows(rows)
+
+	s.mock.ExpectRollback()
+
+	result, err := s.storage.GetEventByID(ctx, eventID, false)
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), "Europe/Madrid", result.TimeZone)
+	require.Equal(s.T(), "Europe/Madrid", result.StartTime.Location().String())
+	require.Equal(s.T(), 9, result.StartTime.Hour())
+	require.True(s.T(), start.Equal(result.StartTime))
+}
+
 func (s *StorageTestSuite) TestGetEventByID_NotFound() {
-	ctx := context.Background()
+	ctx := s.ctx
 	eventID := "nonexistent-id"
 
-	s.mock.ExpectQuery("SELECT id, title, description, start_time, end_time, created_at FROM events WHERE id = \\$1").
+	s.expectTenant()
+	s.mock.ExpectQuery("SELECT id, title, description, start_time, end_time, created_at, deleted_at, rrule, exdate, rdate, time_zone, all_day, resource, capacity, calendar_id, created_by, version FROM events WHERE id = \\$1 AND deleted_at IS NULL").
 		WithArgs(eventID).
 		WillReturnError(sql.ErrNoRows)
 
-	_, err := s.storage.GetEventByID(ctx, eventID)
+	s.mock.ExpectRollback()
+
+	_, err := s.storage.GetEventByID(ctx, eventID, false)
 
 	require.Error(s.T(), err)
 	require.ErrorIs(s.T(), err, internal.ErrNotFound)
@@ -278,20 +811,1562 @@ func (s *StorageTestSuite) TestGetEventByID_NotFound() {
 }
 
 func (s *StorageTestSuite) TestGetEventByID_QueryError() {
-	ctx := context.Background()
+	ctx := s.ctx
 	eventID := "test-id"
 
-	s.mock.ExpectQuery("SELECT id, title, description, start_time, end_time, created_at FROM events WHERE id = \\$1").
+	s.expectTenant()
+	s.mock.ExpectQuery("SELECT id, title, description, start_time, end_time, created_at, deleted_at, rrule, exdate, rdate, time_zone, all_day, resource, capacity, calendar_id, created_by, version FROM events WHERE id = \\$1 AND deleted_at IS NULL").
 		WithArgs(eventID).
 		WillReturnError(errors.New("database connection error"))
 
-	_, err := s.storage.GetEventByID(ctx, eventID)
+	s.mock.ExpectRollback()
+
+	_, err := s.storage.GetEventByID(ctx, eventID, false)
 
 	require.Error(s.T(), err)
 	require.NotErrorIs(s.T(), err, internal.ErrNotFound)
 	require.Contains(s.T(), err.Error(), "getting event")
 }
 
-func TestStorageTestSuite(t *testing.T) {
-	suite.Run(t, new(StorageTestSuite))
+func (s *StorageTestSuite) TestUpdateEvent_Success() {
+	ctx := s.ctx
+	eventID := "test-id-123"
+	now := time.Now().UTC()
+	title := strings.Repeat("a", 101)
+
+	request := internal.CreateEventRequest{
+		Title:       title,
+		Description: "Updated Description",
+		StartTime:   now,
+		EndTime:     now.Add(time.Hour),
+	}
+
+	rows := sqlmock.NewRows([]string{"calendar_id", "version"}).AddRow(internal.DefaultCalendarID, 4)
+
+	s.expectTenant()
+	s.mock.ExpectQuery(lockedEventQuery).
+		WithArgs(eventID).
+		WillReturnRows(newEventRows().AddRow(eventRow(eventID, title, "Old Description", now, now.Add(time.Hour), now, nil, nil, nil, nil, "UTC", false,
+			nil, nil, internal.DefaultCalendarID, "auth0|ada", 3)...))
+	s.mock.ExpectQuery("UPDATE events SET title = \\$1, description = \\$2, start_time = \\$3, end_time = \\$4, rrule = \\$5, exdate = \\$6, rdate = \\$7, series_end = \\$8, "+
+		"time_zone = \\$9, all_day = \\$10, resource = \\$11, capacity = \\$12, calendar_id = COALESCE\\(\\$13, calendar_id\\), version = version \\+ 1 "+
+		"WHERE id = \\$14 RETURNING calendar_id, version").
+		WithArgs(title, "Updated Description", now, now.Add(time.Hour), nil, nil, nil, now.Add(time.Hour), "UTC", false, nil, nil, nil, eventID).
+		WillReturnRows(rows)
+	s.mock.ExpectExec("UPDATE attendees SET status = \\$1").
+		WithArgs("accepted", sqlmock.AnyArg(), eventID, "waitlisted").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.expectRevision(eventID, internal.RevisionUpdated, `{"description": {"from": "Old Description", "to": "Updated Description"}}`)
+	s.mock.ExpectCommit()
+
+	result, err := s.storage.UpdateEvent(ctx, eventID, 3, request)
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), eventID, result.ID)
+	require.Equal(s.T(), 4, result.Version)
+	require.Equal(s.T(), title, result.Title)
+	require.Equal(s.T(), "Updated Description", result.Description)
+	require.Equal(s.T(), now, result.CreatedAt)
+	require.Equal(s.T(), "auth0|ada", result.CreatedBy)
+}
+
+func (s *StorageTestSuite) TestUpdateEvent_NotFound() {
+	ctx := s.ctx
+	now := time.Now().UTC()
+
+	request := internal.CreateEventRequest{
+		Title:       strings.Repeat("a", 101),
+		Description: "Updated Description",
+		StartTime:   now,
+		EndTime:     now.Add(time.Hour),
+	}
+
+	s.expectTenant()
+	s.mock.ExpectQuery(lockedEventQuery).
+		WithArgs("nonexistent-id").
+		WillReturnError(sql.ErrNoRows)
+	s.mock.ExpectRollback()
+
+	_, err := s.storage.UpdateEvent(ctx, "nonexistent-id", 0, request)
+
+	require.Error(s.T(), err)
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+}
+
+func (s *StorageTestSuite) TestUpdateEvent_VersionMismatch() {
+	now := time.Now().UTC()
+
+	request := internal.CreateEventRequest{
+		Title:       strings.Repeat("a", 101),
+		Description: "Updated Description",
+		StartTime:   now,
+		EndTime:     now.Add(time.Hour),
+	}
+
+	s.expectTenant()
+	s.mock.ExpectQuery(lockedEventQuery).
+		WithArgs("test-id").
+		WillReturnRows(newEventRows().AddRow(eventRow("test-id", request.Title, "", now, now.Add(time.Hour), now, nil, nil, nil, nil, "UTC", false,
+			nil, nil, internal.DefaultCalendarID, nil, 5)...))
+	s.mock.ExpectRollback()
+
+	_, err := s.storage.UpdateEvent(s.ctx, "test-id", 4, request)
+
+	require.ErrorIs(s.T(), err, internal.ErrVersionMismatch)
+	require.EqualError(s.T(), err, "event is at version 5, not 4: version mismatch")
+}
+
+func (s *StorageTestSuite) TestUpdateEvent_QueryError() {
+	ctx := s.ctx
+	now := time.Now().UTC()
+
+	request := internal.CreateEventRequest{
+		Title:       strings.Repeat("a", 101),
+		Description: "Updated Description",
+		StartTime:   now,
+		EndTime:     now.Add(time.Hour),
+	}
+
+	s.expectTenant()
+	s.mock.ExpectQuery(lockedEventQuery).
+		WithArgs("test-id").
+		WillReturnRows(newEventRows().AddRow(eventRow("test-id", request.Title, "", now, now.Add(time.Hour), now)...))
+	s.mock.ExpectQuery("UPDATE events SET").
+		WillReturnError(errors.New("database connection error"))
+	s.mock.ExpectRollback()
+
+	_, err := s.storage.UpdateEvent(ctx, "test-id", 0, request)
+
+	require.Error(s.T(), err)
+	require.NotErrorIs(s.T(), err, internal.ErrNotFound)
+	require.Contains(s.T(), err.Error(), "updating event")
+}
+
+func (s *StorageTestSuite) TestGetEvents_IncludeDeleted() {
+	ctx := s.ctx
+	now := time.Now().UTC()
+
+	rows := newEventRows().AddRow(eventRow(
+		"id-1",
+		strings.Repeat("a", 101),
+		"Description 1",
+		now,
+		now.Add(time.Hour),
+		now,
+		now,
+	)...)
+
+	s.expectTenant()
+	s.mock.ExpectQuery("SELECT id, title, description, start_time, end_time, created_at, deleted_at, rrule, exdate, rdate, time_zone, all_day, resource, capacity, calendar_id, created_by, version FROM events ORDER BY start_time ASC, id ASC LIMIT \\$1").
+		WillReturnRows(rows)
+
+	s.mock.ExpectRollback()
+
+	results, err := s.storage.GetEvents(ctx, internal.EventFilter{IncludeDeleted: true}, internal.Page{Limit: 10})
+
+	require.NoError(s.T(), err)
+	require.Len(s.T(), results, 1)
+	require.NotNil(s.T(), results[0].DeletedAt)
+	require.Equal(s.T(), now, *results[0].DeletedAt)
+}
+
+func (s *StorageTestSuite) TestGetEventByID_IncludeDeleted() {
+	ctx := s.ctx
+	eventID := "test-id-123"
+	now := time.Now().UTC()
+
+	rows := newEventRows().AddRow(eventRow(eventID, strings.Repeat("a", 101), "Test Description", now, now.Add(time.Hour), now, now)...)
+
+	s.expectTenant()
+	s.mock.ExpectQuery("SELECT id, title, description, start_time, end_time, created_at, deleted_at, rrule, exdate, rdate, time_zone, all_day, resource, capacity, calendar_id, created_by, version FROM events WHERE id = \\$1$").
+		WithArgs(eventID).
+		WillReturnRows(rows)
+
+	s.mock.ExpectRollback()
+
+	result, err := s.storage.GetEventByID(ctx, eventID, true)
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), eventID, result.ID)
+	require.NotNil(s.T(), result.DeletedAt)
+}
+
+func (s *StorageTestSuite) TestGetOccurrenceExceptions_Success() {
+	ctx := s.ctx
+	recurrenceID := time.Date(2025, 12, 8, 9, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{"series_id", "recurrence_id", "cancelled", "title", "description", "start_time", "end_time"}).
+		AddRow("series-1", recurrenceID, true, nil, nil, nil, nil).
+		AddRow("series-2", recurrenceID, false, "moved", nil, recurrenceID.Add(time.Hour), recurrenceID.Add(2*time.Hour))
+
+	s.expectTenant()
+	s.mock.ExpectQuery("SELECT series_id, recurrence_id, cancelled, title, description, start_time, end_time FROM event_exceptions " +
+		"WHERE series_id = ANY\\(\\$1\\) ORDER BY series_id, recurrence_id").
+		WithArgs("{\"series-1\",\"series-2\"}").
+		WillReturnRows(rows)
+
+	s.mock.ExpectRollback()
+
+	result, err := s.storage.GetOccurrenceExceptions(ctx, []string{"series-1", "series-2"})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), []internal.OccurrenceException{
+		{SeriesID: "series-1", RecurrenceID: recurrenceID, Cancelled: true},
+		{SeriesID: "series-2", RecurrenceID: recurrenceID, Title: "moved", StartTime: recurrenceID.Add(time.Hour), EndTime: recurrenceID.Add(2 * time.Hour)},
+	}, result)
+}
+
+var exceptionQuery = regexp.QuoteMeta("SELECT series_id, recurrence_id, cancelled, title, description, start_time, end_time FROM event_exceptions " +
+	"WHERE series_id = $1 AND recurrence_id = $2")
+
+func (s *StorageTestSuite) TestSaveOccurrenceException_Success() {
+	ctx := s.ctx
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	recurrenceID := time.Date(2025, 12, 8, 9, 0, 0, 0, time.UTC)
+
+	s.expectTenant()
+	s.mock.ExpectQuery(lockedEventQuery).
+		WithArgs("series-1").
+		WillReturnRows(newEventRows().AddRow(eventRow("series-1", "sync", "", start, start.Add(time.Hour), start, nil, "FREQ=WEEKLY")...))
+	s.mock.ExpectQuery(exceptionQuery).
+		WithArgs("series-1", recurrenceID).
+		WillReturnError(sql.ErrNoRows)
+	s.mock.ExpectExec("INSERT INTO event_exceptions \\(series_id, recurrence_id, cancelled, title, description, start_time, end_time\\) "+
+		"VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5, \\$6, \\$7\\) ON CONFLICT \\(series_id, recurrence_id\\) DO UPDATE").
+		WithArgs("series-1", recurrenceID, false, "moved", nil, recurrenceID.Add(time.Hour), nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE events SET version = version + 1 WHERE id = $1")).
+		WithArgs("series-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.expectRevision("series-1", internal.RevisionUpdated,
+		`{"occurrences/2025-12-08T09:00:00Z": {"to": {"title": "moved", "start_time": "2025-12-08T10:00:00Z"}}}`)
+	s.mock.ExpectCommit()
+
+	err := s.storage.SaveOccurrenceException(ctx, internal.OccurrenceException{
+		SeriesID:     "series-1",
+		RecurrenceID: recurrenceID,
+		Title:        "moved",
+		StartTime:    recurrenceID.Add(time.Hour),
+	})
+
+	require.NoError(s.T(), err)
+}
+
+func (s *StorageTestSuite) TestSaveOccurrenceException_MovesBooking() {
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	recurrenceID := start.AddDate(0, 0, 7)
+	moved := recurrenceID.Add(2 * time.Hour)
+
+	s.expectTenant()
+	s.mock.ExpectQuery(lockedEventQuery).
+		WithArgs("series-1").
+		WillReturnRows(newEventRows().AddRow(eventRow("series-1", "sync", "", start, start.Add(time.Hour), start, nil,
+			"FREQ=WEEKLY;COUNT=3", nil, nil, "UTC", false, "room-4b")...))
+	s.mock.ExpectQuery(exceptionQuery).
+		WithArgs("series-1", recurrenceID).
+		WillReturnError(sql.ErrNoRows)
+	s.mock.ExpectExec("INSERT INTO event_exceptions").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE events SET version = version + 1 WHERE id = $1")).
+		WithArgs("series-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec(releaseQuery).WithArgs("series-1").WillReturnResult(sqlmock.NewResult(0, 3))
+	s.mock.ExpectQuery(seriesExceptions).
+		WithArgs(`{"series-1"}`).
+		WillReturnRows(sqlmock.NewRows([]string{"series_id", "recurrence_id", "cancelled", "title", "description", "start_time", "end_time"}).
+			AddRow("series-1", recurrenceID, false, nil, nil, moved, moved.Add(time.Hour)))
+	s.mock.ExpectExec(bookQuery).
+		WithArgs("series-1", "room-4b", bookedRanges(
+			start, start.Add(time.Hour),
+			moved, moved.Add(time.Hour),
+			start.AddDate(0, 0, 14), start.AddDate(0, 0, 14).Add(time.Hour),
+		)).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	s.expectRevision("series-1", internal.RevisionUpdated)
+	s.mock.ExpectCommit()
+
+	err := s.storage.SaveOccurrenceException(s.ctx, internal.OccurrenceException{
+		SeriesID:     "series-1",
+		RecurrenceID: recurrenceID,
+		StartTime:    moved,
+		EndTime:      moved.Add(time.Hour),
+	})
+
+	require.NoError(s.T(), err)
+}
+
+func (s *StorageTestSuite) TestSaveOccurrenceException_ReplacesException() {
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	recurrenceID := time.Date(2025, 12, 8, 9, 0, 0, 0, time.UTC)
+
+	s.expectTenant()
+	s.mock.ExpectQuery(lockedEventQuery).
+		WithArgs("series-1").
+		WillReturnRows(newEventRows().AddRow(eventRow("series-1", "sync", "", start, start.Add(time.Hour), start, nil, "FREQ=WEEKLY")...))
+	s.mock.ExpectQuery(exceptionQuery).
+		WithArgs("series-1", recurrenceID).
+		WillReturnRows(sqlmock.NewRows([]string{"series_id", "recurrence_id", "cancelled", "title", "description", "start_time", "end_time"}).
+			AddRow("series-1", recurrenceID, false, "moved", nil, nil, nil))
+	s.mock.ExpectExec("INSERT INTO event_exceptions").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE events SET version = version + 1 WHERE id = $1")).
+		WithArgs("series-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.expectRevision("series-1", internal.RevisionUpdated,
+		`{"occurrences/2025-12-08T09:00:00Z": {"from": {"title": "moved"}, "to": {"cancelled": true}}}`)
+	s.mock.ExpectCommit()
+
+	err := s.storage.SaveOccurrenceException(s.ctx, internal.OccurrenceException{SeriesID: "series-1", RecurrenceID: recurrenceID, Cancelled: true})
+
+	require.NoError(s.T(), err)
+}
+
+func (s *StorageTestSuite) TestSaveOccurrenceException_SeriesNotFound() {
+	s.expectTenant()
+	s.mock.ExpectQuery(lockedEventQuery).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+	s.mock.ExpectRollback()
+
+	err := s.storage.SaveOccurrenceException(s.ctx, internal.OccurrenceException{SeriesID: "missing", RecurrenceID: time.Now()})
+
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+}
+
+func (s *StorageTestSuite) TestSplitSeries_Success() {
+	ctx := s.ctx
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	at := start.AddDate(0, 0, 14)
+
+	head := internal.CreateEventRequest{Title: "sync", StartTime: start, EndTime: start.Add(time.Hour), RRule: "FREQ=WEEKLY;UNTIL=20251215T085959Z"}
+	tail := internal.CreateEventRequest{Title: "sync", StartTime: at, EndTime: at.Add(time.Hour), RRule: "FREQ=WEEKLY"}
+
+	s.expectTenant()
+
+	s.mock.ExpectQuery(lockedEventQuery).
+		WithArgs("series-1").
+		WillReturnRows(newEventRows().AddRow(eventRow("series-1", "sync", "", start, start.Add(time.Hour), start, nil, "FREQ=WEEKLY")...))
+
+	s.mock.ExpectExec("UPDATE events SET rrule = \\$1, exdate = \\$2, rdate = \\$3, series_end = \\$4, version = version \\+ 1 WHERE id = \\$5$").
+		WithArgs("FREQ=WEEKLY;UNTIL=20251215T085959Z", nil, nil, start.AddDate(0, 0, 7).Add(time.Hour), "series-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.expectRevision("series-1", internal.RevisionUpdated, `{"rrule": {"from": "FREQ=WEEKLY", "to": "FREQ=WEEKLY;UNTIL=20251215T085959Z"}}`)
+
+	s.mock.ExpectExec("DELETE FROM event_exceptions WHERE series_id = \\$1 AND recurrence_id >= \\$2").
+		WithArgs("series-1", at).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	s.mock.ExpectExec("INSERT INTO events").
+		WithArgs(sqlmock.AnyArg(), "sync", "", at, at.Add(time.Hour), sqlmock.AnyArg(), "FREQ=WEEKLY", nil, nil, nil, "UTC", false, nil, nil, "", nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	s.expectRevision(sqlmock.AnyArg(), internal.RevisionCreated)
+
+	s.mock.ExpectCommit()
+
+	result, err := s.storage.SplitSeries(ctx, "series-1", at, head, tail)
+
+	require.NoError(s.T(), err)
+	require.NotEmpty(s.T(), result.ID)
+	require.NotEqual(s.T(), "series-1", result.ID)
+	require.Equal(s.T(), at, result.StartTime)
+	require.Equal(s.T(), "FREQ=WEEKLY", result.RRule)
+}
+
+func (s *StorageTestSuite) TestSplitSeries_NotFound() {
+	ctx := s.ctx
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	at := start.AddDate(0, 0, 14)
+
+	head := internal.CreateEventRequest{StartTime: start, EndTime: start.Add(time.Hour), RRule: "FREQ=WEEKLY;UNTIL=20251215T085959Z"}
+	tail := internal.CreateEventRequest{StartTime: at, EndTime: at.Add(time.Hour), RRule: "FREQ=WEEKLY"}
+
+	s.expectTenant()
+
+	s.mock.ExpectQuery(lockedEventQuery).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	s.mock.ExpectRollback()
+
+	_, err := s.storage.SplitSeries(ctx, "missing", at, head, tail)
+
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+}
+
+func (s *StorageTestSuite) TestImportEvents_CreatesAndUpdates() {
+	ctx := s.ctx
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	event := internal.CreateEventRequest{Title: "imported", Description: "from ics", StartTime: start, EndTime: start.Add(time.Hour)}
+
+	s.expectTenant()
+	s.expectDefaultCalendar()
+
+	s.mock.ExpectQuery("SELECT " + strings.Join(eventColumns, ", ") + " FROM events WHERE id = \\$1 OR ical_uid = \\$1 LIMIT 1 FOR UPDATE").
+		WithArgs("new@example.com").
+		WillReturnError(sql.ErrNoRows)
+
+	s.mock.ExpectExec("INSERT INTO events \\(id, title, description, start_time, end_time, created_at, rrule, exdate, rdate, series_end, time_zone, all_day, ical_uid, created_by\\)").
+		WithArgs(sqlmock.AnyArg(), "imported", "from ics", start, start.Add(time.Hour), sqlmock.AnyArg(), nil, nil, nil, start.Add(time.Hour), "UTC", false, "new@example.com", nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	s.expectRevision(sqlmock.AnyArg(), internal.RevisionCreated)
+
+	s.mock.ExpectQuery("SELECT " + strings.Join(eventColumns, ", ") + " FROM events WHERE id = \\$1 OR ical_uid = \\$1 LIMIT 1 FOR UPDATE").
+		WithArgs("known@example.com").
+		WillReturnRows(newEventRows().AddRow(eventRow("existing-id", "imported", "old", start, start.Add(time.Hour), start)...))
+
+	s.mock.ExpectExec("UPDATE events SET title = \\$1, description = \\$2, start_time = \\$3, end_time = \\$4, rrule = \\$5, exdate = \\$6, rdate = \\$7, "+
+		"series_end = \\$8, time_zone = \\$9, all_day = \\$10, deleted_at = NULL, version = version \\+ 1 WHERE id = \\$11").
+		WithArgs("imported", "from ics", start, start.Add(time.Hour), nil, nil, nil, start.Add(time.Hour), "UTC", false, "existing-id").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.expectRevision("existing-id", internal.RevisionUpdated, `{"description": {"from": "old", "to": "from ics"}}`)
+
+	s.mock.ExpectCommit()
+
+	results, err := s.storage.ImportEvents(ctx, []internal.ImportedEvent{
+		{UID: "new@example.com", Event: event},
+		{UID: "known@example.com", Event: event},
+	})
+
+	require.NoError(s.T(), err)
+	require.Len(s.T(), results, 2)
+	require.Equal(s.T(), internal.ImportCreated, results[0].Status)
+	require.NotEmpty(s.T(), results[0].ID)
+	require.Equal(s.T(), internal.ImportResult{UID: "known@example.com", ID: "existing-id", Status: internal.ImportUpdated}, results[1])
+}
+
+func (s *StorageTestSuite) TestImportEvents_RollsBack() {
+	ctx := s.ctx
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	event := internal.CreateEventRequest{Title: "imported", Description: "from ics", StartTime: start, EndTime: start.Add(time.Hour)}
+
+	s.expectTenant()
+	s.expectDefaultCalendar()
+
+	s.mock.ExpectQuery("SELECT id, title").
+		WithArgs("new@example.com").
+		WillReturnError(errors.New("database error"))
+
+	s.mock.ExpectRollback()
+
+	_, err := s.storage.ImportEvents(ctx, []internal.ImportedEvent{{UID: "new@example.com", Event: event}})
+
+	require.Error(s.T(), err)
+}
+
+func (s *StorageTestSuite) TestRestoreEvent_ResourceConflict() {
+	ctx := s.ctx
+	eventID := "test-id-123"
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+
+	s.expectTenant()
+	s.mock.ExpectQuery(lockedDeletedEventQuery).
+		WithArgs(eventID).
+		WillReturnRows(newEventRows().AddRow(eventRow(eventID, "standup", "", start, start.Add(time.Hour), start, start, nil, nil, nil, "UTC", false, "room-4b")...))
+	s.mock.ExpectQuery("UPDATE events SET deleted_at = NULL, version = version \\+ 1 WHERE id = \\$1 RETURNING").
+		WithArgs(eventID).
+		WillReturnRows(newEventRows().AddRow(eventRow(eventID, "standup", "", start, start.Add(time.Hour), start, nil, nil, nil, nil, "UTC", false, "room-4b")...))
+	s.mock.ExpectExec(bookQuery).
+		WithArgs(eventID, "room-4b", bookedRanges(start, start.Add(time.Hour))).
+		WillReturnError(&pq.Error{Code: "23P01", Constraint: "event_bookings_no_overlap"})
+	s.mock.ExpectRollback()
+
+	s.expectTenant()
+	s.mock.ExpectQuery(conflictQuery).
+		WithArgs("room-4b", eventID, bookedRanges(start, start.Add(time.Hour))).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("booking-1"))
+	s.mock.ExpectRollback()
+
+	_, err := s.storage.RestoreEvent(ctx, eventID)
+
+	var conflict *internal.ConflictError
+
+	require.ErrorAs(s.T(), err, &conflict)
+	require.Equal(s.T(), []string{"booking-1"}, conflict.EventIDs)
+}
+
+func (s *StorageTestSuite) TestDeleteEvent_Success() {
+	ctx := s.ctx
+	eventID := "test-id-123"
+
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+
+	s.expectTenant()
+	s.mock.ExpectQuery(lockedEventQuery).
+		WithArgs(eventID).
+		WillReturnRows(newEventRows().AddRow(eventRow(eventID, "standup", "", start, start.Add(time.Hour), start)...))
+	s.mock.ExpectExec("UPDATE events SET deleted_at = \\$1, version = version \\+ 1 WHERE id = \\$2$").
+		WithArgs(sqlmock.AnyArg(), eventID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.expectRevision(eventID, internal.RevisionDeleted)
+
+	s.mock.ExpectCommit()
+
+	err := s.storage.DeleteEvent(ctx, eventID)
+
+	require.NoError(s.T(), err)
+}
+
+func (s *StorageTestSuite) TestDeleteEvent_ReleasesBooking() {
+	eventID := "test-id-123"
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+
+	s.expectTenant()
+	s.mock.ExpectQuery(lockedEventQuery).
+		WithArgs(eventID).
+		WillReturnRows(newEventRows().AddRow(eventRow(eventID, "standup", "", start, start.Add(time.Hour), start, nil, nil, nil, nil, "UTC", false, "room-4b")...))
+	s.mock.ExpectExec("UPDATE events SET deleted_at = \\$1, version = version \\+ 1 WHERE id = \\$2$").
+		WithArgs(sqlmock.AnyArg(), eventID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec(releaseQuery).WithArgs(eventID).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.expectRevision(eventID, internal.RevisionDeleted)
+	s.mock.ExpectCommit()
+
+	err := s.storage.DeleteEvent(s.ctx, eventID)
+
+	require.NoError(s.T(), err)
+}
+
+func (s *StorageTestSuite) TestDeleteEvent_NotFound() {
+	ctx := s.ctx
+	eventID := "nonexistent-id"
+
+	s.expectTenant()
+	s.mock.ExpectQuery(lockedEventQuery).
+		WithArgs(eventID).
+		WillReturnError(sql.ErrNoRows)
+
+	s.mock.ExpectRollback()
+
+	err := s.storage.DeleteEvent(ctx, eventID)
+
+	require.Error(s.T(), err)
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+}
+
+func (s *StorageTestSuite) TestDeleteEvent_ExecError() {
+	ctx := s.ctx
+
+	now := time.Now().UTC()
+
+	s.expectTenant()
+	s.mock.ExpectQuery(lockedEventQuery).
+		WithArgs("test-id").
+		WillReturnRows(newEventRows().AddRow(eventRow("test-id", "standup", "", now, now.Add(time.Hour), now)...))
+	s.mock.ExpectExec("UPDATE events SET deleted_at").
+		WillReturnError(errors.New("database connection error"))
+
+	s.mock.ExpectRollback()
+
+	err := s.storage.DeleteEvent(ctx, "test-id")
+
+	require.Error(s.T(), err)
+	require.NotErrorIs(s.T(), err, internal.ErrNotFound)
+	require.Contains(s.T(), err.Error(), "deleting event")
+}
+
+func (s *StorageTestSuite) TestRestoreEvent_Success() {
+	ctx := s.ctx
+	eventID := "test-id-123"
+	now := time.Now().UTC()
+
+	rows := newEventRows().AddRow(eventRow(eventID, strings.Repeat("a", 101), "Test Description", now, now.Add(time.Hour), now, nil)...)
+
+	s.expectTenant()
+	s.mock.ExpectQuery(lockedDeletedEventQuery).
+		WithArgs(eventID).
+		WillReturnRows(newEventRows().AddRow(eventRow(eventID, strings.Repeat("a", 101), "Test Description", now, now.Add(time.Hour), now, now)...))
+	s.mock.ExpectQuery("UPDATE events SET deleted_at = NULL, version = version \\+ 1 WHERE id = \\$1 RETURNING").
+		WithArgs(eventID).
+		WillReturnRows(rows)
+	s.expectRevision(eventID, internal.RevisionRestored, fmt.Sprintf(`{"deleted_at": {"from": %q}}`, now.Truncate(time.Microsecond).Format(time.RFC3339Nano)))
+
+	s.mock.ExpectCommit()
+
+	result, err := s.storage.RestoreEvent(ctx, eventID)
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), eventID, result.ID)
+	require.Nil(s.T(), result.DeletedAt)
+}
+
+func (s *StorageTestSuite) TestRestoreEvent_NotDeleted() {
+	ctx := s.ctx
+	eventID := "test-id-123"
+
+	s.expectTenant()
+	s.mock.ExpectQuery(lockedDeletedEventQuery).
+		WithArgs(eventID).
+		WillReturnError(sql.ErrNoRows)
+
+	s.mock.ExpectRollback()
+
+	_, err := s.storage.RestoreEvent(ctx, eventID)
+
+	require.Error(s.T(), err)
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+}
+
+var revisionColumns = []string{"event_id", "revision", "action", "actor", "created_at", "changes", "snapshot"}
+
+func (s *StorageTestSuite) TestGetEventRevisions_Success() {
+	at := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+
+	s.expectTenant()
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT event_id, revision, action, actor, created_at, changes, snapshot FROM event_revisions WHERE event_id = $1 ORDER BY revision")).
+		WithArgs("evt").
+		WillReturnRows(sqlmock.NewRows(revisionColumns).
+			AddRow("evt", 1, "created", "auth0|ada", at, []byte(`{"title": {"to": "standup"}}`),
+				[]byte(`{"title": "standup", "start_time": "2025-12-01T09:00:00Z", "end_time": "2025-12-01T10:00:00Z", "time_zone": "Europe/Madrid"}`)).
+			AddRow("evt", 2, "updated", nil, at.Add(time.Hour), []byte(`{"title": {"from": "standup", "to": "retro"}}`),
+				[]byte(`{"title": "retro", "start_time": "2025-12-01T09:00:00Z", "end_time": "2025-12-01T10:00:00Z"}`)))
+	s.mock.ExpectRollback()
+
+	revisions, err := s.storage.GetEventRevisions(s.ctx, "evt")
+
+	require.NoError(s.T(), err)
+	require.Len(s.T(), revisions, 2)
+	require.Equal(s.T(), internal.EventRevision{
+		EventID:   "evt",
+		Revision:  1,
+		Action:    internal.RevisionCreated,
+		Actor:     "auth0|ada",
+		CreatedAt: at,
+		Changes:   map[string]internal.FieldChange{"title": {To: []byte(`"standup"`)}},
+		Snapshot:  internal.CreateEventRequest{Title: "standup", StartTime: at, EndTime: at.Add(time.Hour), TimeZone: "Europe/Madrid"},
+	}, revisions[0])
+	require.Equal(s.T(), internal.RevisionUpdated, revisions[1].Action)
+	require.Empty(s.T(), revisions[1].Actor)
+	require.JSONEq(s.T(), `"standup"`, string(revisions[1].Changes["title"].From))
+}
+
+func (s *StorageTestSuite) TestGetEventRevisions_NoHistoryYet() {
+	s.expectTenant()
+	s.mock.ExpectQuery("SELECT event_id, revision").
+		WithArgs("evt").
+		WillReturnRows(sqlmock.NewRows(revisionColumns))
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM events WHERE id = $1)")).
+		WithArgs("evt").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	s.mock.ExpectRollback()
+
+	revisions, err := s.storage.GetEventRevisions(s.ctx, "evt")
+
+	require.NoError(s.T(), err)
+	require.Empty(s.T(), revisions)
+	require.NotNil(s.T(), revisions)
+}
+
+func (s *StorageTestSuite) TestGetEventRevisions_EventNotFound() {
+	s.expectTenant()
+	s.mock.ExpectQuery("SELECT event_id, revision").
+		WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows(revisionColumns))
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM events WHERE id = $1)")).
+		WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	s.mock.ExpectRollback()
+
+	_, err := s.storage.GetEventRevisions(s.ctx, "missing")
+
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+}
+
+func (s *StorageTestSuite) TestGetEventRevision_NotFound() {
+	s.expectTenant()
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT event_id, revision, action, actor, created_at, changes, snapshot FROM event_revisions WHERE event_id = $1 AND revision = $2")).
+		WithArgs("evt", 7).
+		WillReturnError(sql.ErrNoRows)
+	s.mock.ExpectRollback()
+
+	_, err := s.storage.GetEventRevision(s.ctx, "evt", 7)
+
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+	require.EqualError(s.T(), err, "revision not found: not found")
+}
+
+func (s *StorageTestSuite) TestCreateEvent_WritesOutbox() {
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	ctx := internal.WithActor(s.ctx, "auth0|ada")
+
+	var payload map[string]any
+
+	s.expectTenant()
+	s.mock.ExpectExec("INSERT INTO events").WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectQuery("INSERT INTO event_revisions").
+		WillReturnRows(sqlmock.NewRows([]string{"revision"}).AddRow(1))
+	s.expectOutbox("events.created", sqlmock.AnyArg(), argFunc(func(value driver.Value) bool {
+		data, ok := value.([]byte)
+		return ok && json.Unmarshal(data, &payload) == nil
+	}))
+	s.mock.ExpectCommit()
+
+	created, err := s.storage.CreateEvent(ctx, internal.CreateEventRequest{
+		Title:      "Standup",
+		StartTime:  start,
+		EndTime:    start.Add(15 * time.Minute),
+		CalendarID: "team",
+	})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), created.ID, payload["event_id"])
+	require.Equal(s.T(), float64(1), payload["revision"])
+	require.Equal(s.T(), "created", payload["action"])
+	require.Equal(s.T(), "auth0|ada", payload["actor"])
+	require.Equal(s.T(), map[string]any{"to": "Standup"}, payload["changes"].(map[string]any)["title"])
+	require.Equal(s.T(), map[string]any{
+		"title":       "Standup",
+		"start_time":  "2025-12-01T09:00:00Z",
+		"end_time":    "2025-12-01T09:15:00Z",
+		"time_zone":   "UTC",
+		"calendar_id": "team",
+	}, payload["event"])
+}
+
+func (s *StorageTestSuite) TestCreateEvent_OutboxError() {
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+
+	s.expectTenant()
+	s.mock.ExpectExec("INSERT INTO events").WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectQuery("INSERT INTO event_revisions").
+		WillReturnRows(sqlmock.NewRows([]string{"revision"}).AddRow(1))
+	s.mock.ExpectExec("INSERT INTO outbox").WillReturnError(errors.New("disk full"))
+	s.mock.ExpectRollback()
+
+	_, err := s.storage.CreateEvent(s.ctx, internal.CreateEventRequest{
+		Title:      "Standup",
+		StartTime:  start,
+		EndTime:    start.Add(15 * time.Minute),
+		CalendarID: "team",
+	})
+
+	require.EqualError(s.T(), err, "writing outbox message: disk full")
+}
+
+var outboxColumns = []string{"id", "tenant_id", "topic", "key", "payload", "created_at", "attempts"}
+
+const (
+	claimOutboxQuery = "UPDATE outbox SET next_attempt_at = $1 WHERE id IN (SELECT id FROM outbox " +
+		"WHERE published_at IS NULL AND dead_at IS NULL AND next_attempt_at <= $2 " +
+		"AND NOT EXISTS (SELECT 1 FROM outbox earlier WHERE earlier.tenant_id = outbox.tenant_id AND earlier.key = outbox.key " +
+		"AND earlier.id < outbox.id AND earlier.published_at IS NULL AND earlier.dead_at IS NULL) " +
+		"ORDER BY id LIMIT $3 FOR UPDATE SKIP LOCKED) " +
+		"RETURNING id, tenant_id, topic, key, payload, created_at, attempts"
+	deliveredQuery = "UPDATE outbox SET attempts = attempts + 1, published_at = $1, last_error = NULL WHERE id = $2"
+	failedQuery    = "UPDATE outbox SET attempts = $1, last_error = $2, next_attempt_at = $3, dead_at = $4 WHERE id = $5"
+)
+
+// expectClaim expects the relay to claim the messages due, leasing them for a
+// minute. The relay reads every tenant, so it does not set one.
+func (s *StorageTestSuite) expectClaim(rows *sqlmock.Rows) {
+	s.mock.ExpectQuery(regexp.QuoteMeta(claimOutboxQuery)).
+		WithArgs(argFunc(func(value driver.Value) bool {
+			lease, ok := value.(time.Time)
+			return ok && time.Until(lease) > 50*time.Second
+		}), sqlmock.AnyArg(), 10).
+		WillReturnRows(rows)
+}
+
+// retryIn matches a retry time about after from now.
+func retryIn(after time.Duration) sqlmock.Argument {
+	return argFunc(func(value driver.Value) bool {
+		retry, ok := value.(time.Time)
+		wait := time.Until(retry)
+
+		return ok && wait > after-5*time.Second && wait <= after
+	})
+}
+
+func (s *StorageTestSuite) TestDeliverOutbox_Success() {
+	now := time.Now().UTC()
+
+	// The claim returns the messages unordered; they are delivered by id.
+	s.expectClaim(sqlmock.NewRows(outboxColumns).
+		AddRow(2, "globex", "events.deleted", "evt-2", []byte(`{"event_id":"evt-2"}`), now, 3).
+		AddRow(1, "acme", "events.created", "evt-1", []byte(`{"event_id":"evt-1"}`), now, 0))
+	s.mock.ExpectExec(regexp.QuoteMeta(deliveredQuery)).WithArgs(sqlmock.AnyArg(), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta(deliveredQuery)).WithArgs(sqlmock.AnyArg(), 2).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	var published []internal.OutboxMessage
+
+	delivered, err := s.storage.DeliverOutbox(context.Background(), 10, func(_ context.Context, message internal.OutboxMessage) error {
+		published = append(published, message)
+		return nil
+	})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 2, delivered)
+	require.Equal(s.T(), []internal.OutboxMessage{
+		{ID: 1, TenantID: "acme", Topic: "events.created", Key: "evt-1", Payload: []byte(`{"event_id":"evt-1"}`), CreatedAt: now},
+		{ID: 2, TenantID: "globex", Topic: "events.deleted", Key: "evt-2", Payload: []byte(`{"event_id":"evt-2"}`), CreatedAt: now, Attempts: 3},
+	}, published)
+}
+
+func (s *StorageTestSuite) TestDeliverOutbox_PublishError() {
+	now := time.Now().UTC()
+
+	// Message 2 fails for the second time, so it waits two seconds, and the
+	// other events go ahead.
+	s.expectClaim(sqlmock.NewRows(outboxColumns).
+		AddRow(1, "acme", "events.created", "evt-1", []byte(`{}`), now, 0).
+		AddRow(2, "acme", "events.updated", "evt-2", []byte(`{}`), now, 1).
+		AddRow(4, "acme", "events.created", "evt-3", []byte(`{}`), now, 0))
+	s.mock.ExpectExec(regexp.QuoteMeta(deliveredQuery)).WithArgs(sqlmock.AnyArg(), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta(failedQuery)).
+		WithArgs(2, "broker down", retryIn(2*time.Second), sql.NullTime{}, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta(deliveredQuery)).WithArgs(sqlmock.AnyArg(), 4).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	var published []int64
+
+	delivered, err := s.storage.DeliverOutbox(context.Background(), 10, func(_ context.Context, message internal.OutboxMessage) error {
+		if message.ID == 2 {
+			return errors.New("broker down")
+		}
+
+		published = append(published, message.ID)
+		return nil
+	})
+
+	require.EqualError(s.T(), err, "publishing outbox message 2: broker down")
+	require.Equal(s.T(), 2, delivered)
+	require.Equal(s.T(), []int64{1, 4}, published)
+}
+
+func (s *StorageTestSuite) TestDeliverOutbox_DeadLetter() {
+	now := time.Now().UTC()
+
+	// The tenth failure sets the message aside, so the next claim hands over
+	// the next one of its event.
+	s.expectClaim(sqlmock.NewRows(outboxColumns).
+		AddRow(1, "acme", "events.updated", "evt-1", []byte(`{}`), now, 9))
+	s.mock.ExpectExec(regexp.QuoteMeta(failedQuery)).
+		WithArgs(10, "rejected", sqlmock.AnyArg(), argFunc(func(value driver.Value) bool {
+			deadAt, ok := value.(time.Time)
+			return ok && time.Since(deadAt) < 5*time.Second
+		}), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	delivered, err := s.storage.DeliverOutbox(context.Background(), 10, func(_ context.Context, message internal.OutboxMessage) error {
+		return errors.New("rejected")
+	})
+
+	require.EqualError(s.T(), err, "publishing outbox message 1: rejected")
+	require.Zero(s.T(), delivered)
+}
+
+func (s *StorageTestSuite) TestDeliverOutbox_ClaimError() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(claimOutboxQuery)).WillReturnError(errors.New("connection reset"))
+
+	_, err := s.storage.DeliverOutbox(context.Background(), 10, func(context.Context, internal.OutboxMessage) error {
+		s.T().Fatal("nothing to deliver")
+		return nil
+	})
+
+	require.EqualError(s.T(), err, "claiming outbox messages: connection reset")
+}
+
+func (s *StorageTestSuite) TestDeliverOutbox_Empty() {
+	s.expectClaim(sqlmock.NewRows(outboxColumns))
+
+	delivered, err := s.storage.DeliverOutbox(context.Background(), 10, func(context.Context, internal.OutboxMessage) error {
+		s.T().Fatal("nothing to deliver")
+		return nil
+	})
+
+	require.NoError(s.T(), err)
+	require.Zero(s.T(), delivered)
+}
+
+func (s *StorageTestSuite) TestDeliverOutbox_InvalidLimit() {
+	_, err := s.storage.DeliverOutbox(context.Background(), 0, nil)
+
+	require.ErrorIs(s.T(), err, internal.ErrInput)
+}
+
+var attendeeColumns = []string{"event_id", "email", "name", "role", "status", "invited_at", "status_changed_at"}
+
+func (s *StorageTestSuite) TestAddAttendee_Success() {
+	ctx := s.ctx
+	now := time.Now().UTC()
+	attendee := internal.Attendee{
+		EventID:         "event",
+		Email:           "ada@example.com",
+		Name:            "Ada",
+		Role:            internal.RoleChair,
+		Status:          internal.StatusInvited,
+		InvitedAt:       now,
+		StatusChangedAt: now,
+	}
+
+	s.expectTenant()
+	s.mock.ExpectQuery(lockEventQuery).WithArgs("event").WillReturnRows(sqlmock.NewRows([]string{"capacity"}).AddRow(nil))
+	s.mock.ExpectExec("INSERT INTO attendees \\("+strings.Join(attendeeColumns, ", ")+"\\) "+
+		"SELECT id, \\$2, \\$3, \\$4, \\$5, \\$6, \\$7 FROM events WHERE id = \\$1 AND deleted_at IS NULL").
+		WithArgs("event", "ada@example.com", "Ada", "chair", "invited", now, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectCommit()
+
+	require.NoError(s.T(), s.storage.AddAttendee(ctx, attendee))
+}
+
+func (s *StorageTestSuite) TestAddAttendee_EventNotFound() {
+	s.expectTenant()
+	s.mock.ExpectQuery(lockEventQuery).WithArgs("missing").WillReturnError(sql.ErrNoRows)
+
+	s.mock.ExpectRollback()
+
+	err := s.storage.AddAttendee(s.ctx, internal.Attendee{EventID: "missing", Email: "ada@example.com"})
+
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+}
+
+func (s *StorageTestSuite) TestAddAttendee_AlreadyInvited() {
+	s.expectTenant()
+	s.mock.ExpectQuery(lockEventQuery).WithArgs("event").WillReturnRows(sqlmock.NewRows([]string{"capacity"}).AddRow(nil))
+	s.mock.ExpectExec("INSERT INTO attendees").WillReturnError(&pq.Error{Code: "23505", Constraint: "attendees_pkey"})
+
+	s.mock.ExpectRollback()
+
+	err := s.storage.AddAttendee(s.ctx, internal.Attendee{EventID: "event", Email: "ada@example.com"})
+
+	require.ErrorIs(s.T(), err, internal.ErrConflict)
+	require.EqualError(s.T(), err, "ada@example.com is already invited: conflict")
+}
+
+func (s *StorageTestSuite) TestGetAttendees_Success() {
+	ctx := s.ctx
+	now := time.Now().UTC()
+
+	s.expectTenant()
+	s.mock.ExpectQuery("SELECT EXISTS \\(SELECT 1 FROM events WHERE id = \\$1 AND deleted_at IS NULL\\)").
+		WithArgs("event").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	s.mock.ExpectQuery("SELECT " + strings.Join(attendeeColumns, ", ") + " FROM attendees WHERE event_id = \\$1 ORDER BY invited_at, email").
+		WithArgs("event").
+		WillReturnRows(sqlmock.NewRows(attendeeColumns).
+			AddRow("event", "ada@example.com", "Ada", "chair", "accepted", now, now.Add(time.Hour)).
+			AddRow("event", "grace@example.com", "", "optional", "invited", now, now))
+
+	s.mock.ExpectRollback()
+
+	attendees, err := s.storage.GetAttendees(ctx, "event")
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), []internal.Attendee{
+		{EventID: "event", Email: "ada@example.com", Name: "Ada", Role: internal.RoleChair, Status: internal.StatusAccepted, InvitedAt: now, StatusChangedAt: now.Add(time.Hour)},
+		{EventID: "event", Email: "grace@example.com", Role: internal.RoleOptional, Status: internal.StatusInvited, InvitedAt: now, StatusChangedAt: now},
+	}, attendees)
+}
+
+func (s *StorageTestSuite) TestGetAttendees_EventNotFound() {
+	s.expectTenant()
+	s.mock.ExpectQuery("SELECT EXISTS").WithArgs("missing").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	s.mock.ExpectRollback()
+
+	_, err := s.storage.GetAttendees(s.ctx, "missing")
+
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+}
+
+const (
+	lockEventQuery      = "UPDATE events SET version = version \\+ 1 WHERE id = \\$1 AND deleted_at IS NULL RETURNING capacity"
+	selectAttendeeQuery = "SELECT event_id, email, name, role, status, invited_at, status_changed_at FROM attendees WHERE event_id = \\$1 AND email = \\$2"
+	countAcceptedQuery  = "SELECT COUNT\\(\\*\\) FROM attendees WHERE event_id = \\$1 AND status = \\$2"
+	setStatusQuery      = "UPDATE attendees SET status_changed_at = CASE WHEN status = \\$1 THEN status_changed_at ELSE \\$2 END, status = \\$1 " +
+		"WHERE event_id = \\$3 AND email = \\$4 RETURNING event_id, email, name, role, status, invited_at, status_changed_at"
+	promoteWaitlistQuery = "UPDATE attendees SET status = \\$1, status_changed_at = \\$2 WHERE event_id = \\$3 AND email IN \\(" +
+		"SELECT email FROM attendees WHERE event_id = \\$3 AND status = \\$4 ORDER BY status_changed_at, email " +
+		"LIMIT \\(SELECT CASE WHEN capacity IS NULL THEN NULL " +
+		"ELSE GREATEST\\(capacity - \\(SELECT COUNT\\(\\*\\) FROM attendees WHERE event_id = \\$3 AND status = \\$1\\), 0\\) END " +
+		"FROM events WHERE id = \\$3\\)\\)"
+)
+
+func (s *StorageTestSuite) expectAttendee(status string, invitedAt time.Time) {
+	s.mock.ExpectQuery(selectAttendeeQuery).
+		WithArgs("event", "ada@example.com").
+		WillReturnRows(sqlmock.NewRows(attendeeColumns).AddRow("event", "ada@example.com", "", "required", status, invitedAt, invitedAt))
+}
+
+func (s *StorageTestSuite) TestSetAttendeeStatus_Success() {
+	ctx := s.ctx
+	now := time.Now().UTC()
+
+	s.expectTenant()
+	s.mock.ExpectQuery(lockEventQuery).WithArgs("event").WillReturnRows(sqlmock.NewRows([]string{"capacity"}).AddRow(nil))
+	s.expectAttendee("invited", now.Add(-time.Hour))
+	s.mock.ExpectQuery(setStatusQuery).
+		WithArgs("declined", now, "event", "ada@example.com").
+		WillReturnRows(sqlmock.NewRows(attendeeColumns).AddRow("event", "ada@example.com", "", "required", "declined", now.Add(-time.Hour), now))
+	s.mock.ExpectCommit()
+
+	attendee, err := s.storage.SetAttendeeStatus(ctx, "event", "ada@example.com", internal.StatusDeclined, now)
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), internal.StatusDeclined, attendee.Status)
+	require.Equal(s.T(), now, attendee.StatusChangedAt)
+}
+
+func (s *StorageTestSuite) TestSetAttendeeStatus_AcceptsWithinCapacity() {
+	now := time.Now().UTC()
+
+	s.expectTenant()
+	s.mock.ExpectQuery(lockEventQuery).WithArgs("event").WillReturnRows(sqlmock.NewRows([]string{"capacity"}).AddRow(10))
+	s.expectAttendee("invited", now)
+	s.mock.ExpectQuery(countAcceptedQuery).WithArgs("event", "accepted").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(9))
+	s.mock.ExpectQuery(setStatusQuery).
+		WithArgs("accepted", now, "event", "ada@example.com").
+		WillReturnRows(sqlmock.NewRows(attendeeColumns).AddRow("event", "ada@example.com", "", "required", "accepted", now, now))
+	s.mock.ExpectCommit()
+
+	attendee, err := s.storage.SetAttendeeStatus(s.ctx, "event", "ada@example.com", internal.StatusAccepted, now)
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), internal.StatusAccepted, attendee.Status)
+}
+
+func (s *StorageTestSuite) TestSetAttendeeStatus_WaitlistsWhenFull() {
+	now := time.Now().UTC()
+
+	s.expectTenant()
+	s.mock.ExpectQuery(lockEventQuery).WithArgs("event").WillReturnRows(sqlmock.NewRows([]string{"capacity"}).AddRow(10))
+	s.expectAttendee("tentative", now)
+	s.mock.ExpectQuery(countAcceptedQuery).WithArgs("event", "accepted").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(10))
+	s.mock.ExpectQuery(setStatusQuery).
+		WithArgs("waitlisted", now, "event", "ada@example.com").
+		WillReturnRows(sqlmock.NewRows(attendeeColumns).AddRow("event", "ada@example.com", "", "required", "waitlisted", now, now))
+	s.mock.ExpectCommit()
+
+	attendee, err := s.storage.SetAttendeeStatus(s.ctx, "event", "ada@example.com", internal.StatusAccepted, now)
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), internal.StatusWaitlisted, attendee.Status)
+}
+
+func (s *StorageTestSuite) TestSetAttendeeStatus_DeclinePromotesWaitlist() {
+	now := time.Now().UTC()
+
+	s.expectTenant()
+	s.mock.ExpectQuery(lockEventQuery).WithArgs("event").WillReturnRows(sqlmock.NewRows([]string{"capacity"}).AddRow(10))
+	s.expectAttendee("accepted", now.Add(-time.Hour))
+	s.mock.ExpectQuery(setStatusQuery).
+		WithArgs("declined", now, "event", "ada@example.com").
+		WillReturnRows(sqlmock.NewRows(attendeeColumns).AddRow("event", "ada@example.com", "", "required", "declined", now, now))
+	s.mock.ExpectExec(promoteWaitlistQuery).
+		WithArgs("accepted", now, "event", "waitlisted").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	_, err := s.storage.SetAttendeeStatus(s.ctx, "event", "ada@example.com", internal.StatusDeclined, now)
+
+	require.NoError(s.T(), err)
+}
+
+func (s *StorageTestSuite) TestSetAttendeeStatus_EventNotFound() {
+	s.expectTenant()
+	s.mock.ExpectQuery(lockEventQuery).WithArgs("event").WillReturnRows(sqlmock.NewRows([]string{"capacity"}))
+	s.mock.ExpectRollback()
+
+	_, err := s.storage.SetAttendeeStatus(s.ctx, "event", "ada@example.com", internal.StatusAccepted, time.Now())
+
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+	require.EqualError(s.T(), err, "event not found: not found")
+}
+
+func (s *StorageTestSuite) TestSetAttendeeStatus_NotFound() {
+	s.expectTenant()
+	s.mock.ExpectQuery(lockEventQuery).WithArgs("event").WillReturnRows(sqlmock.NewRows([]string{"capacity"}).AddRow(nil))
+	s.mock.ExpectQuery(selectAttendeeQuery).WithArgs("event", "ada@example.com").WillReturnRows(sqlmock.NewRows(attendeeColumns))
+	s.mock.ExpectRollback()
+
+	_, err := s.storage.SetAttendeeStatus(s.ctx, "event", "ada@example.com", internal.StatusAccepted, time.Now())
+
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+}
+
+func (s *StorageTestSuite) TestRemoveAttendee_PromotesWaitlist() {
+	s.expectTenant()
+	s.mock.ExpectQuery(lockEventQuery).WithArgs("event").WillReturnRows(sqlmock.NewRows([]string{"capacity"}).AddRow(10))
+	s.mock.ExpectQuery("DELETE FROM attendees WHERE event_id = \\$1 AND email = \\$2 RETURNING status").
+		WithArgs("event", "ada@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("accepted"))
+	s.mock.ExpectExec(promoteWaitlistQuery).
+		WithArgs("accepted", sqlmock.AnyArg(), "event", "waitlisted").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	require.NoError(s.T(), s.storage.RemoveAttendee(s.ctx, "event", "ada@example.com"))
+}
+
+func (s *StorageTestSuite) TestRemoveAttendee_NotFound() {
+	s.expectTenant()
+	s.mock.ExpectQuery(lockEventQuery).WithArgs("event").WillReturnRows(sqlmock.NewRows([]string{"capacity"}).AddRow(nil))
+	s.mock.ExpectQuery("DELETE FROM attendees").
+		WithArgs("event", "ada@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}))
+	s.mock.ExpectRollback()
+
+	err := s.storage.RemoveAttendee(s.ctx, "event", "ada@example.com")
+
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+}
+
+func (s *StorageTestSuite) TestCountAttendees_Success() {
+	s.expectTenant()
+	s.mock.ExpectQuery("SELECT status, COUNT\\(\\*\\) FROM attendees WHERE event_id = \\$1 GROUP BY status").
+		WithArgs("event").
+		WillReturnRows(sqlmock.NewRows([]string{"status", "count"}).AddRow("accepted", 2).AddRow("declined", 1))
+
+	s.mock.ExpectRollback()
+
+	counts, err := s.storage.CountAttendees(s.ctx, "event")
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), map[internal.AttendeeStatus]int{internal.StatusAccepted: 2, internal.StatusDeclined: 1}, counts)
+}
+
+func TestStorageTestSuite(t *testing.T) {
+	suite.Run(t, new(StorageTestSuite))
+}
+
+var calendarColumns = []string{"id", "name", "description", "created_at", "created_by"}
+
+func (s *StorageTestSuite) TestCreateEvent_CalendarDeleted() {
+	now := time.Now().UTC()
+
+	s.expectTenant()
+	s.mock.ExpectExec("INSERT INTO events").WillReturnError(&pq.Error{Code: "23503", Constraint: "events_calendar_id_fkey"})
+	s.mock.ExpectRollback()
+
+	_, err := s.storage.CreateEvent(s.ctx, internal.CreateEventRequest{
+		Title:      strings.Repeat("a", 101),
+		StartTime:  now,
+		EndTime:    now.Add(time.Hour),
+		CalendarID: "work",
+	})
+
+	require.ErrorIs(s.T(), err, internal.ErrInput)
+	require.EqualError(s.T(), err, `creating event: calendar "work" does not exist: missing input values`)
+}
+
+func (s *StorageTestSuite) TestCreateCalendar_Success() {
+	s.expectTenant()
+	s.mock.ExpectExec("INSERT INTO calendars \\(id, name, description, created_at, created_by\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5\\)").
+		WithArgs(sqlmock.AnyArg(), "Work", "Team events", sqlmock.AnyArg(), "auth0|ada").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	s.mock.ExpectCommit()
+
+	calendar, err := s.storage.CreateCalendar(s.ctx, internal.Calendar{Name: "Work", Description: "Team events", CreatedBy: "auth0|ada"})
+
+	require.NoError(s.T(), err)
+	require.NotEmpty(s.T(), calendar.ID)
+	require.False(s.T(), calendar.CreatedAt.IsZero())
+}
+
+func (s *StorageTestSuite) TestGetCalendars_Success() {
+	now := time.Now().UTC()
+
+	s.expectTenant()
+	s.expectDefaultCalendar()
+	s.mock.ExpectQuery("SELECT id, name, description, created_at, created_by FROM calendars ORDER BY name, id").
+		WillReturnRows(sqlmock.NewRows(calendarColumns).
+			AddRow(internal.DefaultCalendarID, "Default", "", now, nil).
+			AddRow("work", "Work", "Team events", now, "auth0|ada"))
+
+	s.mock.ExpectCommit()
+
+	calendars, err := s.storage.GetCalendars(s.ctx, "")
+
+	require.NoError(s.T(), err)
+	require.Len(s.T(), calendars, 2)
+	require.Equal(s.T(), "work", calendars[1].ID)
+	require.Equal(s.T(), "auth0|ada", calendars[1].CreatedBy)
+}
+
+func (s *StorageTestSuite) TestGetCalendar_NotFound() {
+	s.expectTenant()
+	s.mock.ExpectQuery("SELECT id, name, description, created_at, created_by FROM calendars WHERE id = \\$1").
+		WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows(calendarColumns))
+
+	s.mock.ExpectRollback()
+
+	_, err := s.storage.GetCalendar(s.ctx, "missing")
+
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+}
+
+func (s *StorageTestSuite) TestUpdateCalendar_Success() {
+	now := time.Now().UTC()
+
+	s.expectTenant()
+	s.mock.ExpectQuery("UPDATE calendars SET name = \\$1, description = \\$2 WHERE id = \\$3 RETURNING id, name, description, created_at, created_by").
+		WithArgs("Office", "", "work").
+		WillReturnRows(sqlmock.NewRows(calendarColumns).AddRow("work", "Office", "", now, nil))
+
+	s.mock.ExpectCommit()
+
+	calendar, err := s.storage.UpdateCalendar(s.ctx, internal.Calendar{ID: "work", Name: "Office"})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), internal.Calendar{ID: "work", Name: "Office", CreatedAt: now}, calendar)
+}
+
+func (s *StorageTestSuite) TestDeleteCalendar_StillHasEvents() {
+	s.expectTenant()
+	s.mock.ExpectExec("DELETE FROM calendars WHERE id = \\$1").
+		WithArgs("work").
+		WillReturnError(&pq.Error{Code: "23503", Constraint: "events_calendar_id_fkey"})
+
+	s.mock.ExpectRollback()
+
+	err := s.storage.DeleteCalendar(s.ctx, "work")
+
+	require.ErrorIs(s.T(), err, internal.ErrConflict)
+}
+
+func (s *StorageTestSuite) TestDeleteCalendar_NotFound() {
+	s.expectTenant()
+	s.mock.ExpectExec("DELETE FROM calendars WHERE id = \\$1").
+		WithArgs("missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	s.mock.ExpectRollback()
+
+	err := s.storage.DeleteCalendar(s.ctx, "missing")
+
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+}
+
+func (s *StorageTestSuite) TestGetEventByID_NoTenant() {
+	_, err := s.storage.GetEventByID(context.Background(), "test-id-123", false)
+
+	require.EqualError(s.T(), err, "no tenant in context")
+}
+
+func (s *StorageTestSuite) TestGetEventByID_SetTenantError() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec("SELECT set_config").
+		WithArgs("acme").
+		WillReturnError(errors.New("connection reset"))
+	s.mock.ExpectRollback()
+
+	_, err := s.storage.GetEventByID(s.ctx, "test-id-123", false)
+
+	require.EqualError(s.T(), err, "setting tenant: connection reset")
+}
+
+func (s *StorageTestSuite) TestGetCalendar_CreatesDefault() {
+	now := time.Now().UTC()
+
+	s.expectTenant()
+	s.expectDefaultCalendar()
+	s.mock.ExpectQuery("SELECT id, name, description, created_at, created_by FROM calendars WHERE id = \\$1").
+		WithArgs(internal.DefaultCalendarID).
+		WillReturnRows(sqlmock.NewRows(calendarColumns).AddRow(internal.DefaultCalendarID, "Default", "", now, nil))
+	s.mock.ExpectCommit()
+
+	calendar, err := s.storage.GetCalendar(s.ctx, internal.DefaultCalendarID)
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), "Default", calendar.Name)
+}
+
+func (s *StorageTestSuite) TestValidateTenantID() {
+	for _, tenantID := range []string{"acme", "Acme-2", "a_b", strings.Repeat("a", 63)} {
+		require.NoError(s.T(), internal.ValidateTenantID(tenantID), tenantID)
+	}
+
+	for _, tenantID := range []string{"", "-acme", "acme corp", "acme'", strings.Repeat("a", 64)} {
+		require.ErrorIs(s.T(), internal.ValidateTenantID(tenantID), internal.ErrInput, tenantID)
+	}
+}
+
+func (s *StorageTestSuite) TestCreateEvent_CreatedBy() {
+	ctx := internal.WithActor(s.ctx, "auth0|ada")
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+
+	s.expectTenant()
+	s.mock.ExpectExec("INSERT INTO events").
+		WithArgs(sqlmock.AnyArg(), "standup", "", start, start.Add(time.Hour), sqlmock.AnyArg(), nil, nil, nil, start.Add(time.Hour),
+			"UTC", false, nil, nil, "", "auth0|ada").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.expectRevision(sqlmock.AnyArg(), internal.RevisionCreated,
+		`{"title": {"to": "standup"}, "start_time": {"to": "2025-12-01T09:00:00Z"}, "end_time": {"to": "2025-12-01T10:00:00Z"}, "time_zone": {"to": "UTC"}}`)
+	s.mock.ExpectCommit()
+
+	result, err := s.storage.CreateEvent(ctx, internal.CreateEventRequest{Title: "standup", StartTime: start, EndTime: start.Add(time.Hour)})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), "auth0|ada", result.CreatedBy)
+}
+
+var apiKeyColumns = []string{"id", "tenant_id", "name", "prefix", "scopes", "created_by", "created_at", "expires_at", "last_used_at", "revoked_at"}
+
+func (s *StorageTestSuite) TestCreateAPIKey_Success() {
+	now := time.Now().UTC()
+	key := internal.APIKey{ID: "key", Name: "sync", Prefix: "evk.acme.abcdefg", Scopes: []internal.Scope{internal.ScopeEventsRead},
+		CreatedBy: "auth0|ada", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+
+	s.expectTenant()
+	s.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO api_keys (id, name, prefix, hash, scopes, created_by, created_at, expires_at)")).
+		WithArgs("key", "sync", "evk.acme.abcdefg", []byte("hash"), "{\"events:read\"}", "auth0|ada", now, now.Add(time.Hour)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	require.NoError(s.T(), s.storage.CreateAPIKey(s.ctx, key, []byte("hash")))
+}
+
+func (s *StorageTestSuite) TestGetAPIKeys_Success() {
+	now := time.Now().UTC()
+	lastUsed := now.Add(-time.Minute)
+
+	s.expectTenant()
+	s.mock.ExpectQuery("SELECT .* FROM api_keys ORDER BY created_at DESC, id").
+		WillReturnRows(sqlmock.NewRows(apiKeyColumns).
+			AddRow("key-1", "acme", "sync", "evk.acme.abcdefg", "{events:read,events:write}", "auth0|ada", now, now.Add(time.Hour),
+				lastUsed, nil).
+			AddRow("key-2", "acme", "old", "evk.acme.hijklmn", "{events:read}", nil, now, now.Add(time.Hour), nil, now))
+	s.mock.ExpectRollback()
+
+	keys, err := s.storage.GetAPIKeys(s.ctx)
+
+	require.NoError(s.T(), err)
+	require.Len(s.T(), keys, 2)
+	require.Equal(s.T(), []internal.Scope{internal.ScopeEventsRead, internal.ScopeEventsWrite}, keys[0].Scopes)
+	require.Equal(s.T(), "auth0|ada", keys[0].CreatedBy)
+	require.Equal(s.T(), lastUsed, *keys[0].LastUsedAt)
+	require.Nil(s.T(), keys[0].RevokedAt)
+	require.Empty(s.T(), keys[1].CreatedBy)
+	require.NotNil(s.T(), keys[1].RevokedAt)
+}
+
+func (s *StorageTestSuite) TestGetAPIKeyByHash_NotFound() {
+	s.expectTenant()
+	s.mock.ExpectQuery("SELECT .* FROM api_keys WHERE hash = \\$1").
+		WithArgs([]byte("hash")).
+		WillReturnRows(sqlmock.NewRows(apiKeyColumns))
+	s.mock.ExpectRollback()
+
+	_, err := s.storage.GetAPIKeyByHash(s.ctx, []byte("hash"))
+
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+}
+
+func (s *StorageTestSuite) TestRevokeAPIKey_NotFound() {
+	now := time.Now().UTC()
+
+	s.expectTenant()
+	s.mock.ExpectExec(regexp.QuoteMeta("UPDATE api_keys SET revoked_at = COALESCE(revoked_at, $1) WHERE id = $2")).
+		WithArgs(now, "missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectRollback()
+
+	err := s.storage.RevokeAPIKey(s.ctx, "missing", now)
+
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+}
+
+func (s *StorageTestSuite) TestReserveIdempotencyKey_Reserved() {
+	now := time.Now().UTC()
+	record := internal.IdempotencyRecord{Principal: "auth0|ada", Key: "retry-1", Fingerprint: []byte("fp"), CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+
+	s.expectTenant()
+	s.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM idempotency_keys WHERE expires_at <= $1")).
+		WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	s.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO idempotency_keys (principal, key, fingerprint, created_at, expires_at) "+
+		"VALUES ($1, $2, $3, $4, $5) ON CONFLICT (tenant_id, principal, key) DO NOTHING")).
+		WithArgs("auth0|ada", "retry-1", []byte("fp"), now, now.Add(time.Hour)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	stored, reserved, err := s.storage.ReserveIdempotencyKey(s.ctx, record)
+
+	require.NoError(s.T(), err)
+	require.True(s.T(), reserved)
+	require.Equal(s.T(), record, stored)
+}
+
+func (s *StorageTestSuite) TestReserveIdempotencyKey_Taken() {
+	now := time.Now().UTC()
+	record := internal.IdempotencyRecord{Principal: "auth0|ada", Key: "retry-1", Fingerprint: []byte("fp"), CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	created := now.Add(-time.Minute)
+
+	s.expectTenant()
+	s.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM idempotency_keys WHERE expires_at <= $1")).
+		WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO idempotency_keys")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT fingerprint, status, body, created_at, expires_at FROM idempotency_keys WHERE principal = $1 AND key = $2")).
+		WithArgs("auth0|ada", "retry-1").
+		WillReturnRows(sqlmock.NewRows([]string{"fingerprint", "status", "body", "created_at", "expires_at"}).
+			AddRow([]byte("fp"), 201, []byte(`{"id":"e1"}`), created, created.Add(time.Hour)))
+	s.mock.ExpectRollback()
+
+	stored, reserved, err := s.storage.ReserveIdempotencyKey(s.ctx, record)
+
+	require.NoError(s.T(), err)
+	require.False(s.T(), reserved)
+	require.Equal(s.T(), internal.IdempotencyRecord{Principal: "auth0|ada", Key: "retry-1", Fingerprint: []byte("fp"), Status: 201,
+		Body: []byte(`{"id":"e1"}`), CreatedAt: created, ExpiresAt: created.Add(time.Hour)}, stored)
+}
+
+func (s *StorageTestSuite) TestReleaseIdempotencyKey() {
+	s.expectTenant()
+	s.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM idempotency_keys WHERE principal = $1 AND key = $2 AND status IS NULL")).
+		WithArgs("auth0|ada", "retry-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	require.NoError(s.T(), s.storage.ReleaseIdempotencyKey(s.ctx, "auth0|ada", "retry-1"))
+}
+
+func (s *StorageTestSuite) TestGetEvents_VisibleTo() {
+	s.expectTenant()
+	s.mock.ExpectQuery("FROM events WHERE deleted_at IS NULL AND \\(calendar_id = \\$2 OR created_by = \\$1 "+
+		"OR calendar_id IN \\(SELECT id FROM calendars WHERE created_by = \\$1\\) "+
+		"OR EXISTS \\(SELECT 1 FROM grants WHERE principal = \\$1 .*\\)\\) ORDER BY start_time ASC, id ASC LIMIT \\$3").
+		WithArgs("auth0|ada", internal.DefaultCalendarID, 10).
+		WillReturnRows(newEventRows())
+
+	s.mock.ExpectRollback()
+
+	results, err := s.storage.GetEvents(s.ctx, internal.EventFilter{VisibleTo: "auth0|ada"}, internal.Page{Limit: 10})
+
+	require.NoError(s.T(), err)
+	require.Empty(s.T(), results)
+}
+
+func (s *StorageTestSuite) TestGetCalendars_VisibleTo() {
+	s.expectTenant()
+	s.expectDefaultCalendar()
+	s.mock.ExpectQuery("SELECT .* FROM calendars WHERE id = \\$1 OR created_by = \\$2 OR id IN "+
+		"\\(SELECT object_id FROM grants WHERE object_type = 'calendar' AND principal = \\$2\\) ORDER BY name, id").
+		WithArgs(internal.DefaultCalendarID, "auth0|ada").
+		WillReturnRows(sqlmock.NewRows(calendarColumns))
+
+	s.mock.ExpectCommit()
+
+	calendars, err := s.storage.GetCalendars(s.ctx, "auth0|ada")
+
+	require.NoError(s.T(), err)
+	require.Empty(s.T(), calendars)
+}
+
+func (s *StorageTestSuite) TestDeleteCalendar_DeletesGrants() {
+	s.expectTenant()
+	s.mock.ExpectExec("DELETE FROM calendars WHERE id = \\$1").
+		WithArgs("work").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("DELETE FROM grants WHERE object_type = 'calendar' AND object_id = \\$1").
+		WithArgs("work").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	s.mock.ExpectCommit()
+
+	require.NoError(s.T(), s.storage.DeleteCalendar(s.ctx, "work"))
+}
+
+func (s *StorageTestSuite) TestGetCalendarAccess_Success() {
+	s.expectTenant()
+	s.mock.ExpectQuery("SELECT ARRAY\\(SELECT role FROM grants .* UNION ALL SELECT 'owner' FROM calendars .*\\)").
+		WithArgs("work", "auth0|ada").
+		WillReturnRows(sqlmock.NewRows([]string{"array"}).AddRow("{viewer,owner}"))
+	s.mock.ExpectRollback()
+
+	roles, err := s.storage.GetCalendarAccess(s.ctx, "auth0|ada", "work")
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), []internal.AccessRole{internal.AccessViewer, internal.AccessOwner}, roles)
+}
+
+func (s *StorageTestSuite) TestGetEventAccess_Success() {
+	s.expectTenant()
+	s.mock.ExpectQuery("SELECT e.calendar_id, ARRAY\\(.*\\) FROM events e JOIN calendars c .* WHERE e.id = \\$1 LIMIT 1").
+		WithArgs("evt", "auth0|ada").
+		WillReturnRows(sqlmock.NewRows([]string{"calendar_id", "array"}).AddRow("work", "{editor}"))
+	s.mock.ExpectRollback()
+
+	access, err := s.storage.GetEventAccess(s.ctx, "auth0|ada", "evt")
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), internal.EventAccess{CalendarID: "work", Roles: []internal.AccessRole{internal.AccessEditor}}, access)
+}
+
+func (s *StorageTestSuite) TestGetEventAccessByUID_NotFound() {
+	s.expectTenant()
+	s.mock.ExpectQuery("FROM events e JOIN calendars c .* WHERE \\(e.id = \\$1 OR e.ical_uid = \\$1\\) LIMIT 1").
+		WithArgs("uid@example.com", "auth0|ada").
+		WillReturnRows(sqlmock.NewRows([]string{"calendar_id", "array"}))
+	s.mock.ExpectRollback()
+
+	_, err := s.storage.GetEventAccessByUID(s.ctx, "auth0|ada", "uid@example.com")
+
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+}
+
+func (s *StorageTestSuite) TestGetGrants_Success() {
+	now := time.Now().UTC()
+
+	s.expectTenant()
+	s.mock.ExpectQuery("SELECT object_type, object_id, principal, role, granted_by, created_at FROM grants "+
+		"WHERE object_type = \\$1 AND object_id = \\$2 ORDER BY created_at, principal").
+		WithArgs(internal.ObjectCalendar, "work").
+		WillReturnRows(sqlmock.NewRows([]string{"object_type", "object_id", "principal", "role", "granted_by", "created_at"}).
+			AddRow("calendar", "work", "auth0|bob", "viewer", "auth0|ada", now))
+	s.mock.ExpectRollback()
+
+	grants, err := s.storage.GetGrants(s.ctx, internal.ObjectCalendar, "work")
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), []internal.Grant{{ObjectType: internal.ObjectCalendar, ObjectID: "work", Principal: "auth0|bob",
+		Role: internal.AccessViewer, GrantedBy: "auth0|ada", CreatedAt: now}}, grants)
+}
+
+func (s *StorageTestSuite) TestSetGrant_Success() {
+	now := time.Now().UTC()
+
+	s.expectTenant()
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM events WHERE id = $1 AND deleted_at IS NULL)")).
+		WithArgs("evt").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	s.mock.ExpectExec("INSERT INTO grants .* ON CONFLICT \\(tenant_id, object_type, object_id, principal\\) DO UPDATE").
+		WithArgs(internal.ObjectEvent, "evt", "auth0|bob", internal.AccessEditor, "auth0|ada", now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	err := s.storage.SetGrant(s.ctx, internal.Grant{ObjectType: internal.ObjectEvent, ObjectID: "evt", Principal: "auth0|bob",
+		Role: internal.AccessEditor, GrantedBy: "auth0|ada", CreatedAt: now})
+
+	require.NoError(s.T(), err)
+}
+
+func (s *StorageTestSuite) TestSetGrant_NotFound() {
+	s.expectTenant()
+	s.mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM calendars WHERE id = $1)")).
+		WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	s.mock.ExpectRollback()
+
+	err := s.storage.SetGrant(s.ctx, internal.Grant{ObjectType: internal.ObjectCalendar, ObjectID: "missing",
+		Principal: "auth0|bob", Role: internal.AccessViewer})
+
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+}
+
+func (s *StorageTestSuite) TestDeleteGrant_NotFound() {
+	s.expectTenant()
+	s.mock.ExpectExec("DELETE FROM grants WHERE object_type = \\$1 AND object_id = \\$2 AND principal = \\$3").
+		WithArgs(internal.ObjectEvent, "evt", "auth0|bob").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectRollback()
+
+	err := s.storage.DeleteGrant(s.ctx, internal.ObjectEvent, "evt", "auth0|bob")
+
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
 }
@@ -0,0 +1,42 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// DefaultTenantID owns the rows that existed before tenants were introduced.
+const DefaultTenantID = "default"
+
+var (
+	tenantPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,62}$`)
+
+	errNoTenant = errors.New("no tenant in context")
+)
+
+type tenantKey struct{}
+
+// WithTenant scopes ctx to a tenant. Storage only reads and writes the rows of
+// the tenant of its context.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// TenantFromContext returns the tenant set by WithTenant.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenantID, ok := ctx.Value(tenantKey{}).(string)
+
+	return tenantID, ok && tenantID != ""
+}
+
+// ValidateTenantID checks a tenant id is 1 to 63 letters, digits, dashes and
+// underscores, starting with a letter or digit.
+func ValidateTenantID(tenantID string) error {
+	if !tenantPattern.MatchString(tenantID) {
+		return fmt.Errorf("invalid tenant %q: %w", tenantID, ErrInput)
+	}
+
+	return nil
+}
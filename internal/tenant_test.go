@@ -0,0 +1,242 @@
+package internal_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal"
+	"github.com/ObiaNzk/LTK-test-manu/internal/outbox"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// TenantIsolationTestSuite checks the row level security policies against a
+// real Postgres. Storage of one tenant must not see the rows of another, even
+// through a query without a tenant condition.
+type TenantIsolationTestSuite struct {
+	postgresSuite
+	acme   context.Context
+	globex context.Context
+}
+
+func (s *TenantIsolationTestSuite) SetupSuite() {
+	s.setupPostgres("tenants")
+	s.acme = internal.WithTenant(context.Background(), "acme")
+	s.globex = internal.WithTenant(context.Background(), "globex")
+}
+
+func (s *TenantIsolationTestSuite) createEvent(ctx context.Context, title string) internal.CreateEventResponse {
+	start := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Hour)
+
+	event, err := s.storage.CreateEvent(ctx, internal.CreateEventRequest{
+		Title:       title,
+		Description: "Quarterly review",
+		StartTime:   start,
+		EndTime:     start.Add(time.Hour),
+		CalendarID:  internal.DefaultCalendarID,
+	})
+	require.NoError(s.T(), err)
+
+	return event
+}
+
+// countEvents counts the events visible to a tenant with a query that has no
+// tenant condition of its own.
+func (s *TenantIsolationTestSuite) countEvents(tenantID string) int {
+	trx, err := s.db.Begin()
+	require.NoError(s.T(), err)
+
+	defer trx.Rollback()
+
+	if tenantID != "" {
+		_, err = trx.Exec("SELECT set_config('app.tenant_id', $1, true)", tenantID)
+		require.NoError(s.T(), err)
+	}
+
+	var count int
+	require.NoError(s.T(), trx.QueryRow("SELECT COUNT(*) FROM events").Scan(&count))
+
+	return count
+}
+
+func (s *TenantIsolationTestSuite) TestGetEventByID_OtherTenant() {
+	event := s.createEvent(s.acme, "Board meeting")
+
+	_, err := s.storage.GetEventByID(s.globex, event.ID, true)
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+
+	found, err := s.storage.GetEventByID(s.acme, event.ID, false)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), "Board meeting", found.Title)
+}
+
+func (s *TenantIsolationTestSuite) TestWrites_OtherTenant() {
+	event := s.createEvent(s.acme, "Offsite")
+
+	_, err := s.storage.UpdateEvent(s.globex, event.ID, event.Version, internal.CreateEventRequest{
+		Title:     "Hijacked",
+		StartTime: event.StartTime,
+		EndTime:   event.EndTime,
+	})
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+
+	require.ErrorIs(s.T(), s.storage.DeleteEvent(s.globex, event.ID), internal.ErrNotFound)
+
+	err = s.storage.AddAttendee(s.globex, internal.Attendee{
+		EventID:         event.ID,
+		Email:           "mallory@example.com",
+		Role:            internal.RoleRequired,
+		Status:          internal.StatusInvited,
+		InvitedAt:       time.Now().UTC(),
+		StatusChangedAt: time.Now().UTC(),
+	})
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+
+	found, err := s.storage.GetEventByID(s.acme, event.ID, false)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), "Offsite", found.Title)
+}
+
+func (s *TenantIsolationTestSuite) TestRawQuery_OnlySeesOwnRows() {
+	initech := internal.WithTenant(context.Background(), "initech")
+
+	s.createEvent(initech, "Planning")
+	s.createEvent(initech, "Retro")
+
+	require.Equal(s.T(), 2, s.countEvents("initech"))
+	require.Zero(s.T(), s.countEvents("umbrella"))
+	require.Zero(s.T(), s.countEvents(""))
+}
+
+func (s *TenantIsolationTestSuite) TestCalendars_PerTenant() {
+	calendar, err := s.storage.CreateCalendar(s.acme, internal.Calendar{Name: "Finance"})
+	require.NoError(s.T(), err)
+
+	_, err = s.storage.GetCalendar(s.globex, calendar.ID)
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+
+	// Every tenant gets a default calendar of its own.
+	defaults, err := s.storage.GetCalendar(s.globex, internal.DefaultCalendarID)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), internal.DefaultCalendarID, defaults.ID)
+
+	calendars, err := s.storage.GetCalendars(s.globex, "")
+	require.NoError(s.T(), err)
+	require.Len(s.T(), calendars, 1)
+}
+
+func (s *TenantIsolationTestSuite) TestIdempotencyKeys_PerTenantAndExpiry() {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	record := internal.IdempotencyRecord{Principal: "auth0|ada", Key: "retry-1", Fingerprint: []byte("fp"), CreatedAt: now,
+		ExpiresAt: now.Add(time.Hour)}
+
+	_, reserved, err := s.storage.ReserveIdempotencyKey(s.acme, record)
+	require.NoError(s.T(), err)
+	require.True(s.T(), reserved)
+
+	require.NoError(s.T(), s.storage.SaveIdempotentResponse(s.acme, "auth0|ada", "retry-1", 201, []byte(`{"id":"e1"}`)))
+
+	stored, reserved, err := s.storage.ReserveIdempotencyKey(s.acme, record)
+	require.NoError(s.T(), err)
+	require.False(s.T(), reserved)
+	require.Equal(s.T(), 201, stored.Status)
+	require.Equal(s.T(), []byte(`{"id":"e1"}`), stored.Body)
+
+	// Completed keys survive a release.
+	require.NoError(s.T(), s.storage.ReleaseIdempotencyKey(s.acme, "auth0|ada", "retry-1"))
+
+	_, reserved, err = s.storage.ReserveIdempotencyKey(s.globex, record)
+	require.NoError(s.T(), err)
+	require.True(s.T(), reserved, "another tenant has keys of its own")
+
+	later := record
+	later.CreatedAt = now.Add(2 * time.Hour)
+	later.ExpiresAt = later.CreatedAt.Add(time.Hour)
+
+	_, reserved, err = s.storage.ReserveIdempotencyKey(s.acme, later)
+	require.NoError(s.T(), err)
+	require.True(s.T(), reserved, "an expired key is reserved again")
+}
+
+func (s *TenantIsolationTestSuite) TestEventHistory() {
+	ada := internal.WithActor(s.acme, "auth0|ada")
+	event := s.createEvent(ada, "Kickoff")
+
+	update := internal.CreateEventRequest{Title: "Kickoff v2", Description: event.Description, StartTime: event.StartTime, EndTime: event.EndTime}
+	_, err := s.storage.UpdateEvent(ada, event.ID, event.Version, update)
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), s.storage.DeleteEvent(ada, event.ID))
+	_, err = s.storage.RestoreEvent(ada, event.ID)
+	require.NoError(s.T(), err)
+
+	revisions, err := s.storage.GetEventRevisions(s.acme, event.ID)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), revisions, 4)
+
+	for i, action := range []internal.RevisionAction{internal.RevisionCreated, internal.RevisionUpdated, internal.RevisionDeleted, internal.RevisionRestored} {
+		require.Equal(s.T(), i+1, revisions[i].Revision)
+		require.Equal(s.T(), action, revisions[i].Action)
+		require.Equal(s.T(), "auth0|ada", revisions[i].Actor)
+	}
+
+	require.Equal(s.T(), map[string]internal.FieldChange{"title": {From: []byte(`"Kickoff"`), To: []byte(`"Kickoff v2"`)}}, revisions[1].Changes)
+	require.Contains(s.T(), revisions[2].Changes, "deleted_at")
+	require.Equal(s.T(), "Kickoff", revisions[0].Snapshot.Title)
+
+	_, err = s.storage.GetEventRevisions(s.globex, event.ID)
+	require.ErrorIs(s.T(), err, internal.ErrNotFound)
+
+	// The history is append-only for the API.
+	_, err = s.db.Exec("UPDATE event_revisions SET actor = 'mallory'")
+	require.ErrorContains(s.T(), err, "permission denied")
+}
+
+func (s *TenantIsolationTestSuite) TestOutbox() {
+	event := s.createEvent(s.acme, "Launch")
+	require.NoError(s.T(), s.storage.DeleteEvent(s.acme, event.ID))
+
+	// A tenant writes its messages but cannot read any, not even its own.
+	trx, err := s.db.Begin()
+	require.NoError(s.T(), err)
+
+	_, err = trx.Exec("SELECT set_config('app.tenant_id', $1, true)", "acme")
+	require.NoError(s.T(), err)
+
+	var count int
+	require.NoError(s.T(), trx.QueryRow("SELECT COUNT(*) FROM outbox").Scan(&count))
+	require.Zero(s.T(), count)
+	require.NoError(s.T(), trx.Rollback())
+
+	memory := outbox.NewMemory()
+	relay := internal.NewRelay(s.storage, memory, time.Second)
+
+	for {
+		delivered, err := relay.Flush(context.Background())
+		require.NoError(s.T(), err)
+
+		if delivered == 0 {
+			break
+		}
+	}
+
+	var topics []string
+
+	for _, message := range memory.Messages() {
+		if message.Key == event.ID {
+			require.Equal(s.T(), "acme", message.TenantID)
+			topics = append(topics, message.Topic)
+		}
+	}
+
+	require.Equal(s.T(), []string{"events.created", "events.deleted"}, topics)
+
+	// Delivered messages are not published again.
+	delivered, err := relay.Flush(context.Background())
+	require.NoError(s.T(), err)
+	require.Zero(s.T(), delivered)
+}
+
+func TestTenantIsolationTestSuite(t *testing.T) {
+	suite.Run(t, new(TenantIsolationTestSuite))
+}
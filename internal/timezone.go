@@ -0,0 +1,93 @@
+package internal
+
+import "time"
+
+// zoneName is the IANA zone of the event: TimeZone when set, otherwise the
+// location of StartTime when it is a named zone, otherwise UTC. Offsets alone,
+// as in RFC 3339 times, do not name a zone.
+func (e CreateEventRequest) zoneName() string {
+	if e.TimeZone != "" {
+		return e.TimeZone
+	}
+
+	name := e.StartTime.Location().String()
+	if name == "" || name == "Local" {
+		return "UTC"
+	}
+
+	if _, err := time.LoadLocation(name); err != nil {
+		return "UTC"
+	}
+
+	return name
+}
+
+// location is where the event is laid out. The floating dates of all-day events
+// are kept in UTC whatever their zone.
+func (e CreateEventRequest) location() *time.Location {
+	if e.AllDay {
+		return time.UTC
+	}
+
+	return loadZone(e.zoneName())
+}
+
+func (e CreateEventResponse) location() *time.Location {
+	if e.AllDay {
+		return time.UTC
+	}
+
+	return loadZone(e.TimeZone)
+}
+
+// loadZone resolves a zone that was validated before it was stored. Unknown
+// zones fall back to UTC.
+func loadZone(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil || name == "" {
+		return time.UTC
+	}
+
+	return loc
+}
+
+// In returns the event with every time expressed in loc. The dates of an
+// all-day event are not instants and stay at midnight UTC.
+func (e CreateEventResponse) In(loc *time.Location) CreateEventResponse {
+	e.CreatedAt = e.CreatedAt.In(loc)
+
+	if e.DeletedAt != nil {
+		deletedAt := e.DeletedAt.In(loc)
+		e.DeletedAt = &deletedAt
+	}
+
+	if e.AllDay {
+		loc = time.UTC
+	}
+
+	e.StartTime = e.StartTime.In(loc)
+	e.EndTime = e.EndTime.In(loc)
+
+	if e.RecurrenceID != nil {
+		recurrenceID := e.RecurrenceID.In(loc)
+		e.RecurrenceID = &recurrenceID
+	}
+
+	e.ExDates = datesIn(e.ExDates, loc)
+	e.RDates = datesIn(e.RDates, loc)
+
+	return e
+}
+
+func datesIn(dates []time.Time, loc *time.Location) []time.Time {
+	if dates == nil {
+		return nil
+	}
+
+	result := make([]time.Time, 0, len(dates))
+	for _, date := range dates {
+		result = append(result, date.In(loc))
+	}
+
+	return result
+}
@@ -0,0 +1,179 @@
+// Package validation checks requests against the rules in their validate
+// struct tags, plus any rule the caller adds, and reports every violation
+// rather than the first one.
+//
+// A tag lists rules separated by commas:
+//
+//		Title     string    `validate:"required,min=101"`
+//		Role      string    `validate:"oneof=owner editor viewer"`
+//		EndTime   time.Time `validate:"required,gtefield=StartTime"`
+//
+//	  - required: the field is not its zero value
+//	  - min=N, max=N: at least or at most N characters for strings, N items for
+//	    slices, and N itself for numbers
+//	  - oneof=a b c: a non-empty string is one of the listed values
+//	  - gtefield=F: a time is not before the time in field F, when both are set
+//
+// Fields are named in snake case, so StartTime is reported as start_time.
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Violation is one broken rule. Message names the field, so it reads on its own.
+type Violation struct {
+	Field   string
+	Message string
+}
+
+// Errors is every violation of a request.
+type Errors []Violation
+
+func (e Errors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, violation := range e {
+		messages = append(messages, violation.Message)
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Add records a violation of field.
+func (e *Errors) Add(field, message string) {
+	*e = append(*e, Violation{Field: field, Message: message})
+}
+
+// Struct checks the validate tags of v, a struct or a pointer to one.
+func Struct(v any) Errors {
+	value := reflect.Indirect(reflect.ValueOf(v))
+	if value.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("validation: %T is not a struct", v))
+	}
+
+	var errs Errors
+
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+
+		tag := field.Tag.Get("validate")
+		if tag == "" {
+			continue
+		}
+
+		for _, rule := range strings.Split(tag, ",") {
+			name, param, _ := strings.Cut(rule, "=")
+
+			// Like a failed required, a failed rule makes the later ones moot.
+			message, ok := check(value, value.Field(i), name, param)
+			if !ok {
+				errs.Add(FieldName(field.Name), FieldName(field.Name)+" "+message)
+				break
+			}
+		}
+	}
+
+	return errs
+}
+
+// check applies one rule to field, a field of parent. It returns what is
+// wrong when the rule is broken.
+func check(parent, field reflect.Value, rule, param string) (string, bool) {
+	switch rule {
+	case "required":
+		return "cannot be empty", !field.IsZero()
+	case "min", "max":
+		limit, err := strconv.Atoi(param)
+		if err != nil {
+			panic(fmt.Sprintf("validation: invalid %s limit %q", rule, param))
+		}
+
+		size, format := measure(field)
+
+		if rule == "min" {
+			return fmt.Sprintf(format, "at least", limit), size >= limit
+		}
+
+		return fmt.Sprintf(format, "at most", limit), size <= limit
+	case "oneof":
+		allowed := strings.Fields(param)
+		value := field.String()
+
+		if value == "" {
+			return "", true
+		}
+
+		for _, candidate := range allowed {
+			if value == candidate {
+				return "", true
+			}
+		}
+
+		return "should be " + list(allowed), false
+	case "gtefield":
+		other := parent.FieldByName(param)
+
+		start, ok := other.Interface().(time.Time)
+		end, _ := field.Interface().(time.Time)
+
+		if !ok || start.IsZero() || end.IsZero() {
+			return "", true
+		}
+
+		return "should not be before " + FieldName(param), !end.Before(start)
+	default:
+		panic(fmt.Sprintf("validation: unknown rule %q", rule))
+	}
+}
+
+// measure returns the size min and max compare, and how to phrase a limit on it.
+func measure(field reflect.Value) (int, string) {
+	switch field.Kind() {
+	case reflect.String:
+		return utf8.RuneCountInString(field.String()), "should have %s %d characters"
+	case reflect.Slice, reflect.Map:
+		return field.Len(), "should have %s %d items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(field.Int()), "should be %s %d"
+	default:
+		panic(fmt.Sprintf("validation: cannot measure a %s", field.Kind()))
+	}
+}
+
+// list joins values as "a, b or c".
+func list(values []string) string {
+	if len(values) < 2 {
+		return strings.Join(values, "")
+	}
+
+	return strings.Join(values[:len(values)-1], ", ") + " or " + values[len(values)-1]
+}
+
+// FieldName turns a Go field name into the snake case name reported for it,
+// keeping initialisms together: CalendarID becomes calendar_id.
+func FieldName(name string) string {
+	runes := []rune(name)
+
+	var b strings.Builder
+
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			previousLower := unicode.IsLower(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+			if previousLower || (unicode.IsUpper(runes[i-1]) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+
+		b.WriteRune(unicode.ToLower(r))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,90 @@
+package validation_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ObiaNzk/LTK-test-manu/internal/validation"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type ValidationTestSuite struct {
+	suite.Suite
+}
+
+type booking struct {
+	Name      string    `validate:"required,max=5"`
+	Role      string    `validate:"oneof=chair required optional"`
+	Seats     int       `validate:"min=1"`
+	Tags      []string  `validate:"max=2"`
+	StartTime time.Time `validate:"required"`
+	EndTime   time.Time `validate:"gtefield=StartTime"`
+	Notes     string
+}
+
+var start = time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+
+func valid() booking {
+	return booking{Name: "room", Seats: 1, StartTime: start, EndTime: start.Add(time.Hour)}
+}
+
+func (s *ValidationTestSuite) TestStruct_Valid() {
+	require.Empty(s.T(), validation.Struct(valid()))
+	require.Empty(s.T(), validation.Struct(&booking{Name: "room", Role: "chair", Seats: 3, StartTime: start}))
+}
+
+func (s *ValidationTestSuite) TestStruct_CollectsEveryViolation() {
+	b := booking{
+		Name:      "meeting",
+		Role:      "host",
+		Tags:      []string{"a", "b", "c"},
+		StartTime: start,
+		EndTime:   start.Add(-time.Minute),
+	}
+
+	require.Equal(s.T(), validation.Errors{
+		{Field: "name", Message: "name should have at most 5 characters"},
+		{Field: "role", Message: "role should be chair, required or optional"},
+		{Field: "seats", Message: "seats should be at least 1"},
+		{Field: "tags", Message: "tags should have at most 2 items"},
+		{Field: "end_time", Message: "end_time should not be before start_time"},
+	}, validation.Struct(b))
+}
+
+func (s *ValidationTestSuite) TestStruct_RequiredSkipsLaterRules() {
+	b := valid()
+	b.Name = ""
+
+	require.Equal(s.T(), validation.Errors{{Field: "name", Message: "name cannot be empty"}}, validation.Struct(b))
+}
+
+func (s *ValidationTestSuite) TestStruct_CountsCharacters() {
+	b := valid()
+	b.Name = "ñandú"
+
+	require.Empty(s.T(), validation.Struct(b))
+}
+
+func (s *ValidationTestSuite) TestErrors_Add() {
+	errs := validation.Struct(valid())
+	errs.Add("time_zone", `unknown time zone "Mars/Olympus"`)
+	errs.Add("rrule", "invalid rrule: missing FREQ")
+
+	require.EqualError(s.T(), errs, `unknown time zone "Mars/Olympus"; invalid rrule: missing FREQ`)
+}
+
+func (s *ValidationTestSuite) TestFieldName() {
+	for name, want := range map[string]string{
+		"Title":      "title",
+		"StartTime":  "start_time",
+		"CalendarID": "calendar_id",
+		"ID":         "id",
+	} {
+		require.Equal(s.T(), want, validation.FieldName(name), name)
+	}
+}
+
+func TestValidationTestSuite(t *testing.T) {
+	suite.Run(t, new(ValidationTestSuite))
+}